@@ -1,10 +1,19 @@
 module privatemarbles
 
-go 1.12
+go 1.18
 
 require (
-	github.com/hyperledger/fabric v1.4.1 // indirect
+	github.com/golang/protobuf v1.3.2
 	github.com/hyperledger/fabric-chaincode-go v0.0.0-20200330074746-2584993c3b5e
 	github.com/hyperledger/fabric-protos-go v0.0.0-20200330074707-cfe579e86986
+	golang.org/x/text v0.3.0
+)
+
+require (
+	github.com/hyperledger/fabric v1.4.1 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/net v0.0.0-20190522155817-f3200d17e092 // indirect
+	golang.org/x/sys v0.0.0-20190710143415-6ec70d6a5542 // indirect
+	google.golang.org/genproto v0.0.0-20180831171423-11092d34479b // indirect
+	google.golang.org/grpc v1.23.0 // indirect
 )
@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// AcceptOffer - transfer an article to the owner named in an offer of a buyer org, at the
+// offered price. The owner org passes the offer as the buyer made it, which must match
+// the commitment of the buyer org, and the offer must not have expired at the transaction
+// timestamp. The offers made on the article are withdrawn, they were made to the old owner.
+// ===========================================================================================
+func AcceptOffer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start accept offer")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	acceptJsonBytes, ok := transMap["offer_accept"]
+	if !ok {
+		return invalidInput("", "offer_accept must be a key in the transient map")
+	}
+
+	if len(acceptJsonBytes) == 0 {
+		return invalidInput("", "offer_accept value in the transient map must be a non-empty JSON string")
+	}
+
+	var acceptInput model.OfferAcceptTransientInput
+	err = model.DecodeTransientInput("offer_accept", acceptJsonBytes, &acceptInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = acceptInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(acceptInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, acceptInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may accept offers ====
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== A lock of the buyer org reserves the article for this very transfer ====
+	if article.LockedBy != acceptInput.BuyerOrg {
+		err = verifyNotLockedByOtherOrg(stub, article)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	commitmentKey, err := stub.CreateCompositeKey(model.OfferCommitmentIndex, []string{acceptInput.Name, acceptInput.BuyerOrg})
+	if err != nil {
+		return errorResponse(err)
+	}
+	commitmentAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, commitmentKey)
+	if err != nil {
+		return internalError(acceptInput.Name, "Failed to get offer commitment: "+err.Error())
+	} else if commitmentAsBytes == nil {
+		return notFound(acceptInput.Name, "Org "+acceptInput.BuyerOrg+" made no offer on article: "+acceptInput.Name)
+	}
+	commitment := model.OfferCommitment{}
+	err = json.Unmarshal(commitmentAsBytes, &commitment)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The offer must still be open at the transaction timestamp ====
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, commitment.ExpiresAt)
+	if err != nil {
+		return internalError(acceptInput.Name, "invalid expiry of offer: "+err.Error())
+	}
+	if !now.Before(expiresAt) {
+		return accessDenied(acceptInput.Name, "the offer of org "+acceptInput.BuyerOrg+" on article "+acceptInput.Name+" expired at "+commitment.ExpiresAt)
+	}
+
+	// ==== The offer must be the one committed to ====
+	offerJSONasBytes, err := model.MarshalCanonical(&model.Offer{
+		ObjectType: "offer",
+		Name:       acceptInput.Name,
+		Price:      acceptInput.Price,
+		Owner:      acceptInput.Owner,
+		ExpiresAt:  acceptInput.ExpiresAt,
+		Salt:       acceptInput.Salt,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	offerHash := sha256.Sum256(offerJSONasBytes)
+	if hex.EncodeToString(offerHash[:]) != commitment.Hash {
+		return accessDenied(acceptInput.Name, "offer does not match the commitment of org "+acceptInput.BuyerOrg)
+	}
+
+	err = verifyOwnerRegistered(stub, cfg, acceptInput.Owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The details must be readable before the first write, those of older articles are created ====
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, acceptInput.Name)
+	if ccErr, ok := err.(*chaincodeError); ok && ccErr.Code == CodeArticleNotFound {
+		creatorID, err := cid.GetID(stub)
+		if err != nil {
+			return internalError(acceptInput.Name, "Failed to get client ID: "+err.Error())
+		}
+		privateDetails = &model.ArticlePrivateDetails{Name: acceptInput.Name, CreatorID: creatorID}
+	} else if err != nil {
+		return errorResponse(err)
+	}
+
+	oldOwner := article.Owner
+
+	err = changeArticleOwner(stub, cfg, article, acceptInput.Owner, acceptInput.BuyerOrg, "acceptOffer")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putArticlePrivateDetails(stub, cfg, acceptInput.Name, privateDetails.CreatorID, privateDetails.Currency, privateDetails.Price, acceptInput.Price)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = putPriceRecord(stub, cfg, acceptInput.Name, privateDetails.Price, acceptInput.Price)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Offers made to the old owner do not carry over ====
+	err = deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.OfferCommitmentIndex, []string{acceptInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleTransferred", model.ArticleEventEntry{
+		Name:     article.Name,
+		OldOwner: oldOwner,
+		NewOwner: article.Owner,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end acceptOffer (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// AcceptTransfer - accept the transfer of a article proposed to the client org and complete
+// it. The article stays under the key-level endorsement policy of the owner org until the
+// transfer, so the transaction is endorsed by a peer of the owner org as well; unlike the
+// other writing functions it does not require the client org to match the peer org.
+// ===========================================================================================
+func AcceptTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start accept transfer")
+
+	pendingTransfer, article, err := getTransferResponse(stub, cfg, args)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	expired, err := pendingTransferExpired(stub, pendingTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if expired {
+		return accessDenied(article.Name, "the transfer of article "+article.Name+" expired at "+pendingTransfer.Expiry)
+	}
+
+	// ==== The owner may have been deactivated since the proposal ====
+	err = verifyOwnerRegistered(stub, cfg, pendingTransfer.NewOwner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	oldOwner := article.Owner
+
+	err = changeArticleOwner(stub, cfg, article, pendingTransfer.NewOwner, pendingTransfer.TargetOrg, "acceptTransfer")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleTransferred", model.ArticleEventEntry{
+		Name:     article.Name,
+		OldOwner: oldOwner,
+		NewOwner: article.Owner,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end acceptTransfer (success)")
+	return shim.Success(nil)
+}
+
+// getTransferResponse reads the "transfer_response" transient input of acceptTransfer and
+// rejectTransfer and returns the transfer pending for the article, which must be proposed
+// to the client org, together with the article. A transfer accepted or rejected before is
+// no longer pending, so answering twice fails with ARTICLE_NOT_FOUND.
+func getTransferResponse(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) (*model.PendingTransfer, *model.Article, error) {
+	if len(args) != 0 {
+		return nil, nil, newError(CodeInvalidInput, "", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return nil, nil, newError(CodeInternal, "", "Error getting transient: %v", err)
+	}
+
+	responseJsonBytes, ok := transMap["transfer_response"]
+	if !ok {
+		return nil, nil, newError(CodeInvalidInput, "", "transfer_response must be a key in the transient map")
+	}
+
+	if len(responseJsonBytes) == 0 {
+		return nil, nil, newError(CodeInvalidInput, "", "transfer_response value in the transient map must be a non-empty JSON string")
+	}
+
+	var responseInput model.TransferResponseTransientInput
+	err = model.DecodeTransientInput("transfer_response", responseJsonBytes, &responseInput)
+	if err != nil {
+		return nil, nil, newError(CodeInvalidInput, "", "%v", err)
+	}
+
+	err = responseInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return nil, nil, newError(CodeInvalidInput, responseInput.Name, "%v", err)
+	}
+
+	article, err := getArticle(stub, cfg, responseInput.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	pendingTransfer, err := getPendingTransfer(stub, cfg, responseInput.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// ==== Only the org the transfer was proposed to may answer it ====
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return nil, nil, newError(CodeInternal, responseInput.Name, "Failed to get client MSP ID: %v", err)
+	}
+	if clientOrgID != pendingTransfer.TargetOrg {
+		return nil, nil, newError(CodeAccessDenied, responseInput.Name, "the transfer of article %s was proposed to org %s, not to org %s", responseInput.Name, pendingTransfer.TargetOrg, clientOrgID)
+	}
+
+	return pendingTransfer, article, nil
+}
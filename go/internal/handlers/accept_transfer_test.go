@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/testutil"
+)
+
+var escrowFunctions = map[string]HandlerFunc{
+	"proposeTransfer": ProposeTransfer,
+	"acceptTransfer":  AcceptTransfer,
+	"rejectTransfer":  RejectTransfer,
+	"cancelTransfer":  CancelTransfer,
+}
+
+// proposeToJerry proposes to transfer article1 to jerry, open for ttlSeconds
+func (n *testNetwork) proposeToJerry(t *testing.T, ttlSeconds string) {
+	t.Helper()
+	response := n.user1.InvokeTransient("proposeTransfer", testutil.Transient("transfer_proposal", `{"name":"article1","newOwner":"jerry","ownerOrg":"`+org2+`","ttlSeconds":`+ttlSeconds+`}`))
+	expectStatus(t, response, shim.OK)
+}
+
+// answerTransfer answers the transfer of article1 with acceptTransfer, rejectTransfer or
+// cancelTransfer as a client of the org, endorsed by the peer of the owner org Org1
+func answerTransfer(client *testutil.Client, function string) *testutil.Transaction {
+	return client.Submit(testutil.Invocation{
+		Function:  function,
+		Transient: testutil.Transient("transfer_response", `{"name":"article1"}`),
+		Peer:      org1,
+	})
+}
+
+func TestProposedTransferExpiry(t *testing.T) {
+	n := newTestNetwork(t, escrowFunctions)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	n.proposeToJerry(t, "60")
+
+	article := n.readArticle(t, "article1")
+	if article.Owner != "tom" || article.PendingTransferTo != org2 {
+		t.Errorf("proposed article1 has owner %s pending to %q, expected tom pending to %s", article.Owner, article.PendingTransferTo, org2)
+	}
+	// the proposal stands until it expires
+	expectCode(t, answerTransfer(n.user1, "cancelTransfer").Response, CodeAccessDenied)
+	expectCode(t, answerTransfer(n.user1, "acceptTransfer").Response, CodeAccessDenied)
+
+	n.Advance(time.Minute)
+	expectCode(t, answerTransfer(n.user2, "acceptTransfer").Response, CodeAccessDenied)
+	expectStatus(t, answerTransfer(n.user1, "cancelTransfer").Response, shim.OK)
+	article = n.readArticle(t, "article1")
+	if article.Owner != "tom" || article.PendingTransferTo != "" {
+		t.Errorf("cancelled article1 has owner %s pending to %q", article.Owner, article.PendingTransferTo)
+	}
+	expectCode(t, answerTransfer(n.user2, "acceptTransfer").Response, CodeArticleNotFound)
+}
+
+func TestProposedTransferAcceptedOnce(t *testing.T) {
+	n := newTestNetwork(t, escrowFunctions)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	n.proposeToJerry(t, "3600")
+
+	expectStatus(t, answerTransfer(n.user2, "acceptTransfer").Response, shim.OK)
+	article := n.readArticle(t, "article1")
+	if article.Owner != "jerry" || article.OwnerOrg != org2 || article.PendingTransferTo != "" {
+		t.Errorf("accepted article1 has owner %s of %s pending to %q, expected jerry of %s", article.Owner, article.OwnerOrg, article.PendingTransferTo, org2)
+	}
+
+	// the transfer is no longer pending
+	for _, function := range []string{"acceptTransfer", "rejectTransfer"} {
+		tx := answerTransfer(n.user2, function)
+		expectCode(t, tx.Response, CodeArticleNotFound)
+		if tx.Writes != 0 {
+			t.Errorf("a second %s wrote %d keys", function, tx.Writes)
+		}
+	}
+}
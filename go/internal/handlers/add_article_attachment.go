@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// AddArticleAttachment - anchor an off-chain document of a article, e.g. an invoice or a
+// photo, by its SHA-256 hash in collectionArticlePrivateDetails. Only the owner org can add
+// attachments and an attachment ID cannot be reused for another document.
+// ===========================================================================================
+func AddArticleAttachment(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start add article attachment")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	attachmentJsonBytes, ok := transMap["article_attachment"]
+	if !ok {
+		return invalidInput("", "article_attachment must be a key in the transient map")
+	}
+
+	if len(attachmentJsonBytes) == 0 {
+		return invalidInput("", "article_attachment value in the transient map must be a non-empty JSON string")
+	}
+
+	var attachmentInput model.ArticleAttachmentTransientInput
+	err = model.DecodeTransientInput("article_attachment", attachmentJsonBytes, &attachmentInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = attachmentInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(attachmentInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, attachmentInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the owner org may attach documents ====
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	attachmentKey, err := stub.CreateCompositeKey(model.AttachmentIndex, []string{attachmentInput.Name, attachmentInput.AttachmentID})
+	if err != nil {
+		return errorResponse(err)
+	}
+	attachmentAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, attachmentKey)
+	if err != nil {
+		return internalError(attachmentInput.Name, "Failed to get attachment: "+err.Error())
+	} else if attachmentAsBytes != nil {
+		return alreadyExists(attachmentInput.Name, "Attachment "+attachmentInput.AttachmentID+" already exists for article: "+attachmentInput.Name)
+	}
+
+	addedAt, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	attachmentJSONasBytes, err := json.Marshal(&model.Attachment{
+		ObjectType:   "attachment",
+		Name:         attachmentInput.Name,
+		AttachmentID: attachmentInput.AttachmentID,
+		SHA256Hex:    strings.ToLower(attachmentInput.SHA256Hex),
+		Description:  attachmentInput.Description,
+		AddedAt:      addedAt,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, attachmentKey, attachmentJSONasBytes)
+	if err != nil {
+		return internalError(attachmentInput.Name, "Failed to put attachment: "+err.Error())
+	}
+
+	txLogger(stub).Infof("end addArticleAttachment (success)")
+	return shim.Success(nil)
+}
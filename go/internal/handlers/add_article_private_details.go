@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// AddArticlePrivateDetails - add the price of a article that was created without one, e.g.
+// by an org that leaves the pricing to another member of collectionArticlePrivateDetails.
+// Adding them to an article imported with DetailsPending clears the marker, which changes
+// the article and so needs the endorsement of its owner org.
+// ===========================================================================================
+func AddArticlePrivateDetails(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start add article private details")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articlePriceJsonBytes, ok := transMap["article_price"]
+	if !ok {
+		return invalidInput("", "article_price must be a key in the transient map")
+	}
+
+	if len(articlePriceJsonBytes) == 0 {
+		return invalidInput("", "article_price value in the transient map must be a non-empty JSON string")
+	}
+
+	var articlePriceInput model.ArticlePriceTransientInput
+	err = model.DecodeTransientInput("article_price", articlePriceJsonBytes, &articlePriceInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articlePriceInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articlePriceInput.Name, err.Error())
+	}
+
+	// ==== The article must exist and must not have private details yet ====
+	articleHash, err := stub.GetPrivateDataHash(cfg.CollectionArticles, articlePriceInput.Name)
+	if err != nil {
+		return internalError(articlePriceInput.Name, "Failed to get article hash: "+err.Error())
+	} else if articleHash == nil {
+		return notFound(articlePriceInput.Name, "Article does not exist: "+articlePriceInput.Name)
+	}
+
+	detailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, articlePriceInput.Name)
+	if err != nil {
+		return internalError(articlePriceInput.Name, "Failed to get private details hash: "+err.Error())
+	} else if detailsHash != nil {
+		return alreadyExists(articlePriceInput.Name, "Article private details already exist: "+articlePriceInput.Name)
+	}
+
+	// ==== The client adding the price becomes its creator, the only one allowed to read it ====
+	creatorID, err := cid.GetID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client ID: "+err.Error())
+	}
+	err = putArticlePrivateDetails(stub, cfg, articlePriceInput.Name, creatorID, articlePriceInput.Currency, 0, articlePriceInput.Price)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== An article imported without its private details no longer waits for them ====
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articlePriceInput.Name)
+	if err != nil {
+		return internalError(articlePriceInput.Name, "Failed to get article: "+err.Error())
+	}
+	var article model.Article
+	err = json.Unmarshal(articleAsBytes, &article)
+	if err != nil {
+		return internalError(articlePriceInput.Name, "Failed to decode JSON of: "+string(articleAsBytes))
+	}
+	if article.DetailsPending {
+		article.DetailsPending = false
+		err = putArticle(stub, cfg, &article)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	err = putAuditRecord(stub, cfg, articlePriceInput.Name, "addArticlePrivateDetails")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end addArticlePrivateDetails (success)")
+	return shim.Success(nil)
+}
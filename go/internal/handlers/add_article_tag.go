@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// AddArticleTag - tag a article and index it under tag~name. Only the owner org can tag,
+// up to model.MaxTags tags per article.
+// ===========================================================================================
+func AddArticleTag(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start add article tag")
+
+	article, tag, err := getArticleTagInput(stub, cfg, args)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	for _, existingTag := range article.Tags {
+		if existingTag == tag {
+			return alreadyExists(article.Name, "Article "+article.Name+" is already tagged "+tag)
+		}
+	}
+	if len(article.Tags) >= model.MaxTags {
+		return invalidInput(article.Name, fmt.Sprintf("article %s already has the maximum of %d tags", article.Name, model.MaxTags))
+	}
+
+	article.Tags = append(article.Tags, tag)
+	article.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = putArticle(stub, cfg, article) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	tagNameIndexKey, err := stub.CreateCompositeKey(model.TagNameIndex, []string{tag, article.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, tagNameIndexKey, []byte{0x00})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, article.Name, "addArticleTag")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end addArticleTag (success)")
+	return shim.Success(nil)
+}
+
+// getArticleTagInput reads the "article_tag" transient input of addArticleTag and
+// removeArticleTag and returns the article, which the client org must own, and the tag
+func getArticleTagInput(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) (*model.Article, string, error) {
+	if len(args) != 0 {
+		return nil, "", newError(CodeInvalidInput, "", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return nil, "", err
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return nil, "", newError(CodeInternal, "", "Error getting transient: %v", err)
+	}
+
+	articleTagJsonBytes, ok := transMap["article_tag"]
+	if !ok {
+		return nil, "", newError(CodeInvalidInput, "", "article_tag must be a key in the transient map")
+	}
+
+	if len(articleTagJsonBytes) == 0 {
+		return nil, "", newError(CodeInvalidInput, "", "article_tag value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleTagInput model.ArticleTagTransientInput
+	err = model.DecodeTransientInput("article_tag", articleTagJsonBytes, &articleTagInput)
+	if err != nil {
+		return nil, "", newError(CodeInvalidInput, "", "%v", err)
+	}
+
+	err = articleTagInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return nil, "", newError(CodeInvalidInput, articleTagInput.Name, "%v", err)
+	}
+
+	article, err := getArticle(stub, cfg, articleTagInput.Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// ==== Only the owner org may change the tags ====
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return article, articleTagInput.Tag, nil
+}
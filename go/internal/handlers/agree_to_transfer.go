@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================
+// AgreeToTransfer - the buyer org records the article properties and the price it agrees to
+// in its implicit collection. transferArticle later compares the hashes of these records
+// with the hashes of the actual article and private details before changing the owner.
+// ===========================================================
+func AgreeToTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	txLogger(stub).Debugf("start agree to transfer")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	// the agreement is written to the implicit collection of the client org, which
+	// is only possible on a peer of that same org
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleAgreementJsonBytes, ok := transMap["article_agreement"]
+	if !ok {
+		return invalidInput("", "article_agreement must be a key in the transient map")
+	}
+
+	if len(articleAgreementJsonBytes) == 0 {
+		return invalidInput("", "article_agreement value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleAgreementInput model.ArticleAgreementTransientInput
+	err = model.DecodeTransientInput("article_agreement", articleAgreementJsonBytes, &articleAgreementInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleAgreementInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleAgreementInput.Name, err.Error())
+	}
+
+	// the buyer agrees to the article exactly as it is currently stored
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleAgreementInput.Name)
+	if err != nil {
+		return internalError(articleAgreementInput.Name, "Failed to get article:"+err.Error())
+	} else if articleAsBytes == nil {
+		return notFound(articleAgreementInput.Name, "Article does not exist: "+articleAgreementInput.Name)
+	}
+
+	// the agreed price is marshaled canonically, exactly the way initArticle stores the private details
+	agreedPrivateDetails := &model.ArticlePrivateDetails{
+		ObjectType:    "articlePrivateDetails",
+		Name:          articleAgreementInput.Name,
+		Price:         articleAgreementInput.Price,
+		Currency:      articleAgreementInput.Currency,
+		SchemaVersion: model.CurrentSchemaVersion,
+	}
+	agreedPrivateDetailsBytes, err := model.MarshalCanonical(agreedPrivateDetails)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client MSP ID: "+err.Error())
+	}
+	collection := implicitCollectionName(clientOrgID)
+
+	propertiesKey, priceKey, err := transferAgreementKeys(stub, articleAgreementInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(collection, propertiesKey, articleAsBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(collection, priceKey, agreedPrivateDetailsBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end agreeToTransfer (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// getAllowedColorsState reads the color allow-list from public state, an empty list when
+// none was set
+func getAllowedColorsState(stub shim.ChaincodeStubInterface) ([]string, error) {
+	colorsAsBytes, err := stub.GetState(model.AllowedColorsKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get allowed colors: %v", err)
+	}
+	colors := []string{}
+	if colorsAsBytes == nil {
+		return colors, nil
+	}
+	err = json.Unmarshal(colorsAsBytes, &colors)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode JSON of allowed colors: %v", err)
+	}
+	return colors, nil
+}
+
+// putAllowedColorsState normalizes and validates a color allow-list and writes it to public
+// state, where every org enforces the same one
+func putAllowedColorsState(stub shim.ChaincodeStubInterface, cfg *model.Config, colors []string) error {
+	normalized := make([]string, len(colors))
+	for i, color := range colors {
+		normalized[i] = model.NormalizeColor(color)
+	}
+	err := model.ValidateAllowedColors(normalized, cfg.MaxNameLength)
+	if err != nil {
+		return newError(CodeInvalidInput, "", "%v", err)
+	}
+	colorsJSONasBytes, err := json.Marshal(normalized)
+	if err != nil {
+		return err
+	}
+	return stub.PutState(model.AllowedColorsKey, colorsJSONasBytes)
+}
+
+// InitAllowedColors replaces the color allow-list with the given JSON array. Init calls it
+// at instantiation and upgrade.
+func InitAllowedColors(stub shim.ChaincodeStubInterface, cfg *model.Config, colorsJSON string) error {
+	colors := []string{}
+	err := json.Unmarshal([]byte(colorsJSON), &colors)
+	if err != nil {
+		return fmt.Errorf("allowed colors must be a JSON array of strings: %v", err)
+	}
+	return putAllowedColorsState(stub, cfg, colors)
+}
+
+// verifyColorAllowed fails with INVALID_INPUT when an allow-list is set and the normalized
+// color is not on it. Only new writes are checked, existing articles keep their color.
+func verifyColorAllowed(stub shim.ChaincodeStubInterface, name string, color string) error {
+	colors, err := getAllowedColorsState(stub)
+	if err != nil {
+		return err
+	}
+	if len(colors) == 0 {
+		return nil
+	}
+	for _, allowedColor := range colors {
+		if allowedColor == color {
+			return nil
+		}
+	}
+	return newError(CodeInvalidInput, name, "color %s is not one of the allowed colors %s", color, strings.Join(colors, ", "))
+}
+
+// ===========================================================================================
+// SetAllowedColors replaces the colors new articles may have with a JSON array. Colors are
+// compared and stored in lowercase, an empty array allows any color. Only admins may call it.
+// ===========================================================================================
+func SetAllowedColors(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting a JSON array of colors")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	colors := []string{}
+	err = json.Unmarshal([]byte(args[0]), &colors)
+	if err != nil {
+		return invalidInput("", "Colors must be a JSON array of strings")
+	}
+	err = putAllowedColorsState(stub, cfg, colors)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end setAllowedColors (success)")
+	return shim.Success(nil)
+}
+
+// ===========================================================================================
+// GetAllowedColors returns the colors new articles may have, an empty array when any color
+// is allowed
+// ===========================================================================================
+func GetAllowedColors(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting none")
+	}
+
+	colors, err := getAllowedColorsState(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	colorsJSONasBytes, err := json.Marshal(colors)
+	if err != nil {
+		return errorResponse(err)
+	}
+	return shim.Success(colorsJSONasBytes)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ApproveTransfer - record the approval of the client org for the transfer initiated for a
+// article. Only the orgs listed when the transfer was initiated can approve it, each of
+// them once.
+// ===========================================================================================
+func ApproveTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start approve transfer")
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	name, err := getTransferApprovalInput(stub, cfg, args)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transferRequest, err := getTransferRequest(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if transferRequest == nil {
+		return notFound(name, "No transfer is initiated for article: "+name)
+	}
+
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(name, "Failed to get client MSP ID: "+err.Error())
+	}
+	listed := false
+	for _, mspID := range transferRequest.Approvers {
+		if mspID == clientOrgID {
+			listed = true
+			break
+		}
+	}
+	if !listed {
+		return accessDenied(name, "org "+clientOrgID+" is not an approver of the transfer of article "+name)
+	}
+
+	approvalKey, err := stub.CreateCompositeKey(model.ApprovalIndex, []string{name, clientOrgID})
+	if err != nil {
+		return errorResponse(err)
+	}
+	approvalAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, approvalKey)
+	if err != nil {
+		return internalError(name, "Failed to get approval: "+err.Error())
+	} else if approvalAsBytes != nil {
+		return alreadyExists(name, "Org "+clientOrgID+" already approved the transfer of article: "+name)
+	}
+
+	timestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	approvalJSONasBytes, err := json.Marshal(&model.Approval{
+		ObjectType: "approval",
+		Name:       name,
+		MSPID:      clientOrgID,
+		TxID:       stub.GetTxID(),
+		Timestamp:  timestamp,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, approvalKey, approvalJSONasBytes)
+	if err != nil {
+		return internalError(name, "Failed to put approval: "+err.Error())
+	}
+
+	txLogger(stub).Infof("end approveTransfer (success)")
+	return shim.Success(nil)
+}
+
+// getTransferApprovalInput reads the "transfer_approval" transient input of approveTransfer,
+// executeTransfer and cancelInitiatedTransfer and returns the name of the article
+func getTransferApprovalInput(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", newError(CodeInvalidInput, "", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return "", newError(CodeInternal, "", "Error getting transient: %v", err)
+	}
+
+	approvalJsonBytes, ok := transMap["transfer_approval"]
+	if !ok {
+		return "", newError(CodeInvalidInput, "", "transfer_approval must be a key in the transient map")
+	}
+
+	if len(approvalJsonBytes) == 0 {
+		return "", newError(CodeInvalidInput, "", "transfer_approval value in the transient map must be a non-empty JSON string")
+	}
+
+	var approvalInput model.TransferApprovalTransientInput
+	err = model.DecodeTransientInput("transfer_approval", approvalJsonBytes, &approvalInput)
+	if err != nil {
+		return "", newError(CodeInvalidInput, "", "%v", err)
+	}
+
+	err = approvalInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return "", newError(CodeInvalidInput, approvalInput.Name, "%v", err)
+	}
+	return approvalInput.Name, nil
+}
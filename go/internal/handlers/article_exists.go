@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// ArticleExists - check whether a article exists in chaincode state
+// The private data hash is available to every org on the channel, so
+// this also works for orgs that are not a member of collectionArticles.
+// ===============================================
+func ArticleExists(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	var name string
+	var err error
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name = args[0]
+	err = model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	valAsbytes, err := stub.GetPrivateDataHash(cfg.CollectionArticles, name)
+	if err != nil {
+		return internalError(name, "Failed to get article private data hash for "+name+": "+err.Error())
+	}
+
+	if valAsbytes == nil {
+		return shim.Success([]byte("{\"exists\":false}"))
+	}
+	return shim.Success([]byte("{\"exists\":true}"))
+}
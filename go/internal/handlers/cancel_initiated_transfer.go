@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// CancelInitiatedTransfer - withdraw the transfer initiated for a article together with the
+// approvals given so far
+// ===========================================================================================
+func CancelInitiatedTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start cancel initiated transfer")
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	name, err := getTransferApprovalInput(stub, cfg, args)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	article, err := getArticle(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may cancel the transfer ====
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transferRequest, err := getTransferRequest(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if transferRequest == nil {
+		return notFound(name, "No transfer is initiated for article: "+name)
+	}
+
+	err = removeTransferRequest(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, name, "cancelInitiatedTransfer")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end cancelInitiatedTransfer (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// CancelTransfer - withdraw a proposed transfer of a article that the receiving org did not
+// accept before it expired. Until then the proposal stands and only the receiving org can
+// end it.
+// ===========================================================================================
+func CancelTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start cancel transfer")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	cancelJsonBytes, ok := transMap["transfer_response"]
+	if !ok {
+		return invalidInput("", "transfer_response must be a key in the transient map")
+	}
+
+	if len(cancelJsonBytes) == 0 {
+		return invalidInput("", "transfer_response value in the transient map must be a non-empty JSON string")
+	}
+
+	var cancelInput model.TransferResponseTransientInput
+	err = model.DecodeTransientInput("transfer_response", cancelJsonBytes, &cancelInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = cancelInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(cancelInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, cancelInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may cancel the proposal ====
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	pendingTransfer, err := getPendingTransfer(stub, cfg, cancelInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	expired, err := pendingTransferExpired(stub, pendingTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if !expired {
+		return accessDenied(article.Name, "the transfer of article "+article.Name+" can be accepted by org "+pendingTransfer.TargetOrg+" until "+pendingTransfer.Expiry)
+	}
+
+	err = clearPendingTransfer(stub, cfg, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	article.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putArticle(stub, cfg, article) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, article.Name, "cancelTransfer")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end cancelTransfer (success)")
+	return shim.Success(nil)
+}
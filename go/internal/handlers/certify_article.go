@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// CertifyArticle - record the result of checking a article against a standard. Only clients
+// with the certifier attribute can certify, and they cannot change the article itself.
+// Each certifier org keeps one certification per article, certifying again replaces it.
+// Certifications stay with the article when it is transferred.
+// ===========================================================================================
+func CertifyArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start certify article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = verifyClientIsCertifier(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	certificationJsonBytes, ok := transMap["certification"]
+	if !ok {
+		return invalidInput("", "certification must be a key in the transient map")
+	}
+
+	if len(certificationJsonBytes) == 0 {
+		return invalidInput("", "certification value in the transient map must be a non-empty JSON string")
+	}
+
+	var certificationInput model.CertificationTransientInput
+	err = model.DecodeTransientInput("certification", certificationJsonBytes, &certificationInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = certificationInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(certificationInput.Name, err.Error())
+	}
+
+	_, err = getArticle(stub, cfg, certificationInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	certifierMSP, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(certificationInput.Name, "Failed to get client MSP ID: "+err.Error())
+	}
+	timestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	certificationJSONasBytes, err := json.Marshal(&model.Certification{
+		ObjectType:   "certification",
+		ArticleName:  certificationInput.Name,
+		CertifierMSP: certifierMSP,
+		Standard:     certificationInput.Standard,
+		Result:       certificationInput.Result,
+		Timestamp:    timestamp,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	certificationKey, err := stub.CreateCompositeKey(model.CertificationIndex, []string{certificationInput.Name, certifierMSP})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, certificationKey, certificationJSONasBytes)
+	if err != nil {
+		return internalError(certificationInput.Name, "Failed to put certification: "+err.Error())
+	}
+
+	err = setArticleEvent(stub, "ArticleCertified", model.ArticleEventEntry{Name: certificationInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end certifyArticle (success)")
+	return shim.Success(nil)
+}
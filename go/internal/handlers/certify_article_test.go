@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// certifiers returns the MSP IDs of the orgs that certified the article, in the order of
+// getCertifications
+func (n *testNetwork) certifiers(t *testing.T, name string) []string {
+	t.Helper()
+	response := n.user2.Query("getCertifications", name)
+	expectStatus(t, response, shim.OK)
+	var certifications []model.Certification
+	err := json.Unmarshal(response.Payload, &certifications)
+	if err != nil {
+		t.Fatalf("failed to decode the certifications %s: %v", response.Payload, err)
+	}
+	mspIDs := []string{}
+	for _, certification := range certifications {
+		if certification.ArticleName != name {
+			t.Errorf("certifications of %s include one of %s", name, certification.ArticleName)
+		}
+		mspIDs = append(mspIDs, certification.CertifierMSP)
+	}
+	return mspIDs
+}
+
+func TestCertificationsOfSeveralCertifiers(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"certifyArticle":      CertifyArticle,
+		"revokeCertification": RevokeCertification,
+		"getCertifications":   GetCertifications,
+		"agreeToTransfer":     AgreeToTransfer,
+		"transferArticle":     TransferArticle,
+		"delete":              Delete,
+	})
+	lab1 := n.Client(org1, "lab1", map[string]string{model.CertifierAttribute: "true"})
+	lab2 := n.Client(org2, "lab2", map[string]string{model.CertifierAttribute: "true"})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+	n.createArticle(t, articleJSON("article2", "red", 35, 0))
+
+	certification := testutil.Transient("certification", `{"name":"article1","standard":"ISO-9001","result":"pass"}`)
+	expectCode(t, n.user1.InvokeTransient("certifyArticle", certification), CodeAccessDenied)
+	expectStatus(t, lab2.InvokeTransient("certifyArticle", certification), shim.OK)
+	expectStatus(t, lab1.InvokeTransient("certifyArticle", certification), shim.OK)
+	if certifiers := n.certifiers(t, "article1"); len(certifiers) != 2 || certifiers[0] != org1 || certifiers[1] != org2 {
+		t.Errorf("article1 is certified by %v, expected %s and %s", certifiers, org1, org2)
+	}
+	if certifiers := n.certifiers(t, "article2"); len(certifiers) != 0 {
+		t.Errorf("article2 is certified by %v", certifiers)
+	}
+
+	// a transfer keeps the certifications
+	expectStatus(t, n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`)), shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+	if certifiers := n.certifiers(t, "article1"); len(certifiers) != 2 {
+		t.Errorf("transferred article1 is certified by %v", certifiers)
+	}
+
+	// a certifier revokes only its own certification
+	revocation := testutil.Transient("certification_revocation", `{"name":"article1"}`)
+	expectCode(t, n.user2.InvokeTransient("revokeCertification", revocation), CodeAccessDenied)
+	expectStatus(t, lab2.InvokeTransient("revokeCertification", revocation), shim.OK)
+	if certifiers := n.certifiers(t, "article1"); len(certifiers) != 1 || certifiers[0] != org1 {
+		t.Errorf("article1 is certified by %v after the revocation of %s, expected %s", certifiers, org2, org1)
+	}
+	expectCode(t, lab2.InvokeTransient("revokeCertification", revocation), CodeArticleNotFound)
+
+	// deleting the article removes its certifications
+	expectStatus(t, lab2.InvokeTransient("certifyArticle", certification), shim.OK)
+	expectStatus(t, n.admin1.InvokeTransient("delete", testutil.Transient("article_delete", `{"name":"article1"}`)), shim.OK)
+	if certifiers := n.certifiers(t, "article1"); len(certifiers) != 0 {
+		t.Errorf("deleted article1 is certified by %v", certifiers)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// CheckCollectionConsistency cross-references the articles and the private details and
+// lists the articles without details and the details without an article. Peers outside
+// collectionArticlePrivateDetails get a partial report based on the private data hashes.
+// Articles created without a price legitimately have no details.
+// ===========================================================================================
+func CheckCollectionConsistency(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting no arguments")
+	}
+
+	articleNames, err := collectionNames(stub, cfg.CollectionArticles)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	report := model.ConsistencyReport{
+		ArticlesScanned:        len(articleNames),
+		ArticlesWithoutDetails: []string{},
+		DetailsWithoutArticle:  []string{},
+	}
+
+	detailsNames, err := collectionNames(stub, cfg.CollectionArticlePrivateDetails)
+	if err != nil {
+		// the peer cannot read the details, their hashes still tell which exist
+		txLogger(stub).Infof("falling back to private data hashes: %v", err)
+		report.Partial = true
+		for _, name := range articleNames {
+			detailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, name)
+			if err != nil {
+				return internalError(name, "Failed to get private details hash: "+err.Error())
+			} else if detailsHash == nil {
+				report.ArticlesWithoutDetails = append(report.ArticlesWithoutDetails, name)
+			}
+		}
+	} else {
+		report.DetailsScanned = len(detailsNames)
+		report.ArticlesWithoutDetails = missingNames(articleNames, detailsNames)
+		report.DetailsWithoutArticle = missingNames(detailsNames, articleNames)
+	}
+	report.ArticlesWithoutDetailsCount = len(report.ArticlesWithoutDetails)
+	report.DetailsWithoutArticleCount = len(report.DetailsWithoutArticle)
+
+	reportJSONasBytes, err := json.Marshal(&report)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(reportJSONasBytes)
+}
+
+// collectionNames returns the keys, in key order, of a collection that are not composite keys
+func collectionNames(stub shim.ChaincodeStubInterface, collection string) ([]string, error) {
+	resultsIterator, err := stub.GetPrivateDataByRange(collection, "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	names := []string{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+		names = append(names, queryResponse.Key)
+	}
+	return names, nil
+}
+
+// missingNames returns the names that are not in others
+func missingNames(names []string, others []string) []string {
+	otherNames := make(map[string]bool, len(others))
+	for _, name := range others {
+		otherNames[name] = true
+	}
+
+	missing := []string{}
+	for _, name := range names {
+		if !otherNames[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,176 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// CloneArticle - create a new article from an existing one used as template. The
+// properties of the source are taken unless overridden, its price only when the caller
+// may read it, and the result is validated like the input of initArticle. The new
+// article belongs to the submitting org and starts without the audit trail, ownership
+// history, locks or listings of its source.
+// ===========================================================================================
+func CloneArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start clone article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleCloneJsonBytes, ok := transMap["article_clone"]
+	if !ok {
+		return invalidInput("", "article_clone must be a key in the transient map")
+	}
+
+	if len(articleCloneJsonBytes) == 0 {
+		return invalidInput("", "article_clone value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleCloneInput model.ArticleCloneTransientInput
+	err = model.DecodeTransientInput("article_clone", articleCloneJsonBytes, &articleCloneInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleCloneInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleCloneInput.NewName, err.Error())
+	}
+
+	source, err := getArticle(stub, cfg, articleCloneInput.SourceName)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Start from the source, its price only if the caller may read it ====
+	articleInput := model.ArticleTransientInput{
+		Name:     articleCloneInput.NewName,
+		Color:    source.Color,
+		Size:     source.Size,
+		Owner:    source.Owner,
+		Salt:     articleCloneInput.Salt,
+		Quantity: source.Units(),
+		Tags:     source.Tags,
+		Category: source.Category,
+	}
+	privateDetailsAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, source.Name)
+	if err == nil && privateDetailsAsBytes != nil && verifyPriceReader(stub, cfg, privateDetailsAsBytes) == nil {
+		var privateDetails model.ArticlePrivateDetails
+		err = json.Unmarshal(privateDetailsAsBytes, &privateDetails)
+		if err != nil {
+			return internalError(source.Name, "Failed to decode JSON of: "+string(privateDetailsAsBytes))
+		}
+		articleInput.Price = privateDetails.Price
+		articleInput.Currency = privateDetails.Currency
+	} else {
+		txLogger(stub).Debugf("private details of %s are not readable, the clone gets no price from them", source.Name)
+	}
+
+	// ==== Apply the overrides and check the result as initArticle does ====
+	overrides := articleCloneInput.Overrides
+	if overrides.Color != nil {
+		articleInput.Color = *overrides.Color
+	}
+	if overrides.Size != nil {
+		articleInput.Size = *overrides.Size
+	}
+	if overrides.Owner != nil {
+		articleInput.Owner = *overrides.Owner
+	}
+	if overrides.Price != nil {
+		articleInput.Price = *overrides.Price
+	}
+	if overrides.Currency != nil {
+		articleInput.Currency = *overrides.Currency
+	}
+	err = articleInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleInput.Name, err.Error())
+	}
+
+	articleInput.Color = model.NormalizeColor(articleInput.Color)
+	err = verifyColorAllowed(stub, articleInput.Name, articleInput.Color)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyOwnerRegistered(stub, cfg, articleInput.Owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client MSP ID: "+err.Error())
+	}
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The new name must be free ====
+	newArticleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleInput.Name)
+	if err != nil {
+		return internalError(articleInput.Name, "Failed to get article: "+err.Error())
+	} else if newArticleAsBytes != nil {
+		return alreadyExists(articleInput.Name, "This article already exists: "+articleInput.Name)
+	}
+
+	article := &model.Article{
+		ObjectType: model.DefaultDocType,
+		Name:       articleInput.Name,
+		Color:      articleInput.Color,
+		Size:       articleInput.Size,
+		Owner:      articleInput.Owner,
+		OwnerOrg:   clientOrgID,
+		Salt:       articleInput.Salt,
+		Quantity:   articleInput.Quantity,
+		Tags:       articleInput.Tags,
+		Category:   articleInput.Category,
+		CreatedAt:  txTimestamp,
+		UpdatedAt:  txTimestamp,
+
+		WeightGrams: source.WeightGrams,
+		Dimensions:  source.Dimensions,
+	}
+	err = putNewArticle(stub, cfg, article, articleInput.Price, articleInput.Currency)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The clone starts its own audit trail ====
+	err = putAuditRecord(stub, cfg, article.Name, "cloneArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleCreated", model.ArticleEventEntry{Name: article.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end cloneArticle (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,261 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// CloseAuction - end the auction of a article after its close time and transfer the article
+// to the highest revealed bid of at least the minimum price, at the price of the bid. While
+// the reveal deadline has not passed every org that placed a bid must have revealed it;
+// after the deadline unrevealed bids are ignored. Equal bids go to the one placed first,
+// then to the lower org ID. When no bid is valid the auction ends without a transfer.
+// The ended auction is returned.
+// ===========================================================================================
+func CloseAuction(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start close auction")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	closeJsonBytes, ok := transMap["auction_close"]
+	if !ok {
+		return invalidInput("", "auction_close must be a key in the transient map")
+	}
+
+	if len(closeJsonBytes) == 0 {
+		return invalidInput("", "auction_close value in the transient map must be a non-empty JSON string")
+	}
+
+	var closeInput model.AuctionNameTransientInput
+	err = model.DecodeTransientInput("auction_close", closeJsonBytes, &closeInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = closeInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(closeInput.Name, err.Error())
+	}
+
+	auction, err := getOpenAuction(stub, cfg, closeInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the org that opened the auction may close it ====
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(closeInput.Name, "Failed to get client MSP ID: "+err.Error())
+	}
+	if clientOrgID != auction.SellerOrg {
+		return accessDenied(closeInput.Name, "only org "+auction.SellerOrg+" may close the auction of article "+closeInput.Name)
+	}
+
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	closeTime, revealDeadline, err := auctionTimes(auction)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if now.Before(closeTime) {
+		return accessDenied(closeInput.Name, "the auction of article "+closeInput.Name+" is open until "+auction.CloseTime)
+	}
+
+	revealedBids, err := getRevealedBids(stub, cfg, closeInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Before the reveal deadline every bidder org must have revealed its bid ====
+	if !now.After(revealDeadline) {
+		unrevealed, err := getUnrevealedBidderOrgs(stub, cfg, closeInput.Name, revealedBids)
+		if err != nil {
+			return errorResponse(err)
+		}
+		if len(unrevealed) != 0 {
+			return accessDenied(closeInput.Name, fmt.Sprintf("orgs %s must reveal their bids on article %s with revealBid before %s", strings.Join(unrevealed, ", "), closeInput.Name, auction.RevealDeadline))
+		}
+	}
+
+	// ==== Bids for owners that left the registry are not valid any more ====
+	validBids := []model.RevealedBid{}
+	for _, bid := range revealedBids {
+		err = verifyOwnerRegistered(stub, cfg, bid.Owner)
+		if ccErr, ok := err.(*chaincodeError); ok && ccErr.Code == CodeUnknownOwner {
+			txLogger(stub).Debugf("ignoring bid of org %s: %v", bid.BidderOrg, err)
+			continue
+		} else if err != nil {
+			return errorResponse(err)
+		}
+		validBids = append(validBids, bid)
+	}
+
+	winningBid := highestBid(validBids, auction.MinPrice)
+	if winningBid != nil {
+		article, err := getArticle(stub, cfg, closeInput.Name)
+		if err != nil {
+			return errorResponse(err)
+		}
+		oldOwner := article.Owner
+
+		err = changeArticleOwner(stub, cfg, article, winningBid.Owner, winningBid.BidderOrg, "closeAuction")
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		// ==== The winning bid is the new price, details of older articles are created ====
+		privateDetails, err := getArticlePrivateDetails(stub, cfg, closeInput.Name)
+		if ccErr, ok := err.(*chaincodeError); ok && ccErr.Code == CodeArticleNotFound {
+			creatorID, err := cid.GetID(stub)
+			if err != nil {
+				return internalError(closeInput.Name, "Failed to get client ID: "+err.Error())
+			}
+			privateDetails = &model.ArticlePrivateDetails{Name: closeInput.Name, CreatorID: creatorID}
+		} else if err != nil {
+			return errorResponse(err)
+		}
+		err = putArticlePrivateDetails(stub, cfg, closeInput.Name, privateDetails.CreatorID, privateDetails.Currency, privateDetails.Price, winningBid.Amount)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = putPriceRecord(stub, cfg, closeInput.Name, privateDetails.Price, winningBid.Amount)
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		auction.Winner = winningBid.Owner
+		auction.WinnerOrg = winningBid.BidderOrg
+
+		err = setArticleEvent(stub, "ArticleTransferred", model.ArticleEventEntry{
+			Name:     article.Name,
+			OldOwner: oldOwner,
+			NewOwner: article.Owner,
+		})
+		if err != nil {
+			return errorResponse(err)
+		}
+	} else {
+		txLogger(stub).Debugf("no valid bid on article %s", closeInput.Name)
+	}
+
+	auction.Status = model.AuctionEnded
+	err = putAuction(stub, cfg, auction)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	auctionJSONasBytes, err := json.Marshal(auction)
+	if err != nil {
+		return errorResponse(err)
+	}
+	auctionJSONasBytes, err = formatPrices(cfg, auctionJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end closeAuction (success)")
+	return shim.Success(auctionJSONasBytes)
+}
+
+// getRevealedBids reads the bids revealed on the auction of an article
+func getRevealedBids(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) ([]model.RevealedBid, error) {
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.RevealedBidIndex, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	bids := []model.RevealedBid{}
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		bid := model.RevealedBid{}
+		err = json.Unmarshal(responseRange.Value, &bid)
+		if err != nil {
+			return nil, newError(CodeInternal, name, "Failed to decode JSON of: %s", responseRange.Value)
+		}
+		bids = append(bids, bid)
+	}
+	return bids, nil
+}
+
+// getUnrevealedBidderOrgs returns the orgs that committed to a bid on the auction of an
+// article without revealing it
+func getUnrevealedBidderOrgs(stub shim.ChaincodeStubInterface, cfg *model.Config, name string, revealedBids []model.RevealedBid) ([]string, error) {
+	revealed := map[string]bool{}
+	for _, bid := range revealedBids {
+		revealed[bid.BidderOrg] = true
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.BidCommitmentIndex, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	unrevealed := []string{}
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		commitment := model.BidCommitment{}
+		err = json.Unmarshal(responseRange.Value, &commitment)
+		if err != nil {
+			return nil, newError(CodeInternal, name, "Failed to decode JSON of: %s", responseRange.Value)
+		}
+		if !revealed[commitment.BidderOrg] {
+			unrevealed = append(unrevealed, commitment.BidderOrg)
+		}
+	}
+	return unrevealed, nil
+}
+
+// highestBid returns the highest bid of at least minPrice, or nil when there is none.
+// Equal bids go to the one placed first, then to the lower org ID, so every endorsing
+// peer picks the same winner.
+func highestBid(bids []model.RevealedBid, minPrice model.Price) *model.RevealedBid {
+	var best *model.RevealedBid
+	for i := range bids {
+		bid := &bids[i]
+		if bid.Amount < minPrice {
+			continue
+		}
+		if best == nil || bid.Amount > best.Amount ||
+			(bid.Amount == best.Amount && (bid.PlacedAt < best.PlacedAt ||
+				(bid.PlacedAt == best.PlacedAt && bid.BidderOrg < best.BidderOrg))) {
+			best = bid
+		}
+	}
+	return best
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+var auctionFunctions = map[string]HandlerFunc{
+	"openAuction":  OpenAuction,
+	"placeBid":     PlaceBid,
+	"revealBid":    RevealBid,
+	"closeAuction": CloseAuction,
+}
+
+// openAuction opens the auction of article1 with a minimum price of 100, closing in an hour
+func (n *testNetwork) openAuction(t *testing.T) {
+	t.Helper()
+	closeTime := n.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	response := n.user1.InvokeTransient("openAuction", testutil.Transient("auction", `{"name":"article1","minPrice":100,"closeTime":"`+closeTime+`"}`))
+	expectStatus(t, response, shim.OK)
+}
+
+// closeAuction closes the auction of article1 and returns the ended auction
+func (n *testNetwork) closeAuction(t *testing.T) *model.Auction {
+	t.Helper()
+	response := n.user1.InvokeTransient("closeAuction", testutil.Transient("auction_close", `{"name":"article1"}`))
+	expectStatus(t, response, shim.OK)
+	var auction model.Auction
+	err := json.Unmarshal(response.Payload, &auction)
+	if err != nil {
+		t.Fatalf("failed to decode the auction %s: %v", response.Payload, err)
+	}
+	if auction.Status != model.AuctionEnded {
+		t.Errorf("closed auction has status %s", auction.Status)
+	}
+	return &auction
+}
+
+func TestHighestBidTieBreaking(t *testing.T) {
+	bid := func(org string, amount model.Price, placedAt string) model.RevealedBid {
+		return model.RevealedBid{BidderOrg: org, Amount: amount, Owner: "owner of " + org, PlacedAt: placedAt}
+	}
+	for _, test := range []struct {
+		name   string
+		bids   []model.RevealedBid
+		winner string
+	}{
+		{"highest", []model.RevealedBid{bid("Org2MSP", 150, "2024-01-01T00:00:00Z"), bid("Org3MSP", 200, "2024-01-01T00:00:01Z")}, "Org3MSP"},
+		{"equal bids go to the earlier", []model.RevealedBid{bid("Org2MSP", 150, "2024-01-01T00:00:01Z"), bid("Org3MSP", 150, "2024-01-01T00:00:00Z")}, "Org3MSP"},
+		{"equal bids at the same time go to the lower org ID", []model.RevealedBid{bid("Org3MSP", 150, "2024-01-01T00:00:00Z"), bid("Org2MSP", 150, "2024-01-01T00:00:00Z")}, "Org2MSP"},
+		{"bids below the minimum price are ignored", []model.RevealedBid{bid("Org2MSP", 99, "2024-01-01T00:00:00Z"), bid("Org3MSP", 100, "2024-01-01T00:00:01Z")}, "Org3MSP"},
+		{"no bid of the minimum price", []model.RevealedBid{bid("Org2MSP", 99, "2024-01-01T00:00:00Z")}, ""},
+		{"no bids", nil, ""},
+	} {
+		for _, reversed := range []bool{false, true} {
+			bids := append([]model.RevealedBid{}, test.bids...)
+			if reversed {
+				for i, j := 0, len(bids)-1; i < j; i, j = i+1, j-1 {
+					bids[i], bids[j] = bids[j], bids[i]
+				}
+			}
+			winner := ""
+			if best := highestBid(bids, 100); best != nil {
+				winner = best.BidderOrg
+			}
+			if winner != test.winner {
+				t.Errorf("%s: bids %v won by %q, expected %q", test.name, bids, winner, test.winner)
+			}
+		}
+	}
+}
+
+func TestAuctionWithoutValidBids(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		amount int
+		reveal bool
+	}{
+		{"below the minimum price", 99, true},
+		{"unrevealed", 150, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			n := newTestNetwork(t, auctionFunctions)
+			n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+			n.openAuction(t)
+			bid := fmt.Sprintf(`{"name":"article1","amount":%d,"owner":"jerry","salt":"%s"}`, test.amount, testSalt)
+			expectStatus(t, n.user2.InvokeTransient("placeBid", testutil.Transient("bid", bid)), shim.OK)
+
+			n.Advance(time.Hour)
+			if test.reveal {
+				expectStatus(t, n.user2.InvokeTransient("revealBid", testutil.Transient("bid_reveal", `{"name":"article1"}`)), shim.OK)
+			} else {
+				// the bid has to be revealed until the reveal deadline, then it is ignored
+				expectCode(t, n.user1.InvokeTransient("closeAuction", testutil.Transient("auction_close", `{"name":"article1"}`)), CodeAccessDenied)
+				n.Advance(model.DefaultRevealPeriodSeconds * time.Second)
+			}
+
+			auction := n.closeAuction(t)
+			if auction.Winner != "" || auction.WinnerOrg != "" {
+				t.Errorf("auction was won by %s of %s", auction.Winner, auction.WinnerOrg)
+			}
+			if article := n.readArticle(t, "article1"); article.Owner != "tom" || article.OwnerOrg != org1 {
+				t.Errorf("article1 went to %s of %s", article.Owner, article.OwnerOrg)
+			}
+		})
+	}
+}
+
+func TestAuctionWonByValidBid(t *testing.T) {
+	n := newTestNetwork(t, auctionFunctions)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	n.openAuction(t)
+	expectStatus(t, n.user2.InvokeTransient("placeBid", testutil.Transient("bid", `{"name":"article1","amount":150,"owner":"jerry","salt":"`+testSalt+`"}`)), shim.OK)
+	n.Advance(time.Hour)
+	expectStatus(t, n.user2.InvokeTransient("revealBid", testutil.Transient("bid_reveal", `{"name":"article1"}`)), shim.OK)
+
+	auction := n.closeAuction(t)
+	if auction.Winner != "jerry" || auction.WinnerOrg != org2 {
+		t.Errorf("auction was won by %s of %s, expected jerry of %s", auction.Winner, auction.WinnerOrg, org2)
+	}
+	if article := n.readArticle(t, "article1"); article.Owner != "jerry" || article.OwnerOrg != org2 {
+		t.Errorf("article1 went to %s of %s, expected jerry", article.Owner, article.OwnerOrg)
+	}
+}
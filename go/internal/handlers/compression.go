@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// compressArg is the optional last argument of the list queries that asks for a
+// compressed response
+const compressArg = "compress"
+
+// maxCompressedResponseSize is the largest compressed payload a list query returns, in
+// bytes, before base64 encoding
+const maxCompressedResponseSize = 4 * 1024 * 1024
+
+// gzipMagic are the first two bytes of every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// maxInflatedTransientSize is the largest size in bytes a gzip-compressed transient value
+// may inflate to, so a small compressed value cannot exhaust the memory of the peer
+const maxInflatedTransientSize = 8 * 1024 * 1024
+
+// compressedResponse is the response of a list query called with compressArg. Data is
+// the base64 of the gzip of the response the query returns without it.
+type compressedResponse struct {
+	Encoding string `json:"encoding"`
+	Data     string `json:"data"`
+}
+
+// WithCompression returns the handler of a list query that takes compressArg after its
+// other arguments, of which minArgs are required, so a required argument can still be
+// "compress". With it the successful payload is gzipped and wrapped in a
+// compressedResponse; a compressed payload above maxCompressedResponseSize fails with
+// TOO_LARGE instead.
+func WithCompression(minArgs int, handler HandlerFunc) HandlerFunc {
+	return func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+		if len(args) <= minArgs || args[len(args)-1] != compressArg {
+			return handler(stub, cfg, args)
+		}
+
+		response := handler(stub, cfg, args[:len(args)-1])
+		if response.Status != shim.OK {
+			return response
+		}
+
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		_, err := writer.Write(response.Payload)
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			return internalError("", "Failed to compress response: "+err.Error())
+		}
+		if compressed.Len() > maxCompressedResponseSize {
+			return errorResponse(newError(CodeTooLarge, "", "compressed response of %d bytes exceeds the limit of %d bytes, query fewer results at a time", compressed.Len(), maxCompressedResponseSize))
+		}
+		txLogger(stub).Debugf("compressed response from %d to %d bytes", len(response.Payload), compressed.Len())
+
+		responseJSONasBytes, err := json.Marshal(&compressedResponse{Encoding: "gzip", Data: base64.StdEncoding.EncodeToString(compressed.Bytes())})
+		if err != nil {
+			return errorResponse(err)
+		}
+		return shim.Success(responseJSONasBytes)
+	}
+}
+
+// inflateTransientValue returns a transient value inflated when it starts with the gzip
+// magic bytes, and unchanged otherwise, so plain JSON keeps working. A corrupt stream fails
+// with INVALID_INPUT and one inflating to more than maxInflatedTransientSize bytes with
+// TOO_LARGE, which is detected without inflating more than one byte past the limit.
+func inflateTransientValue(key string, value []byte) ([]byte, error) {
+	if !bytes.HasPrefix(value, gzipMagic) {
+		return value, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(value))
+	if err != nil {
+		return nil, newError(CodeInvalidInput, "", "%s value in the transient map is not a valid gzip stream: %v", key, err)
+	}
+	inflated, err := ioutil.ReadAll(io.LimitReader(reader, maxInflatedTransientSize+1))
+	if err != nil {
+		return nil, newError(CodeInvalidInput, "", "%s value in the transient map is not a valid gzip stream: %v", key, err)
+	}
+	if len(inflated) > maxInflatedTransientSize {
+		return nil, newError(CodeTooLarge, "", "%s value in the transient map inflates to more than %d bytes", key, maxInflatedTransientSize)
+	}
+	return inflated, nil
+}
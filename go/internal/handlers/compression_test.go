@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// inflate returns the payload wrapped in the compressed response
+func inflate(t *testing.T, response pb.Response) []byte {
+	t.Helper()
+	expectStatus(t, response, shim.OK)
+	var compressed compressedResponse
+	err := json.Unmarshal(response.Payload, &compressed)
+	if err != nil {
+		t.Fatalf("failed to decode the compressed response %s: %v", response.Payload, err)
+	}
+	if compressed.Encoding != "gzip" {
+		t.Errorf("compressed response has encoding %s", compressed.Encoding)
+	}
+	data, err := base64.StdEncoding.DecodeString(compressed.Data)
+	if err != nil {
+		t.Fatalf("failed to decode the base64 of the compressed response: %v", err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to open the gzip of the compressed response: %v", err)
+	}
+	payload, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to inflate the compressed response: %v", err)
+	}
+	return payload
+}
+
+func TestCompressedListRoundTrip(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"getArticlesByOwner": WithCompression(1, GetArticlesByOwner),
+		// randomPayload returns 5 MB of random bytes, which gzip cannot make smaller
+		"randomPayload": WithCompression(0, func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+			payload := make([]byte, 5*1024*1024)
+			_, err := rand.Read(payload)
+			if err != nil {
+				return errorResponse(err)
+			}
+			return shim.Success(payload)
+		}),
+	})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	n.createArticle(t, articleJSON("article2", "red", 40, 0))
+
+	for _, args := range [][]string{{"tom"}, {"tom", "true"}, {"tom", "v2"}, {"nobody"}, {"nobody", "v2"}} {
+		plain := n.user2.Query("getArticlesByOwner", args...)
+		expectStatus(t, plain, shim.OK)
+		payload := inflate(t, n.user2.Query("getArticlesByOwner", append(args, compressArg)...))
+		if !bytes.Equal(payload, plain.Payload) {
+			t.Errorf("compressed getArticlesByOwner %q inflates to\n%s\nexpected\n%s", args, payload, plain.Payload)
+		}
+	}
+	if payload := inflate(t, n.user2.Query("getArticlesByOwner", "nobody", compressArg)); string(payload) != "[]" {
+		t.Errorf("compressed empty result inflates to %s", payload)
+	}
+
+	// a required argument is never taken for the compression flag
+	response := n.user2.Query("getArticlesByOwner", compressArg)
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != "[]" {
+		t.Errorf("articles of owner %s are %s", compressArg, response.Payload)
+	}
+
+	expectCode(t, n.user2.Query("randomPayload", compressArg), CodeTooLarge)
+	expectStatus(t, n.user2.Query("randomPayload"), shim.OK)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// ComputeArticleHash - compute the hash the given article properties have when stored in
+// collectionArticles, i.e. the SHA-256 of their canonical JSON. Clients can check their
+// own canonical marshaling against it. Nothing is read from the ledger.
+// ===============================================
+func ComputeArticleHash(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Article properties must be passed in transient map.")
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articlePropertiesJsonBytes, ok := transMap["article_properties"]
+	if !ok {
+		return invalidInput("", "article_properties must be a key in the transient map")
+	}
+
+	if len(articlePropertiesJsonBytes) == 0 {
+		return invalidInput("", "article_properties value in the transient map must be a non-empty JSON string")
+	}
+
+	var article model.Article
+	err = json.Unmarshal(articlePropertiesJsonBytes, &article)
+	if err != nil {
+		return invalidInput("", "Failed to decode JSON of: "+string(articlePropertiesJsonBytes))
+	}
+
+	err = article.ValidateClaim(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(article.Name, err.Error())
+	}
+
+	article.ObjectType = model.DefaultDocType
+	hash, err := model.ComputeArticleHash(&article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return hashResponse(article.Name, cfg.CollectionArticles, hash, "json")
+}
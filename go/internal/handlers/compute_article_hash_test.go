@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/testutil"
+)
+
+func TestComputedHashMatchesStoredArticle(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"computeArticleHash": ComputeArticleHash,
+		"getArticleHash":     GetArticleHash,
+	})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	article := n.readArticle(t, "article1")
+
+	properties, err := json.Marshal(article)
+	if err != nil {
+		t.Fatalf("failed to encode article1: %v", err)
+	}
+	computed := n.user1.Submit(testutil.Invocation{
+		Function:  "computeArticleHash",
+		Transient: testutil.Transient("article_properties", string(properties)),
+		Evaluate:  true,
+	}).Response
+	expectStatus(t, computed, shim.OK)
+	stored := n.user2.Query("getArticleHash", "article1", "json")
+	expectStatus(t, stored, shim.OK)
+	if string(computed.Payload) != string(stored.Payload) {
+		t.Errorf("computed hash %s, stored hash %s", computed.Payload, stored.Payload)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// DeactivateOwner marks an owner of the owner registry inactive. Its articles stay with it,
+// but no article can be created for or transferred to it until it is registered again.
+// Only admins may call it.
+// ===========================================================================================
+func DeactivateOwner(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start deactivate owner")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Owner must be passed in transient map.")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	ownerDeactivationJsonBytes, ok := transMap["owner_deactivation"]
+	if !ok {
+		return invalidInput("", "owner_deactivation must be a key in the transient map")
+	}
+
+	if len(ownerDeactivationJsonBytes) == 0 {
+		return invalidInput("", "owner_deactivation value in the transient map must be a non-empty JSON string")
+	}
+
+	var ownerDeactivationInput model.OwnerDeactivationTransientInput
+	err = model.DecodeTransientInput("owner_deactivation", ownerDeactivationJsonBytes, &ownerDeactivationInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = ownerDeactivationInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(ownerDeactivationInput.Name, err.Error())
+	}
+
+	ownerRecord, err := getOwnerRecord(stub, cfg, ownerDeactivationInput.Name)
+	if err != nil {
+		return internalError(ownerDeactivationInput.Name, err.Error())
+	} else if ownerRecord == nil {
+		return errorResponse(newError(CodeUnknownOwner, ownerDeactivationInput.Name, "owner %s is not registered", ownerDeactivationInput.Name))
+	}
+
+	ownerRecord.Active = false
+	registryKey, err := ownerRegistryKey(stub, ownerRecord.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	ownerJSONasBytes, err := json.Marshal(ownerRecord)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, registryKey, ownerJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end deactivateOwner (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ==================================================
+// Delete - remove a article key/value pair from state. With soft set the article is kept
+// as a tombstone marked deleted instead, which purgeDeletedArticles removes later on.
+// Retired articles are only deleted by admins with force set, disputed articles not at
+// all until the dispute is resolved. The key-level endorsement policies of the article
+// are cleared in both collections before it is removed.
+// ==================================================
+func Delete(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start delete article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article name must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleDeleteJsonBytes, ok := transMap["article_delete"]
+	if !ok {
+		return invalidInput("", "article_delete must be a key in the transient map")
+	}
+
+	if len(articleDeleteJsonBytes) == 0 {
+		return invalidInput("", "article_delete value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleDeleteInput model.ArticleDeleteTransientInput
+	err = model.DecodeTransientInput("article_delete", articleDeleteJsonBytes, &articleDeleteInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleDeleteInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleDeleteInput.Name, err.Error())
+	}
+
+	// ==== Removing an article for good is reserved to admins, others may only soft-delete ====
+	if !articleDeleteInput.Soft {
+		err = verifyClientIsAdmin(stub)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	docType, err := resolveDocType(cfg, articleDeleteInput.DocType)
+	if err != nil {
+		return errorResponse(err)
+	}
+	key, err := articleKey(stub, docType, articleDeleteInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// to maintain the color~name index, we need to read the article first and get its color
+	valAsbytes, err := stub.GetPrivateData(cfg.CollectionArticles, key) //get the article from chaincode state
+	if err != nil {
+		return internalError(articleDeleteInput.Name, "Failed to get state for "+articleDeleteInput.Name)
+	} else if valAsbytes == nil {
+		return notFound(articleDeleteInput.Name, "Article does not exist: "+articleDeleteInput.Name)
+	}
+
+	var articleToDelete model.Article
+	err = json.Unmarshal([]byte(valAsbytes), &articleToDelete)
+	if err != nil {
+		return internalError(articleDeleteInput.Name, "Failed to decode JSON of: "+string(valAsbytes))
+	}
+
+	err = verifyNotLockedByOtherOrg(stub, &articleToDelete)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== A retired article is a permanent record, only admins may force its removal ====
+	if articleToDelete.Retired {
+		if !articleDeleteInput.Force {
+			return errorResponse(verifyNotRetired(&articleToDelete))
+		}
+		err = verifyClientIsAdmin(stub)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== A disputed article is kept until the dispute is resolved ====
+	err = verifyNotDisputed(&articleToDelete)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Drop the key-level endorsement policies first, so none outlives the article ====
+	err = clearArticleStateBasedEndorsement(stub, cfg, key)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Debugf("deleting %s of doc type %s, soft: %t", articleToDelete.Name, docType, articleDeleteInput.Soft)
+	if articleDeleteInput.Soft {
+		err = verifyNotDeleted(&articleToDelete)
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		// a tombstone keeps the record but leaves every index, so queries walking
+		// the indexes no longer find it
+		if docType == model.DefaultDocType {
+			err = removeArticleIndexes(stub, cfg, &articleToDelete, stub.DelPrivateData)
+			if err != nil {
+				return internalError(articleDeleteInput.Name, err.Error())
+			}
+			err = adjustShareholderCounts(stub, cfg, &articleToDelete, -1)
+			if err != nil {
+				return internalError(articleDeleteInput.Name, err.Error())
+			}
+			err = deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.CertificationIndex, []string{articleToDelete.Name})
+			if err != nil {
+				return internalError(articleDeleteInput.Name, err.Error())
+			}
+			err = deleteByPartialCompositeKey(stub, cfg.CollectionArticlePrivateDetails, model.AttachmentIndex, []string{articleToDelete.Name})
+			if err != nil {
+				return internalError(articleDeleteInput.Name, err.Error())
+			}
+		}
+		_, err = removePublicSummary(stub, key)
+		if err != nil {
+			return internalError(articleDeleteInput.Name, err.Error())
+		}
+
+		txTimestamp, err := txTimestampRFC3339(stub)
+		if err != nil {
+			return errorResponse(err)
+		}
+		articleToDelete.Deleted = true
+		articleToDelete.DeletedAt = txTimestamp
+		articleToDelete.UpdatedAt = txTimestamp
+		articleToDelete.ForSale = false
+		err = putArticle(stub, cfg, &articleToDelete)
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		if docType == model.DefaultDocType {
+			err = putAuditRecord(stub, cfg, articleToDelete.Name, "delete")
+			if err != nil {
+				return errorResponse(err)
+			}
+		}
+	} else if docType != model.DefaultDocType {
+		// objects of other doc types have no indexes or history, only their public summary
+		err = stub.DelPrivateData(cfg.CollectionArticles, key)
+		if err != nil {
+			return internalError(articleDeleteInput.Name, "Failed to delete state:"+err.Error())
+		}
+		err = stub.DelPrivateData(cfg.CollectionArticlePrivateDetails, key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		_, err = removePublicSummary(stub, key)
+		if err != nil {
+			return internalError(articleDeleteInput.Name, err.Error())
+		}
+	} else {
+		// delete the article, its indexes, private details and, unless the caller
+		// asked to retain it, its ownership history
+		err = removeArticle(stub, cfg, &articleToDelete, articleDeleteInput.KeepHistory)
+		if err != nil {
+			return internalError(articleDeleteInput.Name, err.Error())
+		}
+	}
+
+	err = setArticleEvent(stub, "ArticleDeleted", model.ArticleEventEntry{Name: articleToDelete.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(nil)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// DeleteArticlePrivateDetailsOnly - retract the price of a article while keeping the article
+// itself. The private details, their price~name index entry and the price history are
+// deleted. Only the org that last wrote the details may do so; for details written before
+// the writer was recorded, the owner org of the article.
+// ===========================================================================================
+func DeleteArticlePrivateDetailsOnly(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start delete article private details only")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article name must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	detailsDeleteJsonBytes, ok := transMap["article_private_details_delete"]
+	if !ok {
+		return invalidInput("", "article_private_details_delete must be a key in the transient map")
+	}
+
+	if len(detailsDeleteJsonBytes) == 0 {
+		return invalidInput("", "article_private_details_delete value in the transient map must be a non-empty JSON string")
+	}
+
+	var detailsDeleteInput model.ArticlePrivateDetailsDeleteTransientInput
+	err = model.DecodeTransientInput("article_private_details_delete", detailsDeleteJsonBytes, &detailsDeleteInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = detailsDeleteInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(detailsDeleteInput.Name, err.Error())
+	}
+
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, detailsDeleteInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the org that wrote the details may retract them ====
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client MSP ID: "+err.Error())
+	}
+	detailsWriterKey, err := stub.CreateCompositeKey(model.DetailsWriterIndex, []string{privateDetails.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	writerOrgID, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, detailsWriterKey)
+	if err != nil {
+		return internalError(privateDetails.Name, "Failed to get private details writer: "+err.Error())
+	}
+	if writerOrgID != nil {
+		if string(writerOrgID) != clientOrgID {
+			return accessDenied(privateDetails.Name, "submitting org "+clientOrgID+" did not write the private details, "+string(writerOrgID)+" did")
+		}
+	} else {
+		article, err := getArticle(stub, cfg, privateDetails.Name)
+		if err != nil {
+			return errorResponse(err)
+		}
+		_, err = verifyClientIsOwnerOrg(stub, article)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	err = removeArticlePrivateDetails(stub, cfg, privateDetails, stub.DelPrivateData)
+	if err != nil {
+		return internalError(privateDetails.Name, err.Error())
+	}
+	err = deleteByPartialCompositeKey(stub, cfg.CollectionArticlePrivateDetails, model.PriceHistoryIndex, []string{privateDetails.Name})
+	if err != nil {
+		return internalError(privateDetails.Name, err.Error())
+	}
+
+	err = putAuditRecord(stub, cfg, privateDetails.Name, "deleteArticlePrivateDetailsOnly")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end deleteArticlePrivateDetailsOnly (success)")
+	return shim.Success(nil)
+}
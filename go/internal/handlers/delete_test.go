@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+func TestEndorsementPolicyClearedOnDelete(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"delete":                        Delete,
+		"retireArticle":                 RetireArticle,
+		"resetArticleEndorsementPolicy": ResetArticleEndorsementPolicy,
+		// setDetailsPolicy requires Org1 to endorse changes of the private details of the
+		// article, as a policy set by an older version of the chaincode would
+		"setDetailsPolicy": func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+			err := setArticleStateBasedEndorsement(stub, cfg, args[0], org1)
+			if err == nil {
+				policy, _ := stub.GetPrivateDataValidationParameter(cfg.CollectionArticles, args[0])
+				err = stub.SetPrivateDataValidationParameter(cfg.CollectionArticlePrivateDetails, args[0], policy)
+			}
+			if err != nil {
+				return errorResponse(err)
+			}
+			return shim.Success(nil)
+		},
+	})
+	// policies returns the validation parameters of the article in both collections
+	policies := func(name string) (articles []byte, details []byte) {
+		return n.PrivateDataValidationParameter(model.DefaultCollectionArticles, name), n.PrivateDataValidationParameter(model.DefaultCollectionArticlePrivateDetails, name)
+	}
+
+	for _, test := range []struct {
+		name      string
+		function  string
+		client    *testutil.Client
+		transient map[string][]byte
+	}{
+		{"article1", "delete", n.admin1, testutil.Transient("article_delete", `{"name":"article1"}`)},
+		{"article2", "retireArticle", n.user1, testutil.Transient("article_retire", `{"name":"article2","reason":"worn out"}`)},
+		{"article3", "resetArticleEndorsementPolicy", n.admin1, testutil.Transient("policy_reset", `{"name":"article3"}`)},
+	} {
+		n.createArticle(t, articleJSON(test.name, "blue", 35, 9900))
+		expectStatus(t, n.user1.Invoke("setDetailsPolicy", test.name), shim.OK)
+		if articles, details := policies(test.name); articles == nil || details == nil {
+			t.Fatalf("%s has no key-level endorsement policy to clear", test.name)
+		}
+
+		expectStatus(t, test.client.InvokeTransient(test.function, test.transient), shim.OK)
+		if articles, details := policies(test.name); articles != nil || details != nil {
+			t.Errorf("%s left the validation parameters %x and %x of %s", test.function, articles, details, test.name)
+		}
+	}
+
+	// nothing stops an article of another org under the name of the deleted one
+	response := n.user2.InvokeTransient("initArticle", testutil.Transient("article", `{"name":"article1","color":"red","size":10,"owner":"jerry","salt":"`+testSalt+`"}`))
+	expectStatus(t, response, shim.OK)
+	if article := n.readArticle(t, "article1"); article.Owner != "jerry" || article.OwnerOrg != org2 {
+		t.Errorf("recreated article1 is owned by %s of %s", article.Owner, article.OwnerOrg)
+	}
+}
@@ -0,0 +1,10 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package handlers implements the invoke functions of the articles chaincode.
+// Every handler takes the stub, the chaincode configuration and the invoke arguments
+// and returns the peer response.
+package handlers
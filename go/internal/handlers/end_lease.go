@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// EndLease - end the lease of a article before or after its end date. The lessee and the
+// end date are removed from the article and the end is recorded in its lease history.
+// Only the owner org can end the lease.
+// ===========================================================================================
+func EndLease(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start end lease")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	leaseEndJsonBytes, ok := transMap["lease_end"]
+	if !ok {
+		return invalidInput("", "lease_end must be a key in the transient map")
+	}
+
+	if len(leaseEndJsonBytes) == 0 {
+		return invalidInput("", "lease_end value in the transient map must be a non-empty JSON string")
+	}
+
+	var leaseEndInput model.LeaseEndTransientInput
+	err = model.DecodeTransientInput("lease_end", leaseEndJsonBytes, &leaseEndInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = leaseEndInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(leaseEndInput.Name, err.Error())
+	}
+
+	leasedArticle, err := getArticle(stub, cfg, leaseEndInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	if len(leasedArticle.Lessee) == 0 {
+		return invalidInput(leasedArticle.Name, "Article is not leased: "+leasedArticle.Name)
+	}
+
+	// ==== Only the organization of the current owner may end the lease ====
+	_, err = verifyClientIsOwnerOrg(stub, leasedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = endArticleLease(stub, cfg, leasedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	leasedArticle.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putArticle(stub, cfg, leasedArticle) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, leasedArticle.Name, "endLease")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end endLease (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// couchDBIndex describes a CouchDB index shipped in
+// META-INF/statedb/couchdb/collections/collectionArticles/indexes
+type couchDBIndex struct {
+	DesignDoc string `json:"ddoc"`
+	Name      string `json:"name"`
+	Field     string `json:"-"` //indexed field besides docType, used by the probe query
+}
+
+// couchDBIndexes are the indexes of collectionArticles that ensureIndexes probes
+var couchDBIndexes = []couchDBIndex{
+	{DesignDoc: "indexOwnerDoc", Name: "indexOwner", Field: "owner"},
+	{DesignDoc: "indexColorDoc", Name: "indexColor", Field: "color"},
+}
+
+// indexStatus is a member of the result of ensureIndexes
+type indexStatus struct {
+	couchDBIndex
+	OK      bool   `json:"ok"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// ===========================================================================================
+// EnsureIndexes runs a cheap rich query against each CouchDB index shipped with the chaincode
+// and reports a warning for the indexes that appear to be missing. The indexes are only
+// deployed on CouchDB peers whose collection name matches the directory of the index files.
+// ===========================================================================================
+func EnsureIndexes(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting no arguments")
+	}
+
+	statuses := []indexStatus{}
+	for _, index := range couchDBIndexes {
+		status := indexStatus{couchDBIndex: index, OK: true}
+
+		probeJSONasBytes, err := json.Marshal(map[string]interface{}{
+			"selector":  map[string]interface{}{"docType": model.DefaultDocType, index.Field: ""},
+			"use_index": []string{"_design/" + index.DesignDoc, index.Name},
+			"limit":     1,
+		})
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		resultsIterator, err := stub.GetPrivateDataQueryResult(cfg.CollectionArticles, string(probeJSONasBytes))
+		if err != nil {
+			status.OK = false
+			status.Warning = "index " + index.Name + " appears to be missing: " + err.Error()
+		} else {
+			resultsIterator.Close()
+		}
+		statuses = append(statuses, status)
+	}
+
+	statusesJSONasBytes, err := json.Marshal(statuses)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(statusesJSONasBytes)
+}
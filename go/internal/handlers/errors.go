@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// Machine-readable codes of failed responses
+const (
+	CodeArticleNotFound = "ARTICLE_NOT_FOUND"
+	CodeAlreadyExists   = "ALREADY_EXISTS"
+	CodeInvalidInput    = "INVALID_INPUT"
+	CodeAccessDenied    = "ACCESS_DENIED"
+	CodeInternal        = "INTERNAL"
+	CodeNotSupported    = "NOT_SUPPORTED"
+	CodeUnknownOwner    = "UNKNOWN_OWNER"
+	CodeTooLarge        = "TOO_LARGE"
+	CodeConflict        = "CONFLICT"
+	CodeArticleRetired  = "ARTICLE_RETIRED"
+)
+
+// errorStatus maps the error codes to the HTTP-like status of the peer response
+var errorStatus = map[string]int32{
+	CodeArticleNotFound: 404,
+	CodeAlreadyExists:   409,
+	CodeInvalidInput:    400,
+	CodeAccessDenied:    403,
+	CodeInternal:        500,
+	CodeNotSupported:    501,
+	CodeUnknownOwner:    422,
+	CodeTooLarge:        413,
+	CodeConflict:        409,
+	CodeArticleRetired:  410,
+}
+
+// chaincodeError is an error with a machine-readable code and the key it is about.
+// Its JSON form is the message of a failed peer response.
+type chaincodeError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Key     string `json:"key"`
+	Hash    string `json:"hash,omitempty"` //hex private data hash of the record the caller may not read
+}
+
+func (e *chaincodeError) Error() string {
+	return e.Message
+}
+
+// newError creates a chaincodeError with a formatted message
+func newError(code string, key string, format string, a ...interface{}) error {
+	return &chaincodeError{Code: code, Message: fmt.Sprintf(format, a...), Key: key}
+}
+
+// errorResponse converts an error into a failed peer response. Errors without a code
+// are reported as INTERNAL.
+func errorResponse(err error) pb.Response {
+	ccErr, ok := err.(*chaincodeError)
+	if !ok {
+		ccErr = &chaincodeError{Code: CodeInternal, Message: err.Error()}
+	}
+
+	errJSONasBytes, marshalErr := json.Marshal(ccErr)
+	if marshalErr != nil {
+		return shim.Error(ccErr.Message)
+	}
+
+	return pb.Response{
+		Status:  errorStatus[ccErr.Code],
+		Message: string(errJSONasBytes),
+	}
+}
+
+// notFound returns an ARTICLE_NOT_FOUND response
+func notFound(key string, message string) pb.Response {
+	return errorResponse(&chaincodeError{Code: CodeArticleNotFound, Message: message, Key: key})
+}
+
+// alreadyExists returns an ALREADY_EXISTS response
+func alreadyExists(key string, message string) pb.Response {
+	return errorResponse(&chaincodeError{Code: CodeAlreadyExists, Message: message, Key: key})
+}
+
+// invalidInput returns an INVALID_INPUT response
+func invalidInput(key string, message string) pb.Response {
+	return errorResponse(&chaincodeError{Code: CodeInvalidInput, Message: message, Key: key})
+}
+
+// accessDenied returns an ACCESS_DENIED response
+func accessDenied(key string, message string) pb.Response {
+	return errorResponse(&chaincodeError{Code: CodeAccessDenied, Message: message, Key: key})
+}
+
+// internalError returns an INTERNAL response
+func internalError(key string, message string) pb.Response {
+	return errorResponse(&chaincodeError{Code: CodeInternal, Message: message, Key: key})
+}
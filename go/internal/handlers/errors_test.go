@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/testutil"
+)
+
+func TestErrorStatusCodes(t *testing.T) {
+	n := newTestNetwork(t, nil)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+
+	for _, test := range []struct {
+		name     string
+		response pb.Response
+		status   int32
+		code     string
+		key      string
+	}{
+		{"not found", n.user1.Query("readArticle", "article2"), 404, CodeArticleNotFound, "article2"},
+		{"duplicate create", n.user1.InvokeTransient("initArticle", testutil.Transient("article", articleJSON("article1", "red", 40, 0))), 409, CodeAlreadyExists, "article1"},
+		{"invalid input", n.user1.Query("readArticle"), 400, CodeInvalidInput, ""},
+	} {
+		if test.response.Status != test.status {
+			t.Errorf("%s: expected status %d, got %d: %s", test.name, test.status, test.response.Status, test.response.Message)
+		}
+		var ccErr chaincodeError
+		err := json.Unmarshal([]byte(test.response.Message), &ccErr)
+		if err != nil {
+			t.Errorf("%s: error is not JSON: %s", test.name, test.response.Message)
+		} else if ccErr.Code != test.code || ccErr.Key != test.key || ccErr.Message == "" {
+			t.Errorf("%s: expected code %s and key %q, got %s", test.name, test.code, test.key, test.response.Message)
+		}
+	}
+
+	// the duplicate did not overwrite the article
+	if article := n.readArticle(t, "article1"); article.Color != "blue" {
+		t.Errorf("duplicate create changed the color to %s", article.Color)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/testutil"
+)
+
+// TestArticleEventPayloads pins the exact events listeners receive, so a change of the
+// payload format shows up here
+func TestArticleEventPayloads(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"agreeToTransfer": AgreeToTransfer,
+		"transferArticle": TransferArticle,
+		"delete":          Delete,
+	})
+
+	for _, test := range []struct {
+		client    *testutil.Client
+		function  string
+		transient map[string][]byte
+		event     string
+		payload   string
+	}{
+		{n.user1, "initArticle", testutil.Transient("article", articleJSON("article1", "blue", 35, 9900)),
+			"ArticleCreated", `{"articles":[{"name":"article1"}]}`},
+		{n.user1, "initArticle", testutil.Transient("article", articleJSON("article2", "red", 40, 0)),
+			"ArticleCreated", `{"articles":[{"name":"article2"}]}`},
+		{n.user2, "agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1","price":9900,"currency":"EUR"}`),
+			"", ""},
+		{n.user1, "transferArticle", transferToJerry("article1"),
+			"ArticleTransferred", `{"articles":[{"name":"article1","oldOwner":"tom","newOwner":"jerry"}]}`},
+		{n.admin1, "delete", testutil.Transient("article_delete", `{"name":"article2"}`),
+			"ArticleDeleted", `{"articles":[{"name":"article2"}]}`},
+	} {
+		tx := test.client.Submit(testutil.Invocation{Function: test.function, Transient: test.transient})
+		expectStatus(t, tx.Response, shim.OK)
+		if len(test.event) == 0 {
+			continue
+		}
+		if len(tx.Events) != 1 {
+			t.Fatalf("%s set %d events, expected 1", test.function, len(tx.Events))
+		}
+		event := tx.Events[0]
+		if event.EventName != test.event || string(event.Payload) != test.payload {
+			t.Errorf("%s set event %s %s, expected %s %s", test.function, event.EventName, event.Payload, test.event, test.payload)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ExecuteTransfer - complete the transfer initiated for a article once every listed org has
+// approved it. The transfer request and its approvals are removed with the change of owner.
+// ===========================================================================================
+func ExecuteTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start execute transfer")
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	name, err := getTransferApprovalInput(stub, cfg, args)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	article, err := getArticle(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may execute the transfer ====
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transferRequest, err := getTransferRequest(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if transferRequest == nil {
+		return notFound(name, "No transfer is initiated for article: "+name)
+	}
+
+	// ==== Every listed org must have approved ====
+	missing := []string{}
+	for _, mspID := range transferRequest.Approvers {
+		approvalKey, err := stub.CreateCompositeKey(model.ApprovalIndex, []string{name, mspID})
+		if err != nil {
+			return errorResponse(err)
+		}
+		approvalHash, err := stub.GetPrivateDataHash(cfg.CollectionArticles, approvalKey)
+		if err != nil {
+			return internalError(name, "Failed to get approval: "+err.Error())
+		}
+		if approvalHash == nil {
+			missing = append(missing, mspID)
+		}
+	}
+	if len(missing) != 0 {
+		return accessDenied(name, "the transfer of article "+name+" still needs the approval of orgs "+strings.Join(missing, ", "))
+	}
+
+	// ==== The owner may have been deactivated since the initiation ====
+	err = verifyOwnerRegistered(stub, cfg, transferRequest.NewOwner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	if article.LockedBy != transferRequest.NewOwnerOrg {
+		err = verifyNotLockedByOtherOrg(stub, article)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	oldOwner := article.Owner
+
+	err = changeArticleOwner(stub, cfg, article, transferRequest.NewOwner, transferRequest.NewOwnerOrg, "executeTransfer")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleTransferred", model.ArticleEventEntry{
+		Name:     article.Name,
+		OldOwner: oldOwner,
+		NewOwner: article.Owner,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end executeTransfer (success)")
+	return shim.Success(nil)
+}
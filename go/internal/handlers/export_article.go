@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ExportArticle packages an article for importArticle on another channel: the article,
+// its private details when the client may read the price (their hash otherwise), its salt,
+// the ID of this transaction and the hash of the package. Invoked rather than queried, the
+// export leaves an audit record, so the exportTxID of the package can be looked up on the
+// ledger of this channel. Only the owner org exports an article.
+// ===========================================================================================
+func ExportArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start export article")
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to export")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	err = verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	article, err := getArticle(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	export := &model.ArticleExport{
+		Article:    article,
+		Salt:       article.Salt,
+		ExportTxID: stub.GetTxID(),
+	}
+
+	// ==== The price is exported only to its readers, everyone else hands over the hash ====
+	detailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, name)
+	if err != nil {
+		return internalError(name, "Failed to get article private details hash for "+name+": "+err.Error())
+	}
+	if detailsHash != nil {
+		detailsAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, name)
+		if err == nil && detailsAsBytes != nil && verifyPriceReader(stub, cfg, detailsAsBytes) == nil {
+			export.PrivateDetails = &model.ArticlePrivateDetails{}
+			err = json.Unmarshal(detailsAsBytes, export.PrivateDetails)
+			if err != nil {
+				return internalError(name, "Failed to decode JSON of: "+string(detailsAsBytes))
+			}
+		} else {
+			export.PrivateDetailsHash = hex.EncodeToString(detailsHash)
+		}
+	}
+
+	export.HashHex, err = model.ComputeExportHash(export)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, name, "exportArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	exportJSONasBytes, err := model.MarshalCanonical(export)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end exportArticle (success)")
+	return shim.Success(exportJSONasBytes)
+}
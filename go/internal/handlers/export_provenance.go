@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ExportProvenance assembles the lifecycle of an article into one document for auditors:
+// the current article record, its ownership history, its certifications and the private
+// data hashes of both collections. The private details are included only when the client
+// may read the price, otherwise their hash stands in for them. The document carries a
+// contentHash, the SHA-256 of its canonical JSON without the contentHash, so it can be
+// checked for tampering off-chain. The history is paginated: the optional second argument
+// is the sequence number of the first history entry, at most MaxResults entries are
+// returned and nextOffset tells where the next page starts.
+// ===========================================================================================
+func ExportProvenance(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 && len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to export and an optional history offset")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	offset := 0
+	if len(args) == 2 && len(args[1]) > 0 {
+		offset, err = strconv.Atoi(args[1])
+		if err != nil || offset < 0 {
+			return invalidInput(name, "history offset must be a non-negative integer")
+		}
+	}
+
+	// ==== The record is exported as stored, retired and soft-deleted articles included ====
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, name)
+	if err != nil {
+		return internalError(name, "Failed to get state for "+name)
+	} else if articleAsBytes == nil {
+		return notFound(name, "Article does not exist: "+name)
+	}
+
+	export := &model.ProvenanceExport{
+		Name:           name,
+		Article:        &model.Article{},
+		History:        []model.OwnershipRecord{},
+		HistoryOffset:  offset,
+		Certifications: []model.Certification{},
+	}
+	err = json.Unmarshal(articleAsBytes, export.Article)
+	if err != nil {
+		return internalError(name, "Failed to decode JSON of: "+string(articleAsBytes))
+	}
+
+	articleHash, err := stub.GetPrivateDataHash(cfg.CollectionArticles, name)
+	if err != nil {
+		return internalError(name, "Failed to get article hash for "+name+": "+err.Error())
+	}
+	export.ArticleHash = hex.EncodeToString(articleHash)
+
+	// ==== One page of the ownership history, selected by sequence number ====
+	historyResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.HistoryIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer historyResultsIterator.Close()
+
+	for historyResultsIterator.HasNext() {
+		responseRange, err := historyResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		seq, err := strconv.Atoi(compositeKeyParts[1])
+		if err != nil {
+			return internalError(name, "invalid sequence number in history key: "+compositeKeyParts[1])
+		}
+		if seq < offset {
+			continue
+		}
+		if len(export.History) == cfg.MaxResults {
+			export.Truncated = true
+			export.NextOffset = seq
+			break
+		}
+
+		var ownershipRecord model.OwnershipRecord
+		err = json.Unmarshal(responseRange.Value, &ownershipRecord)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		export.History = append(export.History, ownershipRecord)
+	}
+
+	certificationsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.CertificationIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer certificationsIterator.Close()
+
+	for certificationsIterator.HasNext() {
+		responseRange, err := certificationsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var certification model.Certification
+		err = json.Unmarshal(responseRange.Value, &certification)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		export.Certifications = append(export.Certifications, certification)
+	}
+
+	// ==== The price is exported only to its readers, everyone gets the hash of the details ====
+	detailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, name)
+	if err != nil {
+		return internalError(name, "Failed to get article private details hash for "+name+": "+err.Error())
+	}
+	if detailsHash != nil {
+		export.PrivateDetailsHash = hex.EncodeToString(detailsHash)
+
+		// a peer outside the collection fails the read, which leaves the hash alone
+		detailsAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, name)
+		if err == nil && detailsAsBytes != nil && verifyPriceReader(stub, cfg, detailsAsBytes) == nil {
+			export.PrivateDetails = &model.ArticlePrivateDetails{}
+			err = json.Unmarshal(detailsAsBytes, export.PrivateDetails)
+			if err != nil {
+				return internalError(name, "Failed to decode JSON of: "+string(detailsAsBytes))
+			}
+		}
+	}
+
+	// ==== Seal the export with the hash of its canonical JSON ====
+	bodyAsBytes, err := model.MarshalCanonical(export)
+	if err != nil {
+		return errorResponse(err)
+	}
+	contentHash := sha256.Sum256(bodyAsBytes)
+	export.ContentHash = hex.EncodeToString(contentHash[:])
+
+	exportJSONasBytes, err := model.MarshalCanonical(export)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(exportJSONasBytes)
+}
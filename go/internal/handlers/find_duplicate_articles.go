@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// FindDuplicateArticles returns the groups of articles that share a fingerprint, i.e. the
+// same color, size and SKU, by walking the fingerprint~hash~name index. The index is
+// sorted by fingerprint, so the members of a group come one after the other.
+// ===========================================================================================
+func FindDuplicateArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting none")
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.FingerprintIndex, []string{})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	groups := []model.DuplicateGroup{}
+	var group *model.DuplicateGroup
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		fingerprint, name := compositeKeyParts[0], compositeKeyParts[1]
+
+		if group == nil || group.Fingerprint != fingerprint {
+			if group != nil && len(group.Names) > 1 {
+				groups = append(groups, *group)
+			}
+			group = &model.DuplicateGroup{Fingerprint: fingerprint}
+		}
+		group.Names = append(group.Names, name)
+	}
+	if group != nil && len(group.Names) > 1 {
+		groups = append(groups, *group)
+	}
+
+	groupsJSONasBytes, err := json.Marshal(groups)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(groupsJSONasBytes)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// getFunctionACLState reads the access-control map from public state, an empty map
+// when none was set
+func getFunctionACLState(stub shim.ChaincodeStubInterface) (model.FunctionACL, error) {
+	aclAsBytes, err := stub.GetState(model.FunctionACLKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get function ACL: %v", err)
+	}
+	acl := model.FunctionACL{}
+	if aclAsBytes == nil {
+		return acl, nil
+	}
+	err = json.Unmarshal(aclAsBytes, &acl)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode JSON of function ACL: %v", err)
+	}
+	return acl, nil
+}
+
+// putFunctionACLState writes the access-control map to public state, where every org
+// enforces the same one
+func putFunctionACLState(stub shim.ChaincodeStubInterface, acl model.FunctionACL) error {
+	aclJSONasBytes, err := json.Marshal(acl)
+	if err != nil {
+		return err
+	}
+	return stub.PutState(model.FunctionACLKey, aclJSONasBytes)
+}
+
+// InitFunctionACL replaces the access-control map with the given JSON object of function
+// names to MSP ID lists. Init calls it at instantiation and upgrade.
+func InitFunctionACL(stub shim.ChaincodeStubInterface, cfg *model.Config, aclJSON string) error {
+	acl := model.FunctionACL{}
+	err := json.Unmarshal([]byte(aclJSON), &acl)
+	if err != nil {
+		return fmt.Errorf("function ACL must be a JSON object of function names to MSP ID lists: %v", err)
+	}
+	err = acl.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return err
+	}
+	return putFunctionACLState(stub, acl)
+}
+
+// WithFunctionACL returns the handler of the function guarded by the access-control map:
+// clients of an MSP that is not allowed to call the function get ACCESS_DENIED before
+// the handler runs
+func WithFunctionACL(function string, handler HandlerFunc) HandlerFunc {
+	return func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+		acl, err := getFunctionACLState(stub)
+		if err != nil {
+			return errorResponse(err)
+		}
+		clientOrgID, err := cid.GetMSPID(stub)
+		if err != nil {
+			return internalError("", "Failed to get client MSP ID: "+err.Error())
+		}
+		if !acl.Allows(function, clientOrgID) {
+			return accessDenied("", "clients of "+clientOrgID+" may not call "+function)
+		}
+		return handler(stub, cfg, args)
+	}
+}
+
+// ===========================================================================================
+// SetFunctionACL sets the MSP IDs allowed to call a function, given as a JSON array. An
+// empty array lets any member call it. Only admins may call it.
+// ===========================================================================================
+func SetFunctionACL(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting function name and a JSON array of MSP IDs")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	function := args[0]
+	var mspIDs []string
+	err = json.Unmarshal([]byte(args[1]), &mspIDs)
+	if err != nil {
+		return invalidInput(function, "MSP IDs must be a JSON array of strings")
+	}
+	err = model.FunctionACL{function: mspIDs}.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(function, err.Error())
+	}
+
+	acl, err := getFunctionACLState(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if len(mspIDs) == 0 {
+		delete(acl, function)
+	} else {
+		acl[function] = mspIDs
+	}
+	err = putFunctionACLState(stub, acl)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end setFunctionACL (success)")
+	return shim.Success(nil)
+}
+
+// ===========================================================================================
+// GetFunctionACL returns the access-control map, or with a function name as argument the
+// MSP IDs allowed to call that function. Only admins may call it.
+// ===========================================================================================
+func GetFunctionACL(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) > 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting an optional function name")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	acl, err := getFunctionACLState(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	var aclJSONasBytes []byte
+	if len(args) == 1 {
+		mspIDs := acl[args[0]]
+		if mspIDs == nil {
+			mspIDs = []string{}
+		}
+		aclJSONasBytes, err = json.Marshal(mspIDs)
+	} else {
+		aclJSONasBytes, err = json.Marshal(acl)
+	}
+	if err != nil {
+		return errorResponse(err)
+	}
+	return shim.Success(aclJSONasBytes)
+}
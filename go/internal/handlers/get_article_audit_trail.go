@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticleAuditTrail returns the audit records of an article, oldest first. The records
+// are read from the audit~name~txid index, whose keys sort by transaction ID, so they are
+// ordered by their transaction timestamps afterwards.
+// ===========================================================================================
+func GetArticleAuditTrail(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	auditResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.AuditIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer auditResultsIterator.Close()
+
+	auditTrail := []model.AuditRecord{}
+	for auditResultsIterator.HasNext() {
+		responseRange, err := auditResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var auditRecord model.AuditRecord
+		err = json.Unmarshal(responseRange.Value, &auditRecord)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		auditTrail = append(auditTrail, auditRecord)
+	}
+
+	sort.SliceStable(auditTrail, func(i, j int) bool {
+		return auditTrail[i].Timestamp < auditTrail[j].Timestamp
+	})
+
+	auditTrailJSONasBytes, err := json.Marshal(auditTrail)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(auditTrailJSONasBytes)
+}
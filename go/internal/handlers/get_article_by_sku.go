@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticleBySKU returns the article with an SKU, looked up in the sku~code index. SKUs are
+// matched without regard to case.
+// ===========================================================================================
+func GetArticleBySKU(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting SKU of the article to query")
+	}
+
+	sku := args[0]
+	err := model.ValidateSKU("sku", &sku)
+	if err != nil {
+		return invalidInput(sku, err.Error())
+	}
+
+	key, err := skuKey(stub, sku)
+	if err != nil {
+		return errorResponse(err)
+	}
+	name, err := stub.GetPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return internalError(sku, "Failed to get SKU "+sku+": "+err.Error())
+	} else if name == nil {
+		return notFound(sku, "No article has SKU: "+sku)
+	}
+
+	valAsbytes, err := stub.GetPrivateData(cfg.CollectionArticles, string(name))
+	if err != nil {
+		return internalError(string(name), "Failed to get state for "+string(name)+": "+err.Error())
+	} else if valAsbytes == nil {
+		return notFound(string(name), "Article of SKU "+sku+" does not exist: "+string(name))
+	}
+
+	return shim.Success(valAsbytes)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// GetArticleEndorsementPolicy - get the key-level endorsement policy of a article in collectionArticles
+// ===============================================
+func GetArticleEndorsementPolicy(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	var name string
+	var err error
+
+	type articleEndorsementPolicy struct {
+		Name string   `json:"name"`
+		Orgs []string `json:"orgs"`
+	}
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name = args[0]
+	err = model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	policyBytes, err := stub.GetPrivateDataValidationParameter(cfg.CollectionArticles, name)
+	if err != nil {
+		return internalError(name, "Failed to get endorsement policy for "+name+": "+err.Error())
+	}
+
+	endorsementPolicy, err := statebased.NewStateEP(policyBytes)
+	if err != nil {
+		return internalError(name, "Failed to decode endorsement policy for "+name+": "+err.Error())
+	}
+
+	policy := &articleEndorsementPolicy{
+		Name: name,
+		Orgs: endorsementPolicy.ListOrgs(),
+	}
+	if policy.Orgs == nil {
+		policy.Orgs = []string{}
+	}
+	policyJSONasBytes, err := json.Marshal(policy)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(policyJSONasBytes)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// endorsingOrgs returns the orgs of the committed key-level endorsement policy of the key
+func (n *testNetwork) endorsingOrgs(t *testing.T, collection string, key string) []string {
+	t.Helper()
+	policy := n.PrivateDataValidationParameter(collection, key)
+	if policy == nil {
+		return nil
+	}
+	endorsementPolicy, err := statebased.NewStateEP(policy)
+	if err != nil {
+		t.Fatalf("failed to decode the endorsement policy of %s: %v", key, err)
+	}
+	return endorsementPolicy.ListOrgs()
+}
+
+func TestArticleEndorsementPolicyFollowsOwnerOrg(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"agreeToTransfer":             AgreeToTransfer,
+		"transferArticle":             TransferArticle,
+		"getArticleEndorsementPolicy": GetArticleEndorsementPolicy,
+	})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+	if orgs := n.endorsingOrgs(t, model.DefaultCollectionArticles, "article1"); len(orgs) != 1 || orgs[0] != org1 {
+		t.Fatalf("new article is endorsed by %v, expected %s", orgs, org1)
+	}
+	response := n.user2.Query("getArticleEndorsementPolicy", "article1")
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != `{"name":"article1","orgs":["`+org1+`"]}` {
+		t.Errorf("getArticleEndorsementPolicy returned %s", response.Payload)
+	}
+
+	response = n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`))
+	expectStatus(t, response, shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+
+	if orgs := n.endorsingOrgs(t, model.DefaultCollectionArticles, "article1"); len(orgs) != 1 || orgs[0] != org2 {
+		t.Errorf("transferred article is endorsed by %v, expected %s", orgs, org2)
+	}
+	response = n.user1.Query("getArticleEndorsementPolicy", "article1")
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != `{"name":"article1","orgs":["`+org2+`"]}` {
+		t.Errorf("getArticleEndorsementPolicy returned %s", response.Payload)
+	}
+
+	// an article without a policy has no orgs
+	response = n.user1.Query("getArticleEndorsementPolicy", "article2")
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != `{"name":"article2","orgs":[]}` {
+		t.Errorf("getArticleEndorsementPolicy returned %s for a missing article", response.Payload)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// helloSHA256Hex is the SHA-256 of "hello"
+const helloSHA256Hex = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestArticleHashEncodings(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"getArticleHash":               GetArticleHash,
+		"getArticlePrivateDetailsHash": GetArticlePrivateDetailsHash,
+		// putHello writes "hello" under article1 to both collections
+		"putHello": func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+			for _, collection := range []string{cfg.CollectionArticles, cfg.CollectionArticlePrivateDetails} {
+				err := stub.PutPrivateData(collection, "article1", []byte("hello"))
+				if err != nil {
+					return errorResponse(err)
+				}
+			}
+			return shim.Success(nil)
+		},
+	})
+	expectStatus(t, n.user1.Invoke("putHello"), shim.OK)
+
+	for _, test := range []struct {
+		function   string
+		collection string
+	}{
+		{"getArticleHash", model.DefaultCollectionArticles},
+		{"getArticlePrivateDetailsHash", model.DefaultCollectionArticlePrivateDetails},
+	} {
+		// Org2 is no member of the private details collection, but has the hashes
+		response := n.user2.Query(test.function, "article1")
+		expectStatus(t, response, shim.OK)
+		if hex.EncodeToString(response.Payload) != helloSHA256Hex {
+			t.Errorf("%s returned %x, expected %s", test.function, response.Payload, helloSHA256Hex)
+		}
+		response = n.user2.Query(test.function, "article1", "raw")
+		expectStatus(t, response, shim.OK)
+		if hex.EncodeToString(response.Payload) != helloSHA256Hex {
+			t.Errorf("%s raw returned %x, expected %s", test.function, response.Payload, helloSHA256Hex)
+		}
+
+		response = n.user2.Query(test.function, "article1", "json")
+		expectStatus(t, response, shim.OK)
+		expected := `{"name":"article1","collection":"` + test.collection + `","hashHex":"` + helloSHA256Hex + `","hashBase64":"LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="}`
+		if string(response.Payload) != expected {
+			t.Errorf("%s json returned %s, expected %s", test.function, response.Payload, expected)
+		}
+
+		expectCode(t, n.user2.Query(test.function, "article1", "hex"), CodeInvalidInput)
+		expectCode(t, n.user2.Query(test.function, "article2", "json"), CodeArticleNotFound)
+	}
+}
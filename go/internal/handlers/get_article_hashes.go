@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// Collection selectors of getArticleHashes
+const (
+	hashSelectorArticles = "articles"
+	hashSelectorDetails  = "details"
+	hashSelectorBoth     = "both"
+)
+
+// articleHashEntry is the private data hash of an article in one collection, Found false
+// when there is no record
+type articleHashEntry struct {
+	Found bool   `json:"found"`
+	Hash  string `json:"hash,omitempty"` //hex encoded SHA-256
+}
+
+// articleHashes are the hashes of an article in the selected collections
+type articleHashes struct {
+	Articles *articleHashEntry `json:"articles,omitempty"`
+	Details  *articleHashEntry `json:"details,omitempty"`
+}
+
+// ===========================================================================================
+// GetArticleHashes returns the private data hashes of several articles at once, for
+// verification services. The first argument is a JSON array of up to MaxBatchSize names,
+// the second selects the collections: "articles", "details" or "both". Every name gets an
+// entry per selected collection, with found false when there is no record. Hashes are
+// readable without access to the collections.
+// ===========================================================================================
+func GetArticleHashes(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting a JSON array of article names and a collection selector")
+	}
+
+	var names []string
+	err := json.Unmarshal([]byte(args[0]), &names)
+	if err != nil {
+		return invalidInput("", "names must be a JSON array of strings: "+err.Error())
+	}
+	if len(names) == 0 {
+		return invalidInput("", "names must hold at least one name")
+	}
+	if len(names) > cfg.MaxBatchSize {
+		return errorResponse(newError(CodeTooLarge, "", "%d names requested, the maximum is %d", len(names), cfg.MaxBatchSize))
+	}
+
+	selector := args[1]
+	if selector != hashSelectorArticles && selector != hashSelectorDetails && selector != hashSelectorBoth {
+		return invalidInput("", fmt.Sprintf("collection selector must be %s, %s or %s, got %q", hashSelectorArticles, hashSelectorDetails, hashSelectorBoth, selector))
+	}
+
+	results := map[string]*articleHashes{}
+	for _, name := range names {
+		err = model.ValidateName("name", &name, cfg.MaxNameLength)
+		if err != nil {
+			return invalidInput(name, err.Error())
+		}
+
+		hashes := &articleHashes{}
+		if selector != hashSelectorDetails {
+			hashes.Articles, err = getArticleHashEntry(stub, cfg.CollectionArticles, name)
+			if err != nil {
+				return internalError(name, err.Error())
+			}
+		}
+		if selector != hashSelectorArticles {
+			hashes.Details, err = getArticleHashEntry(stub, cfg.CollectionArticlePrivateDetails, name)
+			if err != nil {
+				return internalError(name, err.Error())
+			}
+		}
+		results[name] = hashes
+	}
+
+	resultsJSONasBytes, err := json.Marshal(results)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(resultsJSONasBytes)
+}
+
+// getArticleHashEntry reads the private data hash of an article in a collection
+func getArticleHashEntry(stub shim.ChaincodeStubInterface, collection string, name string) (*articleHashEntry, error) {
+	hash, err := stub.GetPrivateDataHash(collection, name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get hash of %s in %s: %v", name, collection, err)
+	} else if hash == nil {
+		return &articleHashEntry{Found: false}, nil
+	}
+	return &articleHashEntry{Found: true, Hash: hex.EncodeToString(hash)}, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlePrivateDetailsByRange performs a range query over collectionArticlePrivateDetails
+// based on the start and end keys provided, e.g. to export prices in bulk. Empty start or
+// end keys leave the range open on that side. Only members of the collection can read it.
+// ===========================================================================================
+func GetArticlePrivateDetailsByRange(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 2, false)
+
+	if len(args) < 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting 2")
+	}
+
+	startKey := args[0]
+	endKey := args[1]
+
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticlePrivateDetails, startKey, endKey)
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		// skip index entries, a range with an empty start key would include them
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+
+		// details the caller may not read are listed by their hash
+		record := queryResponse.Value
+		if err = verifyPriceReader(stub, cfg, record); err != nil {
+			record, err = privateDataHashFallback(stub, cfg.CollectionArticlePrivateDetails, queryResponse.Key, queryResponse.Key)
+			if err != nil {
+				return errorResponse(err)
+			}
+		}
+
+		err = results.add(queryResponse.Key, record)
+		if err != nil {
+			return internalError(queryResponse.Key, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	resultsJSONasBytes, err = formatPrices(cfg, resultsJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlePrivateDetailsByRange returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
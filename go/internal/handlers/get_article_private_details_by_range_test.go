@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// rangeRecord is a member of the result of a range query
+type rangeRecord struct {
+	Key    string `json:"Key"`
+	Record struct {
+		ObjectType string `json:"docType"`
+		Name       string `json:"name"`
+	} `json:"Record"`
+}
+
+func TestPriceAndArticleRangesDoNotMix(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"getArticlesByRange":              GetArticlesByRange,
+		"getArticlePrivateDetailsByRange": GetArticlePrivateDetailsByRange,
+		"updateArticlePrice":              UpdateArticlePrice,
+	})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	n.createArticle(t, articleJSON("article2", "red", 35, 0))
+	n.createArticle(t, articleJSON("article3", "green", 35, 5000))
+	// the price history and the index entries share the collections with the records
+	response := n.user1.InvokeTransient("updateArticlePrice", testutil.Transient("article_price", `{"name":"article1","price":9000,"currency":"EUR"}`))
+	expectStatus(t, response, shim.OK)
+
+	response = n.user1.Query("getArticlePrivateDetailsByRange", "", "")
+	expectStatus(t, response, shim.OK)
+	var details []rangeRecord
+	err := json.Unmarshal(response.Payload, &details)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", response.Payload, err)
+	}
+	if len(details) != 2 || details[0].Key != "article1" || details[1].Key != "article3" {
+		t.Errorf("private details range returned %s, expected article1 and article3", response.Payload)
+	}
+	for _, result := range details {
+		if result.Record.ObjectType != "articlePrivateDetails" || result.Record.Name != result.Key {
+			t.Errorf("private details range returned a %s record under %s", result.Record.ObjectType, result.Key)
+		}
+	}
+
+	response = n.user1.Query("getArticlesByRange", "", "")
+	expectStatus(t, response, shim.OK)
+	var articles []rangeRecord
+	err = json.Unmarshal(response.Payload, &articles)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", response.Payload, err)
+	}
+	if len(articles) != 3 {
+		t.Errorf("article range returned %s, expected the 3 articles", response.Payload)
+	}
+	for _, result := range articles {
+		if result.Record.ObjectType != model.DefaultDocType || result.Record.Name != result.Key {
+			t.Errorf("article range returned a %s record under %s", result.Record.ObjectType, result.Key)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// GetArticlePrivateDetailsHash - get article private data hash for collectionArticlePrivateDetails from chaincode state
+// The hash is returned as raw bytes unless the optional second argument asks for the "json" envelope
+// ===============================================
+func GetArticlePrivateDetailsHash(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	var name string
+	var err error
+
+	if len(args) != 1 && len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query and an optional hash format")
+	}
+
+	name = args[0]
+	err = model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	valAsbytes, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, name)
+	if err != nil {
+		return internalError(name, "Failed to get article private details hash for "+name+": "+err.Error())
+	} else if valAsbytes == nil {
+		return notFound(name, "Article private details hash does not exist: "+name)
+	}
+
+	if len(args) == 2 {
+		return hashResponse(name, cfg.CollectionArticlePrivateDetails, valAsbytes, args[1])
+	}
+	return shim.Success(valAsbytes)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlePublicHistory returns when the public summary of an article was written and
+// deleted, in the order GetHistoryForKey returns the changes, so anyone on the channel can
+// see when the article appeared or disappeared. The values are left out, only transaction
+// IDs, timestamps and deletions are returned. An article that never had a public summary
+// has an empty history. At most MaxResults entries are returned; the optional second
+// argument is the continuation token "next" of the previous page, the ID of the transaction
+// the page starts at, and the optional third the doc type, "article" by default. Requires
+// the history database of the peer.
+// ===========================================================================================
+func GetArticlePublicHistory(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) < 1 || len(args) > 3 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query, an optional continuation token and an optional docType")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	next := ""
+	if len(args) >= 2 {
+		next = args[1]
+	}
+	docType := ""
+	if len(args) == 3 {
+		docType = args[2]
+	}
+	docType, err = resolveDocType(cfg, docType)
+	if err != nil {
+		return errorResponse(err)
+	}
+	key, err := articleKey(stub, docType, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	historyIterator, err := stub.GetHistoryForKey(publicSummaryKey(key))
+	if err != nil {
+		return internalError(name, "Failed to get public history of "+name+": "+err.Error())
+	}
+	defer historyIterator.Close()
+
+	history := &model.PublicHistory{Name: name, Entries: []model.PublicHistoryEntry{}}
+	started := len(next) == 0
+	for historyIterator.HasNext() {
+		modification, err := historyIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		if !started {
+			if modification.GetTxId() != next {
+				continue
+			}
+			started = true
+		}
+		if len(history.Entries) == cfg.MaxResults {
+			history.Next = modification.GetTxId()
+			break
+		}
+
+		timestamp := modification.GetTimestamp()
+		history.Entries = append(history.Entries, model.PublicHistoryEntry{
+			TxID:      modification.GetTxId(),
+			Timestamp: time.Unix(timestamp.GetSeconds(), int64(timestamp.GetNanos())).UTC().Format(time.RFC3339),
+			IsDelete:  modification.GetIsDelete(),
+		})
+	}
+	if !started {
+		return invalidInput(name, "unknown continuation token "+next)
+	}
+
+	historyJSONasBytes, err := json.Marshal(history)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(historyJSONasBytes)
+}
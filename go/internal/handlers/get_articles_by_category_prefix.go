@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlesByCategoryPrefix returns the articles of a category and of every category below
+// it, e.g. "media/journal" includes "media/journal/medical" but not "media/journalism". The
+// category~name index holds a composite key attribute per path segment, so a partial
+// composite key on the segments of the prefix scans the subtree.
+// ===========================================================================================
+func GetArticlesByCategoryPrefix(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 1, false)
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting category prefix")
+	}
+
+	prefix := args[0]
+	err := model.ValidateCategory("prefix", prefix, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(prefix, err.Error())
+	}
+
+	categoryResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.CategoryNameIndex, strings.Split(prefix, "/"))
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer categoryResultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for categoryResultsIterator.HasNext() {
+		responseRange, err := categoryResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		// the name is the last attribute of the category~name composite key
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		returnedArticleName := compositeKeyParts[len(compositeKeyParts)-1]
+
+		articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, returnedArticleName)
+		if err != nil {
+			return internalError(returnedArticleName, "Failed to get article:"+err.Error())
+		} else if articleAsBytes == nil {
+			// the index is stale, skip the entry rather than failing the whole query
+			continue
+		}
+
+		// an article named like a segment, e.g. "journal" in category "media", shares the
+		// key prefix of the subtree without being part of it
+		var article model.Article
+		err = json.Unmarshal(articleAsBytes, &article)
+		if err != nil {
+			return internalError(returnedArticleName, "Failed to decode JSON of: "+string(articleAsBytes))
+		}
+		if !inCategory(article.Category, prefix) {
+			continue
+		}
+
+		err = results.add(returnedArticleName, articleAsBytes)
+		if err != nil {
+			return internalError(returnedArticleName, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlesByCategoryPrefix returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlesByDocType returns all objects of a doc type. Articles are stored under their
+// bare names, which a range query with empty start and end keys returns without the index
+// entries. Objects of other doc types are found under their docType~name composite keys.
+// ===========================================================================================
+func GetArticlesByDocType(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 1, false)
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting docType")
+	}
+
+	docType, err := resolveDocType(cfg, args[0])
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	var resultsIterator shim.StateQueryIteratorInterface
+	if docType == model.DefaultDocType {
+		resultsIterator, err = stub.GetPrivateDataByRange(cfg.CollectionArticles, "", "")
+	} else {
+		resultsIterator, err = stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, docType, []string{})
+	}
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		name := queryResponse.Key
+		if docType != model.DefaultDocType {
+			_, compositeKeyParts, err := stub.SplitCompositeKey(queryResponse.Key)
+			if err != nil {
+				return errorResponse(err)
+			}
+			name = compositeKeyParts[0]
+		} else if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+
+		err = results.add(name, queryResponse.Value)
+		if err != nil {
+			return internalError(name, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlesByDocType returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"unicode/utf8"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlesByNamePrefix returns the articles whose name starts with the given prefix.
+// Names cannot contain utf8.MaxRune, so the range from the prefix to the prefix followed by
+// utf8.MaxRune holds exactly the names starting with the prefix. Soft-deleted articles
+// are left out unless the optional includeDeleted argument is true.
+// ===========================================================================================
+func GetArticlesByNamePrefix(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 1, false)
+
+	if len(args) != 1 && len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name prefix and an optional includeDeleted flag")
+	}
+
+	prefix := args[0]
+	err := model.ValidateName("prefix", &prefix, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(prefix, err.Error())
+	}
+
+	includeDeleted := false
+	if len(args) == 2 {
+		includeDeleted, err = parseIncludeDeleted(args[1])
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticles, prefix, prefix+string(utf8.MaxRune))
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		// skip index entries, they never start with a valid prefix but stay on the safe side
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+		if !includeDeleted && isTombstone(queryResponse.Value) {
+			continue
+		}
+
+		err = results.add(queryResponse.Key, queryResponse.Value)
+		if err != nil {
+			return internalError(queryResponse.Key, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlesByNamePrefix returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+func TestGetArticlesByNamePrefix(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{"getArticlesByNamePrefix": GetArticlesByNamePrefix})
+	for _, name := range []string{"JOUR-2024-01", "JOUR-2025-01", "JOUR-2024"} {
+		n.createArticle(t, articleJSON(name, "blue", 35, 0))
+	}
+
+	response := n.user1.Query("getArticlesByNamePrefix", "JOUR-2024-")
+	expectStatus(t, response, shim.OK)
+	var results []struct {
+		Key string `json:"Key"`
+	}
+	err := json.Unmarshal(response.Payload, &results)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", response.Payload, err)
+	}
+	if len(results) != 1 || results[0].Key != "JOUR-2024-01" {
+		t.Errorf("prefix JOUR-2024- matched %+v, expected JOUR-2024-01 only", results)
+	}
+
+	expectCode(t, n.user1.Query("getArticlesByNamePrefix", ""), CodeInvalidInput)
+	expectCode(t, n.user1.Query("getArticlesByNamePrefix", "JOUR\x00"), CodeInvalidInput)
+}
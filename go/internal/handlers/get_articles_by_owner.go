@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlesByOwner returns all articles of an owner, those it co-owns included, by
+// walking the owner~name index, which has an entry per co-owner.
+// The index only stores the key names, so each article is read back from the collection,
+// unless the optional keysOnly argument is true: the names are then returned straight from
+// the index, without reading the articles.
+// ===========================================================================================
+func GetArticlesByOwner(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 1, true)
+
+	if len(args) < 1 || len(args) > 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting owner name and an optional keysOnly flag")
+	}
+
+	owner := args[0]
+	err := model.ValidateName("owner", &owner, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(owner, err.Error())
+	}
+
+	keysOnly := false
+	if len(args) == 2 {
+		keysOnly, err = parseKeysOnly(args[1])
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+	if keysOnly && format == responseFormatCSV {
+		return invalidInput("", "keysOnly cannot be combined with the csv format")
+	}
+
+	ownerResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.OwnerNameIndex, []string{owner})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer ownerResultsIterator.Close()
+
+	// results is a JSON array containing QueryResults, or the CSV export
+	results := newQueryResultsBuilder(format)
+	for ownerResultsIterator.HasNext() {
+		responseRange, err := ownerResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		// get the owner and name from owner~name composite key
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		returnedArticleName := compositeKeyParts[1]
+
+		if keysOnly {
+			err = results.addKey(returnedArticleName)
+			if err != nil {
+				return internalError(returnedArticleName, err.Error())
+			}
+			continue
+		}
+
+		articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, returnedArticleName)
+		if err != nil {
+			return internalError(returnedArticleName, "Failed to get article:"+err.Error())
+		} else if articleAsBytes == nil {
+			// the index is stale, skip the entry rather than failing the whole query
+			continue
+		}
+
+		err = results.add(returnedArticleName, articleAsBytes)
+		if err != nil {
+			return internalError(returnedArticleName, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlesByOwner returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
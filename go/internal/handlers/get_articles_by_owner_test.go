@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// articlesOf returns the names of the articles getArticlesByOwner lists for the owner
+func (n *testNetwork) articlesOf(t *testing.T, client *testutil.Client, owner string) []string {
+	t.Helper()
+	response := client.Query("getArticlesByOwner", owner, "true")
+	expectStatus(t, response, shim.OK)
+	var names []string
+	err := json.Unmarshal(response.Payload, &names)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", response.Payload, err)
+	}
+	return names
+}
+
+func TestOwnerNameIndexFollowsTransfer(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"agreeToTransfer":    AgreeToTransfer,
+		"transferArticle":    TransferArticle,
+		"delete":             Delete,
+		"getArticlesByOwner": GetArticlesByOwner,
+	})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+	n.createArticle(t, articleJSON("article2", "red", 35, 0))
+	tomKey := compositeKey(t, model.OwnerNameIndex, "tom", "article1")
+	jerryKey := compositeKey(t, model.OwnerNameIndex, "jerry", "article1")
+	if n.PrivateData(model.DefaultCollectionArticles, tomKey) == nil {
+		t.Fatalf("initArticle did not index article1 under tom")
+	}
+
+	response := n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`))
+	expectStatus(t, response, shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+
+	if n.PrivateData(model.DefaultCollectionArticles, tomKey) != nil {
+		t.Errorf("transfer left article1 indexed under tom")
+	}
+	if n.PrivateData(model.DefaultCollectionArticles, jerryKey) == nil {
+		t.Errorf("transfer did not index article1 under jerry")
+	}
+	if names := n.articlesOf(t, n.user2, "tom"); len(names) != 1 || names[0] != "article2" {
+		t.Errorf("tom owns %v, expected article2", names)
+	}
+	if names := n.articlesOf(t, n.user2, "jerry"); len(names) != 1 || names[0] != "article1" {
+		t.Errorf("jerry owns %v, expected article1", names)
+	}
+
+	// delete removes the index entry of the owner
+	response = n.admin1.InvokeTransient("delete", testutil.Transient("article_delete", `{"name":"article2"}`))
+	expectStatus(t, response, shim.OK)
+	if n.PrivateData(model.DefaultCollectionArticles, compositeKey(t, model.OwnerNameIndex, "tom", "article2")) != nil {
+		t.Errorf("delete left article2 indexed under tom")
+	}
+	if names := n.articlesOf(t, n.user2, "tom"); len(names) != 0 {
+		t.Errorf("tom owns %v after the delete, expected nothing", names)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// priceRangeResult is a member of the result of getArticlesByPriceRange
+type priceRangeResult struct {
+	Name  string      `json:"name"`
+	Price model.Price `json:"price"`
+}
+
+// ===========================================================================================
+// GetArticlesByPriceRange returns the names and prices of the articles priced between min
+// and max, both inclusive, by walking the price~name index. Range queries cannot start at
+// a composite key, so the walk starts at the lowest price and stops past max.
+// ===========================================================================================
+func GetArticlesByPriceRange(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 2, false)
+
+	if len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting minimum and maximum price")
+	}
+
+	minPrice, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || minPrice < 0 || model.Price(minPrice) > model.MaxPrice {
+		return invalidInput("", fmt.Sprintf("minimum price must be an integer between 0 and %d", model.MaxPrice))
+	}
+	maxPrice, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || maxPrice < minPrice || model.Price(maxPrice) > model.MaxPrice {
+		return invalidInput("", fmt.Sprintf("maximum price must be an integer between the minimum price and %d", model.MaxPrice))
+	}
+
+	priceResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticlePrivateDetails, model.PriceNameIndex, []string{})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer priceResultsIterator.Close()
+
+	results := []priceRangeResult{}
+	for priceResultsIterator.HasNext() {
+		responseRange, err := priceResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		// get the price and name from price~name composite key
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		price, err := model.ParsePriceKey(compositeKeyParts[0])
+		if err != nil {
+			return internalError(compositeKeyParts[1], err.Error())
+		}
+		if price < model.Price(minPrice) {
+			continue
+		}
+		if price > model.Price(maxPrice) {
+			break
+		}
+		if isSelfTestKey(compositeKeyParts[1]) {
+			continue
+		}
+
+		// entries left behind by deletes on peers outside the collection have no details
+		detailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, compositeKeyParts[1])
+		if err != nil {
+			return errorResponse(err)
+		} else if detailsHash == nil {
+			continue
+		}
+
+		results = append(results, priceRangeResult{Name: compositeKeyParts[1], Price: price})
+	}
+
+	resultsJSONasBytes, err := json.Marshal(results)
+	if err != nil {
+		return errorResponse(err)
+	}
+	resultsJSONasBytes, err = listResponse(resultsJSONasBytes, len(results), false, "", format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	resultsJSONasBytes, err = formatPrices(cfg, resultsJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Debugf("getArticlesByPriceRange returned %d results", len(results))
+
+	return shim.Success(resultsJSONasBytes)
+}
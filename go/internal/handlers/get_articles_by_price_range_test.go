@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+)
+
+func TestGetArticlesByPriceRangeWidePrices(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{"getArticlesByPriceRange": GetArticlesByPriceRange})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	n.createArticle(t, articleJSON("article2", "blue", 35, 2147483648))
+	n.createArticle(t, articleJSON("article3", "blue", 35, 9007199254740993))
+
+	// the wide prices are indexed after the narrow ones, under the ':' prefix
+	for name, priceKey := range map[string]string{"article1": "000009900", "article2": ":000000002147483648", "article3": ":009007199254740993"} {
+		if n.PrivateData(model.DefaultCollectionArticlePrivateDetails, compositeKey(t, model.PriceNameIndex, priceKey, name)) == nil {
+			t.Errorf("%s is not indexed under the price %s", name, priceKey)
+		}
+	}
+	response := n.user1.Query("readArticlePrivateDetails", "article3")
+	expectStatus(t, response, shim.OK)
+	if !strings.Contains(string(response.Payload), `"price":9007199254740993,`) {
+		t.Errorf("article3 reads as %s, expected the price 9007199254740993", response.Payload)
+	}
+
+	for _, test := range []struct {
+		min      string
+		max      string
+		expected string
+	}{
+		{"0", "999999999999999999", `[{"name":"article1","price":9900},{"name":"article2","price":2147483648},{"name":"article3","price":9007199254740993}]`},
+		{"2147483648", "9007199254740993", `[{"name":"article2","price":2147483648},{"name":"article3","price":9007199254740993}]`},
+		{"2147483649", "9007199254740993", `[{"name":"article3","price":9007199254740993}]`},
+		{"9900", "9007199254740992", `[{"name":"article1","price":9900},{"name":"article2","price":2147483648}]`},
+		{"9007199254740994", "999999999999999999", `[]`},
+	} {
+		response := n.user1.Query("getArticlesByPriceRange", test.min, test.max)
+		expectStatus(t, response, shim.OK)
+		if string(response.Payload) != test.expected {
+			t.Errorf("prices %s to %s returned %s, expected %s", test.min, test.max, response.Payload, test.expected)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlesByRange performs a range query based on the start and end keys provided.
+// It returns at most maxResults articles, by default the configured maximum. In the v2
+// format, the envelope tells when the response is truncated and the key to continue from.
+// Soft-deleted articles are left out unless the optional includeDeleted argument is true.
+// With the optional keysOnly argument true, the results are the keys of the articles
+// alone, which spares encoding the records for clients that only sync keys.
+
+// Read-only function results are not typically submitted to ordering. If the read-only
+// results are submitted to ordering, or if the query is used in an update transaction
+// and submitted to ordering, then the committing peers will re-execute to guarantee that
+// result sets are stable between endorsement time and commit time. The transaction is
+// invalidated by the committing peers if the result set has changed between endorsement
+// time and commit time.
+// Therefore, range queries are a safe option for performing update transactions based on query results.
+// ===========================================================================================
+func GetArticlesByRange(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 2, true)
+
+	if len(args) < 2 || len(args) > 5 {
+		return invalidInput("", "Incorrect number of arguments. Expecting start and end key, an optional maximum number of results, an optional includeDeleted flag and an optional keysOnly flag")
+	}
+
+	startKey := args[0]
+	endKey := args[1]
+
+	maxResults := cfg.MaxResults
+	if len(args) >= 3 && len(args[2]) > 0 {
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n <= 0 || n > cfg.MaxResults {
+			return invalidInput("", fmt.Sprintf("maximum number of results must be an integer between 1 and %d", cfg.MaxResults))
+		}
+		maxResults = n
+	}
+
+	includeDeleted := false
+	if len(args) >= 4 && len(args[3]) > 0 {
+		var err error
+		includeDeleted, err = parseIncludeDeleted(args[3])
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	keysOnly := false
+	if len(args) == 5 {
+		var err error
+		keysOnly, err = parseKeysOnly(args[4])
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+	if keysOnly && format == responseFormatCSV {
+		return invalidInput("", "keysOnly cannot be combined with the csv format")
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticles, startKey, endKey)
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	// results is a JSON array containing QueryResults, or the CSV export
+	results := newQueryResultsBuilder(format)
+	truncated := false
+	lastKey := ""
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		// skip index entries, a range with an empty start key would include them
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+		if !includeDeleted && isTombstone(queryResponse.Value) {
+			txLogger(stub).Debugf("skipping tombstone %s", queryResponse.Key)
+			continue
+		}
+
+		// stop reading once the cap is hit, the key just read starts the next page
+		if results.len() == maxResults {
+			truncated = true
+			lastKey = queryResponse.Key
+			txLogger(stub).Debugf("truncated at %d results", maxResults)
+			break
+		}
+
+		if keysOnly {
+			err = results.addKey(queryResponse.Key)
+		} else {
+			err = results.add(queryResponse.Key, queryResponse.Value)
+		}
+		if err != nil {
+			return internalError(queryResponse.Key, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := listResponse(results.bytes(), results.len(), truncated, lastKey, format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlesByRange returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// rangeStub answers every range query of private data with the same synthetic articles,
+// so the benchmarks measure the handler rather than a ledger
+type rangeStub struct {
+	*shimtest.MockStub
+	kvs []*queryresult.KV
+}
+
+func (s *rangeStub) GetPrivateDataByRange(collection string, startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
+	return testutil.NewIterator(s.kvs), nil
+}
+
+// syntheticArticles returns count stored articles of about 330 bytes, sorted by key
+func syntheticArticles(tb testing.TB, count int) []*queryresult.KV {
+	tb.Helper()
+	kvs := make([]*queryresult.KV, count)
+	for i := range kvs {
+		article := &model.Article{
+			ObjectType:    model.DefaultDocType,
+			Name:          fmt.Sprintf("article%07d", i),
+			Color:         "blue",
+			Size:          35,
+			Owner:         "tom",
+			OwnerOrg:      org1,
+			Salt:          testSalt,
+			CreatedAt:     "2024-01-01T00:00:00Z",
+			UpdatedAt:     "2024-01-01T00:00:00Z",
+			Quantity:      1,
+			SchemaVersion: model.CurrentSchemaVersion,
+		}
+		record, err := model.MarshalCanonical(article)
+		if err != nil {
+			tb.Fatalf("failed to encode %s: %v", article.Name, err)
+		}
+		kvs[i] = &queryresult.KV{Key: article.Name, Value: record}
+	}
+	return kvs
+}
+
+// benchmarkGetArticlesByRange runs getArticlesByRange over count articles with the
+// arguments after the start and end key, all of them fitting in one page
+func benchmarkGetArticlesByRange(b *testing.B, count int, args ...string) {
+	stub := &rangeStub{MockStub: shimtest.NewMockStub("articles", nil), kvs: syntheticArticles(b, count)}
+	cfg := model.DefaultConfig()
+	cfg.MaxResults = count
+	args = append([]string{"", ""}, args...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		response := GetArticlesByRange(stub, cfg, args)
+		if response.Status != shim.OK {
+			b.Fatalf("getArticlesByRange failed: %s", response.Message)
+		}
+	}
+}
+
+func Benchmark_getArticlesByRange_1k(b *testing.B) {
+	benchmarkGetArticlesByRange(b, 1000)
+}
+
+func Benchmark_getArticlesByRange_10k(b *testing.B) {
+	benchmarkGetArticlesByRange(b, 10000)
+}
+
+func Benchmark_getArticlesByRange_100k(b *testing.B) {
+	benchmarkGetArticlesByRange(b, 100000)
+}
+
+// Benchmark_getArticlesByRange_capped reads the first 100 of 1k and of 100k articles,
+// which allocates the same whatever the size of the collection
+func Benchmark_getArticlesByRange_capped(b *testing.B) {
+	for _, count := range []int{1000, 100000} {
+		b.Run(fmt.Sprintf("%dk", count/1000), func(b *testing.B) {
+			benchmarkGetArticlesByRange(b, count, "100")
+		})
+	}
+}
+
+// Benchmark_getArticlesByRange_keysOnly lists 10k articles with their records and as keys only
+func Benchmark_getArticlesByRange_keysOnly(b *testing.B) {
+	b.Run("full", func(b *testing.B) {
+		benchmarkGetArticlesByRange(b, 10000)
+	})
+	b.Run("keysOnly", func(b *testing.B) {
+		benchmarkGetArticlesByRange(b, 10000, "", "", "true")
+	})
+}
+
+func TestGetArticlesByRangeKeysOnly(t *testing.T) {
+	cfg := model.DefaultConfig()
+	stub := &rangeStub{MockStub: shimtest.NewMockStub("articles", nil), kvs: syntheticArticles(t, 1000)}
+	full := GetArticlesByRange(stub, cfg, []string{"", ""})
+	expectStatus(t, full, shim.OK)
+	keysOnly := GetArticlesByRange(stub, cfg, []string{"", "", "", "", "true"})
+	expectStatus(t, keysOnly, shim.OK)
+
+	var keys []string
+	err := json.Unmarshal(keysOnly.Payload, &keys)
+	if err != nil {
+		t.Fatalf("failed to decode the keys: %v", err)
+	}
+	if len(keys) != 1000 || keys[0] != "article0000000" || keys[999] != "article0000999" {
+		t.Errorf("keysOnly returned %d keys", len(keys))
+	}
+	if len(keysOnly.Payload)*10 > len(full.Payload) {
+		t.Errorf("keysOnly response has %d bytes, the full one %d", len(keysOnly.Payload), len(full.Payload))
+	}
+}
+
+func TestGetArticlesByRangeCap(t *testing.T) {
+	cfg := model.DefaultConfig()
+	allocs := map[int]float64{}
+	for _, count := range []int{1000, 20000} {
+		stub := &rangeStub{MockStub: shimtest.NewMockStub("articles", nil), kvs: syntheticArticles(t, count)}
+		response := GetArticlesByRange(stub, cfg, []string{"", "", "100", "v2"})
+		expectStatus(t, response, shim.OK)
+		var page struct {
+			Results   []json.RawMessage `json:"results"`
+			Truncated bool              `json:"truncated"`
+			LastKey   string            `json:"lastKey"`
+		}
+		err := json.Unmarshal(response.Payload, &page)
+		if err != nil {
+			t.Fatalf("failed to decode the response: %v", err)
+		}
+		if len(page.Results) != 100 || !page.Truncated || page.LastKey != "article0000100" {
+			t.Errorf("%d articles capped at 100 returned %d results, truncated %v at %s", count, len(page.Results), page.Truncated, page.LastKey)
+		}
+
+		allocs[count] = testing.AllocsPerRun(10, func() {
+			GetArticlesByRange(stub, cfg, []string{"", "", "100"})
+		})
+	}
+	if allocs[20000] > allocs[1000] {
+		t.Errorf("capped query allocates %v times on 20k articles and %v times on 1k", allocs[20000], allocs[1000])
+	}
+}
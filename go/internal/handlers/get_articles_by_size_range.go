@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlesBySizeRange returns the articles with a size between minSize and maxSize, both
+// inclusive, by walking the size~name index. Range queries cannot start at a composite key,
+// so the walk starts at the smallest size and stops past maxSize.
+// ===========================================================================================
+func GetArticlesBySizeRange(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 2, false)
+
+	if len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting minimum and maximum size")
+	}
+
+	minSize, err := strconv.Atoi(args[0])
+	if err != nil || minSize < 0 || minSize > model.MaxSize {
+		return invalidInput("", fmt.Sprintf("minimum size must be an integer between 0 and %d", model.MaxSize))
+	}
+	maxSize, err := strconv.Atoi(args[1])
+	if err != nil || maxSize < minSize || maxSize > model.MaxSize {
+		return invalidInput("", fmt.Sprintf("maximum size must be an integer between the minimum size and %d", model.MaxSize))
+	}
+
+	sizeResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.SizeNameIndex, []string{})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer sizeResultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for sizeResultsIterator.HasNext() {
+		responseRange, err := sizeResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		// get the size and name from size~name composite key
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		size, err := strconv.Atoi(compositeKeyParts[0])
+		if err != nil {
+			return internalError(compositeKeyParts[1], "invalid size in index key: "+compositeKeyParts[0])
+		}
+		if size < minSize {
+			continue
+		}
+		if size > maxSize {
+			break
+		}
+		returnedArticleName := compositeKeyParts[1]
+
+		articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, returnedArticleName)
+		if err != nil {
+			return internalError(returnedArticleName, "Failed to get article:"+err.Error())
+		} else if articleAsBytes == nil {
+			// the index is stale, skip the entry rather than failing the whole query
+			continue
+		}
+
+		err = results.add(returnedArticleName, articleAsBytes)
+		if err != nil {
+			return internalError(returnedArticleName, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlesBySizeRange returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+)
+
+// articlesBySize returns the names getArticlesBySizeRange lists between the sizes
+func (n *testNetwork) articlesBySize(t *testing.T, minSize int, maxSize int) []string {
+	t.Helper()
+	response := n.user1.Query("getArticlesBySizeRange", strconv.Itoa(minSize), strconv.Itoa(maxSize))
+	expectStatus(t, response, shim.OK)
+	var results []rangeRecord
+	err := json.Unmarshal(response.Payload, &results)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", response.Payload, err)
+	}
+	names := []string{}
+	for _, result := range results {
+		names = append(names, result.Key)
+	}
+	return names
+}
+
+func TestArticlesBySizeRange(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{"getArticlesBySizeRange": GetArticlesBySizeRange})
+	for _, size := range []int{1, 9, 10, 100, model.MaxSize} {
+		n.createArticle(t, articleJSON(fmt.Sprintf("size%d", size), "blue", size, 0))
+	}
+
+	for _, test := range []struct {
+		minSize  int
+		maxSize  int
+		expected []string
+	}{
+		{0, model.MaxSize, []string{"size1", "size9", "size10", "size100", fmt.Sprintf("size%d", model.MaxSize)}},
+		{1, 1, []string{"size1"}},
+		{9, 10, []string{"size9", "size10"}},
+		{10, 99, []string{"size10"}},
+		{11, 99, []string{}},
+		{101, model.MaxSize - 1, []string{}},
+		{model.MaxSize, model.MaxSize, []string{fmt.Sprintf("size%d", model.MaxSize)}},
+	} {
+		if names := n.articlesBySize(t, test.minSize, test.maxSize); !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("sizes %d to %d returned %v, expected %v", test.minSize, test.maxSize, names, test.expected)
+		}
+	}
+	expectCode(t, n.user1.Query("getArticlesBySizeRange", "10", "9"), CodeInvalidInput)
+	expectCode(t, n.user1.Query("getArticlesBySizeRange", "0", strconv.Itoa(model.MaxSize+1)), CodeInvalidInput)
+
+	// an upsert with a new size moves the index entry
+	n.createArticle(t, `{"name":"size9","color":"blue","size":50,"owner":"tom","salt":"`+testSalt+`","upsert":true}`)
+	if n.PrivateData(model.DefaultCollectionArticles, compositeKey(t, model.SizeNameIndex, fmt.Sprintf("%0*d", model.SizeWidth, 9), "size9")) != nil {
+		t.Errorf("update left the size~name entry of the old size")
+	}
+	if names := n.articlesBySize(t, 9, 9); len(names) != 0 {
+		t.Errorf("size 9 returned %v after the update, expected nothing", names)
+	}
+	if names := n.articlesBySize(t, 50, 50); !reflect.DeepEqual(names, []string{"size9"}) {
+		t.Errorf("size 50 returned %v after the update, expected size9", names)
+	}
+}
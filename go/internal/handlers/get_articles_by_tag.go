@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlesByTag returns all articles with a tag by walking the tag~name index, or an
+// empty array when no article has the tag. The index only stores the key names, so each
+// article is read back from the collection.
+// ===========================================================================================
+func GetArticlesByTag(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 1, false)
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting tag")
+	}
+
+	tag := args[0]
+	err := model.ValidateTag(tag, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(tag, err.Error())
+	}
+
+	tagResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.TagNameIndex, []string{tag})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer tagResultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for tagResultsIterator.HasNext() {
+		responseRange, err := tagResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		// get the tag and name from tag~name composite key
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		returnedArticleName := compositeKeyParts[1]
+
+		articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, returnedArticleName)
+		if err != nil {
+			return internalError(returnedArticleName, "Failed to get article:"+err.Error())
+		} else if articleAsBytes == nil {
+			// the index is stale, skip the entry rather than failing the whole query
+			continue
+		}
+
+		err = results.add(returnedArticleName, articleAsBytes)
+		if err != nil {
+			return internalError(returnedArticleName, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlesByTag returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlesForSale returns all articles listed for sale by walking the forsale~name index.
+// Articles whose owner made the asking price visible also carry the price of their private
+// details, as far as the peer is a member of collectionArticlePrivateDetails.
+// ===========================================================================================
+func GetArticlesForSale(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 0, false)
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting no arguments")
+	}
+
+	forSaleResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.ForSaleIndex, []string{})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer forSaleResultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for forSaleResultsIterator.HasNext() {
+		responseRange, err := forSaleResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		// get the name from forsale~name composite key
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		returnedArticleName := compositeKeyParts[0]
+
+		articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, returnedArticleName)
+		if err != nil {
+			return internalError(returnedArticleName, "Failed to get article:"+err.Error())
+		} else if articleAsBytes == nil {
+			// the index is stale, skip the entry rather than failing the whole query
+			continue
+		}
+
+		var article model.Article
+		err = json.Unmarshal(articleAsBytes, &article)
+		if err != nil {
+			return internalError(returnedArticleName, "Failed to decode JSON of: "+string(articleAsBytes))
+		}
+
+		result := &forSaleQueryResult{Key: returnedArticleName, Record: json.RawMessage(articleAsBytes)}
+		if article.AskingPriceVisible {
+			// peers outside collectionArticlePrivateDetails cannot read the price, list the article without it
+			detailsAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, returnedArticleName)
+			if err == nil && detailsAsBytes != nil {
+				var details model.ArticlePrivateDetails
+				err = json.Unmarshal(detailsAsBytes, &details)
+				if err != nil {
+					return internalError(returnedArticleName, "Failed to decode JSON of: "+string(detailsAsBytes))
+				}
+				result.AskingPrice = &details.Price
+			}
+		}
+		err = results.addResult(result)
+		if err != nil {
+			return internalError(returnedArticleName, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	resultsJSONasBytes, err = formatPrices(cfg, resultsJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlesForSale returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
+
+// forSaleQueryResult is a member of the result of getArticlesForSale
+type forSaleQueryResult struct {
+	Key         string          `json:"Key"`
+	Record      json.RawMessage `json:"Record"`
+	AskingPrice *model.Price    `json:"AskingPrice,omitempty"`
+}
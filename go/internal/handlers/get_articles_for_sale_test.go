@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// articlesForSale returns the listed names with the asking price getArticlesForSale shows
+// to the client, empty when it is hidden
+func (n *testNetwork) articlesForSale(t *testing.T, client *testutil.Client) map[string]string {
+	t.Helper()
+	response := client.Query("getArticlesForSale")
+	expectStatus(t, response, shim.OK)
+	var results []struct {
+		Key         string          `json:"Key"`
+		AskingPrice json.RawMessage `json:"AskingPrice"`
+	}
+	err := json.Unmarshal(response.Payload, &results)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", response.Payload, err)
+	}
+	listed := map[string]string{}
+	for _, result := range results {
+		listed[result.Key] = string(result.AskingPrice)
+	}
+	return listed
+}
+
+func TestArticlesForSaleListing(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"setArticleForSale":  SetArticleForSale,
+		"getArticlesForSale": GetArticlesForSale,
+		"agreeToTransfer":    AgreeToTransfer,
+		"transferArticle":    TransferArticle,
+	})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	n.createArticle(t, articleJSON("article2", "red", 35, 5000))
+	if listed := n.articlesForSale(t, n.user1); len(listed) != 0 {
+		t.Fatalf("new articles are listed: %v", listed)
+	}
+
+	// listing
+	response := n.user1.InvokeTransient("setArticleForSale", testutil.Transient("article_sale", `{"name":"article1","forSale":true,"askingPriceVisible":true}`))
+	expectStatus(t, response, shim.OK)
+	response = n.user1.InvokeTransient("setArticleForSale", testutil.Transient("article_sale", `{"name":"article2","forSale":true}`))
+	expectStatus(t, response, shim.OK)
+	listed := n.articlesForSale(t, n.user1)
+	if len(listed) != 2 || listed["article1"] == "" || listed["article2"] != "" {
+		t.Errorf("listed for Org1 %v, expected article1 with its price and article2 without", listed)
+	}
+	// Org2 cannot read the price
+	listed = n.articlesForSale(t, n.user2)
+	if len(listed) != 2 || listed["article1"] != "" {
+		t.Errorf("listed for Org2 %v, expected both articles without a price", listed)
+	}
+	expectCode(t, n.user2.InvokeTransient("setArticleForSale", testutil.Transient("article_sale", `{"name":"article2","forSale":false}`)), CodeAccessDenied)
+
+	// delisting
+	response = n.user1.InvokeTransient("setArticleForSale", testutil.Transient("article_sale", `{"name":"article2","forSale":false}`))
+	expectStatus(t, response, shim.OK)
+	if n.PrivateData(model.DefaultCollectionArticles, compositeKey(t, model.ForSaleIndex, "article2")) != nil {
+		t.Errorf("delisting left the forsale~name entry of article2")
+	}
+	if listed := n.articlesForSale(t, n.user1); len(listed) != 1 || listed["article1"] == "" {
+		t.Errorf("listed %v after delisting article2, expected article1", listed)
+	}
+
+	// transfer
+	response = n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1","price":9900,"currency":"EUR"}`))
+	expectStatus(t, response, shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+	if n.PrivateData(model.DefaultCollectionArticles, compositeKey(t, model.ForSaleIndex, "article1")) != nil {
+		t.Errorf("transfer left the forsale~name entry of article1")
+	}
+	if article := n.readArticle(t, "article1"); article.ForSale || article.AskingPriceVisible {
+		t.Errorf("transferred article is still for sale")
+	}
+	if listed := n.articlesForSale(t, n.user1); len(listed) != 0 {
+		t.Errorf("listed %v after the transfer, expected nothing", listed)
+	}
+}
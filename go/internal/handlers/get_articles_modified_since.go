@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetArticlesModifiedSince returns the articles whose last change happened at or after the
+// given RFC3339 timestamp. It scans all articles of the collection, so it is meant for
+// occasional synchronization rather than frequent polling. Articles written before the
+// timestamps were recorded have an empty updatedAt and are treated as never modified.
+// ===========================================================================================
+func GetArticlesModifiedSince(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 1, false)
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting an RFC3339 timestamp")
+	}
+
+	since, err := time.Parse(time.RFC3339, args[0])
+	if err != nil {
+		return invalidInput("", "timestamp must be in RFC3339 format: "+err.Error())
+	}
+
+	// an empty start and end key scans every article, composite key index entries are not part of the range
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticles, "", "")
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var articleRecord model.Article
+		err = json.Unmarshal(queryResponse.Value, &articleRecord)
+		if err != nil || len(articleRecord.UpdatedAt) == 0 {
+			continue
+		}
+		updatedAt, err := time.Parse(time.RFC3339, articleRecord.UpdatedAt)
+		if err != nil || updatedAt.Before(since) {
+			continue
+		}
+
+		err = results.add(queryResponse.Key, queryResponse.Value)
+		if err != nil {
+			return internalError(queryResponse.Key, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("getArticlesModifiedSince returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetCertifications returns the certifications of an article, one per certifier org
+// ordered by MSP ID
+// ===========================================================================================
+func GetCertifications(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.CertificationIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	certifications := []model.Certification{}
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var certification model.Certification
+		err = json.Unmarshal(responseRange.Value, &certification)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		certifications = append(certifications, certification)
+	}
+
+	certificationsJSONasBytes, err := json.Marshal(certifications)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(certificationsJSONasBytes)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetCollectionSummary returns the number of articles, per color and listed for sale, for
+// monitoring. The totals come from a scan of the articles collection that skips the index
+// entries, the objects of other doc types and the soft-deleted articles. The scan stops after
+// the number of records given as optional argument, DefaultScanLimit by default, and then
+// flags the totals as partial.
+// ===========================================================================================
+func GetCollectionSummary(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) > 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting an optional scan limit")
+	}
+
+	scanLimit := model.DefaultScanLimit
+	if len(args) == 1 && len(args[0]) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return invalidInput("", "scan limit must be a positive integer")
+		}
+		scanLimit = n
+	}
+
+	// an empty start and end key cover all articles, the composite keys are skipped below
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticles, "", "")
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	summary := model.CollectionSummary{Colors: map[string]int{}}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+		if summary.Scanned == scanLimit {
+			summary.ScanLimitReached = true
+			break
+		}
+		summary.Scanned++
+		summary.LastKey = queryResponse.Key
+
+		var article model.Article
+		err = json.Unmarshal(queryResponse.Value, &article)
+		if err != nil || article.ObjectType != model.DefaultDocType || article.Deleted {
+			continue
+		}
+		summary.TotalArticles++
+		summary.Colors[article.Color]++
+		if article.ForSale {
+			summary.ForSale++
+		}
+	}
+	if !summary.ScanLimitReached {
+		summary.LastKey = ""
+	}
+
+	summaryJSONasBytes, err := json.Marshal(&summary)
+	if err != nil {
+		return errorResponse(err)
+	}
+	return shim.Success(summaryJSONasBytes)
+}
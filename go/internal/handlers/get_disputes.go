@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetDisputes returns the disputes of an article, open and resolved, in the order they
+// were raised
+// ===========================================================================================
+func GetDisputes(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.DisputeIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	disputes := []model.Dispute{}
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var dispute model.Dispute
+		err = json.Unmarshal(responseRange.Value, &dispute)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		disputes = append(disputes, dispute)
+	}
+
+	disputesJSONasBytes, err := json.Marshal(disputes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(disputesJSONasBytes)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetLeaseHistory returns the starts and ends of the leases of an article, oldest first,
+// as kept under lease~name~seq keys by leaseArticle, endLease and transferArticle. A lease
+// that ran until its end date has no end record.
+// ===========================================================================================
+func GetLeaseHistory(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	leaseResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.LeaseIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer leaseResultsIterator.Close()
+
+	history := []model.LeaseRecord{}
+	for leaseResultsIterator.HasNext() {
+		responseRange, err := leaseResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var leaseRecord model.LeaseRecord
+		err = json.Unmarshal(responseRange.Value, &leaseRecord)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		history = append(history, leaseRecord)
+	}
+
+	historyJSONasBytes, err := json.Marshal(history)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(historyJSONasBytes)
+}
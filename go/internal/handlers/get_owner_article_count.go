@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================================================
+// GetOwnerArticleCount returns the number of articles of an owner, soft-deleted ones
+// excluded, from the counters maintained as articles are created, transferred and
+// deleted, without walking the owner~name index
+// ===============================================================================
+func GetOwnerArticleCount(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting owner")
+	}
+
+	owner := args[0]
+	err := model.ValidateName("owner", &owner, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	count, err := getOwnerCount(stub, cfg, owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	countJSONasBytes, err := json.Marshal(&model.OwnerArticleCount{Owner: owner, Count: count})
+	if err != nil {
+		return errorResponse(err)
+	}
+	return shim.Success(countJSONasBytes)
+}
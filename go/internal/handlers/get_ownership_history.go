@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetOwnershipHistory returns the changes of owner of an article, oldest first. Private data
+// has no GetHistoryForKey, so transferArticle keeps an explicit log under history~name~seq
+// keys whose zero-padded sequence numbers sort in the order the transfers happened.
+// ===========================================================================================
+func GetOwnershipHistory(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	historyResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.HistoryIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer historyResultsIterator.Close()
+
+	history := []model.OwnershipRecord{}
+	for historyResultsIterator.HasNext() {
+		responseRange, err := historyResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var ownershipRecord model.OwnershipRecord
+		err = json.Unmarshal(responseRange.Value, &ownershipRecord)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		history = append(history, ownershipRecord)
+	}
+
+	historyJSONasBytes, err := json.Marshal(history)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(historyJSONasBytes)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetPriceHistory returns the changes of price of an article, oldest first. The records
+// are kept by updateArticlePrice in collectionArticlePrivateDetails under
+// priceHistory~name~seq keys, so only members of that collection can read them.
+// ===========================================================================================
+func GetPriceHistory(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	priceHistoryResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticlePrivateDetails, model.PriceHistoryIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer priceHistoryResultsIterator.Close()
+
+	history := []model.PriceRecord{}
+	for priceHistoryResultsIterator.HasNext() {
+		responseRange, err := priceHistoryResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var priceRecord model.PriceRecord
+		err = json.Unmarshal(responseRange.Value, &priceRecord)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		history = append(history, priceRecord)
+	}
+
+	historyJSONasBytes, err := json.Marshal(history)
+	if err != nil {
+		return errorResponse(err)
+	}
+	historyJSONasBytes, err = formatPrices(cfg, historyJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(historyJSONasBytes)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+func TestPriceHistoryOrder(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"updateArticlePrice": UpdateArticlePrice,
+		"getPriceHistory":    GetPriceHistory,
+	})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 100))
+
+	prices := []model.Price{150, 120, 990, 130, 100}
+	var txIDs []string
+	for _, price := range prices {
+		tx := n.user1.Submit(testutil.Invocation{
+			Function:  "updateArticlePrice",
+			Transient: testutil.Transient("article_price", `{"name":"article1","price":`+strconv.Itoa(int(price))+`,"currency":"EUR"}`),
+		})
+		expectStatus(t, tx.Response, shim.OK)
+		txIDs = append(txIDs, tx.ID)
+	}
+
+	response := n.user1.Query("getPriceHistory", "article1")
+	expectStatus(t, response, shim.OK)
+	var history []model.PriceRecord
+	err := json.Unmarshal(response.Payload, &history)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", response.Payload, err)
+	}
+	if len(history) != len(prices) {
+		t.Fatalf("price history has %d records, expected %d: %s", len(history), len(prices), response.Payload)
+	}
+	oldPrice := model.Price(100)
+	for i, record := range history {
+		if record.OldPrice != oldPrice || record.NewPrice != prices[i] || record.TxID != txIDs[i] {
+			t.Errorf("record %d changed the price from %d to %d in %s, expected from %d to %d in %s", i, record.OldPrice, record.NewPrice, record.TxID, oldPrice, prices[i], txIDs[i])
+		}
+		if i > 0 && record.Timestamp <= history[i-1].Timestamp {
+			t.Errorf("record %d at %s is not later than the one before at %s", i, record.Timestamp, history[i-1].Timestamp)
+		}
+		oldPrice = prices[i]
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetPriceStatistics returns the count, minimum, maximum, sum and average of the prices of
+// all articles, so the prices do not have to leave the peer one by one. Records that cannot
+// be decoded as article private details are counted as skipped instead of failing the query.
+// Prices in different currencies are not added up: the optional argument restricts the
+// statistics to one currency, "unknown" for older records, and without it the query
+// fails when the articles are priced in more than one currency.
+// ===========================================================================================
+func GetPriceStatistics(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) > 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting an optional currency")
+	}
+	currency := ""
+	if len(args) == 1 {
+		currency = args[0]
+		if currency != model.UnknownCurrency {
+			err := model.ValidateCurrency("currency", &currency)
+			if err != nil {
+				return invalidInput("", err.Error())
+			}
+		}
+	}
+
+	// an empty start and end key cover all articles but none of the composite keys
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticlePrivateDetails, "", "")
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	stats := model.PriceStatistics{}
+	var min, max, sum model.Price
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+
+		var privateDetails model.ArticlePrivateDetails
+		err = json.Unmarshal(queryResponse.Value, &privateDetails)
+		if err != nil || privateDetails.ObjectType != "articlePrivateDetails" {
+			txLogger(stub).Warningf("skipping malformed record %s", queryResponse.Key)
+			stats.Skipped++
+			continue
+		}
+
+		detailsCurrency := model.CurrencyOf(&privateDetails)
+		if len(currency) != 0 && detailsCurrency != currency {
+			continue
+		}
+		if stats.Count > 0 && detailsCurrency != stats.Currency {
+			return invalidInput("", "articles are priced in more than one currency ("+stats.Currency+", "+detailsCurrency+"), pass a currency to get the statistics of")
+		}
+		stats.Currency = detailsCurrency
+
+		if stats.Count == 0 || privateDetails.Price < min {
+			min = privateDetails.Price
+		}
+		if stats.Count == 0 || privateDetails.Price > max {
+			max = privateDetails.Price
+		}
+		if sum > math.MaxInt64-privateDetails.Price {
+			return internalError("", "the sum of the prices overflows a 64-bit integer")
+		}
+		sum += privateDetails.Price
+		stats.Count++
+	}
+
+	if stats.Count > 0 {
+		avg := float64(sum) / float64(stats.Count)
+		stats.Min = &min
+		stats.Max = &max
+		stats.Sum = &sum
+		stats.Avg = &avg
+	}
+
+	statsJSONasBytes, err := json.Marshal(&stats)
+	if err != nil {
+		return errorResponse(err)
+	}
+	statsJSONasBytes, err = formatPrices(cfg, statsJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(statsJSONasBytes)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// principalClassifications and mspRoles name the enum values of the MSP protos
+var (
+	principalClassifications = map[msp.MSPPrincipal_Classification]string{
+		msp.MSPPrincipal_ROLE:              "ROLE",
+		msp.MSPPrincipal_ORGANIZATION_UNIT: "ORGANIZATION_UNIT",
+		msp.MSPPrincipal_IDENTITY:          "IDENTITY",
+	}
+	mspRoles = map[msp.MSPRole_MSPRoleType]string{
+		msp.MSPRole_MEMBER: "MEMBER",
+		msp.MSPRole_ADMIN:  "ADMIN",
+		msp.MSPRole_CLIENT: "CLIENT",
+		msp.MSPRole_PEER:   "PEER",
+	}
+)
+
+// ===========================================================================================
+// GetPrivateDataValidationParameter returns the key-level endorsement policy of an article
+// in one of the two collections, given by its configured name. The SignaturePolicyEnvelope
+// is decoded into its principals and rule, with the rule also written as an expression.
+// A key without a key-level policy is not an error, the response then has policySet false.
+// ===========================================================================================
+func GetPrivateDataValidationParameter(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article and the name of the collection")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	collection := args[1]
+	if collection != cfg.CollectionArticles && collection != cfg.CollectionArticlePrivateDetails {
+		return invalidInput(name, fmt.Sprintf("collection must be %s or %s, got %s", cfg.CollectionArticles, cfg.CollectionArticlePrivateDetails, collection))
+	}
+
+	policyBytes, err := stub.GetPrivateDataValidationParameter(collection, name)
+	if err != nil {
+		return internalError(name, "Failed to get validation parameter for "+name+": "+err.Error())
+	}
+
+	parameter := &model.ValidationParameter{Name: name, Collection: collection}
+	if len(policyBytes) == 0 {
+		parameter.Message = "no key-level policy set"
+	} else {
+		envelope := &common.SignaturePolicyEnvelope{}
+		err = proto.Unmarshal(policyBytes, envelope)
+		if err != nil {
+			return internalError(name, "Failed to decode validation parameter for "+name+": "+err.Error())
+		}
+		parameter.PolicySet = true
+		parameter.Version = int(envelope.GetVersion())
+		parameter.Principals = make([]model.PolicyPrincipal, 0, len(envelope.GetIdentities()))
+		for _, identity := range envelope.GetIdentities() {
+			principal, err := decodePolicyPrincipal(identity)
+			if err != nil {
+				return internalError(name, "Failed to decode validation parameter for "+name+": "+err.Error())
+			}
+			parameter.Principals = append(parameter.Principals, principal)
+		}
+		if envelope.GetRule() != nil {
+			parameter.Rule = decodePolicyRule(envelope.GetRule())
+			parameter.Expression = policyExpression(parameter.Rule, parameter.Principals)
+		}
+	}
+
+	parameterJSONasBytes, err := json.Marshal(parameter)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(parameterJSONasBytes)
+}
+
+// decodePolicyPrincipal decodes a principal of a signature policy, the MSP ID and role
+// of ROLE principals included
+func decodePolicyPrincipal(identity *msp.MSPPrincipal) (model.PolicyPrincipal, error) {
+	classification, ok := principalClassifications[identity.GetPrincipalClassification()]
+	if !ok {
+		classification = fmt.Sprintf("UNKNOWN(%d)", identity.GetPrincipalClassification())
+	}
+	principal := model.PolicyPrincipal{Classification: classification}
+	if identity.GetPrincipalClassification() != msp.MSPPrincipal_ROLE {
+		return principal, nil
+	}
+
+	role := &msp.MSPRole{}
+	err := proto.Unmarshal(identity.GetPrincipal(), role)
+	if err != nil {
+		return principal, fmt.Errorf("invalid MSP role: %v", err)
+	}
+	principal.MSPID = role.GetMspIdentifier()
+	principal.Role, ok = mspRoles[role.GetRole()]
+	if !ok {
+		principal.Role = fmt.Sprintf("UNKNOWN(%d)", role.GetRole())
+	}
+	return principal, nil
+}
+
+// decodePolicyRule decodes a rule of a signature policy and its nested rules
+func decodePolicyRule(rule *common.SignaturePolicy) *model.PolicyRule {
+	nOutOf := rule.GetNOutOf()
+	if nOutOf == nil {
+		signedBy := int(rule.GetSignedBy())
+		return &model.PolicyRule{SignedBy: &signedBy}
+	}
+	n := int(nOutOf.GetN())
+	decoded := &model.PolicyRule{N: &n, Rules: make([]model.PolicyRule, 0, len(nOutOf.GetRules()))}
+	for _, nested := range nOutOf.GetRules() {
+		decoded.Rules = append(decoded.Rules, *decodePolicyRule(nested))
+	}
+	return decoded
+}
+
+// policyExpression writes a rule in the syntax of the peer CLI, e.g.
+// OutOf(1, 'Org1MSP.peer'). Principals other than roles are written by classification.
+func policyExpression(rule *model.PolicyRule, principals []model.PolicyPrincipal) string {
+	if rule.SignedBy != nil {
+		if *rule.SignedBy < 0 || *rule.SignedBy >= len(principals) {
+			return fmt.Sprintf("'principal %d'", *rule.SignedBy)
+		}
+		principal := principals[*rule.SignedBy]
+		if len(principal.MSPID) == 0 {
+			return fmt.Sprintf("'%s principal %d'", principal.Classification, *rule.SignedBy)
+		}
+		return fmt.Sprintf("'%s.%s'", principal.MSPID, strings.ToLower(principal.Role))
+	}
+	operands := make([]string, 0, len(rule.Rules))
+	for i := range rule.Rules {
+		operands = append(operands, policyExpression(&rule.Rules[i], principals))
+	}
+	return fmt.Sprintf("OutOf(%d, %s)", *rule.N, strings.Join(operands, ", "))
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GetPublicAuditTrail returns the public audit records of an article, oldest first. Any
+// member of the channel can read them, also without access to the collections. The keys
+// sort by transaction ID rather than by time, so the records are sorted by timestamp.
+// Each record is written once, the history of its key would add nothing.
+// ===========================================================================================
+func GetPublicAuditTrail(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	resultsIterator, err := stub.GetStateByPartialCompositeKey(model.AuditIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	records := []model.PublicAuditRecord{}
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var record model.PublicAuditRecord
+		err = json.Unmarshal(responseRange.Value, &record)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		records = append(records, record)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp < records[j].Timestamp
+	})
+
+	recordsJSONasBytes, err := json.Marshal(records)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(recordsJSONasBytes)
+}
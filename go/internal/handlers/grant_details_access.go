@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GrantDetailsAccess - let the clients of an org outside collectionArticlePrivateDetails
+// read the private details of an article until expiresAt, so a counterparty can confirm the
+// price without joining the collection. Only the owner org may grant access; a new grant
+// to the same org replaces the previous one. The grant is kept in the details collection.
+// ===========================================================================================
+func GrantDetailsAccess(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start grant details access")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	detailsAccessJsonBytes, ok := transMap["details_access"]
+	if !ok {
+		return invalidInput("", "details_access must be a key in the transient map")
+	}
+
+	if len(detailsAccessJsonBytes) == 0 {
+		return invalidInput("", "details_access value in the transient map must be a non-empty JSON string")
+	}
+
+	var detailsAccessInput model.DetailsAccessTransientInput
+	err = model.DecodeTransientInput("details_access", detailsAccessJsonBytes, &detailsAccessInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = detailsAccessInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(detailsAccessInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, detailsAccessInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	clientOrgID, err := verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if detailsAccessInput.GranteeMSP == clientOrgID {
+		return invalidInput(article.Name, "granteeMSP must be another org than the owner org "+clientOrgID)
+	}
+
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	expiresAt, _ := time.Parse(time.RFC3339, detailsAccessInput.ExpiresAt) //checked by Validate
+	if !expiresAt.After(now) {
+		return invalidInput(article.Name, "expiresAt field must be in the future")
+	}
+
+	grantJSONasBytes, err := model.MarshalCanonical(&model.DetailsAccessGrant{
+		ObjectType: "detailsAccess",
+		GranteeMSP: detailsAccessInput.GranteeMSP,
+		ExpiresAt:  detailsAccessInput.ExpiresAt,
+		GrantedBy:  clientOrgID,
+		GrantedAt:  now.Format(time.RFC3339),
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	detailsAccessKey, err := stub.CreateCompositeKey(model.DetailsAccessIndex, []string{article.Name, detailsAccessInput.GranteeMSP})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, detailsAccessKey, grantJSONasBytes)
+	if err != nil {
+		return internalError(article.Name, "Failed to put details access: "+err.Error())
+	}
+
+	txLogger(stub).Infof("end grantDetailsAccess (success)")
+	return shim.Success(nil)
+}
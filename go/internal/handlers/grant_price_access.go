@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// GrantPriceAccess - let another client read the private details of a article. Only the
+// client that created the details may grant access, by the enrollment ID of the other
+// client, which whoAmI reports.
+// ===========================================================================================
+func GrantPriceAccess(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start grant price access")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	priceAccessJsonBytes, ok := transMap["price_access"]
+	if !ok {
+		return invalidInput("", "price_access must be a key in the transient map")
+	}
+
+	if len(priceAccessJsonBytes) == 0 {
+		return invalidInput("", "price_access value in the transient map must be a non-empty JSON string")
+	}
+
+	var priceAccessInput model.PriceAccessTransientInput
+	err = model.DecodeTransientInput("price_access", priceAccessJsonBytes, &priceAccessInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = priceAccessInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(priceAccessInput.Name, err.Error())
+	}
+
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, priceAccessInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if len(privateDetails.CreatorID) == 0 {
+		return invalidInput(privateDetails.Name, "the private details of "+privateDetails.Name+" have no creator, every member of the collection may read them")
+	}
+
+	clientID, err := cid.GetID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client ID: "+err.Error())
+	}
+	if clientID != privateDetails.CreatorID {
+		return accessDenied(privateDetails.Name, "only the creator of the private details of "+privateDetails.Name+" may grant access to them")
+	}
+
+	priceAccessKey, err := stub.CreateCompositeKey(model.PriceAccessIndex, []string{privateDetails.Name, priceAccessInput.ClientID})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, priceAccessKey, []byte{0x00})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end grantPriceAccess (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,1868 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/logging"
+	"privatemarbles/internal/model"
+)
+
+// verifyClientIsOwnerOrg checks that the submitting client belongs to the owner org of the
+// article and returns the client's MSP ID. Articles created before the owner org was
+// recorded have an empty OwnerOrg and can be changed by any collection member.
+func verifyClientIsOwnerOrg(stub shim.ChaincodeStubInterface, article *model.Article) (string, error) {
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get client MSP ID: %v", err)
+	}
+	if article.OwnerOrg != "" && clientOrgID != article.OwnerOrg {
+		return "", newError(CodeAccessDenied, article.Name, "submitting org %s is not the owner org %s", clientOrgID, article.OwnerOrg)
+	}
+	return clientOrgID, nil
+}
+
+// resolveDocType returns the doc type of a request, "article" when none is given, and
+// fails with INVALID_INPUT when the doc type is not allowed by the configuration
+func resolveDocType(cfg *model.Config, docType string) (string, error) {
+	if len(docType) == 0 {
+		return model.DefaultDocType, nil
+	}
+	if !cfg.AllowsDocType(docType) {
+		return "", newError(CodeInvalidInput, "", "docType %s is not allowed", docType)
+	}
+	return docType, nil
+}
+
+// articleKey returns the state key of an object. Articles keep their bare name as key,
+// so records written before doc types existed stay readable, while other doc types are
+// namespaced by a docType~name composite key and cannot collide with articles or each other.
+func articleKey(stub shim.ChaincodeStubInterface, docType string, name string) (string, error) {
+	if docType == model.DefaultDocType || len(docType) == 0 {
+		return name, nil
+	}
+	return stub.CreateCompositeKey(docType, []string{name})
+}
+
+// txLogger returns the logger of the invocation, whose lines carry the function name and
+// the transaction ID, at the debug level for transactions with the debug decoration.
+// Never log prices, transient values or other private values with it, only their sizes.
+func txLogger(stub shim.ChaincodeStubInterface) *logging.Logger {
+	function, _ := stub.GetFunctionAndParameters()
+	return logging.ForInvocation(function, stub.GetTxID(), stub.GetDecorations())
+}
+
+// verifyClientIsAdmin checks that the submitting client carries the admin attribute
+// required by the maintenance functions
+func verifyClientIsAdmin(stub shim.ChaincodeStubInterface) error {
+	err := cid.AssertAttributeValue(stub, model.AdminAttribute, "true")
+	if err != nil {
+		return newError(CodeAccessDenied, "", "client is not an admin: %v", err)
+	}
+	return nil
+}
+
+// verifyClientIsCertifier checks that the submitting client carries the certifier attribute
+// required to certify articles
+func verifyClientIsCertifier(stub shim.ChaincodeStubInterface) error {
+	err := cid.AssertAttributeValue(stub, model.CertifierAttribute, "true")
+	if err != nil {
+		return newError(CodeAccessDenied, "", "client is not a certifier: %v", err)
+	}
+	return nil
+}
+
+// verifyClientIsArbiter checks that the submitting client carries the arbiter attribute
+// required to resolve disputes
+func verifyClientIsArbiter(stub shim.ChaincodeStubInterface) error {
+	err := cid.AssertAttributeValue(stub, model.ArbiterAttribute, "true")
+	if err != nil {
+		return newError(CodeAccessDenied, "", "client is not an arbiter: %v", err)
+	}
+	return nil
+}
+
+// ownerRegistryKey returns the key of an owner in the owner registry
+func ownerRegistryKey(stub shim.ChaincodeStubInterface, owner string) (string, error) {
+	return stub.CreateCompositeKey(model.OwnerRegistryIndex, []string{owner})
+}
+
+// getOwnerRecord reads an owner from the owner registry, returning nil when the owner
+// was never registered
+func getOwnerRecord(stub shim.ChaincodeStubInterface, cfg *model.Config, owner string) (*model.OwnerRecord, error) {
+	registryKey, err := ownerRegistryKey(stub, owner)
+	if err != nil {
+		return nil, err
+	}
+	ownerAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, registryKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get owner %s: %v", owner, err)
+	} else if ownerAsBytes == nil {
+		return nil, nil
+	}
+
+	ownerRecord := &model.OwnerRecord{}
+	err = json.Unmarshal(ownerAsBytes, ownerRecord)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode JSON of: %s", ownerAsBytes)
+	}
+	return ownerRecord, nil
+}
+
+// verifyOwnerRegistered fails with UNKNOWN_OWNER unless the owner is registered and active
+func verifyOwnerRegistered(stub shim.ChaincodeStubInterface, cfg *model.Config, owner string) error {
+	ownerRecord, err := getOwnerRecord(stub, cfg, owner)
+	if err != nil {
+		return err
+	}
+	if ownerRecord == nil {
+		return newError(CodeUnknownOwner, owner, "owner %s is not registered", owner)
+	}
+	if !ownerRecord.Active {
+		return newError(CodeUnknownOwner, owner, "owner %s is deactivated", owner)
+	}
+	return nil
+}
+
+// verifyParticipantExists asks the participant directory chaincode, when one is configured,
+// whether the owner is a known participant and fails with UNKNOWN_OWNER when it is not.
+// The directory is called with its read-only "exists" function on the channel of the
+// transaction and must answer true or false.
+func verifyParticipantExists(stub shim.ChaincodeStubInterface, cfg *model.Config, owner string) error {
+	if len(cfg.ParticipantsChaincode) == 0 {
+		return nil
+	}
+
+	response := stub.InvokeChaincode(cfg.ParticipantsChaincode, [][]byte{[]byte("exists"), []byte(owner)}, stub.GetChannelID())
+	if response.Status != shim.OK {
+		return fmt.Errorf("participant directory %s failed to look up owner %s: status %d: %s", cfg.ParticipantsChaincode, owner, response.Status, response.Message)
+	}
+	exists, err := strconv.ParseBool(string(response.Payload))
+	if err != nil {
+		return fmt.Errorf("participant directory %s returned an invalid answer for owner %s: %v", cfg.ParticipantsChaincode, owner, err)
+	}
+	if !exists {
+		return newError(CodeUnknownOwner, owner, "owner %s is not a participant of %s", owner, cfg.ParticipantsChaincode)
+	}
+	return nil
+}
+
+// implicitCollectionName returns the name of the implicit private data collection of an org
+func implicitCollectionName(mspID string) string {
+	return model.ImplicitOrgPrefix + mspID
+}
+
+// transferAgreementKeys returns the keys under which a buyer stores the agreed article
+// properties and the agreed price in its implicit collection
+func transferAgreementKeys(stub shim.ChaincodeStubInterface, name string) (string, string, error) {
+	propertiesKey, err := stub.CreateCompositeKey(model.AgreementIndex, []string{name, "properties"})
+	if err != nil {
+		return "", "", err
+	}
+	priceKey, err := stub.CreateCompositeKey(model.AgreementIndex, []string{name, "price"})
+	if err != nil {
+		return "", "", err
+	}
+	return propertiesKey, priceKey, nil
+}
+
+// verifyTransferAgreement checks that the buyer org agreed to the current article properties
+// and to the price, the one stored in the private details when price is 0, or to no price
+// at all when the article has no private details. The buyer's
+// records live in its implicit collection, which the seller cannot read, so only their
+// hashes are compared.
+func verifyTransferAgreement(stub shim.ChaincodeStubInterface, cfg *model.Config, buyerOrgID string, name string, articleAsBytes []byte, price model.Price) error {
+	collection := implicitCollectionName(buyerOrgID)
+
+	propertiesKey, priceKey, err := transferAgreementKeys(stub, name)
+	if err != nil {
+		return err
+	}
+
+	agreedPropertiesHash, err := stub.GetPrivateDataHash(collection, propertiesKey)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer agreement of org %s for article %s: %v", buyerOrgID, name, err)
+	}
+	agreedPriceHash, err := stub.GetPrivateDataHash(collection, priceKey)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer agreement of org %s for article %s: %v", buyerOrgID, name, err)
+	}
+	if agreedPropertiesHash == nil || agreedPriceHash == nil {
+		return newError(CodeAccessDenied, name, "no transfer agreement from org %s exists for article %s", buyerOrgID, name)
+	}
+
+	propertiesHash := sha256.Sum256(articleAsBytes)
+	if !bytes.Equal(agreedPropertiesHash, propertiesHash[:]) {
+		return newError(CodeAccessDenied, name, "article properties agreed by org %s do not match article %s", buyerOrgID, name)
+	}
+
+	// the new price keeps the currency of the stored details. An article without details,
+	// one created without a price or imported with its details pending, is agreed to
+	// without a price: the buyer's record then holds a zero price and no currency.
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, name)
+	if ccErr, ok := err.(*chaincodeError); ok && ccErr.Code == CodeArticleNotFound {
+		privateDetails = &model.ArticlePrivateDetails{ObjectType: "articlePrivateDetails", Name: name}
+	} else if err != nil {
+		return err
+	}
+	if price != 0 {
+		privateDetails.Price = price
+	}
+
+	// the buyer does not know the creator of the details, which is left out of the
+	// agreement, and agrees to the details in the current schema
+	privateDetails.CreatorID = ""
+	privateDetails.SchemaVersion = model.CurrentSchemaVersion
+	privateDetailsBytes, err := model.MarshalCanonical(privateDetails)
+	if err != nil {
+		return err
+	}
+	priceHash := sha256.Sum256(privateDetailsBytes)
+	if !bytes.Equal(agreedPriceHash, priceHash[:]) {
+		return newError(CodeAccessDenied, name, "price agreed by org %s does not match the price of article %s", buyerOrgID, name)
+	}
+
+	return nil
+}
+
+// inaccessiblePrivateData is returned by the read functions in place of a private record
+// that exists but cannot be read by the caller, so off-channel claims can still be verified
+type inaccessiblePrivateData struct {
+	Name       string `json:"name"`
+	Collection string `json:"collection"`
+	Accessible bool   `json:"accessible"`
+	Hash       string `json:"hash"` //hex encoded SHA-256 of the private record
+}
+
+// privateDataHashFallback returns the JSON of an inaccessiblePrivateData for the key,
+// or nil when there is no private data hash, i.e. the key does not exist
+func privateDataHashFallback(stub shim.ChaincodeStubInterface, collection string, key string, name string) ([]byte, error) {
+	hashAsBytes, err := stub.GetPrivateDataHash(collection, key)
+	if err != nil {
+		return nil, err
+	} else if hashAsBytes == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(&inaccessiblePrivateData{
+		Name:       name,
+		Collection: collection,
+		Accessible: false,
+		Hash:       hex.EncodeToString(hashAsBytes),
+	})
+}
+
+// privateDataHash is the JSON envelope of a private data hash, carrying the hash in
+// the encodings clients can print and compare without mangling the raw bytes
+type privateDataHash struct {
+	Name       string `json:"name"`
+	Collection string `json:"collection"`
+	HashHex    string `json:"hashHex"`
+	HashBase64 string `json:"hashBase64"`
+}
+
+// hashResponse returns the private data hash in the requested format, "raw" for the
+// bytes as returned by the peer or "json" for a privateDataHash envelope
+func hashResponse(name string, collection string, hash []byte, format string) pb.Response {
+	switch format {
+	case "raw":
+		return shim.Success(hash)
+	case "json":
+		hashJSONasBytes, err := json.Marshal(&privateDataHash{
+			Name:       name,
+			Collection: collection,
+			HashHex:    hex.EncodeToString(hash),
+			HashBase64: base64.StdEncoding.EncodeToString(hash),
+		})
+		if err != nil {
+			return errorResponse(err)
+		}
+		return shim.Success(hashJSONasBytes)
+	default:
+		return invalidInput(name, "Unknown hash format "+format+". Expecting raw or json")
+	}
+}
+
+// setArticleStateBasedEndorsement sets a key-level endorsement policy on the article key in
+// collectionArticles that requires a peer of the given org to endorse any change
+func setArticleStateBasedEndorsement(stub shim.ChaincodeStubInterface, cfg *model.Config, key string, orgID string) error {
+	endorsementPolicy, err := statebased.NewStateEP(nil)
+	if err != nil {
+		return err
+	}
+	err = endorsementPolicy.AddOrgs(statebased.RoleTypePeer, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to add org %s to endorsement policy: %v", orgID, err)
+	}
+	policy, err := endorsementPolicy.Policy()
+	if err != nil {
+		return fmt.Errorf("failed to create endorsement policy bytes from org: %v", err)
+	}
+	err = stub.SetPrivateDataValidationParameter(cfg.CollectionArticles, key, policy)
+	if err != nil {
+		return fmt.Errorf("failed to set validation parameter on article %s: %v", key, err)
+	}
+	return nil
+}
+
+// clearArticleStateBasedEndorsement removes the key-level endorsement policies of the
+// article key from both collections, so that the collection endorsement policies apply
+// again. The transaction itself still has to satisfy the policies it removes. Private
+// details are only cleared when they exist, a missing key has no policy to clear.
+func clearArticleStateBasedEndorsement(stub shim.ChaincodeStubInterface, cfg *model.Config, key string) error {
+	err := stub.SetPrivateDataValidationParameter(cfg.CollectionArticles, key, nil)
+	if err != nil {
+		return fmt.Errorf("failed to clear validation parameter on article %s: %v", key, err)
+	}
+	detailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, key)
+	if err != nil {
+		return fmt.Errorf("failed to get private details hash of %s: %v", key, err)
+	} else if detailsHash == nil {
+		return nil
+	}
+	err = stub.SetPrivateDataValidationParameter(cfg.CollectionArticlePrivateDetails, key, nil)
+	if err != nil {
+		return fmt.Errorf("failed to clear validation parameter on private details %s: %v", key, err)
+	}
+	return nil
+}
+
+// txTimestampRFC3339 returns the transaction timestamp as an RFC3339 string. Unlike the
+// local clock it is the same on every endorsing peer.
+func txTimestampRFC3339(stub shim.ChaincodeStubInterface) (string, error) {
+	txTime, err := txTime(stub)
+	if err != nil {
+		return "", err
+	}
+	return txTime.Format(time.RFC3339), nil
+}
+
+// txTime returns the transaction timestamp as a UTC time
+func txTime(stub shim.ChaincodeStubInterface) (time.Time, error) {
+	txTimestamp, err := stub.GetTxTimestamp()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get transaction timestamp: %v", err)
+	}
+	return time.Unix(txTimestamp.GetSeconds(), int64(txTimestamp.GetNanos())).UTC(), nil
+}
+
+// verifyNotLockedByOtherOrg fails with ACCESS_DENIED when the article is locked by an org
+// other than the one of the submitting client. Expired locks are ignored, they do not
+// have to be released with unlockArticle.
+func verifyNotLockedByOtherOrg(stub shim.ChaincodeStubInterface, article *model.Article) error {
+	if !article.Locked {
+		return nil
+	}
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return fmt.Errorf("Failed to get client MSP ID: %v", err)
+	}
+	if article.LockedBy == clientOrgID {
+		return nil
+	}
+	expired, err := lockExpired(stub, article)
+	if err != nil {
+		return err
+	}
+	if !expired {
+		return newError(CodeAccessDenied, article.Name, "article %s is locked by %s until %s", article.Name, article.LockedBy, article.LockExpiry)
+	}
+	return nil
+}
+
+// lockExpired reports whether the lock of the article has expired at the time of the transaction
+func lockExpired(stub shim.ChaincodeStubInterface, article *model.Article) (bool, error) {
+	lockExpiry, err := time.Parse(time.RFC3339, article.LockExpiry)
+	if err != nil {
+		return false, fmt.Errorf("invalid lock expiry of article %s: %v", article.Name, err)
+	}
+	now, err := txTime(stub)
+	if err != nil {
+		return false, err
+	}
+	return !now.Before(lockExpiry), nil
+}
+
+// leaseActive reports whether the article is leased and the lease has not ended at the
+// transaction timestamp
+func leaseActive(stub shim.ChaincodeStubInterface, article *model.Article) (bool, error) {
+	if len(article.Lessee) == 0 {
+		return false, nil
+	}
+	leaseEndDate, err := time.Parse(time.RFC3339, article.LeaseEndDate)
+	if err != nil {
+		return false, fmt.Errorf("invalid lease end date of article %s: %v", article.Name, err)
+	}
+	now, err := txTime(stub)
+	if err != nil {
+		return false, err
+	}
+	return now.Before(leaseEndDate), nil
+}
+
+// verifyNotLeased fails with CONFLICT while a lease of the article runs
+func verifyNotLeased(stub shim.ChaincodeStubInterface, article *model.Article) error {
+	active, err := leaseActive(stub, article)
+	if err != nil {
+		return err
+	}
+	if active {
+		return newError(CodeConflict, article.Name, "Article %s is leased to %s until %s", article.Name, article.Lessee, article.LeaseEndDate)
+	}
+	return nil
+}
+
+// endArticleLease ends the lease of an article and records it in the lease history. The
+// caller writes the article.
+func endArticleLease(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article) error {
+	err := putLeaseRecord(stub, cfg, article.Name, model.LeaseEnded, article.Lessee, article.LeaseEndDate)
+	if err != nil {
+		return err
+	}
+	article.Lessee = ""
+	article.LeaseEndDate = ""
+	return nil
+}
+
+// putLeaseRecord appends the start or the end of a lease to the lease history of an article
+func putLeaseRecord(stub shim.ChaincodeStubInterface, cfg *model.Config, name string, action string, lessee string, endDate string) error {
+	timestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return err
+	}
+	leaseRecordJSONasBytes, err := json.Marshal(&model.LeaseRecord{
+		ObjectType: "leaseRecord",
+		Name:       name,
+		Action:     action,
+		Lessee:     lessee,
+		EndDate:    endDate,
+		TxID:       stub.GetTxID(),
+		Timestamp:  timestamp,
+	})
+	if err != nil {
+		return err
+	}
+	leaseKey, err := nextSequenceKey(stub, cfg.CollectionArticles, model.LeaseIndex, name)
+	if err != nil {
+		return err
+	}
+	return stub.PutPrivateData(cfg.CollectionArticles, leaseKey, leaseRecordJSONasBytes)
+}
+
+// putAuditRecord records the current transaction and the invoking client identity as the
+// latest change of an article. The identity is kept in the collection only and must never
+// be part of an event, so trading parties are not revealed channel-wide.
+func putAuditRecord(stub shim.ChaincodeStubInterface, cfg *model.Config, name string, function string) error {
+	clientID, err := cid.GetID(stub)
+	if err != nil {
+		return fmt.Errorf("failed to get client identity: %v", err)
+	}
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return err
+	}
+
+	auditRecordJSONasBytes, err := json.Marshal(&model.AuditRecord{
+		ObjectType: "auditRecord",
+		Name:       name,
+		TxID:       stub.GetTxID(),
+		Function:   function,
+		ModifiedBy: clientID,
+		Timestamp:  txTimestamp,
+	})
+	if err != nil {
+		return err
+	}
+
+	auditKey, err := stub.CreateCompositeKey(model.AuditIndex, []string{name, stub.GetTxID()})
+	if err != nil {
+		return err
+	}
+	return stub.PutPrivateData(cfg.CollectionArticles, auditKey, auditRecordJSONasBytes)
+}
+
+// nextSequenceKey returns the composite key for the next entry of a sequence of records
+// stored under objectType~name~seq, numbering the entries from 0 with zero-padded numbers
+func nextSequenceKey(stub shim.ChaincodeStubInterface, collection string, objectType string, name string) (string, error) {
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(collection, objectType, []string{name})
+	if err != nil {
+		return "", err
+	}
+	defer resultsIterator.Close()
+
+	seq := 0
+	for resultsIterator.HasNext() {
+		_, err = resultsIterator.Next()
+		if err != nil {
+			return "", err
+		}
+		seq++
+	}
+
+	return stub.CreateCompositeKey(objectType, []string{name, fmt.Sprintf("%0*d", model.SequenceWidth, seq)})
+}
+
+// deleteByPartialCompositeKey deletes every key of the collection matching the partial composite key
+func deleteByPartialCompositeKey(stub shim.ChaincodeStubInterface, collection string, objectType string, attributes []string) error {
+	return removeByPartialCompositeKey(stub, collection, objectType, attributes, stub.DelPrivateData)
+}
+
+// removeByPartialCompositeKey removes every key of the collection matching the partial
+// composite key with the given remove function.
+func removeByPartialCompositeKey(stub shim.ChaincodeStubInterface, collection string, objectType string, attributes []string, remove privateDataRemover) error {
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(collection, objectType, attributes)
+	if err != nil {
+		return err
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return err
+		}
+		err = remove(collection, responseRange.Key)
+		if err != nil {
+			return fmt.Errorf("failed to delete state: %v", err)
+		}
+	}
+	return nil
+}
+
+// privateDataRemover removes a key from a collection, either by deleting it from the
+// current state or by purging it from the peers entirely.
+type privateDataRemover func(collection string, key string) error
+
+// privateDataPurger is implemented by stubs of Fabric 2.4 and later, which can purge
+// private data together with its history from the peers.
+type privateDataPurger interface {
+	PurgePrivateData(collection string, key string) error
+}
+
+// articleIndexes lists the object types of the indexes kept for articles in collectionArticles
+var articleIndexes = []string{model.ColorNameIndex, model.OwnerNameIndex, model.SizeNameIndex, model.ForSaleIndex, model.TagNameIndex, model.CategoryNameIndex, model.FingerprintIndex}
+
+// articleIndexKeys returns the color~name, an owner~name per co-owner, size~name,
+// fingerprint~hash~name, a tag~name per tag and,
+// for articles with a category or for sale, category~name and forsale~name index keys an
+// article should have. Retired articles have no color~name entry.
+func articleIndexKeys(stub shim.ChaincodeStubInterface, article *model.Article) ([]string, error) {
+	if article.Deleted {
+		return nil, nil //tombstones are not indexed
+	}
+
+	sizeNameIndexKey, err := sizeIndexKey(stub, article)
+	if err != nil {
+		return nil, err
+	}
+	fingerprintIndexKey, err := stub.CreateCompositeKey(model.FingerprintIndex, []string{article.Fingerprint(), article.Name})
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{sizeNameIndexKey, fingerprintIndexKey}
+
+	for _, share := range article.Shares() {
+		ownerNameIndexKey, err := stub.CreateCompositeKey(model.OwnerNameIndex, []string{share.Owner, article.Name})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, ownerNameIndexKey)
+	}
+
+	if !article.Retired {
+		colorNameIndexKey, err := stub.CreateCompositeKey(model.ColorNameIndex, []string{article.Color, article.Name})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, colorNameIndexKey)
+	}
+
+	for _, tag := range article.Tags {
+		tagNameIndexKey, err := stub.CreateCompositeKey(model.TagNameIndex, []string{tag, article.Name})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, tagNameIndexKey)
+	}
+
+	if len(article.Category) != 0 {
+		categoryNameIndexKey, err := categoryIndexKey(stub, article.Category, article.Name)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, categoryNameIndexKey)
+	}
+
+	if article.ForSale {
+		forSaleIndexKey, err := stub.CreateCompositeKey(model.ForSaleIndex, []string{article.Name})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, forSaleIndexKey)
+	}
+	return keys, nil
+}
+
+// removeArticleIndexes removes the index entries of an article and releases its SKU. delete
+// and purgeArticlePrivateDetails share it so both leave the indexes in the same state.
+func removeArticleIndexes(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article, remove privateDataRemover) error {
+	indexKeys, err := articleIndexKeys(stub, article)
+	if err != nil {
+		return err
+	}
+	for _, indexKey := range indexKeys {
+		err = remove(cfg.CollectionArticles, indexKey)
+		if err != nil {
+			return fmt.Errorf("Failed to delete state:%v", err)
+		}
+	}
+	return releaseSKU(stub, cfg, article, remove)
+}
+
+// skuKey returns the key of an SKU in the sku~code index
+func skuKey(stub shim.ChaincodeStubInterface, sku string) (string, error) {
+	return stub.CreateCompositeKey(model.SKUIndex, []string{sku})
+}
+
+// verifySKUAvailable fails with ALREADY_EXISTS when the SKU belongs to an article other
+// than the named one
+func verifySKUAvailable(stub shim.ChaincodeStubInterface, cfg *model.Config, sku string, name string) error {
+	key, err := skuKey(stub, sku)
+	if err != nil {
+		return err
+	}
+	ownerName, err := stub.GetPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return fmt.Errorf("failed to get SKU %s: %v", sku, err)
+	}
+	if ownerName != nil && string(ownerName) != name {
+		return newError(CodeAlreadyExists, name, "SKU %s is already taken by article %s", sku, ownerName)
+	}
+	return nil
+}
+
+// putSKUIndex maps the SKU of an article to its name, articles without SKU have no entry
+func putSKUIndex(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article) error {
+	if len(article.SKU) == 0 || article.Deleted {
+		return nil
+	}
+	key, err := skuKey(stub, article.SKU)
+	if err != nil {
+		return err
+	}
+	return stub.PutPrivateData(cfg.CollectionArticles, key, []byte(article.Name))
+}
+
+// releaseSKU removes the SKU of an article from the sku~code index, unless another
+// article took the SKU after this one was soft-deleted
+func releaseSKU(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article, remove privateDataRemover) error {
+	if len(article.SKU) == 0 {
+		return nil
+	}
+	key, err := skuKey(stub, article.SKU)
+	if err != nil {
+		return err
+	}
+	ownerName, err := stub.GetPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return fmt.Errorf("failed to get SKU %s: %v", article.SKU, err)
+	}
+	if string(ownerName) != article.Name {
+		return nil
+	}
+	return remove(cfg.CollectionArticles, key)
+}
+
+// putArticle writes the canonical JSON of an article in the current schema version under its state key
+func putArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article) error {
+	key, err := articleKey(stub, article.ObjectType, article.Name)
+	if err != nil {
+		return err
+	}
+
+	article.SchemaVersion = model.CurrentSchemaVersion
+	articleJSONasBytes, err := model.MarshalCanonical(article)
+	if err != nil {
+		return err
+	}
+	return stub.PutPrivateData(cfg.CollectionArticles, key, articleJSONasBytes)
+}
+
+// putNewArticle saves a new article with its private details and indexes it by color,
+// owner, size and SKU. The owner org of the article has to endorse its future changes.
+// Objects of other doc types are saved under their namespaced key and are not indexed.
+// A price of 0 saves no private details, they can be added with addArticlePrivateDetails.
+func putNewArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article, price model.Price, currency string) error {
+	key, err := articleKey(stub, article.ObjectType, article.Name)
+	if err != nil {
+		return err
+	}
+
+	if len(article.SKU) != 0 {
+		err = verifySKUAvailable(stub, cfg, article.SKU, article.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// === Save article to state ===
+	err = putArticle(stub, cfg, article)
+	if err != nil {
+		return err
+	}
+
+	// === Require the endorsement of the owner org for future changes of the article ===
+	err = setArticleStateBasedEndorsement(stub, cfg, key, article.OwnerOrg)
+	if err != nil {
+		return err
+	}
+
+	// ==== The creating client is the only one allowed to read the price ====
+	creatorID, err := cid.GetID(stub)
+	if err != nil {
+		return fmt.Errorf("Failed to get client ID: %v", err)
+	}
+
+	if article.ObjectType != model.DefaultDocType {
+		if price == 0 {
+			return nil
+		}
+		articlePrivateDetailsBytes, err := model.MarshalCanonical(&model.ArticlePrivateDetails{
+			ObjectType:    article.ObjectType + "PrivateDetails",
+			Name:          article.Name,
+			Price:         price,
+			Currency:      currency,
+			SchemaVersion: model.CurrentSchemaVersion,
+			CreatorID:     creatorID,
+		})
+		if err != nil {
+			return err
+		}
+		return stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, key, articlePrivateDetailsBytes)
+	}
+
+	// ==== Create article private details object with price, marshal to JSON, and save to state ====
+	if price != 0 {
+		err = putArticlePrivateDetails(stub, cfg, article.Name, creatorID, currency, 0, price)
+		if err != nil {
+			return err
+		}
+	}
+
+	//  ==== Index the article to enable color-based range queries, e.g. return all blue articles ====
+	//  An 'index' is a normal key/value entry in state.
+	//  The key is a composite key, with the elements that you want to range query on listed first.
+	//  In our case, the composite key is based on indexName~color~name.
+	//  This will enable very efficient state range queries based on composite keys matching indexName~color~*
+	indexName := model.ColorNameIndex
+	colorNameIndexKey, err := stub.CreateCompositeKey(indexName, []string{article.Color, article.Name})
+	if err != nil {
+		return err
+	}
+	//  Save index entry to state. Only the key name is needed, no need to store a duplicate copy of the article.
+	//  Note - passing a 'nil' value will effectively delete the key from state, therefore we pass null character as value
+	value := []byte{0x00}
+	err = stub.PutPrivateData(cfg.CollectionArticles, colorNameIndexKey, value)
+	if err != nil {
+		return err
+	}
+
+	//  ==== Index the article by owner to enable owner-based range queries, e.g. return all articles of tom ====
+	for _, share := range article.Shares() {
+		ownerNameIndexKey, err := stub.CreateCompositeKey(model.OwnerNameIndex, []string{share.Owner, article.Name})
+		if err != nil {
+			return err
+		}
+		err = stub.PutPrivateData(cfg.CollectionArticles, ownerNameIndexKey, value)
+		if err != nil {
+			return err
+		}
+	}
+	err = adjustShareholderCounts(stub, cfg, article, 1)
+	if err != nil {
+		return err
+	}
+
+	//  ==== Index the article by size to enable size range queries ====
+	sizeNameIndexKey, err := sizeIndexKey(stub, article)
+	if err != nil {
+		return err
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, sizeNameIndexKey, value)
+	if err != nil {
+		return err
+	}
+
+	//  ==== Index the article by its fingerprint to find duplicates ====
+	fingerprintIndexKey, err := stub.CreateCompositeKey(model.FingerprintIndex, []string{article.Fingerprint(), article.Name})
+	if err != nil {
+		return err
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, fingerprintIndexKey, value)
+	if err != nil {
+		return err
+	}
+
+	//  ==== Index the article by each of its tags ====
+	for _, tag := range article.Tags {
+		tagNameIndexKey, err := stub.CreateCompositeKey(model.TagNameIndex, []string{tag, article.Name})
+		if err != nil {
+			return err
+		}
+		err = stub.PutPrivateData(cfg.CollectionArticles, tagNameIndexKey, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	//  ==== Index the article by category to enable subtree queries ====
+	if len(article.Category) != 0 {
+		categoryNameIndexKey, err := categoryIndexKey(stub, article.Category, article.Name)
+		if err != nil {
+			return err
+		}
+		err = stub.PutPrivateData(cfg.CollectionArticles, categoryNameIndexKey, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	//  ==== Claim the SKU of the article ====
+	return putSKUIndex(stub, cfg, article)
+}
+
+// publicSummaryKey returns the public state key of the PublicSummary of the article stored
+// under key, its name for the doc type "article", see articleKey
+func publicSummaryKey(key string) string {
+	return model.PublicSummaryKeyPrefix + key
+}
+
+// putPublicSummary writes the PublicSummary of an article to the public world state
+func putPublicSummary(stub shim.ChaincodeStubInterface, article *model.Article) error {
+	key, err := articleKey(stub, article.ObjectType, article.Name)
+	if err != nil {
+		return err
+	}
+	summaryJSONasBytes, err := json.Marshal(&model.PublicSummary{ObjectType: article.ObjectType, Name: article.Name})
+	if err != nil {
+		return err
+	}
+	return stub.PutState(publicSummaryKey(key), summaryJSONasBytes)
+}
+
+// removePublicSummary deletes the PublicSummary of the article stored under key and reports
+// whether there was one. Articles without one are left untouched, so their public history
+// stays empty.
+func removePublicSummary(stub shim.ChaincodeStubInterface, key string) (bool, error) {
+	summaryAsBytes, err := stub.GetState(publicSummaryKey(key))
+	if err != nil {
+		return false, fmt.Errorf("Failed to get public summary of %s: %v", key, err)
+	} else if summaryAsBytes == nil {
+		return false, nil
+	}
+	return true, stub.DelState(publicSummaryKey(key))
+}
+
+// categoryIndexKey returns the category~name index key of an article, with a composite key
+// attribute per segment of the category path
+func categoryIndexKey(stub shim.ChaincodeStubInterface, category string, name string) (string, error) {
+	return stub.CreateCompositeKey(model.CategoryNameIndex, append(strings.Split(category, "/"), name))
+}
+
+// inCategory reports whether a category is the given one or below it
+func inCategory(category string, prefix string) bool {
+	return category == prefix || strings.HasPrefix(category, prefix+"/")
+}
+
+// sizeIndexKey returns the size~name index key of an article. The size is zero-padded
+// to model.SizeWidth digits so the keys sort by size.
+func sizeIndexKey(stub shim.ChaincodeStubInterface, article *model.Article) (string, error) {
+	return stub.CreateCompositeKey(model.SizeNameIndex, []string{fmt.Sprintf("%0*d", model.SizeWidth, article.Size), article.Name})
+}
+
+// putArticlePrivateDetails writes the private details of an article with the given price
+// and currency and creator, the ones of the existing details when the price changes, and moves the article in the price~name index from oldPrice, 0 for a new article, to price.
+func putArticlePrivateDetails(stub shim.ChaincodeStubInterface, cfg *model.Config, name string, creatorID string, currency string, oldPrice model.Price, price model.Price) error {
+	articlePrivateDetailsBytes, err := model.MarshalCanonical(&model.ArticlePrivateDetails{
+		ObjectType:    "articlePrivateDetails",
+		Name:          name,
+		Price:         price,
+		Currency:      currency,
+		SchemaVersion: model.CurrentSchemaVersion,
+		CreatorID:     creatorID,
+	})
+	if err != nil {
+		return err
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, name, articlePrivateDetailsBytes)
+	if err != nil {
+		return err
+	}
+
+	if oldPrice != 0 {
+		err = removePriceIndex(stub, cfg, name, oldPrice, stub.DelPrivateData)
+		if err != nil {
+			return err
+		}
+	}
+	priceNameIndexKey, err := priceIndexKey(stub, name, price)
+	if err != nil {
+		return err
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, priceNameIndexKey, []byte{0x00})
+	if err != nil {
+		return err
+	}
+
+	// ==== Remember which org wrote the details, only it may delete them on their own ====
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return fmt.Errorf("Failed to get client MSP ID: %v", err)
+	}
+	detailsWriterKey, err := stub.CreateCompositeKey(model.DetailsWriterIndex, []string{name})
+	if err != nil {
+		return err
+	}
+	return stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, detailsWriterKey, []byte(clientOrgID))
+}
+
+// removeArticlePrivateDetails removes the private details of an article together with
+// their price~name index entry and the record of the org that wrote them
+func removeArticlePrivateDetails(stub shim.ChaincodeStubInterface, cfg *model.Config, privateDetails *model.ArticlePrivateDetails, remove privateDataRemover) error {
+	err := remove(cfg.CollectionArticlePrivateDetails, privateDetails.Name)
+	if err != nil {
+		return fmt.Errorf("Failed to delete private details:%v", err)
+	}
+
+	err = removePriceIndex(stub, cfg, privateDetails.Name, privateDetails.Price, remove)
+	if err != nil {
+		return err
+	}
+
+	detailsWriterKey, err := stub.CreateCompositeKey(model.DetailsWriterIndex, []string{privateDetails.Name})
+	if err != nil {
+		return err
+	}
+	err = remove(cfg.CollectionArticlePrivateDetails, detailsWriterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to delete state:%v", err)
+	}
+
+	err = removeByPartialCompositeKey(stub, cfg.CollectionArticlePrivateDetails, model.PriceAccessIndex, []string{privateDetails.Name}, remove)
+	if err != nil {
+		return err
+	}
+	return removeByPartialCompositeKey(stub, cfg.CollectionArticlePrivateDetails, model.DetailsAccessIndex, []string{privateDetails.Name}, remove)
+}
+
+// verifyPriceReader fails with ACCESS_DENIED, carrying the private data hash of the details,
+// unless the client created the private details or was granted access by their creator.
+// Details without a creator are readable by every member of the collection.
+func verifyPriceReader(stub shim.ChaincodeStubInterface, cfg *model.Config, privateDetailsAsBytes []byte) error {
+	var privateDetails model.ArticlePrivateDetails
+	err := json.Unmarshal(privateDetailsAsBytes, &privateDetails)
+	if err != nil {
+		return fmt.Errorf("Failed to decode JSON of: %s", privateDetailsAsBytes)
+	}
+	if len(privateDetails.CreatorID) == 0 {
+		return nil
+	}
+
+	clientID, err := cid.GetID(stub)
+	if err != nil {
+		return fmt.Errorf("Failed to get client ID: %v", err)
+	}
+	if clientID == privateDetails.CreatorID {
+		return nil
+	}
+	priceAccessKey, err := stub.CreateCompositeKey(model.PriceAccessIndex, []string{privateDetails.Name, clientID})
+	if err != nil {
+		return err
+	}
+	granted, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, priceAccessKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get price access: %v", err)
+	} else if granted != nil {
+		return nil
+	}
+
+	hash := sha256.Sum256(privateDetailsAsBytes)
+	return &chaincodeError{
+		Code:    CodeAccessDenied,
+		Message: "only the creator of the private details of " + privateDetails.Name + " and the clients it granted access may read them",
+		Key:     privateDetails.Name,
+		Hash:    hex.EncodeToString(hash[:]),
+	}
+}
+
+// detailsAccessGranted reports whether the org of the client, being another org than the
+// one of the endorsing peer, holds a grant of grantDetailsAccess to read the private details
+// of the article that has not expired at the transaction timestamp. Expired grants are
+// ignored, they do not have to be revoked.
+func detailsAccessGranted(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) (bool, error) {
+	clientMSPID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return false, fmt.Errorf("failed getting the client's MSPID: %v", err)
+	}
+	peerMSPID, err := shim.GetMSPID()
+	if err != nil {
+		return false, fmt.Errorf("failed getting the peer's MSPID: %v", err)
+	}
+	if clientMSPID == peerMSPID {
+		return false, nil
+	}
+
+	detailsAccessKey, err := stub.CreateCompositeKey(model.DetailsAccessIndex, []string{name, clientMSPID})
+	if err != nil {
+		return false, err
+	}
+	grantAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, detailsAccessKey)
+	if err != nil {
+		return false, fmt.Errorf("Failed to get details access: %v", err)
+	} else if grantAsBytes == nil {
+		return false, nil
+	}
+	var grant model.DetailsAccessGrant
+	err = json.Unmarshal(grantAsBytes, &grant)
+	if err != nil {
+		return false, fmt.Errorf("Failed to decode JSON of: %s", grantAsBytes)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, grant.ExpiresAt)
+	if err != nil {
+		return false, fmt.Errorf("invalid expiry of details access of org %s to %s: %v", clientMSPID, name, err)
+	}
+	now, err := txTime(stub)
+	if err != nil {
+		return false, err
+	}
+	return now.Before(expiresAt), nil
+}
+
+// putPriceRecord appends a change of price to the price history of an article in
+// collectionArticlePrivateDetails
+func putPriceRecord(stub shim.ChaincodeStubInterface, cfg *model.Config, name string, oldPrice model.Price, newPrice model.Price) error {
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return err
+	}
+	priceRecordJSONasBytes, err := json.Marshal(&model.PriceRecord{
+		ObjectType: "priceRecord",
+		Name:       name,
+		OldPrice:   oldPrice,
+		NewPrice:   newPrice,
+		TxID:       stub.GetTxID(),
+		Timestamp:  txTimestamp,
+	})
+	if err != nil {
+		return err
+	}
+	priceHistoryKey, err := nextSequenceKey(stub, cfg.CollectionArticlePrivateDetails, model.PriceHistoryIndex, name)
+	if err != nil {
+		return err
+	}
+	return stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, priceHistoryKey, priceRecordJSONasBytes)
+}
+
+// priceIndexKey returns the price~name index key of an article. The price is formatted by
+// model.FormatPriceKey so the keys sort by price.
+func priceIndexKey(stub shim.ChaincodeStubInterface, name string, price model.Price) (string, error) {
+	return stub.CreateCompositeKey(model.PriceNameIndex, []string{model.FormatPriceKey(price), name})
+}
+
+// removePriceIndex removes the price~name index entry of an article
+func removePriceIndex(stub shim.ChaincodeStubInterface, cfg *model.Config, name string, price model.Price, remove privateDataRemover) error {
+	priceNameIndexKey, err := priceIndexKey(stub, name, price)
+	if err != nil {
+		return err
+	}
+	err = remove(cfg.CollectionArticlePrivateDetails, priceNameIndexKey)
+	if err != nil {
+		return fmt.Errorf("Failed to delete state:%v", err)
+	}
+	return nil
+}
+
+// getArticle reads an article, failing with ARTICLE_NOT_FOUND when it does not exist
+func getArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) (*model.Article, error) {
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, name)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to get article:%v", err)
+	} else if articleAsBytes == nil {
+		return nil, newError(CodeArticleNotFound, name, "Article does not exist: %s", name)
+	}
+
+	article := &model.Article{}
+	err = json.Unmarshal(articleAsBytes, article)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to decode JSON of: %s", articleAsBytes)
+	}
+	err = verifyNotDeleted(article)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyNotRetired(article)
+	if err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// verifyNotDeleted fails with ARTICLE_NOT_FOUND when the article is soft-deleted, so
+// tombstones cannot be changed as if the article still existed
+func verifyNotDeleted(article *model.Article) error {
+	if len(article.RenamedTo) != 0 {
+		return newError(CodeArticleNotFound, article.Name, "Article %s was renamed to %s", article.Name, article.RenamedTo)
+	}
+	if article.Deleted {
+		return newError(CodeArticleNotFound, article.Name, "Article was deleted at %s: %s", article.DeletedAt, article.Name)
+	}
+	return nil
+}
+
+// verifyNotRetired fails with ARTICLE_RETIRED when the article was retired, which
+// ends its transfers and changes for good
+func verifyNotRetired(article *model.Article) error {
+	if article.Retired {
+		return newError(CodeArticleRetired, article.Name, "Article %s was retired at %s: %s", article.Name, article.RetiredAt, article.RetireReason)
+	}
+	return nil
+}
+
+// removeArticle removes an article from state together with its indexes, its private
+// details and, unless keepHistory is set, its ownership, lease and price history. Its disputes are kept as
+// a record; callers refuse to remove a disputed article with verifyNotDisputed. Peers
+// outside collectionArticlePrivateDetails cannot read the price the price~name entry is
+// keyed by, getArticlesByPriceRange skips the entries they leave behind.
+func removeArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article, keepHistory bool) error {
+	err := stub.DelPrivateData(cfg.CollectionArticles, article.Name)
+	if err != nil {
+		return fmt.Errorf("Failed to delete state:%v", err)
+	}
+
+	err = removeArticleIndexes(stub, cfg, article, stub.DelPrivateData)
+	if err != nil {
+		return err
+	}
+	if !article.Deleted {
+		err = adjustShareholderCounts(stub, cfg, article, -1) //tombstones were no longer counted
+		if err != nil {
+			return err
+		}
+	}
+
+	err = clearPendingTransfer(stub, cfg, article)
+	if err != nil {
+		return err
+	}
+	err = removeTransferRequest(stub, cfg, article.Name)
+	if err != nil {
+		return err
+	}
+	err = deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.CertificationIndex, []string{article.Name})
+	if err != nil {
+		return err
+	}
+	err = deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.OfferCommitmentIndex, []string{article.Name})
+	if err != nil {
+		return err
+	}
+	err = deleteByPartialCompositeKey(stub, cfg.CollectionArticlePrivateDetails, model.AttachmentIndex, []string{article.Name})
+	if err != nil {
+		return err
+	}
+	_, err = removePublicSummary(stub, article.Name)
+	if err != nil {
+		return err
+	}
+
+	if !keepHistory {
+		err = deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.HistoryIndex, []string{article.Name})
+		if err != nil {
+			return err
+		}
+		err = deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.LeaseIndex, []string{article.Name})
+		if err != nil {
+			return err
+		}
+		err = deleteByPartialCompositeKey(stub, cfg.CollectionArticlePrivateDetails, model.PriceHistoryIndex, []string{article.Name})
+		if err != nil {
+			return err
+		}
+	}
+
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, article.Name)
+	if err == nil {
+		return removeArticlePrivateDetails(stub, cfg, privateDetails, stub.DelPrivateData)
+	}
+	return stub.DelPrivateData(cfg.CollectionArticlePrivateDetails, article.Name)
+}
+
+// getArticlePrivateDetails reads the private details of an article, failing with
+// ARTICLE_NOT_FOUND when they do not exist. Their hash, which every peer has, is looked
+// up first, so that orgs outside collectionArticlePrivateDetails can still act on the
+// articles without details.
+func getArticlePrivateDetails(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) (*model.ArticlePrivateDetails, error) {
+	detailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, name)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to get private details hash for %s: %v", name, err)
+	} else if detailsHash == nil {
+		return nil, newError(CodeArticleNotFound, name, "Article private details does not exist: %s", name)
+	}
+
+	privateDetailsAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, name)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to get private details for %s: %v", name, err)
+	} else if privateDetailsAsBytes == nil {
+		return nil, newError(CodeArticleNotFound, name, "Article private details does not exist: %s", name)
+	}
+
+	privateDetails := &model.ArticlePrivateDetails{}
+	err = json.Unmarshal(privateDetailsAsBytes, privateDetails)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to decode JSON of: %s", privateDetailsAsBytes)
+	}
+	return privateDetails, nil
+}
+
+// changeArticleOwner gives the article to a new owner and keeps everything that depends
+// on the owner consistent: the owner~name index, the sale listing, the key-level
+// endorsement policy, the ownership history and the audit trail. function names the
+// chaincode function in the audit record. A disputed or leased article cannot change
+// hands; an expired lease ends with the transfer.
+func changeArticleOwner(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article, newOwner string, newOwnerOrg string, function string) error {
+	oldOwner := article.Owner
+
+	err := verifyNotDisputed(article)
+	if err != nil {
+		return err
+	}
+	err = verifyNotLeased(stub, article)
+	if err != nil {
+		return err
+	}
+
+	// move the article in the owner~name index from the old owners to the new owner
+	oldShares := article.Shares()
+	for _, share := range oldShares {
+		oldOwnerNameIndexKey, err := stub.CreateCompositeKey(model.OwnerNameIndex, []string{share.Owner, article.Name})
+		if err != nil {
+			return err
+		}
+		err = stub.DelPrivateData(cfg.CollectionArticles, oldOwnerNameIndexKey)
+		if err != nil {
+			return fmt.Errorf("Failed to delete state:%v", err)
+		}
+	}
+	newOwnerHeldShare := article.ShareOf(newOwner) > 0
+
+	// a transfer concludes or supersedes a proposed or initiated one
+	err = clearPendingTransfer(stub, cfg, article)
+	if err != nil {
+		return err
+	}
+	err = removeTransferRequest(stub, cfg, article.Name)
+	if err != nil {
+		return err
+	}
+
+	article.Owner = newOwner //change the owner
+	article.Owners = nil     //the new owner gets the whole article
+	article.OwnerOrg = newOwnerOrg
+	article.ForSale = false //the new owner has to list the article again
+	article.AskingPriceVisible = false
+	article.Locked = false //a lock reserves the article for a deal, the transfer concludes it
+	article.LockedBy = ""
+	article.LockExpiry = ""
+	article.Lessee = "" //checked above, only an expired lease is left
+	article.LeaseEndDate = ""
+
+	article.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return err
+	}
+
+	err = putArticle(stub, cfg, article) //rewrite the article
+	if err != nil {
+		return err
+	}
+
+	// from now on the new owner org has to endorse changes of the article
+	key, err := articleKey(stub, article.ObjectType, article.Name)
+	if err != nil {
+		return err
+	}
+	err = setArticleStateBasedEndorsement(stub, cfg, key, article.OwnerOrg)
+	if err != nil {
+		return err
+	}
+
+	newOwnerNameIndexKey, err := stub.CreateCompositeKey(model.OwnerNameIndex, []string{article.Owner, article.Name})
+	if err != nil {
+		return err
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, newOwnerNameIndexKey, []byte{0x00})
+	if err != nil {
+		return err
+	}
+	for _, share := range oldShares {
+		if share.Owner != article.Owner {
+			err = adjustOwnerCount(stub, cfg, share.Owner, -1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if !newOwnerHeldShare {
+		err = adjustOwnerCount(stub, cfg, article.Owner, 1)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = putForSaleIndex(stub, cfg, article)
+	if err != nil {
+		return err
+	}
+
+	// ==== Append the change of owner to the ownership history ====
+	ownershipRecordJSONasBytes, err := json.Marshal(&model.OwnershipRecord{
+		ObjectType:    "ownershipRecord",
+		Name:          article.Name,
+		PreviousOwner: oldOwner,
+		NewOwner:      article.Owner,
+		TxID:          stub.GetTxID(),
+		Timestamp:     article.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	historyKey, err := nextSequenceKey(stub, cfg.CollectionArticles, model.HistoryIndex, article.Name)
+	if err != nil {
+		return err
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, historyKey, ownershipRecordJSONasBytes)
+	if err != nil {
+		return err
+	}
+
+	return putAuditRecord(stub, cfg, article.Name, function)
+}
+
+// putForSaleIndex adds the article to the forsale~name index when it is for sale and
+// removes it otherwise.
+func putForSaleIndex(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article) error {
+	forSaleIndexKey, err := stub.CreateCompositeKey(model.ForSaleIndex, []string{article.Name})
+	if err != nil {
+		return err
+	}
+	if article.ForSale {
+		//  Save index entry to state. Only the key name is needed, no need to store a duplicate copy of the article.
+		return stub.PutPrivateData(cfg.CollectionArticles, forSaleIndexKey, []byte{0x00})
+	}
+	return stub.DelPrivateData(cfg.CollectionArticles, forSaleIndexKey)
+}
+
+// changeResponse returns the success response of an update telling whether it changed
+// anything
+func changeResponse(changed bool) pb.Response {
+	resultJSONasBytes, err := json.Marshal(&model.ChangeResult{Changed: changed})
+	if err != nil {
+		return errorResponse(err)
+	}
+	return shim.Success(resultJSONasBytes)
+}
+
+// setArticleEvent sets a chaincode event with the given name and an ArticleEvent payload
+// listing the affected articles.
+func setArticleEvent(stub shim.ChaincodeStubInterface, eventName string, entries ...model.ArticleEventEntry) error {
+	eventJSONasBytes, err := json.Marshal(model.ArticleEvent{Articles: entries})
+	if err != nil {
+		return err
+	}
+	err = stub.SetEvent(eventName, eventJSONasBytes)
+	if err != nil {
+		return fmt.Errorf("failed to set event %s: %v", eventName, err)
+	}
+	return nil
+}
+
+// verifyClientOrgMatchesPeerOrg checks that the organization of the submitting client
+// matches the organization of the peer it asked to endorse the transaction. Without it
+// a client could ask another org's peer to write into a collection on its behalf.
+func verifyClientOrgMatchesPeerOrg(stub shim.ChaincodeStubInterface) error {
+	clientMSPID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return fmt.Errorf("failed getting the client's MSPID: %v", err)
+	}
+	peerMSPID, err := shim.GetMSPID()
+	if err != nil {
+		return fmt.Errorf("failed getting the peer's MSPID: %v", err)
+	}
+
+	if clientMSPID != peerMSPID {
+		return newError(CodeAccessDenied, "", "client from org %s is not authorized to write private data with an org %s peer", clientMSPID, peerMSPID)
+	}
+
+	return nil
+}
+
+// auctionKey returns the key of the auction of an article in collectionArticles
+func auctionKey(stub shim.ChaincodeStubInterface, name string) (string, error) {
+	return stub.CreateCompositeKey(model.AuctionIndex, []string{name})
+}
+
+// getAuction reads the auction of an article, returning nil when none was ever opened
+func getAuction(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) (*model.Auction, error) {
+	key, err := auctionKey(stub, name)
+	if err != nil {
+		return nil, err
+	}
+	auctionAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to get auction for %s: %v", name, err)
+	} else if auctionAsBytes == nil {
+		return nil, nil
+	}
+
+	auction := &model.Auction{}
+	err = json.Unmarshal(auctionAsBytes, auction)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to decode JSON of: %s", auctionAsBytes)
+	}
+	return auction, nil
+}
+
+// getOpenAuction reads the auction of an article, failing with ARTICLE_NOT_FOUND when the
+// article is not being auctioned
+func getOpenAuction(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) (*model.Auction, error) {
+	auction, err := getAuction(stub, cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	if auction == nil || auction.Status != model.AuctionOpen {
+		return nil, newError(CodeArticleNotFound, name, "no open auction exists for article %s", name)
+	}
+	return auction, nil
+}
+
+// putAuction writes the auction of an article to collectionArticles
+func putAuction(stub shim.ChaincodeStubInterface, cfg *model.Config, auction *model.Auction) error {
+	key, err := auctionKey(stub, auction.Name)
+	if err != nil {
+		return err
+	}
+	auctionJSONasBytes, err := model.MarshalCanonical(auction)
+	if err != nil {
+		return err
+	}
+	return stub.PutPrivateData(cfg.CollectionArticles, key, auctionJSONasBytes)
+}
+
+// auctionTimes parses the close time and the reveal deadline of an auction
+func auctionTimes(auction *model.Auction) (time.Time, time.Time, error) {
+	closeTime, err := time.Parse(time.RFC3339, auction.CloseTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, newError(CodeInternal, auction.Name, "invalid close time of auction %s: %v", auction.Name, err)
+	}
+	revealDeadline, err := time.Parse(time.RFC3339, auction.RevealDeadline)
+	if err != nil {
+		return time.Time{}, time.Time{}, newError(CodeInternal, auction.Name, "invalid reveal deadline of auction %s: %v", auction.Name, err)
+	}
+	return closeTime, revealDeadline, nil
+}
+
+// pendingTransferKey returns the key of the transfer proposed for an article
+func pendingTransferKey(stub shim.ChaincodeStubInterface, name string) (string, error) {
+	return stub.CreateCompositeKey(model.PendingTransferIndex, []string{name})
+}
+
+// getPendingTransfer reads the transfer proposed for an article, failing with
+// ARTICLE_NOT_FOUND when none is pending
+func getPendingTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) (*model.PendingTransfer, error) {
+	key, err := pendingTransferKey(stub, name)
+	if err != nil {
+		return nil, err
+	}
+	pendingTransferAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to get pending transfer for %s: %v", name, err)
+	} else if pendingTransferAsBytes == nil {
+		return nil, newError(CodeArticleNotFound, name, "no transfer is pending for article %s", name)
+	}
+
+	pendingTransfer := &model.PendingTransfer{}
+	err = json.Unmarshal(pendingTransferAsBytes, pendingTransfer)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to decode JSON of: %s", pendingTransferAsBytes)
+	}
+	return pendingTransfer, nil
+}
+
+// clearPendingTransfer removes the transfer proposed for an article and clears its
+// PendingTransferTo. The caller writes the article.
+func clearPendingTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article) error {
+	if len(article.PendingTransferTo) == 0 {
+		return nil
+	}
+	key, err := pendingTransferKey(stub, article.Name)
+	if err != nil {
+		return err
+	}
+	err = stub.DelPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return fmt.Errorf("Failed to delete state:%v", err)
+	}
+	article.PendingTransferTo = ""
+	return nil
+}
+
+// pendingTransferExpired reports whether a proposed transfer has expired at the time of the transaction
+func pendingTransferExpired(stub shim.ChaincodeStubInterface, pendingTransfer *model.PendingTransfer) (bool, error) {
+	expiry, err := time.Parse(time.RFC3339, pendingTransfer.Expiry)
+	if err != nil {
+		return false, fmt.Errorf("invalid expiry of pending transfer of article %s: %v", pendingTransfer.Name, err)
+	}
+	now, err := txTime(stub)
+	if err != nil {
+		return false, err
+	}
+	return !now.Before(expiry), nil
+}
+
+// transferRequestKey returns the key of the transfer initiated for an article
+func transferRequestKey(stub shim.ChaincodeStubInterface, name string) (string, error) {
+	return stub.CreateCompositeKey(model.TransferRequestIndex, []string{name})
+}
+
+// getTransferRequest reads the transfer initiated for an article, returning nil when none is
+func getTransferRequest(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) (*model.TransferRequest, error) {
+	key, err := transferRequestKey(stub, name)
+	if err != nil {
+		return nil, err
+	}
+	transferRequestAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to get transfer request for %s: %v", name, err)
+	} else if transferRequestAsBytes == nil {
+		return nil, nil
+	}
+
+	transferRequest := &model.TransferRequest{}
+	err = json.Unmarshal(transferRequestAsBytes, transferRequest)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to decode JSON of: %s", transferRequestAsBytes)
+	}
+	return transferRequest, nil
+}
+
+// removeTransferRequest removes the transfer initiated for an article together with its approvals
+func removeTransferRequest(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) error {
+	key, err := transferRequestKey(stub, name)
+	if err != nil {
+		return err
+	}
+	err = stub.DelPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return fmt.Errorf("Failed to delete state:%v", err)
+	}
+	return deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.ApprovalIndex, []string{name})
+}
+
+// negotiationCollection returns the collection holding the prices negotiated between a
+// buyer and a seller org: the implicit collection of the buyer or, when configured, the
+// bilateral collection of the two orgs, named the same whichever org is the buyer
+func negotiationCollection(cfg *model.Config, buyerMSP string, sellerMSP string) string {
+	if cfg.NegotiationCollections != model.NegotiationBilateral {
+		return implicitCollectionName(buyerMSP)
+	}
+	if sellerMSP < buyerMSP {
+		return model.BilateralCollectionPrefix + sellerMSP + "_" + buyerMSP
+	}
+	return model.BilateralCollectionPrefix + buyerMSP + "_" + sellerMSP
+}
+
+// negotiatedPriceKey returns the key of the price a buyer org negotiated for an article
+func negotiatedPriceKey(stub shim.ChaincodeStubInterface, name string, buyerMSP string) (string, error) {
+	return stub.CreateCompositeKey(model.NegotiatedPriceIndex, []string{name, buyerMSP})
+}
+
+// verifyNegotiationParty checks that the submitting client belongs to the buyer or the
+// seller org of a negotiation. Only the buyer org writes its implicit collection, so with
+// implicit collections the seller may read but not set negotiated prices.
+func verifyNegotiationParty(stub shim.ChaincodeStubInterface, cfg *model.Config, name string, buyerMSP string, sellerMSP string, write bool) error {
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return newError(CodeInternal, name, "Failed to get client MSP ID: %v", err)
+	}
+	if clientOrgID == buyerMSP {
+		return nil
+	}
+	if clientOrgID == sellerMSP && (!write || cfg.NegotiationCollections == model.NegotiationBilateral) {
+		return nil
+	}
+	return newError(CodeAccessDenied, name, "client from org %s is not a party to the negotiation between buyer org %s and seller org %s", clientOrgID, buyerMSP, sellerMSP)
+}
+
+// negotiatedTransferPrice returns the price of a transfer to the buyer org: the price it
+// negotiated with the seller org when there is one, newPrice otherwise. A negotiated price
+// the seller org cannot read, in the implicit collection of the buyer, must be passed as
+// newPrice and is checked against its private data hash.
+func negotiatedTransferPrice(stub shim.ChaincodeStubInterface, cfg *model.Config, details *model.ArticlePrivateDetails, buyerMSP string, sellerMSP string, newPrice model.Price) (model.Price, error) {
+	name := details.Name
+	collection := negotiationCollection(cfg, buyerMSP, sellerMSP)
+	key, err := negotiatedPriceKey(stub, name, buyerMSP)
+	if err != nil {
+		return 0, err
+	}
+
+	negotiatedAsBytes, err := stub.GetPrivateData(collection, key)
+	if err == nil && negotiatedAsBytes != nil {
+		var negotiated model.NegotiatedPrice
+		err = json.Unmarshal(negotiatedAsBytes, &negotiated)
+		if err != nil {
+			return 0, newError(CodeInternal, name, "Failed to decode JSON of: %s", negotiatedAsBytes)
+		}
+		if negotiated.Currency != details.Currency {
+			return 0, newError(CodeInvalidInput, name, "price negotiated by org %s is in %s, article %s is priced in %s", buyerMSP, negotiated.Currency, name, model.CurrencyOf(details))
+		}
+		if newPrice != 0 && newPrice != negotiated.Price {
+			return 0, newError(CodeInvalidInput, name, "newPrice %d differs from the price %d negotiated by org %s", newPrice, negotiated.Price, buyerMSP)
+		}
+		return negotiated.Price, nil
+	}
+
+	// the seller org is no member of the implicit collection of the buyer, which it can
+	// only compare by hash
+	negotiatedHash, hashErr := stub.GetPrivateDataHash(collection, key)
+	if hashErr != nil {
+		return 0, newError(CodeInternal, name, "Failed to get price negotiated by org %s: %v", buyerMSP, hashErr)
+	}
+	if negotiatedHash == nil {
+		if newPrice == 0 {
+			return 0, newError(CodeInvalidInput, name, "newPrice field is required, org %s negotiated no price for article %s", buyerMSP, name)
+		}
+		return newPrice, nil
+	}
+	if newPrice == 0 {
+		return 0, newError(CodeInvalidInput, name, "price negotiated by org %s cannot be read by this peer, pass it as newPrice", buyerMSP)
+	}
+
+	expectedAsBytes, err := model.MarshalCanonical(&model.NegotiatedPrice{
+		ObjectType:  "negotiatedPrice",
+		ArticleName: name,
+		BuyerMSP:    buyerMSP,
+		SellerMSP:   sellerMSP,
+		Price:       newPrice,
+		Currency:    details.Currency,
+	})
+	if err != nil {
+		return 0, err
+	}
+	expectedHash := sha256.Sum256(expectedAsBytes)
+	if !bytes.Equal(negotiatedHash, expectedHash[:]) {
+		return 0, newError(CodeInvalidInput, name, "newPrice %d does not match the price negotiated by org %s", newPrice, buyerMSP)
+	}
+	return newPrice, nil
+}
+
+// moveByPartialCompositeKey moves the records of an article keyed by objectType~name~...
+// to the same keys under newName. Name fields of JSON records are rewritten to newName,
+// other values are copied as they are.
+func moveByPartialCompositeKey(stub shim.ChaincodeStubInterface, collection string, objectType string, oldName string, newName string) error {
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(collection, objectType, []string{oldName})
+	if err != nil {
+		return err
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return err
+		}
+		_, attributes, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return err
+		}
+		attributes[0] = newName
+		newKey, err := stub.CreateCompositeKey(objectType, attributes)
+		if err != nil {
+			return err
+		}
+
+		value := responseRange.Value
+		dec := json.NewDecoder(bytes.NewReader(value))
+		dec.UseNumber()
+		var record map[string]interface{}
+		if dec.Decode(&record) == nil {
+			for _, field := range []string{"name", "articleName"} {
+				if record[field] == oldName {
+					record[field] = newName
+				}
+			}
+			value, err = model.MarshalCanonical(record)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = stub.PutPrivateData(collection, newKey, value)
+		if err != nil {
+			return err
+		}
+		err = stub.DelPrivateData(collection, responseRange.Key)
+		if err != nil {
+			return fmt.Errorf("failed to delete state: %v", err)
+		}
+	}
+	return nil
+}
+
+// ownerCountKey returns the key of a shard of the article count of an owner
+func ownerCountKey(stub shim.ChaincodeStubInterface, owner string, shard int) (string, error) {
+	return stub.CreateCompositeKey(model.OwnerCountIndex, []string{owner, strconv.Itoa(shard)})
+}
+
+// getOwnerCount returns the number of articles of an owner, the sum of its count shards
+func getOwnerCount(stub shim.ChaincodeStubInterface, cfg *model.Config, owner string) (int, error) {
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.OwnerCountIndex, []string{owner})
+	if err != nil {
+		return 0, err
+	}
+	defer resultsIterator.Close()
+
+	count := 0
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.Atoi(string(responseRange.Value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid article count under %s: %s", responseRange.Key, responseRange.Value)
+		}
+		count += n
+	}
+	return count, nil
+}
+
+// adjustOwnerCount adds delta to the article count of an owner. The transaction writes
+// the shard its ID hashes to, so concurrent creations for the same owner rarely conflict
+// on MVCC validation; a shard can go negative, only the sum over the shards is the count.
+// Reads do not see the writes of their own transaction, so a transaction must adjust the
+// count of an owner at most once.
+func adjustOwnerCount(stub shim.ChaincodeStubInterface, cfg *model.Config, owner string, delta int) error {
+	shardHash := fnv.New32a()
+	shardHash.Write([]byte(stub.GetTxID()))
+	key, err := ownerCountKey(stub, owner, int(shardHash.Sum32()%model.OwnerCountShards))
+	if err != nil {
+		return err
+	}
+
+	countAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return fmt.Errorf("failed to get article count of %s: %v", owner, err)
+	}
+	count := 0
+	if countAsBytes != nil {
+		count, err = strconv.Atoi(string(countAsBytes))
+		if err != nil {
+			return fmt.Errorf("invalid article count under %s: %s", key, countAsBytes)
+		}
+	}
+
+	count += delta
+	if count == 0 {
+		return stub.DelPrivateData(cfg.CollectionArticles, key)
+	}
+	return stub.PutPrivateData(cfg.CollectionArticles, key, []byte(strconv.Itoa(count)))
+}
+
+// adjustShareholderCounts adds delta to the article count of every owner of the article,
+// each co-owner of a jointly owned article counts it
+func adjustShareholderCounts(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article, delta int) error {
+	for _, share := range article.Shares() {
+		err := adjustOwnerCount(stub, cfg, share.Owner, delta)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getOpenDispute returns the open dispute of an article with its key, nil when the article
+// is not disputed
+func getOpenDispute(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) (*model.Dispute, string, error) {
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.DisputeIndex, []string{name})
+	if err != nil {
+		return nil, "", err
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return nil, "", err
+		}
+		var dispute model.Dispute
+		err = json.Unmarshal(responseRange.Value, &dispute)
+		if err != nil {
+			return nil, "", newError(CodeInternal, name, "Failed to decode JSON of: %s", responseRange.Value)
+		}
+		if dispute.Status == model.DisputeOpen {
+			return &dispute, responseRange.Key, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// verifyNotDisputed fails with CONFLICT while a dispute about the article is open
+func verifyNotDisputed(article *model.Article) error {
+	if article.Disputed {
+		return newError(CodeConflict, article.Name, "Article %s is disputed, it cannot change hands or be deleted until the dispute is resolved", article.Name)
+	}
+	return nil
+}
+
+// getArticlesByFingerprint returns the names of the articles with a fingerprint, in the
+// order of the fingerprint~hash~name index
+func getArticlesByFingerprint(stub shim.ChaincodeStubInterface, cfg *model.Config, fingerprint string) ([]string, error) {
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.FingerprintIndex, []string{fingerprint})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	names := []string{}
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, compositeKeyParts[1])
+	}
+	return names, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ImportArticle creates an article from the package exportArticle produced on another
+// channel, passed as "article_import" in the transient map. The hash of the package is
+// recomputed and must match the one it carries. The article keeps its properties and salt
+// and records the exportTxID as ImportedFrom; locks, pending transfers and leases stay
+// behind on the old channel. A package carrying only the hash of the private details
+// creates the article with DetailsPending set, addArticlePrivateDetails adds them later.
+// The client must belong to the owner org of the article.
+// ===========================================================================================
+func ImportArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start import article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Article package must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleImportJsonBytes, ok := transMap["article_import"]
+	if !ok {
+		return invalidInput("", "article_import must be a key in the transient map")
+	}
+
+	if len(articleImportJsonBytes) == 0 {
+		return invalidInput("", "article_import value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleImport model.ArticleExport
+	err = model.DecodeTransientInput("article_import", articleImportJsonBytes, &articleImport)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	// ==== The hash covers the package as exported, before any normalization ====
+	hashHex, err := model.ComputeExportHash(&articleImport)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if hashHex != strings.ToLower(articleImport.HashHex) {
+		return invalidInput("", "hashHex of the package does not match its content, computed "+hashHex)
+	}
+
+	err = articleImport.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+	article := articleImport.Article
+
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = verifyOwnerRegistered(stub, cfg, article.Owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Check if article already exists ====
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, article.Name)
+	if err != nil {
+		return internalError(article.Name, "Failed to get article: "+err.Error())
+	} else if articleAsBytes != nil {
+		return alreadyExists(article.Name, "This article already exists: "+article.Name)
+	}
+
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== State tied to records of the old channel does not migrate ====
+	article.ObjectType = model.DefaultDocType
+	article.SchemaVersion = model.CurrentSchemaVersion
+	article.UpdatedAt = txTimestamp
+	article.Locked = false
+	article.LockedBy = ""
+	article.LockExpiry = ""
+	article.PendingTransferTo = ""
+	article.Lessee = ""
+	article.LeaseEndDate = ""
+	article.ImportedFrom = articleImport.ExportTxID
+	article.DetailsPending = articleImport.PrivateDetails == nil && len(articleImport.PrivateDetailsHash) != 0
+
+	var price model.Price
+	currency := ""
+	if articleImport.PrivateDetails != nil {
+		price = articleImport.PrivateDetails.Price
+		currency = articleImport.PrivateDetails.Currency
+	}
+	err = putNewArticle(stub, cfg, article, price, currency)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, article.Name, "importArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleImported", model.ArticleEventEntry{Name: article.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end importArticle (success)")
+	return shim.Success(nil)
+}
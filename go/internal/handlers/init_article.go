@@ -0,0 +1,355 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ============================================================
+// InitArticle - create a new article, store into chaincode state. With "upsert": true in
+// the input, an existing article is updated to the input instead, see upsertArticle.
+// When articles with the same color, size and SKU exist, a new article is created with a
+// DuplicateWarning naming them as payload, or fails with CONFLICT with "strictDuplicates".
+// ============================================================
+func InitArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	var err error
+
+	// ==== Input sanitation ====
+	txLogger(stub).Debugf("start init article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err = verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleJsonBytes, ok := transMap["article"]
+	if !ok {
+		return invalidInput("", "article must be a key in the transient map")
+	}
+
+	if len(articleJsonBytes) == 0 {
+		return invalidInput("", "article value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleInput model.ArticleTransientInput
+	err = model.DecodeTransientInput("article", articleJsonBytes, &articleInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	docType, key, err := checkArticleInput(stub, cfg, &articleInput)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Get the organization of the submitting client, it becomes the owner org ====
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client MSP ID: "+err.Error())
+	}
+
+	// ==== Take the timestamps from the transaction so all endorsers agree on them ====
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Check if article already exists ====
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, key)
+	if err != nil {
+		return internalError(articleInput.Name, "Failed to get article: "+err.Error())
+	} else if articleAsBytes != nil {
+		txLogger(stub).Debugf("article %s already exists", articleInput.Name)
+		if articleInput.Upsert {
+			return upsertArticle(stub, cfg, &articleInput, articleAsBytes, txTimestamp)
+		}
+		return alreadyExists(articleInput.Name, "This article already exists: "+articleInput.Name)
+	}
+
+	// ==== Create article object and save it with its private details and indexes ====
+	article := articleFromInput(&articleInput, docType, clientOrgID, txTimestamp)
+
+	// ==== Warn about articles that look like the same physical article ====
+	var duplicates []string
+	if docType == model.DefaultDocType {
+		duplicates, err = getArticlesByFingerprint(stub, cfg, article.Fingerprint())
+		if err != nil {
+			return errorResponse(err)
+		}
+		if len(duplicates) != 0 && articleInput.StrictDuplicates {
+			return errorResponse(newError(CodeConflict, article.Name, "Article %s has the color, size and SKU of articles %s", article.Name, strings.Join(duplicates, ", ")))
+		}
+	}
+
+	err = putNewArticle(stub, cfg, article, articleInput.Price, articleInput.Currency)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	if articleInput.PublicSummary {
+		err = putPublicSummary(stub, article)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== Record who created the article in which transaction ====
+	if docType == model.DefaultDocType {
+		err = putAuditRecord(stub, cfg, article.Name, "initArticle")
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== Notify listeners that the article was created ====
+	err = setArticleEvent(stub, "ArticleCreated", model.ArticleEventEntry{Name: article.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Article saved and indexed. Return success, with the duplicates found ====
+	txLogger(stub).Infof("end initArticle (success)")
+	if len(duplicates) != 0 {
+		warningJSONasBytes, err := json.Marshal(&model.DuplicateWarning{Fingerprint: article.Fingerprint(), Duplicates: duplicates})
+		if err != nil {
+			return errorResponse(err)
+		}
+		return shim.Success(warningJSONasBytes)
+	}
+	return shim.Success(nil)
+}
+
+// checkArticleInput validates the input of initArticle, normalizes its color and returns
+// the doc type and the key of the article. It only reads, so initArticles checks every
+// article of a batch with it before the first one is written.
+func checkArticleInput(stub shim.ChaincodeStubInterface, cfg *model.Config, articleInput *model.ArticleTransientInput) (string, string, error) {
+	err := articleInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return "", "", newError(CodeInvalidInput, articleInput.Name, "%s", err.Error())
+	}
+
+	// ==== Colors are stored in lowercase and must be on the allow-list when one is set ====
+	articleInput.Color = model.NormalizeColor(articleInput.Color)
+	err = verifyColorAllowed(stub, articleInput.Name, articleInput.Color)
+	if err != nil {
+		return "", "", err
+	}
+
+	docType, err := resolveDocType(cfg, articleInput.DocType)
+	if err != nil {
+		return "", "", err
+	}
+	key, err := articleKey(stub, docType, articleInput.Name)
+	if err != nil {
+		return "", "", err
+	}
+	if strings.HasPrefix(articleInput.Name, model.SelfTestPrefix) {
+		return "", "", newError(CodeInvalidInput, articleInput.Name, "names starting with %s are reserved for selfTest", model.SelfTestPrefix)
+	}
+	if docType != model.DefaultDocType && len(articleInput.SKU) != 0 {
+		return "", "", newError(CodeInvalidInput, articleInput.Name, "sku field is only supported for articles of doc type %s", model.DefaultDocType)
+	}
+
+	// ==== The owner must be an active owner of the registry ====
+	err = verifyOwnerRegistered(stub, cfg, articleInput.Owner)
+	if err != nil {
+		return "", "", err
+	}
+
+	return docType, key, nil
+}
+
+// articleFromInput returns the article initArticle creates from its validated input, owned by
+// the owner org and created at the transaction timestamp
+func articleFromInput(articleInput *model.ArticleTransientInput, docType string, ownerOrg string, txTimestamp string) *model.Article {
+	weightGrams, dimensions, _ := articleInput.Measurements() //checked by Validate
+
+	return &model.Article{
+		ObjectType: docType,
+		Name:       articleInput.Name,
+		Color:      articleInput.Color,
+		Size:       articleInput.Size,
+		Owner:      articleInput.Owner,
+		OwnerOrg:   ownerOrg,
+		Salt:       articleInput.Salt,
+		Quantity:   articleInput.Quantity,
+		Tags:       articleInput.Tags,
+		Category:   articleInput.Category,
+		SKU:        articleInput.SKU,
+		CreatedAt:  txTimestamp,
+		UpdatedAt:  txTimestamp,
+
+		WeightGrams: weightGrams,
+		Dimensions:  dimensions,
+	}
+}
+
+// upsertArticle brings an existing article in line with the input of initArticle, so that
+// retried submissions succeed. When the canonical JSON of the article with the input
+// fields equals the stored one and the price and currency are the stored ones, nothing is
+// written. Otherwise the article, its indexes and its private details are updated. Only the
+// owner org may update the article, and the owner only changes with a transfer. A price of
+// 0 leaves the private details as they are, and the prices of objects of other doc types
+// are not compared.
+func upsertArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, articleInput *model.ArticleTransientInput, articleAsBytes []byte, txTimestamp string) pb.Response {
+	var storedArticle model.Article
+	err := json.Unmarshal(articleAsBytes, &storedArticle)
+	if err != nil {
+		return internalError(articleInput.Name, "Failed to decode JSON of: "+string(articleAsBytes))
+	}
+	err = verifyNotDeleted(&storedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotRetired(&storedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+	_, err = verifyClientIsOwnerOrg(stub, &storedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLockedByOtherOrg(stub, &storedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if storedArticle.Owner != articleInput.Owner {
+		return invalidInput(articleInput.Name, "the owner of "+articleInput.Name+" is "+storedArticle.Owner+", it can only change with a transfer")
+	}
+
+	// ==== Compare the canonical JSON, so the order of the fields does not matter ====
+	article := storedArticle
+	article.Color = articleInput.Color
+	article.Size = articleInput.Size
+	article.Salt = articleInput.Salt
+	article.Quantity = articleInput.Quantity
+	article.Tags = articleInput.Tags
+	article.Category = articleInput.Category
+	article.SKU = articleInput.SKU
+	article.WeightGrams, article.Dimensions, _ = articleInput.Measurements() //checked by Validate
+	storedJSONasBytes, err := model.MarshalCanonical(&storedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+	articleJSONasBytes, err := model.MarshalCanonical(&article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	articleChanged := !bytes.Equal(storedJSONasBytes, articleJSONasBytes)
+
+	var privateDetails *model.ArticlePrivateDetails
+	priceChanged := false
+	if articleInput.Price != 0 && article.ObjectType == model.DefaultDocType {
+		privateDetailsAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, article.Name)
+		if err != nil {
+			return internalError(article.Name, "Failed to get article private details: "+err.Error())
+		}
+		if privateDetailsAsBytes != nil {
+			privateDetails = &model.ArticlePrivateDetails{}
+			err = json.Unmarshal(privateDetailsAsBytes, privateDetails)
+			if err != nil {
+				return internalError(article.Name, "Failed to decode JSON of: "+string(privateDetailsAsBytes))
+			}
+		}
+		priceChanged = privateDetails == nil || privateDetails.Price != articleInput.Price || model.CurrencyOf(privateDetails) != articleInput.Currency
+	}
+
+	if !articleChanged && !priceChanged {
+		txLogger(stub).Infof("end initArticle (unchanged)")
+		return shim.Success(nil)
+	}
+
+	if articleChanged {
+		article.UpdatedAt = txTimestamp
+		if article.ObjectType == model.DefaultDocType {
+			if len(article.SKU) != 0 && article.SKU != storedArticle.SKU {
+				err = verifySKUAvailable(stub, cfg, article.SKU, article.Name)
+				if err != nil {
+					return errorResponse(err)
+				}
+			}
+			// entries of unchanged fields are deleted and written again, the write wins
+			err = removeArticleIndexes(stub, cfg, &storedArticle, stub.DelPrivateData)
+			if err != nil {
+				return errorResponse(err)
+			}
+			indexKeys, err := articleIndexKeys(stub, &article)
+			if err != nil {
+				return errorResponse(err)
+			}
+			for _, indexKey := range indexKeys {
+				err = stub.PutPrivateData(cfg.CollectionArticles, indexKey, []byte{0x00})
+				if err != nil {
+					return errorResponse(err)
+				}
+			}
+			err = putSKUIndex(stub, cfg, &article)
+			if err != nil {
+				return errorResponse(err)
+			}
+		}
+		err = putArticle(stub, cfg, &article)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	if priceChanged {
+		if privateDetails == nil {
+			creatorID, err := cid.GetID(stub)
+			if err != nil {
+				return internalError(article.Name, "Failed to get client ID: "+err.Error())
+			}
+			err = putArticlePrivateDetails(stub, cfg, article.Name, creatorID, articleInput.Currency, 0, articleInput.Price)
+			if err != nil {
+				return errorResponse(err)
+			}
+		} else {
+			err = putArticlePrivateDetails(stub, cfg, article.Name, privateDetails.CreatorID, articleInput.Currency, privateDetails.Price, articleInput.Price)
+			if err != nil {
+				return errorResponse(err)
+			}
+			err = putPriceRecord(stub, cfg, article.Name, privateDetails.Price, articleInput.Price)
+			if err != nil {
+				return errorResponse(err)
+			}
+		}
+	}
+
+	if article.ObjectType == model.DefaultDocType {
+		err = putAuditRecord(stub, cfg, article.Name, "initArticle")
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	err = setArticleEvent(stub, "ArticleUpdated", model.ArticleEventEntry{Name: article.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end initArticle (updated)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+func TestTransientInputDecodingErrors(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"transferArticle": TransferArticle,
+		"delete":          Delete,
+	})
+	for _, test := range []struct {
+		function string
+		key      string
+		input    string
+		message  string
+	}{
+		{"initArticle", "article", `{"name":"article1","colour":"blue"}`, `unknown field \"colour\" in article input`},
+		{"initArticle", "article", `{"name":"article1","color":"blue","size":4.5}`, "size must be an integer, got 4.5"},
+		{"transferArticle", "article_owner", `{"name":"article1","owner":"jerry"} x`, "unexpected data after the JSON object in article_owner input"},
+		{"delete", "article_delete", `{"name":"article1","keepHistory":1}`, "keepHistory must be of type bool, got number"},
+	} {
+		response := n.admin1.InvokeTransient(test.function, testutil.Transient(test.key, test.input))
+		expectCode(t, response, CodeInvalidInput)
+		if !strings.Contains(response.Message, test.message) {
+			t.Errorf("%s rejected %s with %s, expected %s", test.function, test.input, response.Message, test.message)
+		}
+	}
+}
+
+// FuzzInitArticleName creates an article with an arbitrary name. A rejected name must not
+// write anything, and an accepted one must only write keys of valid parts.
+func FuzzInitArticleName(f *testing.F) {
+	for _, name := range []string{"article1", " Café ", "a\x00b", "\x00color~name\x00blue\x00", "a\U0010FFFFb", "\xff\xfe", "tab\there", strings.Repeat("a", 257), "é"} {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		n := newTestNetwork(t, nil)
+		input := map[string]interface{}{"name": name, "color": "blue", "size": 35, "owner": "tom", "salt": testSalt}
+		inputJSON, err := json.Marshal(input)
+		if err != nil {
+			t.Fatalf("failed to encode the input: %v", err)
+		}
+
+		tx := n.user1.Submit(testutil.Invocation{Function: "initArticle", Transient: testutil.Transient("article", string(inputJSON))})
+		if tx.Response.Status != shim.OK {
+			expectCode(t, tx.Response, CodeInvalidInput)
+			if tx.Writes != 0 {
+				t.Fatalf("rejected name %q wrote %d keys", name, tx.Writes)
+			}
+			return
+		}
+
+		for _, key := range n.PrivateKeys(model.DefaultCollectionArticles) {
+			if !isCompositeKey(key) {
+				err = model.ValidateKeyPart("key", key, n.cfg.MaxNameLength)
+				if err != nil {
+					t.Fatalf("name %q wrote the invalid key %q: %v", name, key, err)
+				}
+				continue
+			}
+			objectType, attributes, err := shimtest.NewMockStub("keys", nil).SplitCompositeKey(key)
+			if err != nil {
+				t.Fatalf("name %q wrote the invalid composite key %q: %v", name, key, err)
+			}
+			for _, attribute := range attributes {
+				if strings.ContainsRune(attribute, 0) || strings.ContainsRune(attribute, 0x10FFFF) {
+					t.Fatalf("name %q wrote the %s key %q with a reserved character", name, objectType, key)
+				}
+			}
+		}
+	})
+}
+
+func TestArticleStoredInCanonicalUnits(t *testing.T) {
+	n := newTestNetwork(t, nil)
+	n.createArticle(t, `{"name":"article1","color":"blue","size":35,"owner":"tom","salt":"`+testSalt+`","weight":{"value":1,"unit":"lb"},"dimensions":{"l":1,"w":1.5,"h":0.1,"unit":"in"}}`)
+
+	response := n.user2.Query("readArticle", "article1")
+	expectStatus(t, response, shim.OK)
+	if !strings.Contains(string(response.Payload), `"dimensions":{"h":3,"l":25,"w":38}`) || !strings.Contains(string(response.Payload), `"weightGrams":454`) {
+		t.Errorf("article1 reads as %s, expected 454 grams and 25x38x3 millimetres", response.Payload)
+	}
+	expectCode(t, n.user1.InvokeTransient("initArticle", testutil.Transient("article", `{"name":"article2","color":"blue","size":35,"owner":"tom","salt":"`+testSalt+`","weight":{"value":0.0004,"unit":"kg"}}`)), CodeInvalidInput)
+}
+
+func TestArticleNamesNormalized(t *testing.T) {
+	n := newTestNetwork(t, transferFunctions)
+	precomposed, decomposed := "Caf\u00e9", "Cafe\u0301"
+
+	// created under the decomposed form, stored under the composed one
+	n.createArticle(t, strings.Replace(articleJSON(decomposed, "blue", 35, 9900), `"tom"`, `" tom "`, 1))
+	article := n.readArticle(t, precomposed)
+	if article.Name != precomposed || article.Owner != "tom" {
+		t.Errorf("article is stored as %q of %q, expected %q of tom", article.Name, article.Owner, precomposed)
+	}
+	if n.PrivateData(model.DefaultCollectionArticles, decomposed) != nil {
+		t.Errorf("article is also stored under the decomposed name")
+	}
+
+	// either form and surrounding spaces find it, and none creates a second article
+	for _, name := range []string{precomposed, decomposed, " " + precomposed + " ", "\t" + decomposed} {
+		expectStatus(t, n.user2.Query("readArticle", name), shim.OK)
+		response := n.user1.InvokeTransient("initArticle", testutil.Transient("article", articleJSON(name, "red", 10, 0)))
+		expectCode(t, response, CodeAlreadyExists)
+	}
+
+	response := n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"`+precomposed+`","price":9900,"currency":"EUR"}`))
+	expectStatus(t, response, shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry(" "+decomposed)), shim.OK)
+	if article := n.readArticle(t, precomposed); article.Owner != "jerry" {
+		t.Errorf("article is owned by %s after the transfer to jerry", article.Owner)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// articlesBatchResult reports the articles initArticles created and, for inputs with
+// "upsert": true, the existing articles it updated
+type articlesBatchResult struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated,omitempty"`
+}
+
+// batchStub is the stub initArticle runs on for each article of an initArticles batch. It
+// presents the article as the "article" transient value and collects the entries of the
+// events initArticle sets, so the batch emits a single aggregated event.
+type batchStub struct {
+	shim.ChaincodeStubInterface
+	transient map[string][]byte
+	eventName string
+	events    []model.ArticleEventEntry
+}
+
+func (s *batchStub) GetTransient() (map[string][]byte, error) {
+	return s.transient, nil
+}
+
+func (s *batchStub) SetEvent(name string, payload []byte) error {
+	var event model.ArticleEvent
+	err := json.Unmarshal(payload, &event)
+	if err != nil {
+		return err
+	}
+	s.eventName = name
+	s.events = append(s.events, event.Articles...)
+	return nil
+}
+
+// ===========================================================================================
+// InitArticles - create several articles in one transaction. The transient value under
+// "articles" is a JSON array of initArticle inputs, which may be gzip-compressed for large
+// batches. All articles are validated before any is written, then each is saved like
+// initArticle saves it, and the batch fails as a whole when one of them fails. A single
+// ArticleCreated event lists the created articles.
+// ===========================================================================================
+func InitArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start init articles")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articlesJSONasBytes, ok := transMap["articles"]
+	if !ok {
+		return invalidInput("", "articles must be a key in the transient map")
+	}
+
+	// ==== Inflate a compressed batch before the JSON is decoded ====
+	articlesJSONasBytes, err = inflateTransientValue("articles", articlesJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if len(articlesJSONasBytes) == 0 {
+		return invalidInput("", "articles value in the transient map must be a non-empty JSON array")
+	}
+	txLogger(stub).Debugf("articles value of %d bytes", len(articlesJSONasBytes))
+
+	var articleInputs []json.RawMessage
+	err = model.DecodeTransientInput("articles", articlesJSONasBytes, &articleInputs)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+	if len(articleInputs) == 0 {
+		return invalidInput("", "articles value in the transient map must list at least one article")
+	}
+
+	// ==== Check every article before the first one is written, so a batch with an invalid
+	// article fails without writes. Reads do not see the writes of the transaction either, so
+	// initArticle cannot tell an article or SKU listed twice from a new one. ====
+	articles := make([]model.ArticleTransientInput, len(articleInputs))
+	docTypes := make([]string, len(articleInputs))
+	keys := map[string]bool{}
+	skus := map[string]bool{}
+	for i, articleJSONasBytes := range articleInputs {
+		articleInput := &articles[i]
+		err = model.DecodeTransientInput("article", articleJSONasBytes, articleInput)
+		if err != nil {
+			return invalidInput("", err.Error())
+		}
+		docType, key, err := checkArticleInput(stub, cfg, articleInput)
+		if err != nil {
+			return errorResponse(err)
+		}
+		if keys[key] {
+			return invalidInput(articleInput.Name, "article "+articleInput.Name+" is listed more than once")
+		}
+		keys[key] = true
+		if len(articleInput.SKU) != 0 {
+			if skus[articleInput.SKU] {
+				return invalidInput(articleInput.Name, "SKU "+articleInput.SKU+" is listed more than once")
+			}
+			skus[articleInput.SKU] = true
+		}
+		docTypes[i] = docType
+	}
+
+	result := articlesBatchResult{Created: []string{}}
+	var created, updated []model.ArticleEventEntry
+	ownerCounts := map[string]int{}
+	var owners []string
+	for i, articleJSONasBytes := range articleInputs {
+		batch := &batchStub{ChaincodeStubInterface: stub, transient: map[string][]byte{"article": articleJSONasBytes}}
+		response := InitArticle(batch, cfg, nil)
+		if response.Status != shim.OK {
+			return response
+		}
+
+		articleInput := &articles[i]
+		switch batch.eventName {
+		case "ArticleCreated":
+			result.Created = append(result.Created, articleInput.Name)
+			created = append(created, batch.events...)
+			if docTypes[i] == model.DefaultDocType {
+				if ownerCounts[articleInput.Owner] == 0 {
+					owners = append(owners, articleInput.Owner)
+				}
+				ownerCounts[articleInput.Owner]++
+			}
+		case "ArticleUpdated":
+			result.Updated = append(result.Updated, articleInput.Name)
+			updated = append(updated, batch.events...)
+		}
+	}
+
+	// ==== initArticle counted each new article on its own, but reads do not see the writes
+	// of the transaction: write the count shard of every owner once more with all its new
+	// articles ====
+	for _, owner := range owners {
+		err = adjustOwnerCount(stub, cfg, owner, ownerCounts[owner])
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== A transaction has a single event: the created articles, or the updated ones
+	// when the batch created none ====
+	if len(created) != 0 {
+		err = setArticleEvent(stub, "ArticleCreated", created...)
+	} else if len(updated) != 0 {
+		err = setArticleEvent(stub, "ArticleUpdated", updated...)
+	}
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	resultJSONasBytes, err := json.Marshal(&result)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end initArticles (success), %d created, %d updated", len(result.Created), len(result.Updated))
+	return shim.Success(resultJSONasBytes)
+}
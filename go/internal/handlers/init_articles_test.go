@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// gzipped returns the value compressed the way clients send large batches
+func gzipped(t *testing.T, value string) string {
+	t.Helper()
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte(value))
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	return compressed.String()
+}
+
+func newInitArticlesNetwork(t *testing.T) *testNetwork {
+	return newTestNetwork(t, map[string]HandlerFunc{
+		"initArticles":         InitArticles,
+		"getOwnerArticleCount": GetOwnerArticleCount,
+	})
+}
+
+func TestInitArticlesCompressedAndUncompressed(t *testing.T) {
+	batch := "[" + articleJSON("article1", "blue", 35, 9900) + "," + articleJSON("article2", "red", 40, 0) + "]"
+	for _, articles := range []string{batch, gzipped(t, batch)} {
+		n := newInitArticlesNetwork(t)
+		tx := n.user1.Submit(testutil.Invocation{Function: "initArticles", Transient: testutil.Transient("articles", articles)})
+		expectStatus(t, tx.Response, shim.OK)
+		if string(tx.Response.Payload) != `{"created":["article1","article2"]}` {
+			t.Errorf("initArticles returned %s", tx.Response.Payload)
+		}
+		if len(tx.Events) != 1 || tx.Events[0].EventName != "ArticleCreated" || string(tx.Events[0].Payload) != `{"articles":[{"name":"article1"},{"name":"article2"}]}` {
+			t.Errorf("initArticles set the events %v", tx.Events)
+		}
+
+		if article := n.readArticle(t, "article2"); article.Color != "red" || article.Size != 40 {
+			t.Errorf("article2 is %s of size %d", article.Color, article.Size)
+		}
+		if n.PrivateData(model.DefaultCollectionArticlePrivateDetails, "article1") == nil {
+			t.Errorf("article1 has no private details")
+		}
+		if count := n.articleCount(t, "tom"); count != 2 {
+			t.Errorf("tom has %d articles, expected 2", count)
+		}
+	}
+}
+
+func TestInitArticlesRejectedWithoutWrites(t *testing.T) {
+	n := newInitArticlesNetwork(t)
+	n.createArticle(t, articleJSON("article0", "blue", 35, 0))
+	article1 := articleJSON("article1", "blue", 35, 9900)
+	withSKU := func(name string, sku string) string {
+		return strings.Replace(articleJSON(name, "blue", 35, 0), `"color"`, `"sku":"`+sku+`","color"`, 1)
+	}
+
+	for _, test := range []struct {
+		description string
+		articles    string
+		code        string
+	}{
+		{"corrupt gzip stream", "\x1f\x8b" + "not a gzip stream", CodeInvalidInput},
+		{"truncated gzip stream", gzipped(t, "["+article1+"]")[:20], CodeInvalidInput},
+		{"more than 8 MiB inflated", gzipped(t, "["+strings.Repeat(" ", maxInflatedTransientSize)+"]"), CodeTooLarge},
+		{"empty batch", "[]", CodeInvalidInput},
+		{"invalid second article", "[" + article1 + "," + articleJSON("article2", "blue", -1, 0) + "]", CodeInvalidInput},
+		{"name listed twice", "[" + article1 + "," + article1 + "]", CodeInvalidInput},
+		{"SKU listed twice", "[" + withSKU("article1", "sku-1") + "," + withSKU("article2", "SKU-1") + "]", CodeInvalidInput},
+		{"existing article", "[" + article1 + "," + articleJSON("article0", "blue", 35, 0) + "]", CodeAlreadyExists},
+	} {
+		tx := n.user1.Submit(testutil.Invocation{Function: "initArticles", Transient: testutil.Transient("articles", test.articles)})
+		expectCode(t, tx.Response, test.code)
+		if test.code == CodeInvalidInput && tx.Writes != 0 {
+			t.Errorf("%s was rejected after %d writes", test.description, tx.Writes)
+		}
+	}
+	if n.PrivateData(model.DefaultCollectionArticles, "article1") != nil {
+		t.Errorf("a rejected batch created article1")
+	}
+}
+
+func TestInitArticlesUpsert(t *testing.T) {
+	n := newInitArticlesNetwork(t)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+
+	upsert := strings.Replace(articleJSON("article1", "green", 35, 0), `"color"`, `"upsert":true,"color"`, 1)
+	tx := n.user1.Submit(testutil.Invocation{Function: "initArticles", Transient: testutil.Transient("articles", "["+upsert+","+articleJSON("article2", "red", 40, 0)+"]")})
+	expectStatus(t, tx.Response, shim.OK)
+	if string(tx.Response.Payload) != `{"created":["article2"],"updated":["article1"]}` {
+		t.Errorf("initArticles returned %s", tx.Response.Payload)
+	}
+	if len(tx.Events) != 1 || string(tx.Events[0].Payload) != `{"articles":[{"name":"article2"}]}` {
+		t.Errorf("initArticles set the events %v", tx.Events)
+	}
+	if article := n.readArticle(t, "article1"); article.Color != "green" {
+		t.Errorf("article1 is %s after the upsert", article.Color)
+	}
+	// the updated article was counted when it was created
+	if count := n.articleCount(t, "tom"); count != 2 {
+		t.Errorf("tom has %d articles, expected 2", count)
+	}
+
+	// a batch of updates only announces them
+	tx = n.user1.Submit(testutil.Invocation{Function: "initArticles", Transient: testutil.Transient("articles", "["+strings.Replace(upsert, "green", "white", 1)+"]")})
+	expectStatus(t, tx.Response, shim.OK)
+	if len(tx.Events) != 1 || tx.Events[0].EventName != "ArticleUpdated" {
+		t.Errorf("a batch of updates set the events %v", tx.Events)
+	}
+}
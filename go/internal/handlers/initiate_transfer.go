@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// InitiateTransfer - record the intended transfer of a article together with the orgs that
+// have to approve it, e.g. seller, buyer and an auditor. Each of them approves with
+// approveTransfer, after which the owner org completes the transfer with executeTransfer.
+// ===========================================================================================
+func InitiateTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start initiate transfer")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	initiationJsonBytes, ok := transMap["transfer_initiation"]
+	if !ok {
+		return invalidInput("", "transfer_initiation must be a key in the transient map")
+	}
+
+	if len(initiationJsonBytes) == 0 {
+		return invalidInput("", "transfer_initiation value in the transient map must be a non-empty JSON string")
+	}
+
+	var initiationInput model.TransferInitiationTransientInput
+	err = model.DecodeTransientInput("transfer_initiation", initiationJsonBytes, &initiationInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = initiationInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(initiationInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, initiationInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may initiate a transfer ====
+	clientOrgID, err := verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transferRequest, err := getTransferRequest(stub, cfg, initiationInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if transferRequest != nil {
+		return alreadyExists(initiationInput.Name, "A transfer is already initiated for article: "+initiationInput.Name)
+	}
+
+	// ==== The new owner must be an active owner of the registry ====
+	err = verifyOwnerRegistered(stub, cfg, initiationInput.NewOwner)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyParticipantExists(stub, cfg, initiationInput.NewOwner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	initiatedAt, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	transferRequestJSONasBytes, err := json.Marshal(&model.TransferRequest{
+		ObjectType:  "transferRequest",
+		Name:        initiationInput.Name,
+		NewOwner:    initiationInput.NewOwner,
+		NewOwnerOrg: initiationInput.OwnerOrg,
+		Approvers:   initiationInput.Approvers,
+		InitiatedBy: clientOrgID,
+		InitiatedAt: initiatedAt,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	key, err := transferRequestKey(stub, initiationInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, key, transferRequestJSONasBytes)
+	if err != nil {
+		return internalError(initiationInput.Name, "Failed to put transfer request: "+err.Error())
+	}
+
+	err = putAuditRecord(stub, cfg, initiationInput.Name, "initiateTransfer")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end initiateTransfer (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// LeaseArticle - rent a article out until an end date without transferring it. The lessee
+// and the end date are recorded on the article, and the start of the lease in its lease
+// history. Until the lease ends or is ended with endLease, the article cannot change hands.
+// Only the owner org can lease the article, and only one lease runs at a time.
+// ===========================================================================================
+func LeaseArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start lease article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleLeaseJsonBytes, ok := transMap["article_lease"]
+	if !ok {
+		return invalidInput("", "article_lease must be a key in the transient map")
+	}
+
+	if len(articleLeaseJsonBytes) == 0 {
+		return invalidInput("", "article_lease value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleLeaseInput model.ArticleLeaseTransientInput
+	err = model.DecodeTransientInput("article_lease", articleLeaseJsonBytes, &articleLeaseInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleLeaseInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleLeaseInput.Name, err.Error())
+	}
+
+	articleToLease, err := getArticle(stub, cfg, articleLeaseInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may lease the article ====
+	_, err = verifyClientIsOwnerOrg(stub, articleToLease)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLockedByOtherOrg(stub, articleToLease)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLeased(stub, articleToLease)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	endDate, _ := time.Parse(time.RFC3339, articleLeaseInput.EndDate) //checked by Validate
+	if !endDate.After(now) {
+		return invalidInput(articleLeaseInput.Name, "endDate field must be in the future")
+	}
+
+	articleToLease.Lessee = articleLeaseInput.Lessee
+	articleToLease.LeaseEndDate = articleLeaseInput.EndDate
+	articleToLease.UpdatedAt = now.Format(time.RFC3339)
+
+	err = putArticle(stub, cfg, articleToLease) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putLeaseRecord(stub, cfg, articleToLease.Name, model.LeaseStarted, articleToLease.Lessee, articleToLease.LeaseEndDate)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, articleToLease.Name, "leaseArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end leaseArticle (success)")
+	return shim.Success(nil)
+}
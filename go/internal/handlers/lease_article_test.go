@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// leaseHistory returns the actions of the lease history of the article, oldest first
+func (n *testNetwork) leaseHistory(t *testing.T, name string) []string {
+	t.Helper()
+	response := n.user1.Query("getLeaseHistory", name)
+	expectStatus(t, response, shim.OK)
+	var history []model.LeaseRecord
+	err := json.Unmarshal(response.Payload, &history)
+	if err != nil {
+		t.Fatalf("failed to decode the lease history %s: %v", response.Payload, err)
+	}
+	actions := []string{}
+	for _, record := range history {
+		actions = append(actions, record.Action+" "+record.Lessee)
+	}
+	return actions
+}
+
+func TestLeaseBlocksTransfer(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"leaseArticle":    LeaseArticle,
+		"getLeaseHistory": GetLeaseHistory,
+		"agreeToTransfer": AgreeToTransfer,
+		"transferArticle": TransferArticle,
+	})
+	for _, name := range []string{"article1", "article2"} {
+		n.createArticle(t, articleJSON(name, "blue", 35, 0))
+	}
+	endDate := n.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	lease := func(name string) map[string][]byte {
+		return testutil.Transient("article_lease", `{"name":"`+name+`","lessee":"jerry","endDate":"`+endDate+`"}`)
+	}
+	expectCode(t, n.user2.InvokeTransient("leaseArticle", lease("article1")), CodeAccessDenied)
+	expectStatus(t, n.user1.InvokeTransient("leaseArticle", lease("article1")), shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("leaseArticle", lease("article2")), shim.OK)
+	// jerry agrees to buy the leased articles
+	for _, name := range []string{"article1", "article2"} {
+		expectStatus(t, n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"`+name+`"}`)), shim.OK)
+	}
+	if article := n.readArticle(t, "article1"); article.Owner != "tom" || article.Lessee != "jerry" || article.LeaseEndDate != endDate {
+		t.Errorf("leased article1 has owner %s, lessee %s until %s", article.Owner, article.Lessee, article.LeaseEndDate)
+	}
+
+	// the lease blocks the transfer
+	tx := n.user1.Submit(testutil.Invocation{Function: "transferArticle", Transient: transferToJerry("article1")})
+	expectCode(t, tx.Response, CodeConflict)
+	if !strings.Contains(tx.Response.Message, "Article article1 is leased to jerry until "+endDate) {
+		t.Errorf("transfer of the leased article returned %s", tx.Response.Message)
+	}
+	if tx.Writes != 0 {
+		t.Errorf("transfer of the leased article wrote %d keys", tx.Writes)
+	}
+
+	// unless the transfer terminates it
+	terminate := testutil.Transient("article_owner", `{"name":"article1","owner":"jerry","ownerOrg":"`+org2+`","terminateLease":true}`)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", terminate), shim.OK)
+	if article := n.readArticle(t, "article1"); article.Owner != "jerry" || article.Lessee != "" || article.LeaseEndDate != "" {
+		t.Errorf("transferred article1 has owner %s, lessee %s until %s", article.Owner, article.Lessee, article.LeaseEndDate)
+	}
+	if history := n.leaseHistory(t, "article1"); len(history) != 2 || history[0] != model.LeaseStarted+" jerry" || history[1] != model.LeaseEnded+" jerry" {
+		t.Errorf("lease history of article1 is %q", history)
+	}
+
+	// or the lease has ended
+	n.Advance(time.Hour)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article2")), shim.OK)
+	if history := n.leaseHistory(t, "article2"); len(history) != 1 || history[0] != model.LeaseStarted+" jerry" {
+		t.Errorf("lease history of article2 is %q", history)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ListArticleAttachments returns the attachments of an article ordered by attachment ID.
+// Only members of collectionArticlePrivateDetails can read them.
+// ===========================================================================================
+func ListArticleAttachments(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticlePrivateDetails, model.AttachmentIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	attachments := []model.Attachment{}
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var attachment model.Attachment
+		err = json.Unmarshal(responseRange.Value, &attachment)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	attachmentsJSONasBytes, err := json.Marshal(attachments)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(attachmentsJSONasBytes)
+}
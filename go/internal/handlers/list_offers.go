@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ListOffers returns the offers made on an article, ordered by buyer MSP ID, with their
+// expiry and whether they expired at the transaction timestamp. The prices stay sealed in
+// the implicit collections of the buyers, so only the offers of the client's own org,
+// read through a peer of that org, show their price.
+// ===========================================================================================
+func ListOffers(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(name, "Failed to get client MSP ID: "+err.Error())
+	}
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.OfferCommitmentIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	offers := []model.OfferListing{}
+	for resultsIterator.HasNext() {
+		responseRange, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		var commitment model.OfferCommitment
+		err = json.Unmarshal(responseRange.Value, &commitment)
+		if err != nil {
+			return internalError(name, "Failed to decode JSON of: "+string(responseRange.Value))
+		}
+		expiresAt, err := time.Parse(time.RFC3339, commitment.ExpiresAt)
+		if err != nil {
+			return internalError(name, "invalid expiry of offer: "+err.Error())
+		}
+		listing := model.OfferListing{
+			Name:      commitment.Name,
+			BuyerOrg:  commitment.BuyerOrg,
+			ExpiresAt: commitment.ExpiresAt,
+			MadeAt:    commitment.MadeAt,
+			Expired:   !now.Before(expiresAt),
+		}
+		if commitment.BuyerOrg == clientOrgID && verifyClientOrgMatchesPeerOrg(stub) == nil {
+			offer, err := getOwnOffer(stub, name, clientOrgID)
+			if err != nil {
+				return errorResponse(err)
+			}
+			if offer != nil {
+				listing.Price = &offer.Price
+			}
+		}
+		offers = append(offers, listing)
+	}
+
+	offersJSONasBytes, err := json.Marshal(offers)
+	if err != nil {
+		return errorResponse(err)
+	}
+	offersJSONasBytes, err = formatPrices(cfg, offersJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(offersJSONasBytes)
+}
+
+// getOwnOffer reads the offer of the org on an article from its implicit collection, nil
+// when it made none
+func getOwnOffer(stub shim.ChaincodeStubInterface, name string, orgID string) (*model.Offer, error) {
+	offerKey, err := stub.CreateCompositeKey(model.OfferIndex, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	offerAsBytes, err := stub.GetPrivateData(implicitCollectionName(orgID), offerKey)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to get offer: %v", err)
+	} else if offerAsBytes == nil {
+		return nil, nil
+	}
+	offer := model.Offer{}
+	err = json.Unmarshal(offerAsBytes, &offer)
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to decode JSON of: %s", offerAsBytes)
+	}
+	return &offer, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ListOwners returns the owners of the owner registry, active or not, in name order
+// ===========================================================================================
+func ListOwners(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 0, false)
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting no arguments")
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.OwnerRegistryIndex, []string{})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		_, compositeKeyParts, err := stub.SplitCompositeKey(queryResponse.Key)
+		if err != nil || len(compositeKeyParts) != 1 {
+			return internalError(queryResponse.Key, "invalid owner registry key")
+		}
+
+		err = results.add(compositeKeyParts[0], queryResponse.Value)
+		if err != nil {
+			return internalError(queryResponse.Key, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("listOwners returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
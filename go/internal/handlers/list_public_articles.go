@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ListPublicArticles returns the public summaries of the articles in key order, starting
+// at the optional start key: the objects of other doc types, whose keys start with U+0000,
+// come before the articles, which are ordered by name. Any member of the channel can list them, also without
+// access to the collections. At most MaxResults summaries are returned; a truncated v2
+// response carries the key to start the next query at as lastKey. Names cannot contain
+// utf8.MaxRune, so the range up to the key prefix followed by it holds every summary.
+// ===========================================================================================
+func ListPublicArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 0, false)
+
+	if len(args) > 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting an optional start name")
+	}
+
+	startKey := ""
+	if len(args) == 1 {
+		startKey = args[0]
+	}
+
+	resultsIterator, err := stub.GetStateByRange(publicSummaryKey(startKey), publicSummaryKey(string(utf8.MaxRune)))
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	var results queryResultsBuilder
+	truncated := false
+	lastKey := ""
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		key := strings.TrimPrefix(queryResponse.Key, model.PublicSummaryKeyPrefix)
+		if results.len() == cfg.MaxResults {
+			truncated = true
+			lastKey = key
+			break
+		}
+
+		var summary model.PublicSummary
+		err = json.Unmarshal(queryResponse.Value, &summary)
+		if err != nil {
+			return internalError(key, "Failed to decode JSON of: "+string(queryResponse.Value))
+		}
+		err = results.addResult(&summary)
+		if err != nil {
+			return internalError(key, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := listResponse(results.bytes(), results.len(), truncated, lastKey, format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("listPublicArticles returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// SampleArticleCount is the number of articles loadSampleData creates
+const SampleArticleCount = 25
+
+// Values the sample articles cycle through
+var (
+	sampleColors     = []string{"blue", "red", "green", "yellow", "black", "white"}
+	sampleOwners     = []string{"tom", "jerry", "alice", "bob"}
+	sampleCategories = []string{"furniture/chairs", "furniture/tables", "lighting/lamps", "textiles/rugs"}
+	sampleCurrencies = []string{"EUR", "USD"}
+)
+
+// sampleDataResult reports the sample articles loadSampleData created and skipped
+type sampleDataResult struct {
+	Created []string `json:"created"`
+	Skipped []string `json:"skipped"` //already existing
+}
+
+// ===========================================================================================
+// LoadSampleData - create a deterministic set of SampleArticleCount articles, sample01 to
+// sample25, with varied colors, sizes, owners, categories and prices, for development
+// networks. The articles are validated and saved like initArticle saves them, so their
+// indexes, private details and audit records are consistent. Articles that already exist
+// are skipped, so it can be run again. The sample owners are registered with the org of
+// the client when missing. Only available with ARTICLES_DEV_MODE=true on the chaincode
+// container; the salts are derived from the names, so the samples are not private.
+// ===========================================================================================
+func LoadSampleData(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start load sample data")
+
+	if !cfg.DevMode {
+		return errorResponse(newError(CodeNotSupported, "", "loadSampleData is only available when the chaincode runs with ARTICLES_DEV_MODE=true"))
+	}
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting none")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client MSP ID: "+err.Error())
+	}
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Register the sample owners first, articles can only be created for them ====
+	for _, owner := range sampleOwners {
+		err = registerSampleOwner(stub, cfg, owner, clientOrgID)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	result := sampleDataResult{Created: []string{}, Skipped: []string{}}
+	var events []model.ArticleEventEntry
+	created := map[string]int{}
+	for i := 0; i < SampleArticleCount; i++ {
+		articleInput := sampleArticleInput(i)
+		err = articleInput.Validate(cfg.MaxNameLength)
+		if err != nil {
+			return internalError(articleInput.Name, err.Error())
+		}
+		err = verifyColorAllowed(stub, articleInput.Name, articleInput.Color)
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleInput.Name)
+		if err != nil {
+			return internalError(articleInput.Name, "Failed to get article: "+err.Error())
+		} else if articleAsBytes != nil {
+			result.Skipped = append(result.Skipped, articleInput.Name)
+			continue
+		}
+
+		article := articleFromInput(articleInput, model.DefaultDocType, clientOrgID, txTimestamp)
+		err = putNewArticle(stub, cfg, article, articleInput.Price, articleInput.Currency)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = putAuditRecord(stub, cfg, article.Name, "loadSampleData")
+		if err != nil {
+			return errorResponse(err)
+		}
+		result.Created = append(result.Created, article.Name)
+		created[article.Owner]++
+		events = append(events, model.ArticleEventEntry{Name: article.Name})
+	}
+
+	// ==== putNewArticle counted each article on its own, but reads do not see the writes of
+	// the transaction: write the count shard of every owner once more with all its articles ====
+	for _, owner := range sampleOwners {
+		if created[owner] != 0 {
+			err = adjustOwnerCount(stub, cfg, owner, created[owner])
+			if err != nil {
+				return errorResponse(err)
+			}
+		}
+	}
+
+	if len(events) != 0 {
+		err = setArticleEvent(stub, "ArticleCreated", events...)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	resultJSONasBytes, err := json.Marshal(&result)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end loadSampleData (success), %d created, %d skipped", len(result.Created), len(result.Skipped))
+	return shim.Success(resultJSONasBytes)
+}
+
+// sampleArticleInput returns the initArticle input of the i-th sample article. The values
+// only depend on i, so every endorser creates the same articles.
+func sampleArticleInput(i int) *model.ArticleTransientInput {
+	name := fmt.Sprintf("sample%02d", i+1)
+	salt := sha256.Sum256([]byte("loadSampleData:" + name))
+	return &model.ArticleTransientInput{
+		Name:     name,
+		Color:    sampleColors[i%len(sampleColors)],
+		Size:     10 + 5*(i%10),
+		Owner:    sampleOwners[i%len(sampleOwners)],
+		Price:    model.Price(1000 + 250*i),
+		Currency: sampleCurrencies[i%len(sampleCurrencies)],
+		Salt:     base64.StdEncoding.EncodeToString(salt[:]),
+		Quantity: 1 + i%3,
+		Category: sampleCategories[i%len(sampleCategories)],
+		Tags:     []string{"sample"},
+	}
+}
+
+// registerSampleOwner registers a sample owner with the org of the client unless the
+// registry already knows it
+func registerSampleOwner(stub shim.ChaincodeStubInterface, cfg *model.Config, owner string, mspID string) error {
+	ownerRecord, err := getOwnerRecord(stub, cfg, owner)
+	if err != nil {
+		return err
+	} else if ownerRecord != nil {
+		if !ownerRecord.Active {
+			return newError(CodeUnknownOwner, owner, "owner %s is deactivated", owner)
+		}
+		return nil
+	}
+	registryKey, err := ownerRegistryKey(stub, owner)
+	if err != nil {
+		return err
+	}
+	ownerJSONasBytes, err := json.Marshal(&model.OwnerRecord{
+		ObjectType: "owner",
+		Name:       owner,
+		MSPID:      mspID,
+		Active:     true,
+	})
+	if err != nil {
+		return err
+	}
+	return stub.PutPrivateData(cfg.CollectionArticles, registryKey, ownerJSONasBytes)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// LockArticle - reserve a article for the submitting org for ttlSeconds. Until the lock
+// expires or is released with unlockArticle, other orgs can neither transfer nor delete
+// the article. The org holding the lock can lock again to extend it.
+// ===========================================================================================
+func LockArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start lock article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleLockJsonBytes, ok := transMap["article_lock"]
+	if !ok {
+		return invalidInput("", "article_lock must be a key in the transient map")
+	}
+
+	if len(articleLockJsonBytes) == 0 {
+		return invalidInput("", "article_lock value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleLockInput model.ArticleLockTransientInput
+	err = model.DecodeTransientInput("article_lock", articleLockJsonBytes, &articleLockInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleLockInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleLockInput.Name, err.Error())
+	}
+	if articleLockInput.TTLSeconds == 0 {
+		return invalidInput(articleLockInput.Name, "ttlSeconds field must be a positive integer")
+	}
+
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleLockInput.Name)
+	if err != nil {
+		return internalError(articleLockInput.Name, "Failed to get article:"+err.Error())
+	} else if articleAsBytes == nil {
+		return notFound(articleLockInput.Name, "Article does not exist: "+articleLockInput.Name)
+	}
+
+	articleToLock := model.Article{}
+	err = json.Unmarshal(articleAsBytes, &articleToLock)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotDeleted(&articleToLock)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotRetired(&articleToLock)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = verifyNotLockedByOtherOrg(stub, &articleToLock)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client MSP ID: "+err.Error())
+	}
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	articleToLock.Locked = true
+	articleToLock.LockedBy = clientOrgID
+	articleToLock.LockExpiry = now.Add(time.Duration(articleLockInput.TTLSeconds) * time.Second).Format(time.RFC3339)
+	articleToLock.UpdatedAt = now.Format(time.RFC3339)
+
+	err = putArticle(stub, cfg, &articleToLock) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, articleToLock.Name, "lockArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end lockArticle (success)")
+	return shim.Success(nil)
+}
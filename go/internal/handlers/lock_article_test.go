@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/testutil"
+)
+
+func TestLockBlocksOtherOrgs(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"lockArticle":     LockArticle,
+		"agreeToTransfer": AgreeToTransfer,
+		"transferArticle": TransferArticle,
+		"delete":          Delete,
+	})
+	n.registerOwner(t, "spike", org1)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+	toSpike := testutil.Transient("article_owner", `{"name":"article1","owner":"spike"}`)
+
+	response := n.user2.InvokeTransient("lockArticle", testutil.Transient("article_lock", `{"name":"article1","ttlSeconds":60}`))
+	expectStatus(t, response, shim.OK)
+
+	// the owner org can neither transfer the article to another org nor delete it
+	response = n.user1.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`))
+	expectStatus(t, response, shim.OK)
+	response = n.user1.InvokeTransient("transferArticle", toSpike)
+	expectCode(t, response, CodeAccessDenied)
+	if !strings.Contains(response.Message, "locked by "+org2) {
+		t.Errorf("transfer of the locked article failed with %s", response.Message)
+	}
+	expectCode(t, n.admin1.InvokeTransient("delete", testutil.Transient("article_delete", `{"name":"article1"}`)), CodeAccessDenied)
+
+	// an expired lock is ignored
+	n.Advance(time.Minute)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", toSpike), shim.OK)
+	if article := n.readArticle(t, "article1"); article.Owner != "spike" || article.Locked {
+		t.Errorf("article is owned by %s and locked %v, expected spike and no lock", article.Owner, article.Locked)
+	}
+}
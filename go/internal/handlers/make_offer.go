@@ -0,0 +1,143 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// MakeOffer - offer to buy an article at a price until an expiry time. The offer is stored
+// in the implicit collection of the buyer org, and only its hash is committed to
+// collectionArticles, so the owner org learns the price only when the buyer hands it the
+// offer to accept with acceptOffer. Making another offer replaces the previous one of the
+// org. The offer lapses at expiresAt, measured against the transaction timestamp.
+// ===========================================================================================
+func MakeOffer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start make offer")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	// ==== The offer goes to the implicit collection of the peer's org ====
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	offerJsonBytes, ok := transMap["offer"]
+	if !ok {
+		return invalidInput("", "offer must be a key in the transient map")
+	}
+
+	if len(offerJsonBytes) == 0 {
+		return invalidInput("", "offer value in the transient map must be a non-empty JSON string")
+	}
+
+	var offerInput model.OfferTransientInput
+	err = model.DecodeTransientInput("offer", offerJsonBytes, &offerInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = offerInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(offerInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, offerInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	buyerOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(offerInput.Name, "Failed to get client MSP ID: "+err.Error())
+	}
+	if buyerOrgID == article.OwnerOrg {
+		return accessDenied(offerInput.Name, "the owner org cannot make an offer on its own article")
+	}
+
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	expiresAt, _ := time.Parse(time.RFC3339, offerInput.ExpiresAt) //checked by Validate
+	if !expiresAt.After(now) {
+		return invalidInput(offerInput.Name, "expiresAt field must be in the future")
+	}
+
+	err = verifyOwnerRegistered(stub, cfg, offerInput.Owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Keep the offer in the buyer org's implicit collection ====
+	offerJSONasBytes, err := model.MarshalCanonical(&model.Offer{
+		ObjectType: "offer",
+		Name:       offerInput.Name,
+		Price:      offerInput.Price,
+		Owner:      offerInput.Owner,
+		ExpiresAt:  offerInput.ExpiresAt,
+		Salt:       offerInput.Salt,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	offerKey, err := stub.CreateCompositeKey(model.OfferIndex, []string{offerInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(implicitCollectionName(buyerOrgID), offerKey, offerJSONasBytes)
+	if err != nil {
+		return internalError(offerInput.Name, "Failed to put offer: "+err.Error())
+	}
+
+	// ==== Commit to the offer with its hash ====
+	madeAt, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	offerHash := sha256.Sum256(offerJSONasBytes)
+	commitmentJSONasBytes, err := json.Marshal(&model.OfferCommitment{
+		ObjectType: "offerCommitment",
+		Name:       offerInput.Name,
+		BuyerOrg:   buyerOrgID,
+		Hash:       hex.EncodeToString(offerHash[:]),
+		ExpiresAt:  offerInput.ExpiresAt,
+		MadeAt:     madeAt,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	commitmentKey, err := stub.CreateCompositeKey(model.OfferCommitmentIndex, []string{offerInput.Name, buyerOrgID})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, commitmentKey, commitmentJSONasBytes)
+	if err != nil {
+		return internalError(offerInput.Name, "Failed to put offer commitment: "+err.Error())
+	}
+
+	txLogger(stub).Infof("end makeOffer (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// MergeArticles - combine two articles of the same color and owner. The quantity and the
+// price of mergedName are added to name, then mergedName is deleted together with its
+// indexes and private details.
+// ===========================================================================================
+func MergeArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start merge articles")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleMergeJsonBytes, ok := transMap["article_merge"]
+	if !ok {
+		return invalidInput("", "article_merge must be a key in the transient map")
+	}
+
+	if len(articleMergeJsonBytes) == 0 {
+		return invalidInput("", "article_merge value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleMergeInput model.ArticleMergeTransientInput
+	err = model.DecodeTransientInput("article_merge", articleMergeJsonBytes, &articleMergeInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleMergeInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleMergeInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, articleMergeInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	mergedArticle, err := getArticle(stub, cfg, articleMergeInput.MergedName)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the owner org may merge, and only lots of the same color and owner ====
+	for _, a := range []*model.Article{article, mergedArticle} {
+		_, err = verifyClientIsOwnerOrg(stub, a)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = verifyNotLockedByOtherOrg(stub, a)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = verifyNotDisputed(a)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+	if article.Color != mergedArticle.Color {
+		return invalidInput(article.Name, "articles of different colors cannot be merged: "+article.Color+", "+mergedArticle.Color)
+	}
+	if len(article.Owners) != 0 || len(mergedArticle.Owners) != 0 {
+		return invalidInput(article.Name, "jointly owned articles cannot be merged")
+	}
+	if article.Owner != mergedArticle.Owner || article.OwnerOrg != mergedArticle.OwnerOrg {
+		return invalidInput(article.Name, "articles of different owners cannot be merged: "+article.Owner+", "+mergedArticle.Owner)
+	}
+
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, article.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	mergedPrivateDetails, err := getArticlePrivateDetails(stub, cfg, mergedArticle.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Prices in different currencies cannot be added ====
+	if privateDetails.Currency != mergedPrivateDetails.Currency {
+		return invalidInput(article.Name, "articles priced in different currencies cannot be merged: "+model.CurrencyOf(privateDetails)+", "+model.CurrencyOf(mergedPrivateDetails))
+	}
+
+	// ==== Add the units and the price of the merged article ====
+	article.Quantity = article.Units() + mergedArticle.Units()
+	if article.Quantity <= 0 {
+		return invalidInput(article.Name, "the quantities of the articles do not add up")
+	}
+	article.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putArticle(stub, cfg, article) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+	mergedPrice := privateDetails.Price + mergedPrivateDetails.Price
+	if mergedPrice > model.MaxPrice {
+		return invalidInput(article.Name, fmt.Sprintf("the merged price must be at most %d", model.MaxPrice))
+	}
+	err = putArticlePrivateDetails(stub, cfg, article.Name, privateDetails.CreatorID, privateDetails.Currency, privateDetails.Price, mergedPrice)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Delete the merged article as delete does ====
+	err = stub.DelPrivateData(cfg.CollectionArticles, mergedArticle.Name)
+	if err != nil {
+		return internalError(mergedArticle.Name, "Failed to delete state:"+err.Error())
+	}
+	err = removeArticleIndexes(stub, cfg, mergedArticle, stub.DelPrivateData)
+	if err != nil {
+		return internalError(mergedArticle.Name, err.Error())
+	}
+	err = adjustOwnerCount(stub, cfg, mergedArticle.Owner, -1)
+	if err != nil {
+		return internalError(mergedArticle.Name, err.Error())
+	}
+	err = deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.HistoryIndex, []string{mergedArticle.Name})
+	if err != nil {
+		return internalError(mergedArticle.Name, err.Error())
+	}
+	err = removeArticlePrivateDetails(stub, cfg, mergedPrivateDetails, stub.DelPrivateData)
+	if err != nil {
+		return internalError(mergedArticle.Name, err.Error())
+	}
+	_, err = removePublicSummary(stub, mergedArticle.Name)
+	if err != nil {
+		return internalError(mergedArticle.Name, err.Error())
+	}
+
+	err = putAuditRecord(stub, cfg, article.Name, "mergeArticles")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleDeleted", model.ArticleEventEntry{Name: mergedArticle.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end mergeArticles (success)")
+	return shim.Success(nil)
+}
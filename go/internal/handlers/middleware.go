@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// HandlerFunc is the signature of the functions Invoke dispatches to
+type HandlerFunc func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response
+
+// Wrap returns the handler of the function wrapped in the middleware every invocation goes
+// through: a panic is recovered and returned as an INTERNAL error naming the function, and
+// the wall-clock duration of the handler is logged. Transient values above the configured
+// maximum size are rejected before the handler decodes them.
+func Wrap(function string, handler HandlerFunc) HandlerFunc {
+	return func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) (response pb.Response) {
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				txLogger(stub).Errorf("%s panicked: %v", function, r)
+				response = internalError("", fmt.Sprintf("%s failed unexpectedly: %v", function, r))
+			}
+			txLogger(stub).Infof("%s took %s, status %d", function, time.Since(start), response.Status)
+		}()
+
+		err := verifyTransientSizes(stub, cfg)
+		if err != nil {
+			return errorResponse(err)
+		}
+		return handler(stub, cfg, args)
+	}
+}
+
+// verifyTransientSizes fails with TOO_LARGE when a value of the transient map is larger
+// than cfg.MaxTransientSize. The keys are checked in order so the error does not depend
+// on the iteration order of the map. A transient map that cannot be read is left to the
+// handler to report.
+func verifyTransientSizes(stub shim.ChaincodeStubInterface, cfg *model.Config) error {
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(transMap))
+	for key := range transMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if len(transMap[key]) > cfg.MaxTransientSize {
+			return newError(CodeTooLarge, "", "%s value in the transient map is %d bytes, the maximum is %d bytes", key, len(transMap[key]), cfg.MaxTransientSize)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// padded returns the JSON padded with trailing spaces to size bytes
+func padded(json string, size int) []byte {
+	return []byte(json + strings.Repeat(" ", size-len(json)))
+}
+
+func TestTransientSizeLimit(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"transferArticle": TransferArticle,
+		"delete":          Delete,
+	})
+	limit := model.DefaultMaxTransientSize
+	if n.cfg.MaxTransientSize != limit {
+		t.Fatalf("default maximum transient size is %d, expected 1 MiB", n.cfg.MaxTransientSize)
+	}
+
+	for _, test := range []struct {
+		function string
+		key      string
+		value    string
+	}{
+		{"initArticle", "article", articleJSON("article1", "blue", 35, 9900)},
+		{"transferArticle", "article_owner", `{"name":"article1","owner":"jerry","ownerOrg":"` + org2 + `"}`},
+		{"delete", "article_delete", `{"name":"article1"}`},
+	} {
+		// just over the limit the value is rejected before it is decoded, whatever it holds
+		for _, value := range [][]byte{padded(test.value, limit+1), padded("{", limit+1)} {
+			tx := n.admin1.Submit(testutil.Invocation{Function: test.function, Transient: testutil.Transient(test.key, string(value))})
+			expectCode(t, tx.Response, CodeTooLarge)
+			if tx.Response.Status != 413 {
+				t.Errorf("%s returned status %d for a transient value over the limit", test.function, tx.Response.Status)
+			}
+			expected := fmt.Sprintf("%s value in the transient map is %d bytes, the maximum is %d bytes", test.key, limit+1, limit)
+			if !strings.Contains(tx.Response.Message, expected) {
+				t.Errorf("%s returned %s, expected %s", test.function, tx.Response.Message, expected)
+			}
+			if tx.Writes != 0 {
+				t.Errorf("%s wrote %d keys for a transient value over the limit", test.function, tx.Writes)
+			}
+		}
+
+		// at the limit it is decoded as usual
+		expectCode(t, n.admin1.InvokeTransient(test.function, testutil.Transient(test.key, string(padded("{", limit)))), CodeInvalidInput)
+		response := n.user1.InvokeTransient(test.function, testutil.Transient(test.key, string(padded(test.value, limit))))
+		if test.function == "transferArticle" {
+			// transfers need the agreement of the buyer, which says the value was decoded
+			expectCode(t, response, CodeAccessDenied)
+			continue
+		}
+		if test.function == "delete" {
+			// delete needs an admin, who then deletes article1
+			expectCode(t, response, CodeAccessDenied)
+			response = n.admin1.InvokeTransient(test.function, testutil.Transient(test.key, string(padded(test.value, limit))))
+		}
+		expectStatus(t, response, shim.OK)
+	}
+
+	// the limit is configurable
+	article2 := articleJSON("article2", "blue", 35, 9900)
+	n.cfg.MaxTransientSize = len(article2)
+	expectCode(t, n.user1.InvokeTransient("initArticle", testutil.Transient("article", string(padded(article2, len(article2)+1)))), CodeTooLarge)
+	expectStatus(t, n.user1.InvokeTransient("initArticle", testutil.Transient("article", article2)), shim.OK)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// MigrateArticles rewrites the articles between the start and end keys that predate the
+// current schema version, filling in defaults for the fields added since and lowercasing
+// colors together with their color~name index entries. Their private
+// details are upgraded as well when the peer can read them. Records already in the current
+// version are skipped, so the function can be run repeatedly and in batches: each batch
+// starts after the lastKey reported by the previous one. Only admins may call it.
+// ===========================================================================================
+func MigrateArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start migrate articles")
+
+	if len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting start and end key, either may be empty")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticles, args[0], args[1])
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	report := model.MigrationReport{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+		report.LastKey = queryResponse.Key
+
+		var article model.Article
+		err = json.Unmarshal(queryResponse.Value, &article)
+		if err != nil || article.SchemaVersion >= model.CurrentSchemaVersion {
+			report.Skipped++
+			continue
+		}
+
+		// ==== Defaults of the fields added before versioning ====
+		if len(article.ObjectType) == 0 {
+			article.ObjectType = model.DefaultDocType
+		}
+		article.Quantity = article.Units()
+
+		// ==== Version 2 stores colors in lowercase, move the color~name entry along ====
+		if color := model.NormalizeColor(article.Color); color != article.Color {
+			if !article.Deleted && !article.Retired {
+				err = moveColorIndex(stub, cfg, &article, color)
+				if err != nil {
+					return internalError(article.Name, err.Error())
+				}
+			}
+			article.Color = color
+		}
+
+		err = putArticle(stub, cfg, &article)
+		if err != nil {
+			return internalError(article.Name, err.Error())
+		}
+
+		// the private details carry the version too, members of their collection upgrade them
+		privateDetails, err := getArticlePrivateDetails(stub, cfg, article.Name)
+		if err == nil && privateDetails.SchemaVersion < model.CurrentSchemaVersion {
+			err = putArticlePrivateDetails(stub, cfg, privateDetails.Name, privateDetails.CreatorID, privateDetails.Currency, privateDetails.Price, privateDetails.Price)
+			if err != nil {
+				return internalError(article.Name, err.Error())
+			}
+		}
+		report.Migrated++
+	}
+
+	reportJSONasBytes, err := json.Marshal(&report)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end migrateArticles: %s", reportJSONasBytes)
+	return shim.Success(reportJSONasBytes)
+}
+
+// moveColorIndex replaces the color~name index entry of an article with the one for the new color
+func moveColorIndex(stub shim.ChaincodeStubInterface, cfg *model.Config, article *model.Article, color string) error {
+	oldColorNameIndexKey, err := stub.CreateCompositeKey(model.ColorNameIndex, []string{article.Color, article.Name})
+	if err != nil {
+		return err
+	}
+	err = stub.DelPrivateData(cfg.CollectionArticles, oldColorNameIndexKey)
+	if err != nil {
+		return fmt.Errorf("Failed to delete state:%v", err)
+	}
+	colorNameIndexKey, err := stub.CreateCompositeKey(model.ColorNameIndex, []string{color, article.Name})
+	if err != nil {
+		return err
+	}
+	return stub.PutPrivateData(cfg.CollectionArticles, colorNameIndexKey, []byte{0x00})
+}
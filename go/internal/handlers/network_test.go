@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+const (
+	org1 = "Org1MSP"
+	org2 = "Org2MSP"
+)
+
+// testSalt is a valid salt of the articles created by the tests
+const testSalt = "c2FsdHNhbHRzYWx0c2FsdHNhbHQ="
+
+// baseFunctions are the functions every test network has besides those under test
+var baseFunctions = map[string]HandlerFunc{
+	"initArticle":               InitArticle,
+	"registerOwner":             RegisterOwner,
+	"readArticle":               ReadArticle,
+	"readArticlePrivateDetails": ReadArticlePrivateDetails,
+}
+
+// testChaincode dispatches to the handlers the way main.go does, each wrapped in Wrap
+type testChaincode struct {
+	cfg       *model.Config
+	functions map[string]HandlerFunc
+}
+
+func (t *testChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	return shim.Success(nil)
+}
+
+func (t *testChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	function, args := stub.GetFunctionAndParameters()
+	handler, ok := t.functions[function]
+	if !ok {
+		return shim.Error("Received unknown function invocation " + function)
+	}
+	return Wrap(function, handler)(stub, t.cfg, args)
+}
+
+// testNetwork is a channel of Org1 and Org2, both members of the articles collection
+// and only Org1 of the private details collection. The owners tom of Org1 and jerry of
+// Org2 are registered.
+type testNetwork struct {
+	*testutil.Network
+	cfg    *model.Config
+	admin1 *testutil.Client
+	user1  *testutil.Client
+	admin2 *testutil.Client
+	user2  *testutil.Client
+}
+
+// newTestNetwork deploys the base functions and the functions under test with the
+// default configuration, which the test may change through cfg
+func newTestNetwork(t *testing.T, functions map[string]HandlerFunc) *testNetwork {
+	t.Helper()
+	cc := &testChaincode{cfg: model.DefaultConfig(), functions: map[string]HandlerFunc{}}
+	for function, handler := range baseFunctions {
+		cc.functions[function] = handler
+	}
+	for function, handler := range functions {
+		cc.functions[function] = handler
+	}
+	network := testutil.NewNetwork(t, cc,
+		testutil.CollectionConfig{Name: model.DefaultCollectionArticles, Members: []string{org1, org2}, MemberOnlyRead: true},
+		testutil.CollectionConfig{Name: model.DefaultCollectionArticlePrivateDetails, Members: []string{org1}, MemberOnlyRead: true},
+	)
+	n := &testNetwork{
+		Network: network,
+		cfg:     cc.cfg,
+		admin1:  network.Client(org1, "admin1", map[string]string{model.AdminAttribute: "true"}),
+		user1:   network.Client(org1, "user1", nil),
+		admin2:  network.Client(org2, "admin2", map[string]string{model.AdminAttribute: "true"}),
+		user2:   network.Client(org2, "user2", nil),
+	}
+	n.registerOwner(t, "tom", org1)
+	n.registerOwner(t, "jerry", org2)
+	return n
+}
+
+func (n *testNetwork) registerOwner(t *testing.T, owner string, mspID string) {
+	t.Helper()
+	response := n.admin1.InvokeTransient("registerOwner", testutil.Transient("owner_registration", `{"name":"`+owner+`","mspID":"`+mspID+`"}`))
+	expectStatus(t, response, shim.OK)
+}
+
+// createArticle creates the article with initArticle as a client of Org1
+func (n *testNetwork) createArticle(t *testing.T, articleJSON string) {
+	t.Helper()
+	expectStatus(t, n.user1.InvokeTransient("initArticle", testutil.Transient("article", articleJSON)), shim.OK)
+}
+
+// readArticle returns the committed article
+func (n *testNetwork) readArticle(t *testing.T, name string) *model.Article {
+	t.Helper()
+	articleAsBytes := n.PrivateData(model.DefaultCollectionArticles, name)
+	if articleAsBytes == nil {
+		t.Fatalf("article %s does not exist", name)
+	}
+	article := &model.Article{}
+	err := json.Unmarshal(articleAsBytes, article)
+	if err != nil {
+		t.Fatalf("failed to decode article %s: %v", name, err)
+	}
+	return article
+}
+
+// compositeKey returns the composite key of the attributes, as the handlers create it
+func compositeKey(t *testing.T, objectType string, attributes ...string) string {
+	t.Helper()
+	key, err := shim.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		t.Fatalf("failed to create %s key: %v", objectType, err)
+	}
+	return key
+}
+
+// articleJSON returns the initArticle input of an article of tom with the size and price,
+// no price when it is 0
+func articleJSON(name string, color string, size int, price int) string {
+	input := map[string]interface{}{
+		"name":     name,
+		"color":    color,
+		"size":     size,
+		"owner":    "tom",
+		"salt":     testSalt,
+		"quantity": 1,
+	}
+	if price != 0 {
+		input["price"] = price
+		input["currency"] = "EUR"
+	}
+	inputJSON, _ := json.Marshal(input)
+	return string(inputJSON)
+}
+
+func expectStatus(t *testing.T, response pb.Response, status int32) {
+	t.Helper()
+	if response.Status != status {
+		t.Fatalf("expected status %d, got %d: %s", status, response.Status, response.Message)
+	}
+}
+
+// expectCode fails unless the response is an error with the code
+func expectCode(t *testing.T, response pb.Response, code string) {
+	t.Helper()
+	var ccErr chaincodeError
+	err := json.Unmarshal([]byte(response.Message), &ccErr)
+	if err != nil || ccErr.Code != code {
+		t.Fatalf("expected %s, got status %d: %s", code, response.Status, response.Message)
+	}
+}
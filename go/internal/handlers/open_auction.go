@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// OpenAuction - put a article up for auction with a minimum price. Bids are sealed in the
+// implicit collections of the bidder orgs until closeTime and revealed until the reveal
+// deadline, after which the owner org closes the auction with closeAuction.
+// ===========================================================================================
+func OpenAuction(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start open auction")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	auctionJsonBytes, ok := transMap["auction"]
+	if !ok {
+		return invalidInput("", "auction must be a key in the transient map")
+	}
+
+	if len(auctionJsonBytes) == 0 {
+		return invalidInput("", "auction value in the transient map must be a non-empty JSON string")
+	}
+
+	var auctionInput model.AuctionTransientInput
+	err = model.DecodeTransientInput("auction", auctionJsonBytes, &auctionInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = auctionInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(auctionInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, auctionInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the owner org may auction the article ====
+	sellerOrgID, err := verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLockedByOtherOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	auction, err := getAuction(stub, cfg, auctionInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if auction != nil && auction.Status == model.AuctionOpen {
+		return alreadyExists(auctionInput.Name, "An auction is already open for article: "+auctionInput.Name)
+	}
+
+	// ==== Bids are accepted from now until the close time ====
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	closeTime, _ := time.Parse(time.RFC3339, auctionInput.CloseTime) //checked by Validate
+	if !closeTime.After(now) {
+		return invalidInput(auctionInput.Name, "closeTime field must be in the future")
+	}
+	revealDeadline := closeTime.Add(model.DefaultRevealPeriodSeconds * time.Second)
+	if len(auctionInput.RevealDeadline) != 0 {
+		revealDeadline, _ = time.Parse(time.RFC3339, auctionInput.RevealDeadline)
+	}
+
+	// ==== The bids of an earlier auction do not carry over ====
+	err = deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.BidCommitmentIndex, []string{auctionInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = deleteByPartialCompositeKey(stub, cfg.CollectionArticles, model.RevealedBidIndex, []string{auctionInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuction(stub, cfg, &model.Auction{
+		ObjectType:     "auction",
+		Name:           auctionInput.Name,
+		SellerOrg:      sellerOrgID,
+		MinPrice:       auctionInput.MinPrice,
+		CloseTime:      closeTime.UTC().Format(time.RFC3339),
+		RevealDeadline: revealDeadline.UTC().Format(time.RFC3339),
+		Status:         model.AuctionOpen,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "AuctionOpened", model.ArticleEventEntry{Name: auctionInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end openAuction (success)")
+	return shim.Success(nil)
+}
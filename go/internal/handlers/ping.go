@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// pingStatus is the result of ping
+type pingStatus struct {
+	Status    string `json:"status"`
+	TxID      string `json:"txId"`
+	Timestamp string `json:"timestamp"` //RFC3339 transaction timestamp
+}
+
+// ===============================================
+// Ping - confirm that the chaincode is alive. It neither reads nor writes state.
+// ===============================================
+func Ping(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting no arguments")
+	}
+
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	pingJSONasBytes, err := json.Marshal(&pingStatus{
+		Status:    "OK",
+		TxID:      stub.GetTxID(),
+		Timestamp: txTimestamp,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	return shim.Success(pingJSONasBytes)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// PlaceBid - place a sealed bid on an open auction. The bid is stored in the implicit
+// collection of the bidder org, and only its hash is committed to collectionArticles, so
+// neither the seller nor the other bidders learn the amount before the close of the
+// auction. Placing another bid replaces the previous one of the org.
+// ===========================================================================================
+func PlaceBid(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start place bid")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	// ==== The bid goes to the implicit collection of the peer's org ====
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	bidJsonBytes, ok := transMap["bid"]
+	if !ok {
+		return invalidInput("", "bid must be a key in the transient map")
+	}
+
+	if len(bidJsonBytes) == 0 {
+		return invalidInput("", "bid value in the transient map must be a non-empty JSON string")
+	}
+
+	var bidInput model.BidTransientInput
+	err = model.DecodeTransientInput("bid", bidJsonBytes, &bidInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = bidInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(bidInput.Name, err.Error())
+	}
+
+	auction, err := getOpenAuction(stub, cfg, bidInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	bidderOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(bidInput.Name, "Failed to get client MSP ID: "+err.Error())
+	}
+	if bidderOrgID == auction.SellerOrg {
+		return accessDenied(bidInput.Name, "the seller org cannot bid on its own auction")
+	}
+
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	closeTime, _, err := auctionTimes(auction)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if !now.Before(closeTime) {
+		return accessDenied(bidInput.Name, "the auction of article "+bidInput.Name+" closed at "+auction.CloseTime)
+	}
+
+	err = verifyOwnerRegistered(stub, cfg, bidInput.Owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Seal the bid in the bidder org's implicit collection ====
+	bidJSONasBytes, err := model.MarshalCanonical(&model.Bid{
+		ObjectType: "bid",
+		Name:       bidInput.Name,
+		Amount:     bidInput.Amount,
+		Owner:      bidInput.Owner,
+		Salt:       bidInput.Salt,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	bidKey, err := stub.CreateCompositeKey(model.BidIndex, []string{bidInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(implicitCollectionName(bidderOrgID), bidKey, bidJSONasBytes)
+	if err != nil {
+		return internalError(bidInput.Name, "Failed to put bid: "+err.Error())
+	}
+
+	// ==== Commit to the bid with its hash ====
+	placedAt, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	bidHash := sha256.Sum256(bidJSONasBytes)
+	commitmentJSONasBytes, err := json.Marshal(&model.BidCommitment{
+		ObjectType: "bidCommitment",
+		Name:       bidInput.Name,
+		BidderOrg:  bidderOrgID,
+		Hash:       hex.EncodeToString(bidHash[:]),
+		PlacedAt:   placedAt,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	commitmentKey, err := stub.CreateCompositeKey(model.BidCommitmentIndex, []string{bidInput.Name, bidderOrgID})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, commitmentKey, commitmentJSONasBytes)
+	if err != nil {
+		return internalError(bidInput.Name, "Failed to put bid commitment: "+err.Error())
+	}
+
+	txLogger(stub).Infof("end placeBid (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"privatemarbles/internal/model"
+)
+
+// priceFields are the JSON fields of responses that hold prices
+var priceFields = map[string]bool{
+	"price":       true,
+	"oldPrice":    true,
+	"newPrice":    true,
+	"minPrice":    true,
+	"amount":      true,
+	"AskingPrice": true,
+	"min":         true,
+	"max":         true,
+	"sum":         true,
+}
+
+// formatPrices returns a response payload with its prices in the configured format. With
+// model.PriceFormatString the integer values of the price fields become JSON strings, so
+// clients that decode numbers as doubles do not lose precision above 2^53. The payload
+// is returned unchanged in the default model.PriceFormatNumber.
+func formatPrices(cfg *model.Config, payload []byte) ([]byte, error) {
+	if cfg.PriceFormat != model.PriceFormatString || len(payload) == 0 {
+		return payload, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var value interface{}
+	err := dec.Decode(&value)
+	if err != nil {
+		return nil, newError(CodeInternal, "", "Failed to decode response: %v", err)
+	}
+	return model.MarshalCanonical(pricesAsStrings(value))
+}
+
+// pricesAsStrings replaces the integer values of the price fields in a decoded JSON value
+func pricesAsStrings(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if number, ok := field.(json.Number); ok && priceFields[key] && !strings.ContainsAny(number.String(), ".eE") {
+				v[key] = number.String()
+				continue
+			}
+			v[key] = pricesAsStrings(field)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = pricesAsStrings(element)
+		}
+	}
+	return value
+}
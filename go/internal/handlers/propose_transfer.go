@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ProposeTransfer - propose to transfer a article to an owner of another org. The article
+// stays with its owner, showing the receiving org in pendingTransferTo, until that org
+// accepts the transfer with acceptTransfer or rejects it with rejectTransfer. A proposal
+// not accepted within ttlSeconds can be cancelled by the owner org with cancelTransfer.
+// ===========================================================================================
+func ProposeTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start propose transfer")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	proposalJsonBytes, ok := transMap["transfer_proposal"]
+	if !ok {
+		return invalidInput("", "transfer_proposal must be a key in the transient map")
+	}
+
+	if len(proposalJsonBytes) == 0 {
+		return invalidInput("", "transfer_proposal value in the transient map must be a non-empty JSON string")
+	}
+
+	var proposalInput model.TransferProposalTransientInput
+	err = model.DecodeTransientInput("transfer_proposal", proposalJsonBytes, &proposalInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = proposalInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(proposalInput.Name, err.Error())
+	}
+	if proposalInput.TTLSeconds == 0 {
+		proposalInput.TTLSeconds = model.DefaultTransferTTLSeconds
+	}
+
+	article, err := getArticle(stub, cfg, proposalInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may propose a transfer ====
+	clientOrgID, err := verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLockedByOtherOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	if len(article.PendingTransferTo) != 0 {
+		return alreadyExists(article.Name, "A transfer to org "+article.PendingTransferTo+" is already pending for article: "+article.Name)
+	}
+
+	// ==== The new owner must be an active owner of the registry ====
+	err = verifyOwnerRegistered(stub, cfg, proposalInput.NewOwner)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyParticipantExists(stub, cfg, proposalInput.NewOwner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	pendingTransferJSONasBytes, err := json.Marshal(&model.PendingTransfer{
+		ObjectType: "pendingTransfer",
+		Name:       article.Name,
+		NewOwner:   proposalInput.NewOwner,
+		TargetOrg:  proposalInput.OwnerOrg,
+		ProposedBy: clientOrgID,
+		Expiry:     now.Add(time.Duration(proposalInput.TTLSeconds) * time.Second).Format(time.RFC3339),
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	key, err := pendingTransferKey(stub, article.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, key, pendingTransferJSONasBytes)
+	if err != nil {
+		return internalError(article.Name, "Failed to put pending transfer: "+err.Error())
+	}
+
+	article.PendingTransferTo = proposalInput.OwnerOrg
+	article.UpdatedAt = now.Format(time.RFC3339)
+
+	err = putArticle(stub, cfg, article) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, article.Name, "proposeTransfer")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "TransferProposed", model.ArticleEventEntry{Name: article.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end proposeTransfer (success)")
+	return shim.Success(nil)
+}
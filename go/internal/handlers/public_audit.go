@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// writeTrackingStub records the private data an invocation writes and deletes, so the
+// hashes the data has after the invocation are known before it commits. GetPrivateDataHash
+// only returns the committed hash, private data has no read-your-writes.
+type writeTrackingStub struct {
+	shim.ChaincodeStubInterface
+	written map[string]map[string][]byte //collection to key to value, nil once deleted
+	names   []string                     //article keys written or deleted in collectionArticles, in order
+	cfg     *model.Config
+}
+
+func (s *writeTrackingStub) track(collection string, key string, value []byte) {
+	if s.written[collection] == nil {
+		s.written[collection] = map[string][]byte{}
+	}
+	if _, ok := s.written[collection][key]; !ok && collection == s.cfg.CollectionArticles && !isCompositeKey(key) {
+		s.names = append(s.names, key)
+	}
+	s.written[collection][key] = value
+}
+
+// PutPrivateData writes the value and tracks it
+func (s *writeTrackingStub) PutPrivateData(collection string, key string, value []byte) error {
+	err := s.ChaincodeStubInterface.PutPrivateData(collection, key, value)
+	if err == nil {
+		s.track(collection, key, value)
+	}
+	return err
+}
+
+// DelPrivateData deletes the key and tracks the deletion
+func (s *writeTrackingStub) DelPrivateData(collection string, key string) error {
+	err := s.ChaincodeStubInterface.DelPrivateData(collection, key)
+	if err == nil {
+		s.track(collection, key, nil)
+	}
+	return err
+}
+
+// hashAfter returns the hex SHA-256 the key has once the invocation commits: the hash of
+// the value written, nothing for a deleted key and the committed hash of an untouched key
+func (s *writeTrackingStub) hashAfter(collection string, key string) (string, error) {
+	if value, ok := s.written[collection][key]; ok {
+		if value == nil {
+			return "", nil
+		}
+		hash := sha256.Sum256(value)
+		return hex.EncodeToString(hash[:]), nil
+	}
+	hash, err := s.ChaincodeStubInterface.GetPrivateDataHash(collection, key)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash), nil
+}
+
+// WithPublicAudit returns the handler of the function recording every article it changes
+// in the public audit trail once it succeeds, see putPublicAuditRecord. The changed
+// articles are the plain keys the handler writes or deletes in collectionArticles: objects
+// of other doc types, stored under composite keys, and private data purged rather than
+// deleted leave no record.
+func WithPublicAudit(function string, handler HandlerFunc) HandlerFunc {
+	return func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+		trackingStub := &writeTrackingStub{ChaincodeStubInterface: stub, written: map[string]map[string][]byte{}, cfg: cfg}
+		response := handler(trackingStub, cfg, args)
+		if response.Status != shim.OK {
+			return response
+		}
+		for _, name := range trackingStub.names {
+			err := putPublicAuditRecord(trackingStub, cfg, name, function)
+			if err != nil {
+				return errorResponse(err)
+			}
+		}
+		return response
+	}
+}
+
+// putPublicAuditRecord writes a PublicAuditRecord of the change of an article to the
+// public world state under audit~name~txid. Apart from the function ACL and the allowed
+// colors set by admins, this is the only public state the chaincode writes, and the only
+// one about articles: it must never hold anything but the name of the article, the MSP ID
+// of the client and private data hashes, which article names already are in events.
+func putPublicAuditRecord(stub *writeTrackingStub, cfg *model.Config, name string, function string) error {
+	clientOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return fmt.Errorf("Failed to get client MSP ID: %v", err)
+	}
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return err
+	}
+	articleHash, err := stub.hashAfter(cfg.CollectionArticles, name)
+	if err != nil {
+		return fmt.Errorf("Failed to get article hash: %v", err)
+	}
+	detailsHash, err := stub.hashAfter(cfg.CollectionArticlePrivateDetails, name)
+	if err != nil {
+		return fmt.Errorf("Failed to get private details hash: %v", err)
+	}
+
+	recordJSONasBytes, err := json.Marshal(&model.PublicAuditRecord{
+		ObjectType:         "publicAuditRecord",
+		Name:               name,
+		TxID:               stub.GetTxID(),
+		Function:           function,
+		MSPID:              clientOrgID,
+		Timestamp:          txTimestamp,
+		ArticleHash:        articleHash,
+		PrivateDetailsHash: detailsHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	auditKey, err := stub.CreateCompositeKey(model.AuditIndex, []string{name, stub.GetTxID()})
+	if err != nil {
+		return err
+	}
+	return stub.PutState(auditKey, recordJSONasBytes)
+}
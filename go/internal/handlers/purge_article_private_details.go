@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// PurgeArticlePrivateDetails - purge the private details of a article, and optionally the
+// article itself, from the peers. Unlike delete, which only removes the keys from the
+// current state, a purge also removes the private data history. Requires Fabric 2.4+.
+// ===========================================================================================
+func PurgeArticlePrivateDetails(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start purge article private details")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article name must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	purger, ok := stub.(privateDataPurger)
+	if !ok {
+		return errorResponse(newError(CodeNotSupported, "", "Purging private data is not supported by this peer, it requires Fabric 2.4 or later"))
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articlePurgeJsonBytes, ok := transMap["article_purge"]
+	if !ok {
+		return invalidInput("", "article_purge must be a key in the transient map")
+	}
+
+	if len(articlePurgeJsonBytes) == 0 {
+		return invalidInput("", "article_purge value in the transient map must be a non-empty JSON string")
+	}
+
+	var articlePurgeInput model.ArticlePurgeTransientInput
+	err = model.DecodeTransientInput("article_purge", articlePurgeJsonBytes, &articlePurgeInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articlePurgeInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articlePurgeInput.Name, err.Error())
+	}
+
+	// ==== The hash is visible to every org, so existence can be checked without read access ====
+	detailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, articlePurgeInput.Name)
+	if err != nil {
+		return internalError(articlePurgeInput.Name, "Failed to get private details hash for "+articlePurgeInput.Name+": "+err.Error())
+	} else if detailsHash == nil {
+		return notFound(articlePurgeInput.Name, "Article private details does not exist: "+articlePurgeInput.Name)
+	}
+
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, articlePurgeInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = removeArticlePrivateDetails(stub, cfg, privateDetails, purger.PurgePrivateData)
+	if err != nil {
+		return internalError(articlePurgeInput.Name, err.Error())
+	}
+
+	// The price history holds the previous prices, so it goes with the details
+	err = removeByPartialCompositeKey(stub, cfg.CollectionArticlePrivateDetails, model.PriceHistoryIndex, []string{articlePurgeInput.Name}, purger.PurgePrivateData)
+	if err != nil {
+		return internalError(articlePurgeInput.Name, err.Error())
+	}
+
+	if articlePurgeInput.IncludeArticle {
+		// to maintain the indexes, we need to read the article first, as delete does
+		valAsbytes, err := stub.GetPrivateData(cfg.CollectionArticles, articlePurgeInput.Name)
+		if err != nil {
+			return internalError(articlePurgeInput.Name, "Failed to get state for "+articlePurgeInput.Name)
+		} else if valAsbytes == nil {
+			return notFound(articlePurgeInput.Name, "Article does not exist: "+articlePurgeInput.Name)
+		}
+
+		var articleToPurge model.Article
+		err = json.Unmarshal(valAsbytes, &articleToPurge)
+		if err != nil {
+			return internalError(articlePurgeInput.Name, "Failed to decode JSON of: "+string(valAsbytes))
+		}
+
+		err = verifyNotLockedByOtherOrg(stub, &articleToPurge)
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		err = purger.PurgePrivateData(cfg.CollectionArticles, articlePurgeInput.Name)
+		if err != nil {
+			return internalError(articlePurgeInput.Name, "Failed to purge state:"+err.Error())
+		}
+
+		err = removeArticleIndexes(stub, cfg, &articleToPurge, purger.PurgePrivateData)
+		if err != nil {
+			return internalError(articlePurgeInput.Name, err.Error())
+		}
+		if !articleToPurge.Deleted {
+			err = adjustShareholderCounts(stub, cfg, &articleToPurge, -1)
+			if err != nil {
+				return internalError(articlePurgeInput.Name, err.Error())
+			}
+		}
+
+		err = removeByPartialCompositeKey(stub, cfg.CollectionArticles, model.HistoryIndex, []string{articlePurgeInput.Name}, purger.PurgePrivateData)
+		if err != nil {
+			return internalError(articlePurgeInput.Name, err.Error())
+		}
+
+		err = setArticleEvent(stub, "ArticleDeleted", model.ArticleEventEntry{Name: articleToPurge.Name})
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	txLogger(stub).Infof("end purgeArticlePrivateDetails (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// PurgeDeletedArticles removes the tombstones of the articles soft-deleted before the given
+// RFC3339 timestamp, together with their private details and ownership history, as delete
+// does without soft. The tombstones renamed articles leave behind are only removed once
+// their redirect has expired, both before the timestamp and the transaction. Only admins
+// may call it.
+// ===========================================================================================
+func PurgeDeletedArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start purge deleted articles")
+
+	if len(args) != 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting an RFC3339 timestamp")
+	}
+
+	before, err := time.Parse(time.RFC3339, args[0])
+	if err != nil {
+		return invalidInput("", "timestamp must be in RFC3339 format: "+err.Error())
+	}
+
+	err = verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticles, "", "")
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	report := model.PurgeReport{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+		report.Scanned++
+
+		var article model.Article
+		err = json.Unmarshal(queryResponse.Value, &article)
+		if err != nil || !article.Deleted {
+			continue
+		}
+		deletedAt, err := time.Parse(time.RFC3339, article.DeletedAt)
+		if err != nil || !deletedAt.Before(before) {
+			continue
+		}
+		if len(article.RenamedTo) != 0 {
+			redirectExpiry, err := time.Parse(time.RFC3339, article.RedirectExpiry)
+			if err != nil || !redirectExpiry.Before(before) || redirectExpiry.After(now) {
+				continue
+			}
+		}
+
+		err = removeArticle(stub, cfg, &article, false)
+		if err != nil {
+			return internalError(article.Name, err.Error())
+		}
+		report.Purged++
+	}
+
+	reportJSONasBytes, err := json.Marshal(&report)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end purgeDeletedArticles: %s", reportJSONasBytes)
+	return shim.Success(reportJSONasBytes)
+}
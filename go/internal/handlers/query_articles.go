@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// QueryArticles runs a CouchDB rich query with the given selector against collectionArticles.
+// The optional second argument names the design document, or design document and index
+// separated by a comma, that is merged into the query as use_index. Rich queries are not
+// re-executed at commit time, so their results must not drive updates.
+// ===========================================================================================
+func QueryArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	args, format := responseFormatArg(args, 1, false)
+
+	if len(args) != 1 && len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting selector and an optional use_index hint")
+	}
+
+	var selector map[string]interface{}
+	err := json.Unmarshal([]byte(args[0]), &selector)
+	if err != nil || selector == nil {
+		return invalidInput("", "selector must be a JSON object")
+	}
+
+	query := map[string]interface{}{"selector": selector}
+	if len(args) == 2 && len(args[1]) > 0 {
+		useIndex := strings.Split(args[1], ",")
+		if len(useIndex) > 2 {
+			return invalidInput("", "use_index hint must be a design document, optionally followed by a comma and an index name")
+		}
+		if !strings.HasPrefix(useIndex[0], "_design/") {
+			useIndex[0] = "_design/" + useIndex[0]
+		}
+		query["use_index"] = useIndex
+	}
+
+	queryJSONasBytes, err := json.Marshal(query)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	resultsIterator, err := stub.GetPrivateDataQueryResult(cfg.CollectionArticles, string(queryJSONasBytes))
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	// results is a JSON array containing QueryResults
+	var results queryResultsBuilder
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		err = results.add(queryResponse.Key, queryResponse.Value)
+		if err != nil {
+			return internalError(queryResponse.Key, err.Error())
+		}
+	}
+
+	resultsJSONasBytes, err := results.response(format)
+	if err != nil {
+		return errorResponse(err)
+	}
+	txLogger(stub).Debugf("queryArticles returned %d results", results.len())
+
+	return shim.Success(resultsJSONasBytes)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RaiseDispute - contest an article, e.g. its provenance or condition. Any member of the
+// collection can raise a dispute, which records the reason, the raising org and when it
+// was raised. While it is open the article cannot be transferred or deleted; only an
+// arbiter can resolve it, see ResolveDispute. An article has one open dispute at a time,
+// which sets the Disputed flag of the article. Like a lock, the flag is a change of the
+// article, so its owner org must endorse the transaction as well.
+// ===========================================================================================
+func RaiseDispute(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start raise dispute")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	disputeJsonBytes, ok := transMap["dispute"]
+	if !ok {
+		return invalidInput("", "dispute must be a key in the transient map")
+	}
+
+	if len(disputeJsonBytes) == 0 {
+		return invalidInput("", "dispute value in the transient map must be a non-empty JSON string")
+	}
+
+	var disputeInput model.DisputeTransientInput
+	err = model.DecodeTransientInput("dispute", disputeJsonBytes, &disputeInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = disputeInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(disputeInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, disputeInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if article.Disputed {
+		return errorResponse(newError(CodeConflict, disputeInput.Name, "Article %s is already disputed, see getDisputes", disputeInput.Name))
+	}
+
+	raisedBy, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(disputeInput.Name, "Failed to get client MSP ID: "+err.Error())
+	}
+	timestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	disputeKey, err := nextSequenceKey(stub, cfg.CollectionArticles, model.DisputeIndex, disputeInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	disputeJSONasBytes, err := json.Marshal(&model.Dispute{
+		ObjectType:  "dispute",
+		ArticleName: disputeInput.Name,
+		Status:      model.DisputeOpen,
+		Reason:      disputeInput.Reason,
+		RaisedBy:    raisedBy,
+		RaisedAt:    timestamp,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, disputeKey, disputeJSONasBytes)
+	if err != nil {
+		return internalError(disputeInput.Name, "Failed to put dispute: "+err.Error())
+	}
+
+	article.Disputed = true
+	article.UpdatedAt = timestamp
+	err = putArticle(stub, cfg, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "DisputeRaised", model.ArticleEventEntry{Name: disputeInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end raiseDispute (success)")
+	return shim.Success(nil)
+}
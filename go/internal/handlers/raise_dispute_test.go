@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+func TestDisputeBlocksTransferAndDelete(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"agreeToTransfer": AgreeToTransfer,
+		"transferArticle": TransferArticle,
+		"delete":          Delete,
+		"raiseDispute":    RaiseDispute,
+		"resolveDispute":  ResolveDispute,
+	})
+	arbiter := n.Client(org1, "arbiter1", map[string]string{model.ArbiterAttribute: "true"})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	agreement := testutil.Transient("article_agreement", `{"name":"article1","price":9900,"currency":"EUR"}`)
+	expectStatus(t, n.user2.InvokeTransient("agreeToTransfer", agreement), shim.OK)
+
+	dispute := testutil.Transient("dispute", `{"name":"article1","reason":"serial number does not match the invoice"}`)
+	expectStatus(t, n.user2.InvokeTransient("raiseDispute", dispute), shim.OK)
+	if article := n.readArticle(t, "article1"); !article.Disputed {
+		t.Fatalf("raiseDispute did not mark article1 as disputed")
+	}
+	expectCode(t, n.user1.InvokeTransient("raiseDispute", dispute), CodeConflict)
+	expectCode(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), CodeConflict)
+	expectCode(t, n.admin1.InvokeTransient("delete", testutil.Transient("article_delete", `{"name":"article1"}`)), CodeConflict)
+
+	resolution := testutil.Transient("dispute_resolution", `{"name":"article1","outcome":"serial number confirmed by the manufacturer"}`)
+	expectCode(t, n.user1.InvokeTransient("resolveDispute", resolution), CodeAccessDenied)
+	expectStatus(t, arbiter.InvokeTransient("resolveDispute", resolution), shim.OK)
+	if article := n.readArticle(t, "article1"); article.Disputed {
+		t.Fatalf("resolveDispute left article1 disputed")
+	}
+	expectCode(t, arbiter.InvokeTransient("resolveDispute", resolution), CodeArticleNotFound)
+
+	// the article changed with the dispute, the buyer agrees to it once more
+	expectStatus(t, n.user2.InvokeTransient("agreeToTransfer", agreement), shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+	if article := n.readArticle(t, "article1"); article.Owner != "jerry" {
+		t.Errorf("article1 is owned by %s after the dispute was resolved", article.Owner)
+	}
+}
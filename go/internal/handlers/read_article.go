@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// ReadArticle - read a article from chaincode state. A soft-deleted article is reported
+// as not found unless the optional includeDeleted argument is true.
+// ===============================================
+func ReadArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	var name string
+	var err error
+
+	if len(args) < 1 || len(args) > 3 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query, an optional docType and an optional includeDeleted flag")
+	}
+
+	name = args[0]
+	err = model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	docType := ""
+	if len(args) >= 2 {
+		docType = args[1]
+	}
+	includeDeleted := false
+	if len(args) == 3 {
+		includeDeleted, err = parseIncludeDeleted(args[2])
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+	docType, err = resolveDocType(cfg, docType)
+	if err != nil {
+		return errorResponse(err)
+	}
+	key, err := articleKey(stub, docType, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	valAsbytes, err := stub.GetPrivateData(cfg.CollectionArticles, key) //get the article from chaincode state
+	if err != nil || valAsbytes == nil {
+		// the caller or the peer may lack access to the collection, in which case the
+		// private data hash still tells whether the record exists
+		hashResp, hashErr := privateDataHashFallback(stub, cfg.CollectionArticles, key, name)
+		if hashErr == nil && hashResp != nil {
+			return shim.Success(hashResp)
+		}
+	}
+	if err != nil {
+		return internalError(name, "Failed to get state for "+name+": "+err.Error())
+	} else if valAsbytes == nil {
+		return notFound(name, "Article does not exist: "+name)
+	}
+
+	if !includeDeleted && isTombstone(valAsbytes) {
+		if newName := renamedTo(valAsbytes); len(newName) != 0 {
+			return notFound(name, "Article "+name+" was renamed to "+newName)
+		}
+		return notFound(name, "Article was deleted: "+name)
+	}
+
+	return shim.Success(valAsbytes)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// ReadArticlePrivateDetails - read a article private details from chaincode state, with
+// the price in minor units of its currency. Clients of an org the owner org granted access
+// with grantDetailsAccess read them through a peer of a member org.
+// ===============================================
+func ReadArticlePrivateDetails(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	var name string
+	var err error
+
+	if len(args) != 1 && len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query and an optional docType")
+	}
+
+	name = args[0]
+	err = model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	docType := ""
+	if len(args) == 2 {
+		docType = args[1]
+	}
+	docType, err = resolveDocType(cfg, docType)
+	if err != nil {
+		return errorResponse(err)
+	}
+	key, err := articleKey(stub, docType, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	valAsbytes, err := readPrivateDetails(stub, cfg, key, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	valAsbytes, err = formatPrices(cfg, valAsbytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(valAsbytes)
+}
+
+// readPrivateDetails returns the JSON of the private details under the key, with the
+// currency of older records reported as unknown. When the caller or the peer lacks access
+// to the collection, it returns the inaccessiblePrivateData with their hash instead, which
+// still tells that the record exists. Clients that may not read the price get ACCESS_DENIED,
+// unless their org holds an unexpired grant of access to the details of the article.
+func readPrivateDetails(stub shim.ChaincodeStubInterface, cfg *model.Config, key string, name string) ([]byte, error) {
+	valAsbytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, key) //get the article private details from chaincode state
+	if err != nil || valAsbytes == nil {
+		hashResp, hashErr := privateDataHashFallback(stub, cfg.CollectionArticlePrivateDetails, key, name)
+		if hashErr == nil && hashResp != nil {
+			return hashResp, nil
+		}
+	}
+	if err != nil {
+		return nil, newError(CodeInternal, name, "Failed to get private details for %s: %v", name, err)
+	} else if valAsbytes == nil {
+		return nil, newError(CodeArticleNotFound, name, "Article private details does not exist: %s", name)
+	}
+
+	// ==== Grants of access are kept per article, other doc types only follow the creator ====
+	granted := false
+	if key == name {
+		granted, err = detailsAccessGranted(stub, cfg, name)
+		if err != nil {
+			return nil, newError(CodeInternal, name, "%v", err)
+		}
+	}
+	if !granted {
+		err = verifyPriceReader(stub, cfg, valAsbytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// ==== Details written before prices carried a currency report it as unknown ====
+	var privateDetails model.ArticlePrivateDetails
+	err = json.Unmarshal(valAsbytes, &privateDetails)
+	if err == nil && len(privateDetails.Currency) == 0 {
+		privateDetails.Currency = model.UnknownCurrency
+		return model.MarshalCanonical(&privateDetails)
+	}
+	return valAsbytes, nil
+}
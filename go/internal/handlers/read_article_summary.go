@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// ReadArticleSummary - read the public summary of an article from the world state. Any
+// member of the channel can read it, also without access to the collections. Articles
+// created without publicSummary have none and are reported as not found. The optional
+// second argument is the doc type, "article" by default.
+// ===============================================
+func ReadArticleSummary(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 1 && len(args) != 2 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article to query and an optional docType")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	docType := ""
+	if len(args) == 2 {
+		docType = args[1]
+	}
+	docType, err = resolveDocType(cfg, docType)
+	if err != nil {
+		return errorResponse(err)
+	}
+	key, err := articleKey(stub, docType, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	summaryAsBytes, err := stub.GetState(publicSummaryKey(key))
+	if err != nil {
+		return internalError(name, "Failed to get public summary of "+name+": "+err.Error())
+	} else if summaryAsBytes == nil {
+		return notFound(name, "Article public summary does not exist: "+name)
+	}
+
+	return shim.Success(summaryAsBytes)
+}
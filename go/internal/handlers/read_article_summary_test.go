@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+func TestPublicSummaryOfOtherDocType(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"readArticleSummary": ReadArticleSummary,
+		"listPublicArticles": ListPublicArticles,
+		"delete":             Delete,
+	})
+	n.cfg.DocTypes = []string{"book"}
+	n.createArticle(t, `{"name":"item1","color":"blue","size":35,"owner":"tom","salt":"`+testSalt+`","publicSummary":true}`)
+	n.createArticle(t, `{"docType":"book","name":"item1","color":"green","size":300,"owner":"tom","salt":"`+testSalt+`","publicSummary":true}`)
+
+	response := n.user2.Query("readArticleSummary", "item1")
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != `{"docType":"article","name":"item1"}` {
+		t.Errorf("article summary is %s", response.Payload)
+	}
+	response = n.user2.Query("readArticleSummary", "item1", "book")
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != `{"docType":"book","name":"item1"}` {
+		t.Errorf("book summary is %s", response.Payload)
+	}
+
+	response = n.user2.Query("listPublicArticles")
+	expectStatus(t, response, shim.OK)
+	var summaries []model.PublicSummary
+	err := json.Unmarshal(response.Payload, &summaries)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", response.Payload, err)
+	}
+	if len(summaries) != 2 || summaries[0].ObjectType != "book" || summaries[1].ObjectType != model.DefaultDocType {
+		t.Errorf("listPublicArticles returned %s, expected the book and then the article", response.Payload)
+	}
+
+	// deleting the book leaves the summary of the article with the same name
+	response = n.admin1.InvokeTransient("delete", testutil.Transient("article_delete", `{"name":"item1","docType":"book"}`))
+	expectStatus(t, response, shim.OK)
+	expectCode(t, n.user2.Query("readArticleSummary", "item1", "book"), CodeArticleNotFound)
+	expectStatus(t, n.user2.Query("readArticleSummary", "item1"), shim.OK)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// batchReadResult is the result of a name in a batch read: the record, or the error a
+// single read of the name would fail with
+type batchReadResult struct {
+	Details json.RawMessage `json:"details,omitempty"`
+	Error   *chaincodeError `json:"error,omitempty"`
+}
+
+// batchRead is the response of readMultiplePrivateDetails, with a result per name
+type batchRead struct {
+	Results   map[string]*batchReadResult `json:"results"`
+	Successes int                         `json:"successes"`
+	Failures  int                         `json:"failures"`
+}
+
+// ===========================================================================================
+// ReadMultiplePrivateDetails reads the private details of up to MaxBatchSize articles given
+// as arguments, for reconciliation jobs that would otherwise read them one at a time. Every
+// name gets a result, the details as readArticlePrivateDetails returns them or the error it
+// would fail with, so a missing record or a name failing validation does not fail the batch.
+// Names given twice are read once.
+// ===========================================================================================
+func ReadMultiplePrivateDetails(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) == 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting the names of the articles to query")
+	}
+	if len(args) > cfg.MaxBatchSize {
+		return errorResponse(newError(CodeTooLarge, "", "%d names requested, the maximum is %d", len(args), cfg.MaxBatchSize))
+	}
+
+	batch := &batchRead{Results: map[string]*batchReadResult{}}
+	for _, arg := range args {
+		name := arg
+		err := model.ValidateName("name", &name, cfg.MaxNameLength)
+		if err != nil {
+			name = arg //reported as given
+			err = newError(CodeInvalidInput, arg, "%v", err)
+		}
+		if _, ok := batch.Results[name]; ok {
+			continue
+		}
+
+		var details []byte
+		if err == nil {
+			details, err = readPrivateDetails(stub, cfg, name, name)
+		}
+		if err != nil {
+			ccErr, ok := err.(*chaincodeError)
+			if !ok {
+				ccErr = &chaincodeError{Code: CodeInternal, Message: err.Error(), Key: name}
+			}
+			batch.Results[name] = &batchReadResult{Error: ccErr}
+			batch.Failures++
+			continue
+		}
+		batch.Results[name] = &batchReadResult{Details: json.RawMessage(details)}
+		batch.Successes++
+	}
+
+	batchJSONasBytes, err := json.Marshal(batch)
+	if err != nil {
+		return errorResponse(err)
+	}
+	batchJSONasBytes, err = formatPrices(cfg, batchJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(batchJSONasBytes)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ReadNegotiatedPrice returns the price a buyer org negotiated for a article with the
+// seller org. Only clients of the two orgs may read it; a party whose peer cannot read
+// the collection, the seller with implicit collections, gets the private data hash.
+// ===========================================================================================
+func ReadNegotiatedPrice(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	if len(args) != 3 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name of the article, buyer MSP ID and seller MSP ID")
+	}
+
+	name := args[0]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	buyerMSP, sellerMSP := args[1], args[2]
+	err = model.ValidateKeyPart("buyerMSP", buyerMSP, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	err = model.ValidateKeyPart("sellerMSP", sellerMSP, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	// ==== Only the two parties may read their collection, checked before any read ====
+	err = verifyNegotiationParty(stub, cfg, name, buyerMSP, sellerMSP, false)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	key, err := negotiatedPriceKey(stub, name, buyerMSP)
+	if err != nil {
+		return errorResponse(err)
+	}
+	collection := negotiationCollection(cfg, buyerMSP, sellerMSP)
+	negotiatedAsBytes, err := stub.GetPrivateData(collection, key)
+	if err != nil || negotiatedAsBytes == nil {
+		hashResp, hashErr := privateDataHashFallback(stub, collection, key, name)
+		if hashErr == nil && hashResp != nil {
+			return shim.Success(hashResp)
+		}
+	}
+	if err != nil {
+		return internalError(name, "Failed to get negotiated price for "+name+": "+err.Error())
+	} else if negotiatedAsBytes == nil {
+		return notFound(name, "Org "+buyerMSP+" negotiated no price for article "+name)
+	}
+
+	negotiatedAsBytes, err = formatPrices(cfg, negotiatedAsBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(negotiatedAsBytes)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RecalculateCounts rebuilds the article counts of the owners from the articles themselves,
+// for counters that drifted or predate them. Every shard is cleared and the count of each
+// owner is written to its first shard. Only admins may call it.
+// ===========================================================================================
+func RecalculateCounts(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start recalculate counts")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting no arguments")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Count the articles of every owner ====
+	counts := make(map[string]int)
+	owners := []string{}
+	report := model.CountReport{}
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticles, "", "")
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+
+		var article model.Article
+		err = json.Unmarshal(queryResponse.Value, &article)
+		if err != nil || article.Deleted || article.ObjectType != model.DefaultDocType {
+			continue
+		}
+		for _, share := range article.Shares() {
+			if _, ok := counts[share.Owner]; !ok {
+				owners = append(owners, share.Owner)
+			}
+			counts[share.Owner]++
+		}
+		report.Articles++
+	}
+
+	// ==== Compare with the stored counts, including owners left with no article ====
+	stored := make(map[string]int)
+	countIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.OwnerCountIndex, []string{})
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer countIterator.Close()
+
+	for countIterator.HasNext() {
+		responseRange, err := countIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil || len(compositeKeyParts) != 2 {
+			continue
+		}
+		owner := compositeKeyParts[0]
+		if _, ok := stored[owner]; !ok {
+			if _, ok := counts[owner]; !ok {
+				owners = append(owners, owner)
+			}
+		}
+		n, _ := strconv.Atoi(string(responseRange.Value))
+		stored[owner] += n
+
+		err = stub.DelPrivateData(cfg.CollectionArticles, responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== Write the counts back to the first shard ====
+	for _, owner := range owners {
+		if counts[owner] != stored[owner] {
+			report.Changed++
+		}
+		if counts[owner] == 0 {
+			continue
+		}
+		report.Owners++
+		key, err := ownerCountKey(stub, owner, 0)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = stub.PutPrivateData(cfg.CollectionArticles, key, []byte(strconv.Itoa(counts[owner])))
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	reportJSONasBytes, err := json.Marshal(&report)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end recalculateCounts: %s", reportJSONasBytes)
+	return shim.Success(reportJSONasBytes)
+}
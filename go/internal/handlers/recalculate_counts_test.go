@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// countShards returns the count shard keys of the owner on the ledger
+func (n *testNetwork) countShards(t *testing.T, owner string) []string {
+	t.Helper()
+	prefix := compositeKey(t, model.OwnerCountIndex, owner)
+	shards := []string{}
+	for _, key := range n.PrivateKeys(model.DefaultCollectionArticles) {
+		if strings.HasPrefix(key, prefix) {
+			shards = append(shards, key)
+		}
+	}
+	return shards
+}
+
+func TestOwnerCountShards(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"getOwnerArticleCount": GetOwnerArticleCount,
+		"recalculateCounts":    RecalculateCounts,
+		"agreeToTransfer":      AgreeToTransfer,
+		"transferArticle":      TransferArticle,
+		"delete":               Delete,
+		// driftCount adds 100 to the last count shard of tom, as a counter gone wrong would
+		"driftCount": func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+			key, err := ownerCountKey(stub, "tom", model.OwnerCountShards-1)
+			if err != nil {
+				return errorResponse(err)
+			}
+			err = stub.PutPrivateData(cfg.CollectionArticles, key, []byte("100"))
+			if err != nil {
+				return errorResponse(err)
+			}
+			return shim.Success(nil)
+		},
+	})
+	for i := 1; i <= 6; i++ {
+		n.createArticle(t, articleJSON(fmt.Sprintf("article%d", i), "blue", 35, 0))
+	}
+	if count := n.articleCount(t, "tom"); count != 6 {
+		t.Errorf("tom has %d articles, expected 6", count)
+	}
+	if shards := n.countShards(t, "tom"); len(shards) < 2 {
+		t.Errorf("the count of tom is kept in %d shards, expected the creations to spread over several", len(shards))
+	}
+
+	// a transfer moves one article between the counts, a delete removes one
+	expectStatus(t, n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`)), shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+	expectStatus(t, n.admin1.InvokeTransient("delete", testutil.Transient("article_delete", `{"name":"article2"}`)), shim.OK)
+	if count := n.articleCount(t, "tom"); count != 4 {
+		t.Errorf("tom has %d articles after the transfer and the delete, expected 4", count)
+	}
+	if count := n.articleCount(t, "jerry"); count != 1 {
+		t.Errorf("jerry has %d articles after the transfer, expected 1", count)
+	}
+
+	// recalculateCounts repairs a drifted count and keeps it in a single shard
+	expectStatus(t, n.user1.Invoke("driftCount"), shim.OK)
+	if count := n.articleCount(t, "tom"); count == 4 {
+		t.Fatalf("the count of tom did not drift")
+	}
+	expectCode(t, n.user1.Invoke("recalculateCounts"), CodeAccessDenied)
+	response := n.admin1.Invoke("recalculateCounts")
+	expectStatus(t, response, shim.OK)
+	var report model.CountReport
+	err := json.Unmarshal(response.Payload, &report)
+	if err != nil {
+		t.Fatalf("failed to decode the report %s: %v", response.Payload, err)
+	}
+	if report.Owners != 2 || report.Articles != 5 || report.Changed != 1 {
+		t.Errorf("recalculateCounts reported %s, expected 2 owners, 5 articles and 1 changed", response.Payload)
+	}
+	if count := n.articleCount(t, "tom"); count != 4 {
+		t.Errorf("tom has %d articles after the recalculation, expected 4", count)
+	}
+	if count := n.articleCount(t, "jerry"); count != 1 {
+		t.Errorf("jerry has %d articles after the recalculation, expected 1", count)
+	}
+	if shards := n.countShards(t, "tom"); len(shards) != 1 || shards[0] != compositeKey(t, model.OwnerCountIndex, "tom", "0") {
+		t.Errorf("the recalculated count of tom is kept in %q, expected the first shard", shards)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RecategorizeArticles moves the articles of a category subtree to another one, e.g. from
+// "media/journal" to "media/periodical", rewriting each article and its category~name index
+// entry. At most batchSize articles, by default the maximum number of results of a query,
+// are moved per call; while the report says remaining, call again with the same prefixes.
+// Only admins may call it.
+// ===========================================================================================
+func RecategorizeArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start recategorize articles")
+
+	if len(args) < 2 || len(args) > 3 {
+		return invalidInput("", "Incorrect number of arguments. Expecting old prefix, new prefix and optionally the batch size")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	oldPrefix := args[0]
+	newPrefix := args[1]
+	err = model.ValidateCategory("oldPrefix", oldPrefix, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(oldPrefix, err.Error())
+	}
+	err = model.ValidateCategory("newPrefix", newPrefix, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(newPrefix, err.Error())
+	}
+	// moving a subtree into itself would keep finding the moved articles
+	if inCategory(newPrefix, oldPrefix) {
+		return invalidInput(newPrefix, "newPrefix must not be oldPrefix or a category below it")
+	}
+
+	batchSize := cfg.MaxResults
+	if len(args) == 3 && len(args[2]) > 0 {
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n <= 0 || n > cfg.MaxResults {
+			return invalidInput("", fmt.Sprintf("batch size must be an integer between 1 and %d", cfg.MaxResults))
+		}
+		batchSize = n
+	}
+
+	categoryResultsIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, model.CategoryNameIndex, strings.Split(oldPrefix, "/"))
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer categoryResultsIterator.Close()
+
+	report := model.RecategorizationReport{}
+	for categoryResultsIterator.HasNext() {
+		responseRange, err := categoryResultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return errorResponse(err)
+		}
+		name := compositeKeyParts[len(compositeKeyParts)-1]
+
+		article, err := getArticle(stub, cfg, name)
+		if ccErr, ok := err.(*chaincodeError); ok && ccErr.Code == CodeArticleNotFound {
+			continue //stale entry, reindexArticles removes it
+		} else if err != nil {
+			return errorResponse(err)
+		}
+		if !inCategory(article.Category, oldPrefix) {
+			continue
+		}
+
+		if report.Recategorized == batchSize {
+			report.Remaining = true
+			break
+		}
+
+		newCategory := newPrefix + strings.TrimPrefix(article.Category, oldPrefix)
+		err = model.ValidateCategory("category", newCategory, cfg.MaxNameLength)
+		if err != nil {
+			return invalidInput(article.Name, err.Error())
+		}
+
+		err = stub.DelPrivateData(cfg.CollectionArticles, responseRange.Key)
+		if err != nil {
+			return internalError(article.Name, "Failed to delete state:"+err.Error())
+		}
+
+		article.Category = newCategory
+		article.UpdatedAt, err = txTimestampRFC3339(stub)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = putArticle(stub, cfg, article) //rewrite the article
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		categoryNameIndexKey, err := categoryIndexKey(stub, article.Category, article.Name)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = stub.PutPrivateData(cfg.CollectionArticles, categoryNameIndexKey, []byte{0x00})
+		if err != nil {
+			return errorResponse(err)
+		}
+
+		err = putAuditRecord(stub, cfg, article.Name, "recategorizeArticles")
+		if err != nil {
+			return errorResponse(err)
+		}
+		report.Recategorized++
+	}
+
+	reportJSONasBytes, err := json.Marshal(&report)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end recategorizeArticles: %s", reportJSONasBytes)
+	return shim.Success(reportJSONasBytes)
+}
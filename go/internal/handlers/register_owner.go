@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RegisterOwner adds an owner to the owner registry, or reactivates it, so articles can be
+// created for and transferred to it. Only admins may call it.
+// ===========================================================================================
+func RegisterOwner(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start register owner")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Owner must be passed in transient map.")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	ownerRegistrationJsonBytes, ok := transMap["owner_registration"]
+	if !ok {
+		return invalidInput("", "owner_registration must be a key in the transient map")
+	}
+
+	if len(ownerRegistrationJsonBytes) == 0 {
+		return invalidInput("", "owner_registration value in the transient map must be a non-empty JSON string")
+	}
+
+	var ownerRegistrationInput model.OwnerRegistrationTransientInput
+	err = model.DecodeTransientInput("owner_registration", ownerRegistrationJsonBytes, &ownerRegistrationInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = ownerRegistrationInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(ownerRegistrationInput.Name, err.Error())
+	}
+
+	registryKey, err := ownerRegistryKey(stub, ownerRegistrationInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	ownerJSONasBytes, err := json.Marshal(&model.OwnerRecord{
+		ObjectType: "owner",
+		Name:       ownerRegistrationInput.Name,
+		MSPID:      ownerRegistrationInput.MSPID,
+		Active:     true,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, registryKey, ownerJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end registerOwner (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ReindexArticles rebuilds the composite key indexes of collectionArticles from the article
+// records. Missing index entries are written and entries that do not belong to an existing
+// article, or no longer match it, are deleted. With the optional dry run argument set to
+// true it only reports what it would change. Only admins may call it.
+// ===========================================================================================
+func ReindexArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start reindex articles")
+
+	if len(args) > 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting an optional dry run flag")
+	}
+
+	dryRun := false
+	if len(args) == 1 {
+		var err error
+		dryRun, err = strconv.ParseBool(args[0])
+		if err != nil {
+			return invalidInput("", "dry run flag must be true or false")
+		}
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	report := model.ReindexReport{DryRun: dryRun}
+
+	// ==== Collect the index entries every article should have ====
+	resultsIterator, err := stub.GetPrivateDataByRange(cfg.CollectionArticles, "", "")
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer resultsIterator.Close()
+
+	expectedIndexKeys := map[string]bool{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return errorResponse(err)
+		}
+		if isCompositeKey(queryResponse.Key) {
+			continue
+		}
+
+		var article model.Article
+		err = json.Unmarshal(queryResponse.Value, &article)
+		if err != nil {
+			return internalError(queryResponse.Key, "Failed to decode JSON of: "+string(queryResponse.Value))
+		}
+		report.Scanned++
+
+		indexKeys, err := articleIndexKeys(stub, &article)
+		if err != nil {
+			return internalError(article.Name, err.Error())
+		}
+		for _, indexKey := range indexKeys {
+			expectedIndexKeys[indexKey] = true
+
+			indexValue, err := stub.GetPrivateData(cfg.CollectionArticles, indexKey)
+			if err != nil {
+				return internalError(article.Name, "Failed to get index entry: "+err.Error())
+			} else if indexValue != nil {
+				continue
+			}
+			report.Added++
+			if !dryRun {
+				err = stub.PutPrivateData(cfg.CollectionArticles, indexKey, []byte{0x00})
+				if err != nil {
+					return errorResponse(err)
+				}
+			}
+		}
+	}
+
+	// ==== Remove the index entries no article accounts for ====
+	for _, indexName := range articleIndexes {
+		removed, err := removeOrphanIndexEntries(stub, cfg, indexName, expectedIndexKeys, dryRun)
+		if err != nil {
+			return errorResponse(err)
+		}
+		report.Removed += removed
+	}
+
+	reportJSONasBytes, err := json.Marshal(&report)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end reindexArticles: %s", reportJSONasBytes)
+	return shim.Success(reportJSONasBytes)
+}
+
+// removeOrphanIndexEntries deletes the entries of an index that are not expected and
+// returns how many there were
+func removeOrphanIndexEntries(stub shim.ChaincodeStubInterface, cfg *model.Config, indexName string, expectedIndexKeys map[string]bool, dryRun bool) (int, error) {
+	indexIterator, err := stub.GetPrivateDataByPartialCompositeKey(cfg.CollectionArticles, indexName, []string{})
+	if err != nil {
+		return 0, err
+	}
+	defer indexIterator.Close()
+
+	removed := 0
+	for indexIterator.HasNext() {
+		responseRange, err := indexIterator.Next()
+		if err != nil {
+			return 0, err
+		}
+		if expectedIndexKeys[responseRange.Key] {
+			continue
+		}
+		removed++
+		if !dryRun {
+			err = stub.DelPrivateData(cfg.CollectionArticles, responseRange.Key)
+			if err != nil {
+				return 0, fmt.Errorf("failed to delete state: %v", err)
+			}
+		}
+	}
+	return removed, nil
+}
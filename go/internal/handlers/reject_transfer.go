@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RejectTransfer - reject the transfer of a article proposed to the client org. The article
+// stays with its owner and the owner org can propose another transfer. Like acceptTransfer
+// it is endorsed by a peer of the owner org as well.
+// ===========================================================================================
+func RejectTransfer(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start reject transfer")
+
+	_, article, err := getTransferResponse(stub, cfg, args)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = clearPendingTransfer(stub, cfg, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	article.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putArticle(stub, cfg, article) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, article.Name, "rejectTransfer")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "TransferRejected", model.ArticleEventEntry{Name: article.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end rejectTransfer (success)")
+	return shim.Success(nil)
+}
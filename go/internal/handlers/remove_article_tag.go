@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RemoveArticleTag - remove a tag of a article together with its tag~name index entry. Only
+// the owner org can remove tags.
+// ===========================================================================================
+func RemoveArticleTag(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start remove article tag")
+
+	article, tag, err := getArticleTagInput(stub, cfg, args)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	tags := []string{}
+	for _, existingTag := range article.Tags {
+		if existingTag != tag {
+			tags = append(tags, existingTag)
+		}
+	}
+	if len(tags) == len(article.Tags) {
+		return notFound(article.Name, "Article "+article.Name+" is not tagged "+tag)
+	}
+
+	article.Tags = tags
+	article.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = putArticle(stub, cfg, article) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	tagNameIndexKey, err := stub.CreateCompositeKey(model.TagNameIndex, []string{tag, article.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.DelPrivateData(cfg.CollectionArticles, tagNameIndexKey)
+	if err != nil {
+		return internalError(article.Name, "Failed to delete state:"+err.Error())
+	}
+
+	err = putAuditRecord(stub, cfg, article.Name, "removeArticleTag")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end removeArticleTag (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,257 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// renamedArticleRecords are the records of an article in collectionArticles keyed by its
+// name first, which follow the article to its new name
+var renamedArticleRecords = []string{model.AuditIndex, model.HistoryIndex, model.LeaseIndex, model.CertificationIndex, model.DisputeIndex, model.AuctionIndex, model.BidCommitmentIndex, model.RevealedBidIndex}
+
+// renamedDetailsRecords are the records of an article in collectionArticlePrivateDetails
+// keyed by its name first, besides the details and their indexes
+var renamedDetailsRecords = []string{model.PriceHistoryIndex, model.PriceAccessIndex, model.DetailsAccessIndex, model.AttachmentIndex}
+
+// ===========================================================================================
+// RenameArticle - move an article to a new name with its private details, indexes, audit
+// trail, ownership and price history, certifications and attachments. The old name keeps
+// a tombstone that answers with the new name until the grace period ends, after which
+// purgeDeletedArticles removes it. Only the owner org may rename, and not while a transfer
+// or auction of the article is under way. Agreements and negotiated prices in the
+// collections of other orgs stay under the old name and have to be given again.
+// ===========================================================================================
+func RenameArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start rename article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleRenameJsonBytes, ok := transMap["article_rename"]
+	if !ok {
+		return invalidInput("", "article_rename must be a key in the transient map")
+	}
+
+	if len(articleRenameJsonBytes) == 0 {
+		return invalidInput("", "article_rename value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleRenameInput model.ArticleRenameTransientInput
+	err = model.DecodeTransientInput("article_rename", articleRenameJsonBytes, &articleRenameInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleRenameInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleRenameInput.OldName, err.Error())
+	}
+	if articleRenameInput.GraceSeconds == 0 {
+		articleRenameInput.GraceSeconds = model.DefaultRenameGraceSeconds
+	}
+	oldName, newName := articleRenameInput.OldName, articleRenameInput.NewName
+
+	article, err := getArticle(stub, cfg, oldName)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the owner org may rename, and only an article at rest ====
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLockedByOtherOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotDisputed(article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if len(article.PendingTransferTo) != 0 {
+		return invalidInput(oldName, "article "+oldName+" has a transfer pending to org "+article.PendingTransferTo)
+	}
+	transferRequest, err := getTransferRequest(stub, cfg, oldName)
+	if err != nil {
+		return errorResponse(err)
+	} else if transferRequest != nil {
+		return invalidInput(oldName, "article "+oldName+" has an initiated transfer")
+	}
+	auction, err := getAuction(stub, cfg, oldName)
+	if err != nil {
+		return errorResponse(err)
+	} else if auction != nil && auction.Status == model.AuctionOpen {
+		return invalidInput(oldName, "article "+oldName+" is being auctioned")
+	}
+
+	// ==== The new name must be free in both collections ====
+	newArticleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, newName)
+	if err != nil {
+		return internalError(newName, "Failed to get article: "+err.Error())
+	} else if newArticleAsBytes != nil {
+		return alreadyExists(newName, "This article already exists: "+newName)
+	}
+	newDetailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, newName)
+	if err != nil {
+		return internalError(newName, "Failed to get private details hash: "+err.Error())
+	} else if newDetailsHash != nil {
+		return alreadyExists(newName, "Private details already exist for "+newName)
+	}
+
+	// ==== The details must be readable to be moved, they are never left behind ====
+	var privateDetails *model.ArticlePrivateDetails
+	detailsHash, err := stub.GetPrivateDataHash(cfg.CollectionArticlePrivateDetails, oldName)
+	if err != nil {
+		return internalError(oldName, "Failed to get private details hash: "+err.Error())
+	} else if detailsHash != nil {
+		privateDetails, err = getArticlePrivateDetails(stub, cfg, oldName)
+		if err != nil {
+			return accessDenied(oldName, "private details of "+oldName+" cannot be moved by this peer, rename with a peer of "+cfg.CollectionArticlePrivateDetails)
+		}
+	}
+
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Write the article and its indexes under the new name ====
+	err = removeArticleIndexes(stub, cfg, article, stub.DelPrivateData)
+	if err != nil {
+		return internalError(oldName, err.Error())
+	}
+	renamedArticle := *article
+	renamedArticle.Name = newName
+	renamedArticle.UpdatedAt = txTimestamp
+	err = putArticle(stub, cfg, &renamedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = setArticleStateBasedEndorsement(stub, cfg, newName, renamedArticle.OwnerOrg)
+	if err != nil {
+		return errorResponse(err)
+	}
+	indexKeys, err := articleIndexKeys(stub, &renamedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+	for _, indexKey := range indexKeys {
+		err = stub.PutPrivateData(cfg.CollectionArticles, indexKey, []byte{0x00})
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+	err = putSKUIndex(stub, cfg, &renamedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+	for _, objectType := range renamedArticleRecords {
+		err = moveByPartialCompositeKey(stub, cfg.CollectionArticles, objectType, oldName, newName)
+		if err != nil {
+			return internalError(oldName, err.Error())
+		}
+	}
+	hadPublicSummary, err := removePublicSummary(stub, oldName)
+	if err != nil {
+		return internalError(oldName, err.Error())
+	}
+	if hadPublicSummary {
+		err = putPublicSummary(stub, &renamedArticle)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== Move the private details with their indexes, keeping their creator and writer ====
+	if privateDetails != nil {
+		for _, objectType := range renamedDetailsRecords {
+			err = moveByPartialCompositeKey(stub, cfg.CollectionArticlePrivateDetails, objectType, oldName, newName)
+			if err != nil {
+				return internalError(oldName, err.Error())
+			}
+		}
+		err = moveByPartialCompositeKey(stub, cfg.CollectionArticlePrivateDetails, model.DetailsWriterIndex, oldName, newName)
+		if err != nil {
+			return internalError(oldName, err.Error())
+		}
+		err = removeArticlePrivateDetails(stub, cfg, privateDetails, stub.DelPrivateData)
+		if err != nil {
+			return internalError(oldName, err.Error())
+		}
+
+		renamedDetails := *privateDetails
+		renamedDetails.Name = newName
+		renamedDetails.SchemaVersion = model.CurrentSchemaVersion
+		renamedDetailsAsBytes, err := model.MarshalCanonical(&renamedDetails)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, newName, renamedDetailsAsBytes)
+		if err != nil {
+			return errorResponse(err)
+		}
+		priceNameIndexKey, err := priceIndexKey(stub, newName, renamedDetails.Price)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = stub.PutPrivateData(cfg.CollectionArticlePrivateDetails, priceNameIndexKey, []byte{0x00})
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== Leave a tombstone redirecting to the new name for the grace period ====
+	err = putArticle(stub, cfg, &model.Article{
+		ObjectType:     model.DefaultDocType,
+		Name:           oldName,
+		OwnerOrg:       article.OwnerOrg,
+		CreatedAt:      article.CreatedAt,
+		UpdatedAt:      txTimestamp,
+		Deleted:        true,
+		DeletedAt:      txTimestamp,
+		RenamedTo:      newName,
+		RedirectExpiry: now.Add(time.Duration(articleRenameInput.GraceSeconds) * time.Second).Format(time.RFC3339),
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, newName, "renameArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleRenamed", model.ArticleEventEntry{Name: newName, OldName: oldName})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end renameArticle (success)")
+	return shim.Success(nil)
+}
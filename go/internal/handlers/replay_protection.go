@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// MinNonceLength and MaxNonceLength bound the request nonce in bytes
+const (
+	MinNonceLength = 16
+	MaxNonceLength = 256
+)
+
+// WithReplayProtection wraps a sensitive handler in the replay checks cfg.ReplayProtection
+// asks for. With binding or nonce, the signed proposal must be bound to this channel,
+// transaction and, when cfg.ChaincodeName is set, chaincode, which catches proposals
+// crafted for one deployment and submitted to another. With nonce, the client must also
+// pass a "request_nonce" in the transient map that no transaction used within
+// cfg.NonceWindowSeconds. Off, the handler runs unchecked.
+func WithReplayProtection(handler HandlerFunc) HandlerFunc {
+	return func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+		if cfg.ReplayProtection == model.ReplayProtectionOff {
+			return handler(stub, cfg, args)
+		}
+
+		err := verifyProposalBinding(stub, cfg)
+		if err != nil {
+			return errorResponse(err)
+		}
+		if cfg.ReplayProtection == model.ReplayProtectionNonce {
+			err = useRequestNonce(stub, cfg)
+			if err != nil {
+				return errorResponse(err)
+			}
+		}
+		return handler(stub, cfg, args)
+	}
+}
+
+// verifyProposalBinding fails with ACCESS_DENIED unless the signed proposal names the
+// channel and transaction of the stub and the configured chaincode, and the binding of the
+// stub is the SHA-256 of the nonce, creator and epoch of the proposal header
+func verifyProposalBinding(stub shim.ChaincodeStubInterface, cfg *model.Config) error {
+	signedProposal, err := stub.GetSignedProposal()
+	if err != nil {
+		return fmt.Errorf("failed to get signed proposal: %v", err)
+	} else if signedProposal == nil {
+		return newError(CodeAccessDenied, "", "the transaction carries no signed proposal")
+	}
+
+	proposal := &pb.Proposal{}
+	err = proto.Unmarshal(signedProposal.GetProposalBytes(), proposal)
+	if err != nil {
+		return newError(CodeAccessDenied, "", "failed to decode proposal: %v", err)
+	}
+	header := &common.Header{}
+	err = proto.Unmarshal(proposal.GetHeader(), header)
+	if err != nil {
+		return newError(CodeAccessDenied, "", "failed to decode proposal header: %v", err)
+	}
+	channelHeader := &common.ChannelHeader{}
+	err = proto.Unmarshal(header.GetChannelHeader(), channelHeader)
+	if err != nil {
+		return newError(CodeAccessDenied, "", "failed to decode channel header: %v", err)
+	}
+	signatureHeader := &common.SignatureHeader{}
+	err = proto.Unmarshal(header.GetSignatureHeader(), signatureHeader)
+	if err != nil {
+		return newError(CodeAccessDenied, "", "failed to decode signature header: %v", err)
+	}
+
+	if channelHeader.GetChannelId() != stub.GetChannelID() {
+		return newError(CodeAccessDenied, "", "proposal is bound to channel %q, not %q", channelHeader.GetChannelId(), stub.GetChannelID())
+	}
+	if channelHeader.GetTxId() != stub.GetTxID() {
+		return newError(CodeAccessDenied, "", "proposal is bound to transaction %q, not %q", channelHeader.GetTxId(), stub.GetTxID())
+	}
+	if len(cfg.ChaincodeName) != 0 {
+		extension := &pb.ChaincodeHeaderExtension{}
+		err = proto.Unmarshal(channelHeader.GetExtension(), extension)
+		if err != nil {
+			return newError(CodeAccessDenied, "", "failed to decode chaincode header extension: %v", err)
+		}
+		if extension.GetChaincodeId().GetName() != cfg.ChaincodeName {
+			return newError(CodeAccessDenied, "", "proposal is bound to chaincode %q, not %q", extension.GetChaincodeId().GetName(), cfg.ChaincodeName)
+		}
+	}
+
+	binding, err := stub.GetBinding()
+	if err != nil {
+		return fmt.Errorf("failed to get binding: %v", err)
+	}
+	epoch := make([]byte, 8)
+	binary.LittleEndian.PutUint64(epoch, channelHeader.Epoch)
+	expected := sha256.New()
+	expected.Write(signatureHeader.Nonce)
+	expected.Write(signatureHeader.Creator)
+	expected.Write(epoch)
+	if !bytes.Equal(binding, expected.Sum(nil)) {
+		return newError(CodeAccessDenied, "", "binding of the transaction does not match its proposal header")
+	}
+	return nil
+}
+
+// useRequestNonce records the request nonce of the transient map with the transaction
+// timestamp, failing with CONFLICT when a transaction already used it within
+// cfg.NonceWindowSeconds. Nonces are kept by their hash in collectionArticles; once the
+// window has passed the record is overwritten by the next use.
+func useRequestNonce(stub shim.ChaincodeStubInterface, cfg *model.Config) error {
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return newError(CodeInternal, "", "Error getting transient: %v", err)
+	}
+	nonce, ok := transMap["request_nonce"]
+	if !ok {
+		return newError(CodeInvalidInput, "", "request_nonce must be a key in the transient map")
+	}
+	if len(nonce) < MinNonceLength || len(nonce) > MaxNonceLength {
+		return newError(CodeInvalidInput, "", "request_nonce value in the transient map must be between %d and %d bytes, got %d", MinNonceLength, MaxNonceLength, len(nonce))
+	}
+
+	hash := sha256.Sum256(nonce)
+	nonceKey, err := stub.CreateCompositeKey(model.NonceIndex, []string{hex.EncodeToString(hash[:])})
+	if err != nil {
+		return err
+	}
+	now, err := txTime(stub)
+	if err != nil {
+		return err
+	}
+	usedAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, nonceKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get request nonce: %v", err)
+	}
+	if usedAsBytes != nil {
+		usedAt, err := time.Parse(time.RFC3339, string(usedAsBytes))
+		if err != nil {
+			return fmt.Errorf("invalid timestamp of request nonce: %v", err)
+		}
+		if now.Before(usedAt.Add(time.Duration(cfg.NonceWindowSeconds) * time.Second)) {
+			return newError(CodeConflict, "", "request_nonce was already used at %s", usedAsBytes)
+		}
+	}
+	return stub.PutPrivateData(cfg.CollectionArticles, nonceKey, []byte(now.Format(time.RFC3339)))
+}
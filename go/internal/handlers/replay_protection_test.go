@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// replayFunctions protect transferArticle and a "protected" function that only succeeds
+var replayFunctions = map[string]HandlerFunc{
+	"agreeToTransfer": AgreeToTransfer,
+	"transferArticle": WithReplayProtection(TransferArticle),
+	"protected": WithReplayProtection(func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+		return shim.Success(nil)
+	}),
+}
+
+// withNonce returns the transient map with the request nonce added
+func withNonce(transient map[string][]byte, nonce string) map[string][]byte {
+	if transient == nil {
+		transient = map[string][]byte{}
+	}
+	transient["request_nonce"] = []byte(nonce)
+	return transient
+}
+
+func TestForgedProposalBinding(t *testing.T) {
+	forgeries := []struct {
+		name  string
+		forge func(channelHeader *common.ChannelHeader, signatureHeader *common.SignatureHeader)
+	}{
+		{"other channel", func(channelHeader *common.ChannelHeader, signatureHeader *common.SignatureHeader) {
+			channelHeader.ChannelId = "otherchannel"
+		}},
+		{"other transaction", func(channelHeader *common.ChannelHeader, signatureHeader *common.SignatureHeader) {
+			channelHeader.TxId = strings.Repeat("0", 64)
+		}},
+		{"other chaincode", func(channelHeader *common.ChannelHeader, signatureHeader *common.SignatureHeader) {
+			channelHeader.Extension, _ = proto.Marshal(&pb.ChaincodeHeaderExtension{ChaincodeId: &pb.ChaincodeID{Name: "otherchaincode"}})
+		}},
+		{"other nonce", func(channelHeader *common.ChannelHeader, signatureHeader *common.SignatureHeader) {
+			signatureHeader.Nonce = []byte("replayed nonce of another proposal")
+		}},
+		{"other epoch", func(channelHeader *common.ChannelHeader, signatureHeader *common.SignatureHeader) {
+			channelHeader.Epoch++
+		}},
+	}
+
+	// off, the forged proposals run as before
+	n := newTestNetwork(t, replayFunctions)
+	n.cfg.ChaincodeName = testutil.DefaultChaincodeName
+	for _, forgery := range forgeries {
+		tx := n.user1.Submit(testutil.Invocation{Function: "protected", Forge: forgery.forge})
+		if tx.Response.Status != shim.OK {
+			t.Errorf("%s was rejected without replay protection: %s", forgery.name, tx.Response.Message)
+		}
+	}
+
+	for _, mode := range []string{model.ReplayProtectionBinding, model.ReplayProtectionNonce} {
+		t.Run(mode, func(t *testing.T) {
+			n := newTestNetwork(t, replayFunctions)
+			n.cfg.ReplayProtection = mode
+			n.cfg.ChaincodeName = testutil.DefaultChaincodeName
+			n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+			expectStatus(t, n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1","price":9900,"currency":"EUR"}`)), shim.OK)
+
+			for i, forgery := range forgeries {
+				nonce := strings.Repeat(string(rune('a'+i)), MinNonceLength)
+				tx := n.user1.Submit(testutil.Invocation{Function: "transferArticle", Transient: withNonce(transferToJerry("article1"), nonce), Forge: forgery.forge})
+				expectCode(t, tx.Response, CodeAccessDenied)
+				if tx.Writes != 0 {
+					t.Errorf("proposal of %s wrote %d keys", forgery.name, tx.Writes)
+				}
+			}
+			if article := n.readArticle(t, "article1"); article.Owner != "tom" {
+				t.Fatalf("forged proposals transferred article1 to %s", article.Owner)
+			}
+
+			tx := n.user1.Submit(testutil.Invocation{Function: "transferArticle", Transient: withNonce(transferToJerry("article1"), strings.Repeat("z", MinNonceLength))})
+			expectStatus(t, tx.Response, shim.OK)
+			if article := n.readArticle(t, "article1"); article.Owner != "jerry" {
+				t.Errorf("article1 is owned by %s, expected jerry", article.Owner)
+			}
+		})
+	}
+
+	// without a chaincode name, proposals for any chaincode are accepted
+	n = newTestNetwork(t, replayFunctions)
+	n.cfg.ReplayProtection = model.ReplayProtectionBinding
+	tx := n.user1.Submit(testutil.Invocation{Function: "protected", Forge: forgeries[2].forge})
+	expectStatus(t, tx.Response, shim.OK)
+}
+
+func TestRequestNonceReplay(t *testing.T) {
+	n := newTestNetwork(t, replayFunctions)
+	n.cfg.ReplayProtection = model.ReplayProtectionNonce
+	nonce := strings.Repeat("n", MinNonceLength)
+
+	expectCode(t, n.user1.Invoke("protected"), CodeInvalidInput)
+	for _, invalid := range []string{strings.Repeat("n", MinNonceLength-1), strings.Repeat("n", MaxNonceLength+1)} {
+		tx := n.user1.Submit(testutil.Invocation{Function: "protected", Transient: withNonce(nil, invalid)})
+		expectCode(t, tx.Response, CodeInvalidInput)
+	}
+
+	expectStatus(t, n.user1.InvokeTransient("protected", withNonce(nil, nonce)), shim.OK)
+	// a duplicate is rejected for either org until the window has passed
+	expectCode(t, n.user1.InvokeTransient("protected", withNonce(nil, nonce)), CodeConflict)
+	expectCode(t, n.user2.InvokeTransient("protected", withNonce(nil, nonce)), CodeConflict)
+	expectStatus(t, n.user1.InvokeTransient("protected", withNonce(nil, nonce+"2")), shim.OK)
+
+	n.Advance(model.DefaultNonceWindowSeconds * time.Second)
+	expectStatus(t, n.user1.InvokeTransient("protected", withNonce(nil, nonce)), shim.OK)
+	expectCode(t, n.user1.InvokeTransient("protected", withNonce(nil, nonce)), CodeConflict)
+
+	// a shorter window lets the nonce be used again sooner
+	n.cfg.NonceWindowSeconds = 5
+	n.Advance(5 * time.Second)
+	expectStatus(t, n.user1.InvokeTransient("protected", withNonce(nil, nonce)), shim.OK)
+}
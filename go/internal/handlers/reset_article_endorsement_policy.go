@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ResetArticleEndorsementPolicy removes the key-level endorsement policies of an existing
+// article from both collections, so that the collection endorsement policies apply to it
+// again. Only admins may call it, and the transaction still needs the endorsements the
+// current key-level policy requires, i.e. of a peer of the owner org.
+// ===========================================================================================
+func ResetArticleEndorsementPolicy(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start reset article endorsement policy")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article name must be passed in transient map.")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	policyResetJsonBytes, ok := transMap["policy_reset"]
+	if !ok {
+		return invalidInput("", "policy_reset must be a key in the transient map")
+	}
+
+	if len(policyResetJsonBytes) == 0 {
+		return invalidInput("", "policy_reset value in the transient map must be a non-empty JSON string")
+	}
+
+	var policyResetInput model.PolicyResetTransientInput
+	err = model.DecodeTransientInput("policy_reset", policyResetJsonBytes, &policyResetInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = policyResetInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(policyResetInput.Name, err.Error())
+	}
+
+	articleHash, err := stub.GetPrivateDataHash(cfg.CollectionArticles, policyResetInput.Name)
+	if err != nil {
+		return internalError(policyResetInput.Name, "Failed to get article hash: "+err.Error())
+	} else if articleHash == nil {
+		return notFound(policyResetInput.Name, "Article does not exist: "+policyResetInput.Name)
+	}
+
+	err = clearArticleStateBasedEndorsement(stub, cfg, policyResetInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, policyResetInput.Name, "resetArticleEndorsementPolicy")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end resetArticleEndorsementPolicy (success)")
+	return shim.Success(nil)
+}
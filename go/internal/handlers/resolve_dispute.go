@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// ResolveDispute - close the open dispute of an article with an outcome. Only clients with
+// the arbiter attribute can resolve disputes. The dispute is kept as a resolved record and
+// the Disputed flag of the article is cleared, so it can be transferred and deleted again.
+// ===========================================================================================
+func ResolveDispute(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start resolve dispute")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = verifyClientIsArbiter(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	resolutionJsonBytes, ok := transMap["dispute_resolution"]
+	if !ok {
+		return invalidInput("", "dispute_resolution must be a key in the transient map")
+	}
+
+	if len(resolutionJsonBytes) == 0 {
+		return invalidInput("", "dispute_resolution value in the transient map must be a non-empty JSON string")
+	}
+
+	var resolutionInput model.DisputeResolutionTransientInput
+	err = model.DecodeTransientInput("dispute_resolution", resolutionJsonBytes, &resolutionInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = resolutionInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(resolutionInput.Name, err.Error())
+	}
+
+	dispute, disputeKey, err := getOpenDispute(stub, cfg, resolutionInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if dispute == nil {
+		return notFound(resolutionInput.Name, "Article "+resolutionInput.Name+" has no open dispute")
+	}
+
+	resolvedBy, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(resolutionInput.Name, "Failed to get client MSP ID: "+err.Error())
+	}
+	timestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	dispute.Status = model.DisputeResolved
+	dispute.Outcome = resolutionInput.Outcome
+	dispute.ResolvedBy = resolvedBy
+	dispute.ResolvedAt = timestamp
+	disputeJSONasBytes, err := json.Marshal(dispute)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, disputeKey, disputeJSONasBytes)
+	if err != nil {
+		return internalError(resolutionInput.Name, "Failed to put dispute: "+err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, resolutionInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	article.Disputed = false
+	article.UpdatedAt = timestamp
+	err = putArticle(stub, cfg, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "DisputeResolved", model.ArticleEventEntry{Name: resolutionInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end resolveDispute (success)")
+	return shim.Success(nil)
+}
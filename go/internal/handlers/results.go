@@ -0,0 +1,275 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"privatemarbles/internal/model"
+)
+
+// compositeKeyNamespace starts every composite key, see shim.CreateCompositeKey
+const compositeKeyNamespace = "\x00"
+
+// isCompositeKey reports whether a key returned by a range query is a composite key, i.e.
+// an index entry or a log record rather than an article
+func isCompositeKey(key string) bool {
+	return strings.HasPrefix(key, compositeKeyNamespace)
+}
+
+// isSelfTestKey reports whether a key is the name of a temporary article of selfTest,
+// which the list queries leave out in case its cleanup failed
+func isSelfTestKey(key string) bool {
+	return strings.HasPrefix(key, model.SelfTestPrefix)
+}
+
+// queryResultsBuilder builds the JSON array returned by the list queries. Unlike
+// formatting the members with Sprintf, it escapes the keys and rejects records that
+// are not valid JSON, so a stored value can never break the structure of the result.
+type queryResultsBuilder struct {
+	buffer  bytes.Buffer
+	written bool
+	count   int
+	csv     *csv.Writer   // set for the CSV export, see newQueryResultsBuilder
+	scratch bytes.Buffer  // compacted record, reused across addRecord calls
+	keys    *json.Encoder // encodes the keys of addRecord straight into the buffer
+}
+
+// articleCSVHeader is the header row of the CSV export of articles. The export is meant
+// for spreadsheets that get passed around, so it never holds private details.
+var articleCSVHeader = []string{"name", "color", "size", "owner"}
+
+// newQueryResultsBuilder returns a builder for the response format of a list query. For
+// responseFormatCSV it builds an RFC 4180 export of articles with a header row, whose
+// writer quotes the fields holding commas, quotes or line breaks.
+func newQueryResultsBuilder(format string) *queryResultsBuilder {
+	b := &queryResultsBuilder{}
+	if format == responseFormatCSV {
+		b.csv = csv.NewWriter(&b.buffer)
+		b.csv.UseCRLF = true
+		b.csv.Write(articleCSVHeader)
+	}
+	return b
+}
+
+// add appends a stored record under its key, unless it is a selfTest article
+func (b *queryResultsBuilder) add(key string, record []byte) error {
+	if isSelfTestKey(key) {
+		return nil
+	}
+	if b.csv != nil {
+		return b.addCSVRow(record)
+	}
+	return b.addRecord(key, record)
+}
+
+// addRecord appends a stored record as a {"Key":...,"Record":...} member. It writes the
+// bytes json.Marshal writes for the record as json.RawMessage, compacted and HTML-escaped,
+// but straight into the buffer: the record is compacted into the reused scratch buffer,
+// which also rejects invalid JSON, and escaped from there, and the key is encoded by the
+// reused keys encoder, so a range query allocates per result rather than per byte of its
+// records.
+func (b *queryResultsBuilder) addRecord(key string, record []byte) error {
+	b.scratch.Reset()
+	err := json.Compact(&b.scratch, record)
+	if err != nil {
+		return fmt.Errorf("failed to encode query result: record of %s is not valid JSON: %v", key, err)
+	}
+
+	b.separate()
+	b.buffer.WriteString(`{"Key":`)
+	err = b.writeKey(key)
+	if err != nil {
+		return err
+	}
+	b.buffer.WriteString(`,"Record":`)
+	json.HTMLEscape(&b.buffer, b.scratch.Bytes())
+	b.buffer.WriteString("}")
+	b.count++
+	return nil
+}
+
+// addCSVRow appends the public fields of a stored article as a CSV row
+func (b *queryResultsBuilder) addCSVRow(record []byte) error {
+	var article model.Article
+	err := json.Unmarshal(record, &article)
+	if err != nil {
+		return fmt.Errorf("failed to decode article: %v", err)
+	}
+	err = b.csv.Write([]string{article.Name, article.Color, strconv.Itoa(article.Size), article.Owner})
+	if err != nil {
+		return fmt.Errorf("failed to encode query result: %v", err)
+	}
+	b.count++
+	return nil
+}
+
+// addResult appends any JSON-marshalable result
+func (b *queryResultsBuilder) addResult(result interface{}) error {
+	resultJSONasBytes, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode query result: %v", err)
+	}
+
+	b.separate()
+	b.buffer.Write(resultJSONasBytes)
+	b.count++
+	return nil
+}
+
+// separate opens the JSON array before the first result and separates the others
+func (b *queryResultsBuilder) separate() {
+	if b.written {
+		b.buffer.WriteString(",")
+	} else {
+		b.buffer.WriteString("[")
+		b.written = true
+	}
+}
+
+// addKey appends a key alone, for the keys-only listings, unless it is a selfTest article.
+// Like addRecord it writes the key with the reused keys encoder.
+func (b *queryResultsBuilder) addKey(key string) error {
+	if isSelfTestKey(key) {
+		return nil
+	}
+	b.separate()
+	err := b.writeKey(key)
+	if err != nil {
+		return err
+	}
+	b.count++
+	return nil
+}
+
+// writeKey writes the key as a JSON string, the way json.Marshal writes it
+func (b *queryResultsBuilder) writeKey(key string) error {
+	if b.keys == nil {
+		b.keys = json.NewEncoder(&b.buffer)
+	}
+	err := b.keys.Encode(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode query result: %v", err)
+	}
+	b.buffer.Truncate(b.buffer.Len() - 1) //the newline Encode ends every value with
+	return nil
+}
+
+// len returns the number of results added so far
+func (b *queryResultsBuilder) len() int {
+	return b.count
+}
+
+// bytes returns the JSON array of the results added so far, or the CSV export
+func (b *queryResultsBuilder) bytes() []byte {
+	if b.csv != nil {
+		b.csv.Flush()
+		return b.buffer.Bytes()
+	}
+	if !b.written {
+		return []byte("[]")
+	}
+	return append(b.buffer.Bytes(), ']')
+}
+
+// response returns the results added so far in the response format of the list query
+func (b *queryResultsBuilder) response(format string) ([]byte, error) {
+	return listResponse(b.bytes(), b.count, false, "", format)
+}
+
+// Response formats of the list queries. v1, the default, is the bare JSON array of the
+// results; v2 wraps it in a listEnvelope that tells the count and whether it is truncated.
+// Some queries can also export the articles as CSV.
+const (
+	responseFormatV1  = "v1"
+	responseFormatV2  = "v2"
+	responseFormatCSV = "csv"
+)
+
+// listEnvelope is the v2 response of the list queries. When it is truncated, LastKey is
+// the first key that was not returned and starts the range of the next query.
+type listEnvelope struct {
+	Count     int             `json:"count"`
+	Truncated bool            `json:"truncated"`
+	Results   json.RawMessage `json:"results"`
+	LastKey   string          `json:"lastKey,omitempty"`
+}
+
+// responseFormatArg takes the optional response format off the end of the arguments of a
+// list query, "csv" only for the queries that allow it, and returns the format, v1 when
+// there is none. The last argument is only taken as the format when there are more than
+// the minArgs required arguments, so that a required argument can still be "v1" or "v2".
+func responseFormatArg(args []string, minArgs int, allowCSV bool) ([]string, string) {
+	if len(args) > minArgs {
+		switch format := args[len(args)-1]; format {
+		case responseFormatV1, responseFormatV2:
+			return args[:len(args)-1], format
+		case responseFormatCSV:
+			if allowCSV {
+				return args[:len(args)-1], format
+			}
+		}
+	}
+	return args, responseFormatV1
+}
+
+// listResponse returns the results of a list query, the JSON array or CSV export as built
+// or, for v2, the envelope around the JSON array
+func listResponse(results []byte, count int, truncated bool, lastKey string, format string) ([]byte, error) {
+	if format != responseFormatV2 {
+		return results, nil
+	}
+	envelopeJSONasBytes, err := json.Marshal(&listEnvelope{Count: count, Truncated: truncated, Results: json.RawMessage(results), LastKey: lastKey})
+	if err != nil {
+		return nil, newError(CodeInternal, "", "Failed to encode response: %v", err)
+	}
+	return envelopeJSONasBytes, nil
+}
+
+// isTombstone reports whether a record of collectionArticles is a soft-deleted article
+func isTombstone(value []byte) bool {
+	var record struct {
+		Deleted bool `json:"deleted"`
+	}
+	return json.Unmarshal(value, &record) == nil && record.Deleted
+}
+
+// renamedTo returns the new name a tombstone left by renameArticle points to, empty for
+// other records
+func renamedTo(value []byte) string {
+	var record struct {
+		RenamedTo string `json:"renamedTo"`
+	}
+	if json.Unmarshal(value, &record) != nil {
+		return ""
+	}
+	return record.RenamedTo
+}
+
+// parseIncludeDeleted parses the optional includeDeleted argument of the read functions
+func parseIncludeDeleted(arg string) (bool, error) {
+	includeDeleted, err := strconv.ParseBool(arg)
+	if err != nil {
+		return false, newError(CodeInvalidInput, "", "includeDeleted flag must be true or false")
+	}
+	return includeDeleted, nil
+}
+
+// parseKeysOnly parses the optional keysOnly argument of the list queries, which then
+// return the keys of the articles without their records
+func parseKeysOnly(arg string) (bool, error) {
+	keysOnly, err := strconv.ParseBool(arg)
+	if err != nil {
+		return false, newError(CodeInvalidInput, "", "keysOnly flag must be true or false")
+	}
+	return keysOnly, nil
+}
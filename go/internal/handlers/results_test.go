@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// marshaledQueryResult is the member the builder used to marshal for each record, which
+// addRecord has to write byte for byte
+type marshaledQueryResult struct {
+	Key    string          `json:"Key"`
+	Record json.RawMessage `json:"Record"`
+}
+
+func TestAddRecordMatchesMarshal(t *testing.T) {
+	records := []struct {
+		key    string
+		record string
+	}{
+		{`a"<b>`, "{ \"name\": \"a\\\"<b>\",\n  \"tags\": [\"x&y\", \"é\"],\t\"n\": 1.50 }"},
+		{"k2", `{"n":[1, 2, {"x": null}]}`},
+		{"line separators", "{\"s\":\"\u2028\u2029\"}"},
+		{"invalid\xffutf8", `{"s":"<é😀"}`},
+		{"article1", `{"docType":"article","name":"article1","color":"blue","size":35}`},
+	}
+
+	b := newQueryResultsBuilder(responseFormatV1)
+	var marshaled []interface{}
+	for _, r := range records {
+		err := b.add(r.key, []byte(r.record))
+		if err != nil {
+			t.Fatalf("failed to add %s: %v", r.key, err)
+		}
+		marshaled = append(marshaled, &marshaledQueryResult{Key: r.key, Record: json.RawMessage(r.record)})
+	}
+	err := b.addKey("k3")
+	if err != nil {
+		t.Fatalf("failed to add k3: %v", err)
+	}
+	marshaled = append(marshaled, "k3")
+
+	expected, err := json.Marshal(marshaled)
+	if err != nil {
+		t.Fatalf("failed to marshal the records: %v", err)
+	}
+	if string(b.bytes()) != string(expected) {
+		t.Errorf("builder wrote\n%s\nmarshal writes\n%s", b.bytes(), expected)
+	}
+
+	// the output before addRecord wrote the records itself
+	b = newQueryResultsBuilder(responseFormatV1)
+	b.add(records[0].key, []byte(records[0].record))
+	b.add(records[1].key, []byte(records[1].record))
+	b.addKey("k3")
+	golden := `[{"Key":"a\"\u003cb\u003e","Record":{"name":"a\"\u003cb\u003e","tags":["x\u0026y","é"],"n":1.50}},{"Key":"k2","Record":{"n":[1,2,{"x":null}]}},"k3"]`
+	if string(b.bytes()) != golden {
+		t.Errorf("builder wrote\n%s\nexpected\n%s", b.bytes(), golden)
+	}
+}
+
+func TestAddRecordRejectsInvalidJSON(t *testing.T) {
+	for _, record := range []string{"", "{", `{"a":1}x`, `{"a":'b'}`} {
+		b := newQueryResultsBuilder(responseFormatV1)
+		if b.add("k", []byte(record)) == nil {
+			t.Errorf("record %q was accepted", record)
+		}
+		if _, err := json.Marshal(&marshaledQueryResult{Key: "k", Record: json.RawMessage(record)}); err == nil && len(record) != 0 {
+			t.Errorf("marshal accepted record %q", record)
+		}
+	}
+}
+
+func TestListResponseFormats(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"getArticlesByRange": GetArticlesByRange,
+		"getArticlesByOwner": GetArticlesByOwner,
+	})
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+	n.createArticle(t, articleJSON("article2", "red", 40, 0))
+
+	article1 := `{"Key":"article1","Record":{"askingPriceVisible":false,"color":"blue","createdAt":"2024-01-01T00:00:02Z","deleted":false,"deletedAt":"","docType":"article","forSale":false,"lockExpiry":"","locked":false,"lockedBy":"","name":"article1","owner":"tom","ownerOrg":"Org1MSP","quantity":1,"salt":"` + testSalt + `","schemaVersion":2,"size":35,"updatedAt":"2024-01-01T00:00:02Z"}}`
+	article2 := `{"Key":"article2","Record":{"askingPriceVisible":false,"color":"red","createdAt":"2024-01-01T00:00:03Z","deleted":false,"deletedAt":"","docType":"article","forSale":false,"lockExpiry":"","locked":false,"lockedBy":"","name":"article2","owner":"tom","ownerOrg":"Org1MSP","quantity":1,"salt":"` + testSalt + `","schemaVersion":2,"size":40,"updatedAt":"2024-01-01T00:00:03Z"}}`
+	for _, test := range []struct {
+		function string
+		args     []string
+		payload  string
+	}{
+		// v1 is the format of the clients that do not ask for one
+		{"getArticlesByRange", []string{"article1", "article3"}, `[` + article1 + `,` + article2 + `]`},
+		{"getArticlesByRange", []string{"article1", "article3", "v1"}, `[` + article1 + `,` + article2 + `]`},
+		{"getArticlesByRange", []string{"article1", "article3", "1"}, `[` + article1 + `]`},
+		{"getArticlesByOwner", []string{"tom", "true"}, `["article1","article2"]`},
+		{"getArticlesByOwner", []string{"nobody"}, `[]`},
+		// v2 wraps the results in the envelope
+		{"getArticlesByRange", []string{"article1", "article3", "v2"}, `{"count":2,"truncated":false,"results":[` + article1 + `,` + article2 + `]}`},
+		{"getArticlesByRange", []string{"article1", "article3", "1", "v2"}, `{"count":1,"truncated":true,"results":[` + article1 + `],"lastKey":"article2"}`},
+		{"getArticlesByOwner", []string{"tom", "true", "v2"}, `{"count":2,"truncated":false,"results":["article1","article2"]}`},
+		{"getArticlesByOwner", []string{"nobody", "v2"}, `{"count":0,"truncated":false,"results":[]}`},
+		// a required argument is never taken for the format
+		{"getArticlesByOwner", []string{"v2"}, `[]`},
+	} {
+		response := n.user2.Query(test.function, test.args...)
+		expectStatus(t, response, shim.OK)
+		if string(response.Payload) != test.payload {
+			t.Errorf("%s %q returned\n%s\nexpected\n%s", test.function, test.args, response.Payload, test.payload)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RetireArticle records that an article was destroyed physically. Unlike delete, the record
+// stays readable with the reason and time of its retirement, while it leaves the color~name
+// and forsale~name indexes so marketplace queries no longer list it. Transfers and changes
+// of a retired article fail with ARTICLE_RETIRED. Only the owner org may retire an article,
+// and not while a transfer or auction of it is under way. The key-level endorsement
+// policies of the article are cleared in both collections.
+// ===========================================================================================
+func RetireArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start retire article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleRetireJsonBytes, ok := transMap["article_retire"]
+	if !ok {
+		return invalidInput("", "article_retire must be a key in the transient map")
+	}
+
+	if len(articleRetireJsonBytes) == 0 {
+		return invalidInput("", "article_retire value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleRetireInput model.ArticleRetireTransientInput
+	err = model.DecodeTransientInput("article_retire", articleRetireJsonBytes, &articleRetireInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleRetireInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleRetireInput.Name, err.Error())
+	}
+	name := articleRetireInput.Name
+
+	article, err := getArticle(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the owner org may retire, and only an article at rest ====
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLockedByOtherOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if len(article.PendingTransferTo) != 0 {
+		return invalidInput(name, "article "+name+" has a transfer pending to org "+article.PendingTransferTo)
+	}
+	transferRequest, err := getTransferRequest(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	} else if transferRequest != nil {
+		return invalidInput(name, "article "+name+" has an initiated transfer")
+	}
+	auction, err := getAuction(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	} else if auction != nil && auction.Status == model.AuctionOpen {
+		return invalidInput(name, "article "+name+" is being auctioned")
+	}
+
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== A retired article no longer changes, its key-level endorsement policies go ====
+	err = clearArticleStateBasedEndorsement(stub, cfg, name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Leave the indexes marketplace queries walk, articleIndexKeys no longer lists them ====
+	colorNameIndexKey, err := stub.CreateCompositeKey(model.ColorNameIndex, []string{article.Color, name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	forSaleIndexKey, err := stub.CreateCompositeKey(model.ForSaleIndex, []string{name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	for _, indexKey := range []string{colorNameIndexKey, forSaleIndexKey} {
+		err = stub.DelPrivateData(cfg.CollectionArticles, indexKey)
+		if err != nil {
+			return internalError(name, "Failed to delete state:"+err.Error())
+		}
+	}
+
+	article.Retired = true
+	article.RetiredAt = txTimestamp
+	article.RetireReason = articleRetireInput.Reason
+	article.ForSale = false
+	article.UpdatedAt = txTimestamp
+	err = putArticle(stub, cfg, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, name, "retireArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleRetired", model.ArticleEventEntry{Name: name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end retireArticle (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RevealBid - reveal the sealed bid of the client org after the close of the auction. The
+// bid is read from the org's implicit collection and must match the hash committed when it
+// was placed. Bids not revealed before the reveal deadline are ignored by closeAuction.
+// ===========================================================================================
+func RevealBid(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start reveal bid")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	// ==== Only a peer of the bidder org can read its implicit collection ====
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	revealJsonBytes, ok := transMap["bid_reveal"]
+	if !ok {
+		return invalidInput("", "bid_reveal must be a key in the transient map")
+	}
+
+	if len(revealJsonBytes) == 0 {
+		return invalidInput("", "bid_reveal value in the transient map must be a non-empty JSON string")
+	}
+
+	var revealInput model.AuctionNameTransientInput
+	err = model.DecodeTransientInput("bid_reveal", revealJsonBytes, &revealInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = revealInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(revealInput.Name, err.Error())
+	}
+
+	auction, err := getOpenAuction(stub, cfg, revealInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	now, err := txTime(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	closeTime, revealDeadline, err := auctionTimes(auction)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if now.Before(closeTime) {
+		return accessDenied(revealInput.Name, "bids on article "+revealInput.Name+" cannot be revealed before "+auction.CloseTime)
+	}
+	if now.After(revealDeadline) {
+		return accessDenied(revealInput.Name, "bids on article "+revealInput.Name+" had to be revealed by "+auction.RevealDeadline)
+	}
+
+	bidderOrgID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(revealInput.Name, "Failed to get client MSP ID: "+err.Error())
+	}
+
+	commitmentKey, err := stub.CreateCompositeKey(model.BidCommitmentIndex, []string{revealInput.Name, bidderOrgID})
+	if err != nil {
+		return errorResponse(err)
+	}
+	commitmentAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, commitmentKey)
+	if err != nil {
+		return internalError(revealInput.Name, "Failed to get bid commitment: "+err.Error())
+	} else if commitmentAsBytes == nil {
+		return notFound(revealInput.Name, "Org "+bidderOrgID+" placed no bid on article: "+revealInput.Name)
+	}
+	commitment := model.BidCommitment{}
+	err = json.Unmarshal(commitmentAsBytes, &commitment)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The sealed bid must be the one committed to ====
+	collection := implicitCollectionName(bidderOrgID)
+	bidKey, err := stub.CreateCompositeKey(model.BidIndex, []string{revealInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+	bidAsBytes, err := stub.GetPrivateData(collection, bidKey)
+	if err != nil {
+		return internalError(revealInput.Name, "Failed to get bid: "+err.Error())
+	} else if bidAsBytes == nil {
+		return notFound(revealInput.Name, "Bid does not exist for article: "+revealInput.Name)
+	}
+	bidHash, err := stub.GetPrivateDataHash(collection, bidKey)
+	if err != nil {
+		return internalError(revealInput.Name, "Failed to get bid hash: "+err.Error())
+	}
+	if hex.EncodeToString(bidHash) != commitment.Hash {
+		return accessDenied(revealInput.Name, "bid of org "+bidderOrgID+" does not match its commitment")
+	}
+
+	bid := model.Bid{}
+	err = json.Unmarshal(bidAsBytes, &bid)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	revealedBidJSONasBytes, err := json.Marshal(&model.RevealedBid{
+		ObjectType: "revealedBid",
+		Name:       bid.Name,
+		BidderOrg:  bidderOrgID,
+		Amount:     bid.Amount,
+		Owner:      bid.Owner,
+		PlacedAt:   commitment.PlacedAt,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	revealedBidKey, err := stub.CreateCompositeKey(model.RevealedBidIndex, []string{revealInput.Name, bidderOrgID})
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, revealedBidKey, revealedBidJSONasBytes)
+	if err != nil {
+		return internalError(revealInput.Name, "Failed to put revealed bid: "+err.Error())
+	}
+
+	txLogger(stub).Infof("end revealBid (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RevokeCertification - remove the certification of a article by the client org. A
+// certifier can only revoke the certifications of its own org.
+// ===========================================================================================
+func RevokeCertification(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start revoke certification")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = verifyClientIsCertifier(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	revocationJsonBytes, ok := transMap["certification_revocation"]
+	if !ok {
+		return invalidInput("", "certification_revocation must be a key in the transient map")
+	}
+
+	if len(revocationJsonBytes) == 0 {
+		return invalidInput("", "certification_revocation value in the transient map must be a non-empty JSON string")
+	}
+
+	var revocationInput model.CertificationRevocationTransientInput
+	err = model.DecodeTransientInput("certification_revocation", revocationJsonBytes, &revocationInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = revocationInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(revocationInput.Name, err.Error())
+	}
+
+	certifierMSP, err := cid.GetMSPID(stub)
+	if err != nil {
+		return internalError(revocationInput.Name, "Failed to get client MSP ID: "+err.Error())
+	}
+	certificationKey, err := stub.CreateCompositeKey(model.CertificationIndex, []string{revocationInput.Name, certifierMSP})
+	if err != nil {
+		return errorResponse(err)
+	}
+	certificationAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, certificationKey)
+	if err != nil {
+		return internalError(revocationInput.Name, "Failed to get certification: "+err.Error())
+	} else if certificationAsBytes == nil {
+		return notFound(revocationInput.Name, "Org "+certifierMSP+" has not certified article: "+revocationInput.Name)
+	}
+
+	err = stub.DelPrivateData(cfg.CollectionArticles, certificationKey)
+	if err != nil {
+		return internalError(revocationInput.Name, "Failed to delete state:"+err.Error())
+	}
+
+	err = setArticleEvent(stub, "CertificationRevoked", model.ArticleEventEntry{Name: revocationInput.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end revokeCertification (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// RevokeDetailsAccess - withdraw the access of an org to the private details of an article
+// before its grant expires. Only the owner org may revoke access.
+// ===========================================================================================
+func RevokeDetailsAccess(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start revoke details access")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	revokeJsonBytes, ok := transMap["details_access_revoke"]
+	if !ok {
+		return invalidInput("", "details_access_revoke must be a key in the transient map")
+	}
+
+	if len(revokeJsonBytes) == 0 {
+		return invalidInput("", "details_access_revoke value in the transient map must be a non-empty JSON string")
+	}
+
+	var revokeInput model.DetailsAccessRevokeTransientInput
+	err = model.DecodeTransientInput("details_access_revoke", revokeJsonBytes, &revokeInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = revokeInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(revokeInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, revokeInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	detailsAccessKey, err := stub.CreateCompositeKey(model.DetailsAccessIndex, []string{article.Name, revokeInput.GranteeMSP})
+	if err != nil {
+		return errorResponse(err)
+	}
+	grantAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, detailsAccessKey)
+	if err != nil {
+		return internalError(article.Name, "Failed to get details access: "+err.Error())
+	} else if grantAsBytes == nil {
+		return notFound(article.Name, "org "+revokeInput.GranteeMSP+" holds no access to the private details of "+article.Name)
+	}
+	err = stub.DelPrivateData(cfg.CollectionArticlePrivateDetails, detailsAccessKey)
+	if err != nil {
+		return internalError(article.Name, "Failed to delete details access: "+err.Error())
+	}
+
+	txLogger(stub).Infof("end revokeDetailsAccess (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,221 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// Phases of selfTest
+const (
+	selfTestPhaseWrite  = "write"
+	selfTestPhaseVerify = "verify"
+)
+
+// selfTestStep is the outcome of a step of selfTest
+type selfTestStep struct {
+	Step     string `json:"step"`
+	OK       bool   `json:"ok"`
+	Duration string `json:"duration"` //wall-clock time on the endorsing peer
+	Error    string `json:"error,omitempty"`
+}
+
+// selfTestReport is the response of selfTest, Passed when every step is OK
+type selfTestReport struct {
+	Name   string          `json:"name"`
+	Phase  string          `json:"phase"`
+	Passed bool            `json:"passed"`
+	Steps  []*selfTestStep `json:"steps"`
+}
+
+// run runs a step, records its outcome and duration and reports whether it succeeded
+func (r *selfTestReport) run(step string, f func() error) bool {
+	start := time.Now()
+	err := f()
+	result := &selfTestStep{Step: step, OK: err == nil, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Error = err.Error()
+		r.Passed = false
+	}
+	r.Steps = append(r.Steps, result)
+	return err == nil
+}
+
+// ===========================================================================================
+// SelfTest - smoke test the chaincode after an upgrade, in two invocations because a
+// transaction does not read its own writes. Without argument it writes a temporary article
+// named __selftest__ followed by the transaction ID, with private details, like initArticle
+// does. Called again with that name, once the first transaction committed, it reads the
+// article back from both collections, checks the records against their private data hashes
+// and deletes it. Both return a report of the outcome and duration of each step. Failed
+// writes and deletes fail the transaction, so no partial state is left. Only admins may
+// call it. The durations differ between peers, so the invocations are sent to one endorser.
+// ===========================================================================================
+func SelfTest(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start self test")
+
+	if len(args) > 1 {
+		return invalidInput("", "Incorrect number of arguments. Expecting the name of the article of a previous selfTest or none")
+	}
+
+	err := verifyClientIsAdmin(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	var report *selfTestReport
+	if len(args) == 0 {
+		report, err = selfTestWrite(stub, cfg)
+	} else {
+		name := args[0]
+		if !strings.HasPrefix(name, model.SelfTestPrefix) {
+			return invalidInput(name, "name must start with "+model.SelfTestPrefix)
+		}
+		report, err = selfTestVerify(stub, cfg, name)
+	}
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	reportJSONasBytes, err := json.Marshal(report)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end selfTest %s of %s, passed: %t", report.Phase, report.Name, report.Passed)
+	return shim.Success(reportJSONasBytes)
+}
+
+// selfTestWrite validates and saves the temporary article with its private details
+func selfTestWrite(stub shim.ChaincodeStubInterface, cfg *model.Config) (*selfTestReport, error) {
+	salt := sha256.Sum256([]byte(stub.GetTxID()))
+	articleInput := &model.ArticleTransientInput{
+		Name:     model.SelfTestPrefix + stub.GetTxID(),
+		Color:    "selftest",
+		Size:     1,
+		Owner:    model.SelfTestPrefix + "owner",
+		Price:    1,
+		Currency: "EUR",
+		Salt:     base64.StdEncoding.EncodeToString(salt[:]),
+		Quantity: 1,
+	}
+	report := &selfTestReport{Name: articleInput.Name, Phase: selfTestPhaseWrite, Passed: true}
+
+	var err error
+	report.run("validate", func() error {
+		err = articleInput.Validate(cfg.MaxNameLength)
+		return err
+	})
+	if err != nil {
+		return nil, newError(CodeInvalidInput, articleInput.Name, "selfTest article is invalid: %v", err)
+	}
+	report.run("write", func() error {
+		clientOrgID, err := cid.GetMSPID(stub)
+		if err != nil {
+			return fmt.Errorf("Failed to get client MSP ID: %v", err)
+		}
+		txTimestamp, err := txTimestampRFC3339(stub)
+		if err != nil {
+			return err
+		}
+		article := articleFromInput(articleInput, model.DefaultDocType, clientOrgID, txTimestamp)
+		err = putNewArticle(stub, cfg, article, articleInput.Price, articleInput.Currency)
+		return err
+	})
+	if !report.Passed {
+		return nil, newError(CodeInternal, articleInput.Name, "selfTest failed to write %s: %s", articleInput.Name, report.Steps[len(report.Steps)-1].Error)
+	}
+	return report, nil
+}
+
+// selfTestVerify reads the temporary article back, compares it with its private data
+// hashes and deletes it. The article is deleted even when a check failed.
+func selfTestVerify(stub shim.ChaincodeStubInterface, cfg *model.Config, name string) (*selfTestReport, error) {
+	report := &selfTestReport{Name: name, Phase: selfTestPhaseVerify, Passed: true}
+
+	var articleAsBytes, detailsAsBytes []byte
+	if !report.run("readArticle", func() error {
+		var err error
+		articleAsBytes, err = stub.GetPrivateData(cfg.CollectionArticles, name)
+		if err != nil {
+			return err
+		} else if articleAsBytes == nil {
+			return fmt.Errorf("article %s does not exist in %s", name, cfg.CollectionArticles)
+		}
+		return nil
+	}) {
+		return report, nil
+	}
+	report.run("readPrivateDetails", func() error {
+		var err error
+		detailsAsBytes, err = stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, name)
+		if err != nil {
+			return err
+		} else if detailsAsBytes == nil {
+			return fmt.Errorf("private details of %s do not exist in %s", name, cfg.CollectionArticlePrivateDetails)
+		}
+		return nil
+	})
+	report.run("verifyArticleHash", func() error {
+		return verifySelfTestHash(stub, cfg.CollectionArticles, name, articleAsBytes)
+	})
+	if detailsAsBytes != nil {
+		report.run("verifyPrivateDetailsHash", func() error {
+			return verifySelfTestHash(stub, cfg.CollectionArticlePrivateDetails, name, detailsAsBytes)
+		})
+	}
+
+	var err error
+	report.run("delete", func() error {
+		var article model.Article
+		err = json.Unmarshal(articleAsBytes, &article)
+		if err != nil {
+			return fmt.Errorf("Failed to decode JSON of: %s", articleAsBytes)
+		}
+		err = clearArticleStateBasedEndorsement(stub, cfg, name)
+		if err != nil {
+			return err
+		}
+		err = removeArticle(stub, cfg, &article, false)
+		return err
+	})
+	if err != nil {
+		return nil, newError(CodeInternal, name, "selfTest failed to delete %s: %v", name, err)
+	}
+	return report, nil
+}
+
+// verifySelfTestHash checks that the SHA-256 of a record read back is its private data hash
+func verifySelfTestHash(stub shim.ChaincodeStubInterface, collection string, name string, record []byte) error {
+	hash, err := stub.GetPrivateDataHash(collection, name)
+	if err != nil {
+		return err
+	} else if hash == nil {
+		return fmt.Errorf("%s has no hash in %s", name, collection)
+	}
+	recordHash := sha256.Sum256(record)
+	if !bytes.Equal(hash, recordHash[:]) {
+		return fmt.Errorf("hash of %s in %s is %x, the record read back hashes to %x", name, collection, hash, recordHash)
+	}
+	return nil
+}
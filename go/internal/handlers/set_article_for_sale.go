@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// SetArticleForSale - list a article for sale or take it off sale. Only the owner org may
+// change the listing, which is kept in the forsale~name index walked by getArticlesForSale.
+// ===========================================================================================
+func SetArticleForSale(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start set article for sale")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleSaleJsonBytes, ok := transMap["article_sale"]
+	if !ok {
+		return invalidInput("", "article_sale must be a key in the transient map")
+	}
+
+	if len(articleSaleJsonBytes) == 0 {
+		return invalidInput("", "article_sale value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleSaleInput model.ArticleSaleTransientInput
+	err = model.DecodeTransientInput("article_sale", articleSaleJsonBytes, &articleSaleInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleSaleInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleSaleInput.Name, err.Error())
+	}
+
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleSaleInput.Name)
+	if err != nil {
+		return internalError(articleSaleInput.Name, "Failed to get article:"+err.Error())
+	} else if articleAsBytes == nil {
+		return notFound(articleSaleInput.Name, "Article does not exist: "+articleSaleInput.Name)
+	}
+
+	articleToList := model.Article{}
+	err = json.Unmarshal(articleAsBytes, &articleToList)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotDeleted(&articleToList)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotRetired(&articleToList)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may list the article ====
+	_, err = verifyClientIsOwnerOrg(stub, &articleToList)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	articleToList.ForSale = articleSaleInput.ForSale
+	articleToList.AskingPriceVisible = articleSaleInput.AskingPriceVisible
+
+	articleToList.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putArticle(stub, cfg, &articleToList) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putForSaleIndex(stub, cfg, &articleToList)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, articleToList.Name, "setArticleForSale")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end setArticleForSale (success)")
+	return shim.Success(nil)
+}
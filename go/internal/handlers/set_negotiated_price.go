@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// SetNegotiatedPrice - record the price a buyer org negotiated with the owner org of a
+// article. The price is kept in the collection of the two orgs, so neither the global
+// price in collectionArticlePrivateDetails nor other buyers learn it, and
+// transferArticleWithPrice to the buyer org uses it instead of a new price.
+// ===========================================================================================
+func SetNegotiatedPrice(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start set negotiated price")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	negotiatedPriceJsonBytes, ok := transMap["negotiated_price"]
+	if !ok {
+		return invalidInput("", "negotiated_price must be a key in the transient map")
+	}
+
+	if len(negotiatedPriceJsonBytes) == 0 {
+		return invalidInput("", "negotiated_price value in the transient map must be a non-empty JSON string")
+	}
+
+	var negotiatedPriceInput model.NegotiatedPriceTransientInput
+	err = model.DecodeTransientInput("negotiated_price", negotiatedPriceJsonBytes, &negotiatedPriceInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = negotiatedPriceInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(negotiatedPriceInput.Name, err.Error())
+	}
+
+	// ==== Only the two parties may write to their collection, checked before any read ====
+	err = verifyNegotiationParty(stub, cfg, negotiatedPriceInput.Name, negotiatedPriceInput.BuyerMSP, negotiatedPriceInput.SellerMSP, true)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The seller is the org that owns the article ====
+	article, err := getArticle(stub, cfg, negotiatedPriceInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if article.OwnerOrg != negotiatedPriceInput.SellerMSP {
+		return invalidInput(article.Name, "sellerMSP "+negotiatedPriceInput.SellerMSP+" is not the owner org of article "+article.Name)
+	}
+
+	key, err := negotiatedPriceKey(stub, article.Name, negotiatedPriceInput.BuyerMSP)
+	if err != nil {
+		return errorResponse(err)
+	}
+	negotiatedJSONasBytes, err := model.MarshalCanonical(&model.NegotiatedPrice{
+		ObjectType:  "negotiatedPrice",
+		ArticleName: article.Name,
+		BuyerMSP:    negotiatedPriceInput.BuyerMSP,
+		SellerMSP:   negotiatedPriceInput.SellerMSP,
+		Price:       negotiatedPriceInput.Price,
+		Currency:    negotiatedPriceInput.Currency,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	collection := negotiationCollection(cfg, negotiatedPriceInput.BuyerMSP, negotiatedPriceInput.SellerMSP)
+	err = stub.PutPrivateData(collection, key, negotiatedJSONasBytes)
+	if err != nil {
+		return internalError(article.Name, "Failed to put negotiated price in "+collection+": "+err.Error())
+	}
+
+	txLogger(stub).Infof("end setNegotiatedPrice (success)")
+	return shim.Success(nil)
+}
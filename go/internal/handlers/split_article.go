@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// SplitArticle - move part of the quantity of a article into a new article with the same
+// color, size and owner. The price of the lot is split in proportion to the quantities,
+// the original article keeps the remainder of the integer division.
+// ===========================================================================================
+func SplitArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start split article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleSplitJsonBytes, ok := transMap["article_split"]
+	if !ok {
+		return invalidInput("", "article_split must be a key in the transient map")
+	}
+
+	if len(articleSplitJsonBytes) == 0 {
+		return invalidInput("", "article_split value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleSplitInput model.ArticleSplitTransientInput
+	err = model.DecodeTransientInput("article_split", articleSplitJsonBytes, &articleSplitInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleSplitInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleSplitInput.Name, err.Error())
+	}
+
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleSplitInput.Name)
+	if err != nil {
+		return internalError(articleSplitInput.Name, "Failed to get article:"+err.Error())
+	} else if articleAsBytes == nil {
+		return notFound(articleSplitInput.Name, "Article does not exist: "+articleSplitInput.Name)
+	}
+
+	articleToSplit := model.Article{}
+	err = json.Unmarshal(articleAsBytes, &articleToSplit)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotDeleted(&articleToSplit)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotRetired(&articleToSplit)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may split the article ====
+	clientOrgID, err := verifyClientIsOwnerOrg(stub, &articleToSplit)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLockedByOtherOrg(stub, &articleToSplit)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Both parts must keep at least one unit ====
+	quantity := articleToSplit.Units()
+	if articleSplitInput.Quantity >= quantity {
+		return invalidInput(articleSplitInput.Name, fmt.Sprintf("quantity field must be less than the quantity %d of the article", quantity))
+	}
+
+	newArticleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleSplitInput.NewName)
+	if err != nil {
+		return internalError(articleSplitInput.NewName, "Failed to get article: "+err.Error())
+	} else if newArticleAsBytes != nil {
+		return alreadyExists(articleSplitInput.NewName, "This article already exists: "+articleSplitInput.NewName)
+	}
+
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, articleToSplit.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	newPrice := model.ScalePrice(privateDetails.Price, articleSplitInput.Quantity, quantity)
+	if newPrice <= 0 {
+		return invalidInput(articleSplitInput.Name, "the price of the article is too low to be split")
+	}
+
+	txTimestamp, err := txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Create the new article ====
+	newArticle := &model.Article{
+		ObjectType: "article",
+		Name:       articleSplitInput.NewName,
+		Color:      articleToSplit.Color,
+		Size:       articleToSplit.Size,
+		Owner:      articleToSplit.Owner,
+		Owners:     articleToSplit.Owners,
+		OwnerOrg:   clientOrgID,
+		Salt:       articleSplitInput.Salt,
+		Quantity:   articleSplitInput.Quantity,
+		Tags:       articleToSplit.Tags,
+		Category:   articleToSplit.Category,
+		CreatedAt:  txTimestamp,
+		UpdatedAt:  txTimestamp,
+
+		WeightGrams: articleToSplit.WeightGrams, //of a unit, so unchanged by the split
+		Dimensions:  articleToSplit.Dimensions,
+	}
+	err = putNewArticle(stub, cfg, newArticle, newPrice, privateDetails.Currency)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Reduce the original article by the units that were split off ====
+	articleToSplit.Quantity = quantity - articleSplitInput.Quantity
+	articleToSplit.UpdatedAt = txTimestamp
+
+	err = putArticle(stub, cfg, &articleToSplit) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = putArticlePrivateDetails(stub, cfg, articleToSplit.Name, privateDetails.CreatorID, privateDetails.Currency, privateDetails.Price, privateDetails.Price-newPrice)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, articleToSplit.Name, "splitArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = putAuditRecord(stub, cfg, newArticle.Name, "splitArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleCreated", model.ArticleEventEntry{Name: newArticle.Name})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end splitArticle (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// storedArticle1 is article1 of TestStoredBytes after its transfer to jerry
+const storedArticle1 = `{"askingPriceVisible":false,"color":"blue","createdAt":"2024-01-01T00:00:02Z","deleted":false,"deletedAt":"","docType":"article","forSale":false,"lockExpiry":"","locked":false,"lockedBy":"","name":"article1","owner":"jerry","ownerOrg":"Org2MSP","quantity":1,"salt":"` + testSalt + `","schemaVersion":2,"size":35,"updatedAt":"2024-01-01T00:00:05Z"}`
+
+// TestStoredBytes pins the keys and bytes a create, an agreement, a transfer and a delete
+// write for a fixed input. The keys are those main.go wrote before the handlers moved to
+// their own package, the values have the fields added since, so a refactoring of the
+// handlers must leave this test as it is.
+func TestStoredBytes(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"agreeToTransfer": AgreeToTransfer,
+		"transferArticle": TransferArticle,
+		"delete":          Delete,
+	})
+	n.createArticle(t, `{"name":"article1","color":"blue","size":35,"owner":"tom","price":99,"currency":"EUR","salt":"`+testSalt+`"}`)
+	n.createArticle(t, `{"name":"article2","color":"red","size":40,"owner":"tom","price":5,"currency":"EUR","salt":"`+testSalt+`"}`)
+	response := n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1","price":99,"currency":"EUR"}`))
+	expectStatus(t, response, shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+	expectStatus(t, n.admin1.InvokeTransient("delete", testutil.Transient("article_delete", `{"name":"article2"}`)), shim.OK)
+
+	implicitOrg2 := "_implicit_org_" + org2
+	for _, test := range []struct {
+		collection string
+		key        string
+		value      string
+	}{
+		{model.DefaultCollectionArticles, "article1", storedArticle1},
+		{model.DefaultCollectionArticles, compositeKey(t, "color~name", "blue", "article1"), "\x00"},
+		{model.DefaultCollectionArticles, compositeKey(t, "owner~name", "jerry", "article1"), "\x00"},
+		{model.DefaultCollectionArticlePrivateDetails, "article1", `{"creatorID":"eDUwOTo6Q049dXNlcjEsTz1PcmcxTVNQOjpDTj11c2VyMSxPPU9yZzFNU1A=","currency":"EUR","docType":"articlePrivateDetails","name":"article1","price":99,"schemaVersion":2}`},
+		{implicitOrg2, compositeKey(t, "agreement~name~terms", "article1", "price"), `{"currency":"EUR","docType":"articlePrivateDetails","name":"article1","price":99,"schemaVersion":2}`},
+		{implicitOrg2, compositeKey(t, "agreement~name~terms", "article1", "properties"), strings.NewReplacer(`"owner":"jerry","ownerOrg":"Org2MSP"`, `"owner":"tom","ownerOrg":"Org1MSP"`, `00:00:05Z`, `00:00:02Z`).Replace(storedArticle1)},
+	} {
+		if value := n.PrivateData(test.collection, test.key); string(value) != test.value {
+			t.Errorf("%s has %q under %q, expected %q", test.collection, value, test.key, test.value)
+		}
+	}
+
+	// the transfer and the delete leave nothing of the old owner or of article2 behind
+	for _, test := range []struct {
+		collection string
+		key        string
+	}{
+		{model.DefaultCollectionArticles, compositeKey(t, "owner~name", "tom", "article1")},
+		{model.DefaultCollectionArticles, "article2"},
+		{model.DefaultCollectionArticles, compositeKey(t, "color~name", "red", "article2")},
+		{model.DefaultCollectionArticles, compositeKey(t, "owner~name", "tom", "article2")},
+		{model.DefaultCollectionArticlePrivateDetails, "article2"},
+	} {
+		if value := n.PrivateData(test.collection, test.key); value != nil {
+			t.Errorf("%s still has %q under %q", test.collection, value, test.key)
+		}
+	}
+}
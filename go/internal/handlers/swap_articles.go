@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// SwapArticles - exchange the owners of two articles in a single transaction. Both articles
+// must be owned as the caller claims and both must belong to the submitting org, so no org
+// can be drawn into a swap by another one; trades between orgs go through agreeToTransfer
+// and transferArticle. Nothing is written unless every check passes.
+// ===========================================================================================
+func SwapArticles(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	txLogger(stub).Debugf("start swap articles")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleSwapJsonBytes, ok := transMap["article_swap"]
+	if !ok {
+		return invalidInput("", "article_swap must be a key in the transient map")
+	}
+
+	if len(articleSwapJsonBytes) == 0 {
+		return invalidInput("", "article_swap value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleSwapInput model.ArticleSwapTransientInput
+	err = model.DecodeTransientInput("article_swap", articleSwapJsonBytes, &articleSwapInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleSwapInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleSwapInput.Name1, err.Error())
+	}
+
+	// ==== Check both articles before writing anything ====
+	article1, err := getArticleOwnedBy(stub, cfg, articleSwapInput.Name1, articleSwapInput.Owner1)
+	if err != nil {
+		return errorResponse(err)
+	}
+	article2, err := getArticleOwnedBy(stub, cfg, articleSwapInput.Name2, articleSwapInput.Owner2)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	clientOrgID, err := verifyClientIsOwnerOrg(stub, article1)
+	if err != nil {
+		return errorResponse(err)
+	}
+	_, err = verifyClientIsOwnerOrg(stub, article2)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = verifyNotLockedByOtherOrg(stub, article1)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLockedByOtherOrg(stub, article2)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Swap the owners ====
+	err = changeArticleOwner(stub, cfg, article1, articleSwapInput.Owner2, clientOrgID, "swapArticles")
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = changeArticleOwner(stub, cfg, article2, articleSwapInput.Owner1, clientOrgID, "swapArticles")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Both owners keep their number of articles ====
+	// the two changes of owner adjusted each count twice from the committed value, which
+	// the reads of this transaction still return, so the counts are written back as they are
+	if articleSwapInput.Owner1 != articleSwapInput.Owner2 {
+		err = adjustOwnerCount(stub, cfg, articleSwapInput.Owner1, 0)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = adjustOwnerCount(stub, cfg, articleSwapInput.Owner2, 0)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	err = setArticleEvent(stub, "ArticleTransferred",
+		model.ArticleEventEntry{Name: article1.Name, OldOwner: articleSwapInput.Owner1, NewOwner: article1.Owner},
+		model.ArticleEventEntry{Name: article2.Name, OldOwner: articleSwapInput.Owner2, NewOwner: article2.Owner},
+	)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end swapArticles (success)")
+	return shim.Success(nil)
+}
+
+// getArticleOwnedBy reads an article and checks that it has the expected owner
+func getArticleOwnedBy(stub shim.ChaincodeStubInterface, cfg *model.Config, name string, owner string) (*model.Article, error) {
+	article, err := getArticle(stub, cfg, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if article.Owner != owner {
+		return nil, newError(CodeAccessDenied, name, "article %s is owned by %s, not %s", name, article.Owner, owner)
+	}
+	if len(article.Owners) != 0 {
+		return nil, newError(CodeInvalidInput, name, "jointly owned article %s cannot be swapped", name)
+	}
+	return article, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// articleCount returns the article count of the owner from getOwnerArticleCount
+func (n *testNetwork) articleCount(t *testing.T, owner string) int {
+	t.Helper()
+	response := n.user1.Query("getOwnerArticleCount", owner)
+	expectStatus(t, response, shim.OK)
+	var count model.OwnerArticleCount
+	err := json.Unmarshal(response.Payload, &count)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", response.Payload, err)
+	}
+	return count.Count
+}
+
+func TestSwapArticlesKeepsCounts(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"swapArticles":         SwapArticles,
+		"getOwnerArticleCount": GetOwnerArticleCount,
+	})
+	n.registerOwner(t, "spike", org1)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+	n.createArticle(t, articleJSON("article2", "blue", 35, 0))
+	n.createArticle(t, `{"name":"article3","color":"red","size":40,"owner":"spike","salt":"`+testSalt+`"}`)
+
+	response := n.user1.InvokeTransient("swapArticles", testutil.Transient("article_swap", `{"name1":"article1","owner1":"tom","name2":"article3","owner2":"spike"}`))
+	expectStatus(t, response, shim.OK)
+
+	if article := n.readArticle(t, "article1"); article.Owner != "spike" {
+		t.Errorf("article1 is owned by %s, expected spike", article.Owner)
+	}
+	if article := n.readArticle(t, "article3"); article.Owner != "tom" {
+		t.Errorf("article3 is owned by %s, expected tom", article.Owner)
+	}
+	for _, key := range []string{compositeKey(t, model.OwnerNameIndex, "spike", "article1"), compositeKey(t, model.OwnerNameIndex, "tom", "article3")} {
+		if n.PrivateData(model.DefaultCollectionArticles, key) == nil {
+			t.Errorf("swap did not write the owner~name entry %q", key)
+		}
+	}
+	if count := n.articleCount(t, "tom"); count != 2 {
+		t.Errorf("tom has %d articles after the swap, expected 2", count)
+	}
+	if count := n.articleCount(t, "spike"); count != 1 {
+		t.Errorf("spike has %d articles after the swap, expected 1", count)
+	}
+
+	// a swap based on a stale owner writes nothing
+	tx := n.user1.Submit(testutil.Invocation{Function: "swapArticles", Transient: testutil.Transient("article_swap", `{"name1":"article2","owner1":"tom","name2":"article3","owner2":"spike"}`)})
+	expectCode(t, tx.Response, CodeAccessDenied)
+	if tx.Writes != 0 {
+		t.Errorf("rejected swap wrote %d keys", tx.Writes)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================
+// TransferArticle - transfer a article by setting a new owner name on the article. A transfer
+// to the current owner and owner org writes nothing and returns {"changed":false}, or fails
+// when the input is strict. With expectedCurrentOwner in the input, the transfer fails with
+// CONFLICT when the article has another owner. A leased article fails with CONFLICT too
+// until the lease ends, unless terminateLease ends it with the transfer.
+// ===========================================================
+func TransferArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	txLogger(stub).Debugf("start transfer article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleOwnerJsonBytes, ok := transMap["article_owner"]
+	if !ok {
+		return invalidInput("", "article_owner must be a key in the transient map")
+	}
+
+	if len(articleOwnerJsonBytes) == 0 {
+		return invalidInput("", "article_owner value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleTransferInput model.ArticleTransferTransientInput
+	err = model.DecodeTransientInput("article_owner", articleOwnerJsonBytes, &articleTransferInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleTransferInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleTransferInput.Name, err.Error())
+	}
+
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleTransferInput.Name)
+	if err != nil {
+		return internalError(articleTransferInput.Name, "Failed to get article:"+err.Error())
+	} else if articleAsBytes == nil {
+		return notFound(articleTransferInput.Name, "Article does not exist: "+articleTransferInput.Name)
+	}
+
+	articleToTransfer := model.Article{}
+	err = json.Unmarshal(articleAsBytes, &articleToTransfer) //unmarshal it aka JSON.parse()
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotDeleted(&articleToTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotRetired(&articleToTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The transfer is based on the owner the client expects ====
+	if len(articleTransferInput.ExpectedCurrentOwner) != 0 && articleTransferInput.ExpectedCurrentOwner != articleToTransfer.Owner {
+		// only the owner org learns who the owner is instead
+		if _, err := verifyClientIsOwnerOrg(stub, &articleToTransfer); err == nil {
+			return errorResponse(newError(CodeConflict, articleTransferInput.Name, "Article %s is owned by %s, not %s", articleTransferInput.Name, articleToTransfer.Owner, articleTransferInput.ExpectedCurrentOwner))
+		}
+		return errorResponse(newError(CodeConflict, articleTransferInput.Name, "Article %s is not owned by %s", articleTransferInput.Name, articleTransferInput.ExpectedCurrentOwner))
+	}
+
+	// ==== Only the organization of the current owner may transfer the article ====
+	clientOrgID, err := verifyClientIsOwnerOrg(stub, &articleToTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	buyerOrgID := articleTransferInput.OwnerOrg
+	if len(buyerOrgID) == 0 {
+		buyerOrgID = clientOrgID
+	}
+
+	// ==== A transfer to the current owner and org writes nothing ====
+	if articleTransferInput.Owner == articleToTransfer.Owner && buyerOrgID == articleToTransfer.OwnerOrg {
+		if articleTransferInput.Strict {
+			return invalidInput(articleTransferInput.Name, "Article "+articleTransferInput.Name+" is already owned by "+articleTransferInput.Owner)
+		}
+		txLogger(stub).Infof("end transferArticle (unchanged)")
+		return changeResponse(false)
+	}
+
+	// ==== A disputed article does not change hands, whatever the buyer agreed to ====
+	err = verifyNotDisputed(&articleToTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The new owner must be an active owner of the registry ====
+	err = verifyOwnerRegistered(stub, cfg, articleTransferInput.Owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyParticipantExists(stub, cfg, articleTransferInput.Owner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The buyer org must have agreed to the article properties and price ====
+	err = verifyTransferAgreement(stub, cfg, buyerOrgID, articleToTransfer.Name, articleAsBytes, 0)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== A lock of the buyer org reserves the article for this very transfer ====
+	if articleToTransfer.LockedBy == buyerOrgID {
+		txLogger(stub).Debugf("article %s is locked by the buyer org, skipping the lock check", articleToTransfer.Name)
+	} else {
+		err = verifyNotLockedByOtherOrg(stub, &articleToTransfer)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== A running lease only ends with the transfer when asked to ====
+	if articleTransferInput.TerminateLease && len(articleToTransfer.Lessee) != 0 {
+		err = endArticleLease(stub, cfg, &articleToTransfer)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	oldOwner := articleToTransfer.Owner
+
+	err = changeArticleOwner(stub, cfg, &articleToTransfer, articleTransferInput.Owner, buyerOrgID, "transferArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleTransferred", model.ArticleEventEntry{
+		Name:     articleToTransfer.Name,
+		OldOwner: oldOwner,
+		NewOwner: articleToTransfer.Owner,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end transferArticle (success)")
+	return changeResponse(true)
+}
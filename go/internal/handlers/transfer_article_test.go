@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+var transferFunctions = map[string]HandlerFunc{
+	"agreeToTransfer": AgreeToTransfer,
+	"transferArticle": TransferArticle,
+}
+
+func transferToJerry(name string) map[string][]byte {
+	return testutil.Transient("article_owner", `{"name":"`+name+`","owner":"jerry","ownerOrg":"`+org2+`"}`)
+}
+
+func TestTransferArticleWithoutPrivateDetails(t *testing.T) {
+	n := newTestNetwork(t, transferFunctions)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+	if n.PrivateData(model.DefaultCollectionArticlePrivateDetails, "article1") != nil {
+		t.Fatalf("article created without a price has private details")
+	}
+
+	// an agreement to a price does not match an article without one
+	response := n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1","price":100,"currency":"EUR"}`))
+	expectStatus(t, response, shim.OK)
+	expectCode(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), CodeAccessDenied)
+
+	// a currency without a price is no agreement at all
+	response = n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1","currency":"EUR"}`))
+	expectCode(t, response, CodeInvalidInput)
+
+	response = n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`))
+	expectStatus(t, response, shim.OK)
+	response = n.user1.InvokeTransient("transferArticle", transferToJerry("article1"))
+	expectStatus(t, response, shim.OK)
+
+	article := n.readArticle(t, "article1")
+	if article.Owner != "jerry" || article.OwnerOrg != org2 {
+		t.Errorf("article is owned by %s of %s, expected jerry of %s", article.Owner, article.OwnerOrg, org2)
+	}
+
+	// Org2 is no member of the private details collection, but the article has none
+	response = n.user1.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`))
+	expectStatus(t, response, shim.OK)
+	response = n.user2.InvokeTransient("transferArticle", testutil.Transient("article_owner", `{"name":"article1","owner":"tom","ownerOrg":"`+org1+`"}`))
+	expectStatus(t, response, shim.OK)
+}
+
+func TestTransferArticleWithoutPriceAgreement(t *testing.T) {
+	n := newTestNetwork(t, transferFunctions)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+
+	// an article with a price is not transferred on an agreement without one
+	response := n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`))
+	expectStatus(t, response, shim.OK)
+	expectCode(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), CodeAccessDenied)
+
+	response = n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1","price":9900,"currency":"EUR"}`))
+	expectStatus(t, response, shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+}
+
+func TestChangeArticleOwnerOfOtherDocType(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		// transferBook gives the book item1 to jerry, the transfer functions only take articles
+		"transferBook": func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+			key, err := articleKey(stub, "book", "item1")
+			if err != nil {
+				return errorResponse(err)
+			}
+			bookAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, key)
+			if err != nil {
+				return errorResponse(err)
+			}
+			book := &model.Article{}
+			err = json.Unmarshal(bookAsBytes, book)
+			if err != nil {
+				return errorResponse(err)
+			}
+			err = changeArticleOwner(stub, cfg, book, "jerry", org2, "transferBook")
+			if err != nil {
+				return errorResponse(err)
+			}
+			return shim.Success(nil)
+		},
+	})
+	n.cfg.DocTypes = []string{"book"}
+	n.createArticle(t, `{"docType":"book","name":"item1","color":"green","size":300,"owner":"tom","salt":"`+testSalt+`"}`)
+	expectStatus(t, n.user1.Invoke("transferBook"), shim.OK)
+
+	key, _ := shimtest.NewMockStub("keys", nil).CreateCompositeKey("book", []string{"item1"})
+	policy, err := statebased.NewStateEP(n.PrivateDataValidationParameter(model.DefaultCollectionArticles, key))
+	if err != nil {
+		t.Fatalf("failed to decode the endorsement policy of the book: %v", err)
+	}
+	if orgs := policy.ListOrgs(); len(orgs) != 1 || orgs[0] != org2 {
+		t.Errorf("book is endorsed by %v, expected %s", orgs, org2)
+	}
+	if n.PrivateDataValidationParameter(model.DefaultCollectionArticles, "item1") != nil {
+		t.Errorf("transfer of the book set an endorsement policy on the article item1")
+	}
+}
+
+func TestTransferArticleByOwnerOrgOnly(t *testing.T) {
+	n := newTestNetwork(t, transferFunctions)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+	if article := n.readArticle(t, "article1"); article.OwnerOrg != org1 {
+		t.Fatalf("article created by a client of %s has owner org %s", org1, article.OwnerOrg)
+	}
+	response := n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`))
+	expectStatus(t, response, shim.OK)
+
+	// a client of Org2 cannot take the article of an Org1 owner
+	response = n.user2.InvokeTransient("transferArticle", transferToJerry("article1"))
+	expectCode(t, response, CodeAccessDenied)
+	if !strings.Contains(response.Message, "submitting org "+org2+" is not the owner org "+org1) {
+		t.Errorf("denied transfer failed with %s", response.Message)
+	}
+	if article := n.readArticle(t, "article1"); article.Owner != "tom" {
+		t.Fatalf("denied transfer gave the article to %s", article.Owner)
+	}
+
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+
+	// the transfer recorded the org of the new owner, which alone may transfer it now
+	response = n.user1.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`))
+	expectStatus(t, response, shim.OK)
+	toTom := testutil.Transient("article_owner", `{"name":"article1","owner":"tom","ownerOrg":"`+org1+`"}`)
+	expectCode(t, n.user1.InvokeTransient("transferArticle", toTom), CodeAccessDenied)
+	expectStatus(t, n.user2.InvokeTransient("transferArticle", toTom), shim.OK)
+	if article := n.readArticle(t, "article1"); article.Owner != "tom" || article.OwnerOrg != org1 {
+		t.Errorf("article is owned by %s of %s, expected tom of %s", article.Owner, article.OwnerOrg, org1)
+	}
+}
+
+func TestTransferExpectedCurrentOwner(t *testing.T) {
+	n := newTestNetwork(t, transferFunctions)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+	expectStatus(t, n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`)), shim.OK)
+	transfer := func(client *testutil.Client, expectedCurrentOwner string) *testutil.Transaction {
+		return client.Submit(testutil.Invocation{
+			Function:  "transferArticle",
+			Transient: testutil.Transient("article_owner", `{"name":"article1","owner":"jerry","ownerOrg":"`+org2+`","expectedCurrentOwner":"`+expectedCurrentOwner+`"}`),
+		})
+	}
+
+	// the owner org learns the actual owner, other orgs do not
+	for _, test := range []struct {
+		client  *testutil.Client
+		message string
+	}{
+		{n.user1, "Article article1 is owned by tom, not jerry"},
+		{n.user2, "Article article1 is not owned by jerry"},
+	} {
+		tx := transfer(test.client, "jerry")
+		expectCode(t, tx.Response, CodeConflict)
+		if !strings.Contains(tx.Response.Message, test.message) || (test.client == n.user2 && strings.Contains(tx.Response.Message, "tom")) {
+			t.Errorf("stale transfer by a client of %s returned %s, expected %s", test.client.MSPID, tx.Response.Message, test.message)
+		}
+		if tx.Writes != 0 {
+			t.Errorf("stale transfer by a client of %s wrote %d keys", test.client.MSPID, tx.Writes)
+		}
+	}
+
+	expectStatus(t, transfer(n.user1, "tom").Response, shim.OK)
+	if article := n.readArticle(t, "article1"); article.Owner != "jerry" {
+		t.Fatalf("article1 was transferred to %s, expected jerry", article.Owner)
+	}
+
+	// a client that read the owner before the transfer learns nothing about the new one
+	tx := transfer(n.user1, "tom")
+	expectCode(t, tx.Response, CodeConflict)
+	if strings.Contains(tx.Response.Message, "jerry") {
+		t.Errorf("stale transfer by a client of %s returned %s, which names the new owner", org1, tx.Response.Message)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// TransferArticleWithPrice - transfer a article and set its new price in one transaction.
+// A price the buyer org negotiated with setNegotiatedPrice takes precedence over the new
+// price. The buyer org must have agreed to the price with agreeToTransfer. The private details
+// are read before anything is written, so an org outside collectionArticlePrivateDetails
+// fails without leaving a transferred article with the old price behind.
+// ===========================================================================================
+func TransferArticleWithPrice(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start transfer article with price")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleTransferJsonBytes, ok := transMap["article_transfer"]
+	if !ok {
+		return invalidInput("", "article_transfer must be a key in the transient map")
+	}
+
+	if len(articleTransferJsonBytes) == 0 {
+		return invalidInput("", "article_transfer value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleTransferInput model.ArticleTransferWithPriceTransientInput
+	err = model.DecodeTransientInput("article_transfer", articleTransferJsonBytes, &articleTransferInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleTransferInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleTransferInput.Name, err.Error())
+	}
+
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleTransferInput.Name)
+	if err != nil {
+		return internalError(articleTransferInput.Name, "Failed to get article:"+err.Error())
+	} else if articleAsBytes == nil {
+		return notFound(articleTransferInput.Name, "Article does not exist: "+articleTransferInput.Name)
+	}
+
+	articleToTransfer := model.Article{}
+	err = json.Unmarshal(articleAsBytes, &articleToTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotDeleted(&articleToTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotRetired(&articleToTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owner may transfer the article ====
+	clientOrgID, err := verifyClientIsOwnerOrg(stub, &articleToTransfer)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The new owner must be an active owner of the registry ====
+	err = verifyOwnerRegistered(stub, cfg, articleTransferInput.NewOwner)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyParticipantExists(stub, cfg, articleTransferInput.NewOwner)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The details must be readable before the first write ====
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, articleToTransfer.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The buyer org must have agreed to the article properties and the new price ====
+	buyerOrgID := articleTransferInput.OwnerOrg
+	if len(buyerOrgID) == 0 {
+		buyerOrgID = clientOrgID
+	}
+	newPrice, err := negotiatedTransferPrice(stub, cfg, privateDetails, buyerOrgID, clientOrgID, articleTransferInput.NewPrice)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyTransferAgreement(stub, cfg, buyerOrgID, articleToTransfer.Name, articleAsBytes, newPrice)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== A lock of the buyer org reserves the article for this very transfer ====
+	if articleToTransfer.LockedBy != buyerOrgID {
+		err = verifyNotLockedByOtherOrg(stub, &articleToTransfer)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	oldOwner := articleToTransfer.Owner
+
+	err = changeArticleOwner(stub, cfg, &articleToTransfer, articleTransferInput.NewOwner, buyerOrgID, "transferArticleWithPrice")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putArticlePrivateDetails(stub, cfg, privateDetails.Name, privateDetails.CreatorID, privateDetails.Currency, privateDetails.Price, newPrice)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = putPriceRecord(stub, cfg, privateDetails.Name, privateDetails.Price, newPrice)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleTransferred", model.ArticleEventEntry{
+		Name:     articleToTransfer.Name,
+		OldOwner: oldOwner,
+		NewOwner: articleToTransfer.Owner,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end transferArticleWithPrice (success)")
+	return shim.Success(nil)
+}
@@ -0,0 +1,205 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// TransferShare - pass on part of an article from one owner to another, making it jointly
+// owned. The shares always add up to 100 percent; an owner whose share drops to 0 is no
+// longer a co-owner, and an article left with a single owner is stored as before co-owning
+// existed. The owner~name index and the article counts follow the co-owners, and the
+// change is appended to the ownership history. Only the owner org can transfer shares,
+// all co-owners belong to it.
+// ===========================================================================================
+func TransferShare(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start transfer share")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	shareTransferJsonBytes, ok := transMap["share_transfer"]
+	if !ok {
+		return invalidInput("", "share_transfer must be a key in the transient map")
+	}
+
+	if len(shareTransferJsonBytes) == 0 {
+		return invalidInput("", "share_transfer value in the transient map must be a non-empty JSON string")
+	}
+
+	var shareTransferInput model.ShareTransferTransientInput
+	err = model.DecodeTransientInput("share_transfer", shareTransferJsonBytes, &shareTransferInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = shareTransferInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(shareTransferInput.Name, err.Error())
+	}
+
+	article, err := getArticle(stub, cfg, shareTransferInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Only the organization of the current owners may transfer shares ====
+	_, err = verifyClientIsOwnerOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotLockedByOtherOrg(stub, article)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotDisputed(article)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	fromShare := article.ShareOf(shareTransferInput.FromOwner)
+	if fromShare == 0 {
+		return invalidInput(article.Name, "Article "+article.Name+" is not owned by "+shareTransferInput.FromOwner)
+	}
+	if fromShare < shareTransferInput.Percent {
+		return invalidInput(article.Name, fmt.Sprintf("%s holds %d percent of article %s, cannot transfer %d", shareTransferInput.FromOwner, fromShare, article.Name, shareTransferInput.Percent))
+	}
+
+	// ==== The new co-owner must be an active owner of the registry ====
+	toOwnerHeldShare := article.ShareOf(shareTransferInput.ToOwner) > 0
+	if !toOwnerHeldShare {
+		err = verifyOwnerRegistered(stub, cfg, shareTransferInput.ToOwner)
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = verifyParticipantExists(stub, cfg, shareTransferInput.ToOwner)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== Move the percentage, dropping an emptied share ====
+	shares := []model.OwnerShare{}
+	for _, share := range article.Shares() {
+		switch share.Owner {
+		case shareTransferInput.FromOwner:
+			share.Percent -= shareTransferInput.Percent
+		case shareTransferInput.ToOwner:
+			share.Percent += shareTransferInput.Percent
+		}
+		if share.Percent > 0 {
+			shares = append(shares, share)
+		}
+	}
+	if !toOwnerHeldShare {
+		shares = append(shares, model.OwnerShare{Owner: shareTransferInput.ToOwner, Percent: shareTransferInput.Percent})
+	}
+	err = model.ValidateShares(shares)
+	if err != nil {
+		return internalError(article.Name, err.Error())
+	}
+	fromOwnerLeft := fromShare == shareTransferInput.Percent
+
+	article.SetShares(shares)
+	article.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putArticle(stub, cfg, article) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The owner~name index and the counts list the co-owners ====
+	if fromOwnerLeft {
+		fromOwnerNameIndexKey, err := stub.CreateCompositeKey(model.OwnerNameIndex, []string{shareTransferInput.FromOwner, article.Name})
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = stub.DelPrivateData(cfg.CollectionArticles, fromOwnerNameIndexKey)
+		if err != nil {
+			return internalError(article.Name, "Failed to delete state:"+err.Error())
+		}
+		err = adjustOwnerCount(stub, cfg, shareTransferInput.FromOwner, -1)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+	if !toOwnerHeldShare {
+		toOwnerNameIndexKey, err := stub.CreateCompositeKey(model.OwnerNameIndex, []string{shareTransferInput.ToOwner, article.Name})
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = stub.PutPrivateData(cfg.CollectionArticles, toOwnerNameIndexKey, []byte{0x00})
+		if err != nil {
+			return errorResponse(err)
+		}
+		err = adjustOwnerCount(stub, cfg, shareTransferInput.ToOwner, 1)
+		if err != nil {
+			return errorResponse(err)
+		}
+	}
+
+	// ==== Append the share transfer to the ownership history ====
+	ownershipRecordJSONasBytes, err := json.Marshal(&model.OwnershipRecord{
+		ObjectType:    "ownershipRecord",
+		Name:          article.Name,
+		PreviousOwner: shareTransferInput.FromOwner,
+		NewOwner:      shareTransferInput.ToOwner,
+		Percent:       shareTransferInput.Percent,
+		TxID:          stub.GetTxID(),
+		Timestamp:     article.UpdatedAt,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+	historyKey, err := nextSequenceKey(stub, cfg.CollectionArticles, model.HistoryIndex, article.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = stub.PutPrivateData(cfg.CollectionArticles, historyKey, ownershipRecordJSONasBytes)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, article.Name, "transferShare")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = setArticleEvent(stub, "ArticleShareTransferred", model.ArticleEventEntry{
+		Name:     article.Name,
+		OldOwner: shareTransferInput.FromOwner,
+		NewOwner: shareTransferInput.ToOwner,
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end transferShare (success)")
+	return shim.Success(nil)
+}
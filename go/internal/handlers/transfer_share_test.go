@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+// legacyArticle is article1 of tom as stored before co-owning existed
+const legacyArticle = `{"docType":"article","name":"article1","color":"blue","size":35,"owner":"tom","ownerOrg":"Org1MSP","salt":"` + testSalt + `"}`
+
+func TestSingleOwnerRecordsUnchanged(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"agreeToTransfer":    AgreeToTransfer,
+		"transferArticle":    TransferArticle,
+		"getArticlesByOwner": GetArticlesByOwner,
+		// putLegacyArticle writes legacyArticle the way older versions of the chaincode did
+		"putLegacyArticle": func(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+			ownerNameIndexKey, err := stub.CreateCompositeKey(model.OwnerNameIndex, []string{"tom", "article1"})
+			if err == nil {
+				err = stub.PutPrivateData(cfg.CollectionArticles, ownerNameIndexKey, []byte{0x00})
+			}
+			if err == nil {
+				err = stub.PutPrivateData(cfg.CollectionArticles, "article1", []byte(legacyArticle))
+			}
+			if err != nil {
+				return errorResponse(err)
+			}
+			return shim.Success(nil)
+		},
+	})
+	expectStatus(t, n.user1.Invoke("putLegacyArticle"), shim.OK)
+
+	response := n.user2.Query("readArticle", "article1")
+	expectStatus(t, response, shim.OK)
+	if strings.Contains(string(response.Payload), `"owners"`) {
+		t.Errorf("legacy article1 reads as %s", response.Payload)
+	}
+	article := n.readArticle(t, "article1")
+	if article.Owner != "tom" || article.Owners != nil || article.ShareOf("tom") != 100 {
+		t.Errorf("legacy article1 reads as owned by %s with shares %v", article.Owner, article.Owners)
+	}
+
+	expectStatus(t, n.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1"}`)), shim.OK)
+	expectStatus(t, n.user1.InvokeTransient("transferArticle", transferToJerry("article1")), shim.OK)
+	stored := string(n.PrivateData(model.DefaultCollectionArticles, "article1"))
+	if strings.Contains(stored, `"owners"`) || !strings.Contains(stored, `"owner":"jerry"`) {
+		t.Errorf("transferred legacy article1 is stored as %s", stored)
+	}
+	if articles := n.articlesOf(t, n.user1, "jerry"); len(articles) != 1 || articles[0] != "article1" {
+		t.Errorf("jerry owns %v, expected article1", articles)
+	}
+	if articles := n.articlesOf(t, n.user1, "tom"); len(articles) != 0 {
+		t.Errorf("tom still owns %v", articles)
+	}
+}
+
+func TestTransferShare(t *testing.T) {
+	n := newTestNetwork(t, map[string]HandlerFunc{
+		"transferShare":      TransferShare,
+		"getArticlesByOwner": GetArticlesByOwner,
+	})
+	n.registerOwner(t, "spike", org1)
+	n.createArticle(t, articleJSON("article1", "blue", 35, 0))
+	single := string(n.PrivateData(model.DefaultCollectionArticles, "article1"))
+	if strings.Contains(single, `"owners"`) {
+		t.Errorf("article1 of a single owner is stored with shares: %s", single)
+	}
+	share := func(from string, to string, percent string) *testutil.Transaction {
+		return n.user1.Submit(testutil.Invocation{
+			Function:  "transferShare",
+			Transient: testutil.Transient("share_transfer", `{"name":"article1","fromOwner":"`+from+`","toOwner":"`+to+`","percent":`+percent+`}`),
+		})
+	}
+
+	expectStatus(t, share("tom", "spike", "25").Response, shim.OK)
+	article := n.readArticle(t, "article1")
+	shares, _ := json.Marshal(article.Owners)
+	if article.Owner != "tom" || string(shares) != `[{"owner":"tom","percent":75},{"owner":"spike","percent":25}]` {
+		t.Errorf("article1 is owned by %s with shares %s", article.Owner, shares)
+	}
+	for _, owner := range []string{"tom", "spike"} {
+		if articles := n.articlesOf(t, n.user1, owner); len(articles) != 1 || articles[0] != "article1" {
+			t.Errorf("%s owns %v, expected article1", owner, articles)
+		}
+	}
+
+	// no share goes negative or above 100 percent
+	for _, test := range []struct{ from, to, percent string }{
+		{"spike", "tom", "26"},
+		{"jerry", "tom", "10"},
+		{"tom", "spike", "0"},
+		{"tom", "spike", "101"},
+		{"tom", "spike", "-1"},
+	} {
+		tx := share(test.from, test.to, test.percent)
+		expectCode(t, tx.Response, CodeInvalidInput)
+		if tx.Writes != 0 {
+			t.Errorf("invalid share transfer %v wrote %d keys", test, tx.Writes)
+		}
+	}
+
+	// once tom holds it all again, article1 is stored as before
+	expectStatus(t, share("spike", "tom", "25").Response, shim.OK)
+	if articles := n.articlesOf(t, n.user1, "spike"); len(articles) != 0 {
+		t.Errorf("spike still owns %v", articles)
+	}
+	stored := string(n.PrivateData(model.DefaultCollectionArticles, "article1"))
+	article = n.readArticle(t, "article1")
+	if strings.Contains(stored, `"owners"`) || article.Owner != "tom" {
+		t.Errorf("article1 back with tom alone is stored as %s", stored)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/testutil"
+)
+
+// transientFunctions are the functions that take their input from the transient map, with
+// the transient key they decode
+var transientFunctions = []struct {
+	function string
+	handler  HandlerFunc
+	key      string
+}{
+	{"initArticle", InitArticle, "article"},
+	{"initArticles", InitArticles, "articles"},
+	{"transferArticle", TransferArticle, "article_owner"},
+	{"delete", Delete, "article_delete"},
+	{"acceptOffer", AcceptOffer, "offer_accept"},
+	{"acceptTransfer", AcceptTransfer, "transfer_response"},
+	{"addArticleAttachment", AddArticleAttachment, "article_attachment"},
+	{"addArticlePrivateDetails", AddArticlePrivateDetails, "article_price"},
+	{"addArticleTag", AddArticleTag, "article_tag"},
+	{"agreeToTransfer", AgreeToTransfer, "article_agreement"},
+	{"approveTransfer", ApproveTransfer, "transfer_approval"},
+	{"cancelTransfer", CancelTransfer, "transfer_response"},
+	{"certifyArticle", CertifyArticle, "certification"},
+	{"cloneArticle", CloneArticle, "article_clone"},
+	{"closeAuction", CloseAuction, "auction_close"},
+	{"deactivateOwner", DeactivateOwner, "owner_deactivation"},
+	{"deleteArticlePrivateDetailsOnly", DeleteArticlePrivateDetailsOnly, "article_private_details_delete"},
+	{"endLease", EndLease, "lease_end"},
+	{"grantDetailsAccess", GrantDetailsAccess, "details_access"},
+	{"grantPriceAccess", GrantPriceAccess, "price_access"},
+	{"importArticle", ImportArticle, "article_import"},
+	{"initiateTransfer", InitiateTransfer, "transfer_initiation"},
+	{"leaseArticle", LeaseArticle, "article_lease"},
+	{"lockArticle", LockArticle, "article_lock"},
+	{"makeOffer", MakeOffer, "offer"},
+	{"mergeArticles", MergeArticles, "article_merge"},
+	{"openAuction", OpenAuction, "auction"},
+	{"placeBid", PlaceBid, "bid"},
+	{"proposeTransfer", ProposeTransfer, "transfer_proposal"},
+	{"purgeArticlePrivateDetails", PurgeArticlePrivateDetails, "article_purge"},
+	{"raiseDispute", RaiseDispute, "dispute"},
+	{"registerOwner", RegisterOwner, "owner_registration"},
+	{"renameArticle", RenameArticle, "article_rename"},
+	{"resetArticleEndorsementPolicy", ResetArticleEndorsementPolicy, "policy_reset"},
+	{"resolveDispute", ResolveDispute, "dispute_resolution"},
+	{"retireArticle", RetireArticle, "article_retire"},
+	{"revealBid", RevealBid, "bid_reveal"},
+	{"revokeCertification", RevokeCertification, "certification_revocation"},
+	{"revokeDetailsAccess", RevokeDetailsAccess, "details_access_revoke"},
+	{"setArticleForSale", SetArticleForSale, "article_sale"},
+	{"setNegotiatedPrice", SetNegotiatedPrice, "negotiated_price"},
+	{"splitArticle", SplitArticle, "article_split"},
+	{"swapArticles", SwapArticles, "article_swap"},
+	{"transferArticleWithPrice", TransferArticleWithPrice, "article_transfer"},
+	{"transferShare", TransferShare, "share_transfer"},
+	{"unlockArticle", UnlockArticle, "article_lock"},
+	{"updateArticlePrice", UpdateArticlePrice, "article_price"},
+}
+
+// FuzzTransientInput feeds arbitrary transient values to the functions as a client of the
+// owner org, with an article1 of tom on the ledger. Every response has to be a success or
+// a chaincode error, never a recovered panic, and invalid input must not write anything.
+func FuzzTransientInput(f *testing.F) {
+	seeds := []string{
+		articleJSON("article2", "blue", 35, 9900),
+		"[" + articleJSON("article2", "blue", 35, 9900) + "," + articleJSON("article3", "red", 40, 0) + "]",
+		"\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff",
+		`{"name":"article1","owner":"jerry","ownerOrg":"` + org2 + `"}`,
+		`{"name":"article1","keepHistory":true}`,
+		`{"name":"article1"}`,
+		`{"name":"article1"} {}`,
+		`{"name":"\xff\xfe\xfd"}`,
+		`{"name":"article1","size":99999999999999999999999999999}`,
+		`{"name":"article1","price":1e400,"currency":"EUR"}`,
+		`{"name":"` + strings.Repeat("a", 10000) + `"}`,
+		`{"name":` + strings.Repeat(`{"a":`, 10000) + "1" + strings.Repeat("}", 10000) + "}",
+		strings.Repeat("[", 10000) + strings.Repeat("]", 10000),
+	}
+	for i := range transientFunctions {
+		for _, seed := range seeds {
+			f.Add(uint8(i), []byte(seed))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, kind uint8, data []byte) {
+		target := transientFunctions[int(kind)%len(transientFunctions)]
+		n := newTestNetwork(t, map[string]HandlerFunc{target.function: target.handler})
+		n.createArticle(t, articleJSON("article1", "blue", 35, 9900))
+
+		tx := n.user1.Submit(testutil.Invocation{Function: target.function, Transient: map[string][]byte{target.key: data}})
+		if tx.Response.Status == shim.OK {
+			return
+		}
+		var ccErr chaincodeError
+		err := json.Unmarshal([]byte(tx.Response.Message), &ccErr)
+		if err != nil || ccErr.Code == "" {
+			t.Fatalf("%s returned a malformed error for %q: %s", target.function, data, tx.Response.Message)
+		}
+		if ccErr.Code == CodeInternal && strings.Contains(ccErr.Message, "failed unexpectedly") {
+			t.Fatalf("%s panicked on %q: %s", target.function, data, ccErr.Message)
+		}
+		if ccErr.Code == CodeInvalidInput && tx.Writes != 0 {
+			t.Fatalf("%s wrote %d keys before rejecting %q: %s", target.function, tx.Writes, data, ccErr.Message)
+		}
+	})
+}
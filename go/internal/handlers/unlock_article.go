@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================================================
+// UnlockArticle - release the lock of a article. Only the org holding the lock can release
+// it before it expires.
+// ===========================================================================================
+func UnlockArticle(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	txLogger(stub).Debugf("start unlock article")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleLockJsonBytes, ok := transMap["article_lock"]
+	if !ok {
+		return invalidInput("", "article_lock must be a key in the transient map")
+	}
+
+	if len(articleLockJsonBytes) == 0 {
+		return invalidInput("", "article_lock value in the transient map must be a non-empty JSON string")
+	}
+
+	var articleLockInput model.ArticleLockTransientInput
+	err = model.DecodeTransientInput("article_lock", articleLockJsonBytes, &articleLockInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articleLockInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articleLockInput.Name, err.Error())
+	}
+
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articleLockInput.Name)
+	if err != nil {
+		return internalError(articleLockInput.Name, "Failed to get article:"+err.Error())
+	} else if articleAsBytes == nil {
+		return notFound(articleLockInput.Name, "Article does not exist: "+articleLockInput.Name)
+	}
+
+	articleToUnlock := model.Article{}
+	err = json.Unmarshal(articleAsBytes, &articleToUnlock)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotDeleted(&articleToUnlock)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotRetired(&articleToUnlock)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	if !articleToUnlock.Locked {
+		return invalidInput(articleToUnlock.Name, "Article is not locked: "+articleToUnlock.Name)
+	}
+
+	err = verifyNotLockedByOtherOrg(stub, &articleToUnlock)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	articleToUnlock.Locked = false
+	articleToUnlock.LockedBy = ""
+	articleToUnlock.LockExpiry = ""
+
+	articleToUnlock.UpdatedAt, err = txTimestampRFC3339(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putArticle(stub, cfg, &articleToUnlock) //rewrite the article
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = putAuditRecord(stub, cfg, articleToUnlock.Name, "unlockArticle")
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end unlockArticle (success)")
+	return shim.Success(nil)
+}
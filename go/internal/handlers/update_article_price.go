@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===========================================================
+// UpdateArticlePrice - change the price in the private details of a article and append
+// the change to its price history in collectionArticlePrivateDetails. The current price and
+// currency write nothing and return {"changed":false}, or fail when the input is strict.
+// ===========================================================
+func UpdateArticlePrice(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+
+	txLogger(stub).Debugf("start update article price")
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Private article data must be passed in transient map.")
+	}
+
+	err := verifyClientOrgMatchesPeerOrg(stub)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articlePriceJsonBytes, ok := transMap["article_price"]
+	if !ok {
+		return invalidInput("", "article_price must be a key in the transient map")
+	}
+
+	if len(articlePriceJsonBytes) == 0 {
+		return invalidInput("", "article_price value in the transient map must be a non-empty JSON string")
+	}
+
+	var articlePriceInput model.ArticlePriceTransientInput
+	err = model.DecodeTransientInput("article_price", articlePriceJsonBytes, &articlePriceInput)
+	if err != nil {
+		return invalidInput("", err.Error())
+	}
+
+	err = articlePriceInput.Validate(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(articlePriceInput.Name, err.Error())
+	}
+
+	// ==== Only the owner org may change the price ====
+	articleAsBytes, err := stub.GetPrivateData(cfg.CollectionArticles, articlePriceInput.Name)
+	if err != nil {
+		return internalError(articlePriceInput.Name, "Failed to get article:"+err.Error())
+	} else if articleAsBytes == nil {
+		return notFound(articlePriceInput.Name, "Article does not exist: "+articlePriceInput.Name)
+	}
+
+	articleToUpdate := model.Article{}
+	err = json.Unmarshal(articleAsBytes, &articleToUpdate)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotDeleted(&articleToUpdate)
+	if err != nil {
+		return errorResponse(err)
+	}
+	err = verifyNotRetired(&articleToUpdate)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	_, err = verifyClientIsOwnerOrg(stub, &articleToUpdate)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	privateDetails, err := getArticlePrivateDetails(stub, cfg, articlePriceInput.Name)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== The same price and currency write nothing ====
+	if privateDetails.Price == articlePriceInput.Price && model.CurrencyOf(privateDetails) == articlePriceInput.Currency {
+		if articlePriceInput.Strict {
+			return invalidInput(articlePriceInput.Name, "Article "+articlePriceInput.Name+" already has this price")
+		}
+		txLogger(stub).Infof("end updateArticlePrice (unchanged)")
+		return changeResponse(false)
+	}
+
+	oldPrice := privateDetails.Price
+	privateDetails.Price = articlePriceInput.Price //change the price
+	privateDetails.Currency = articlePriceInput.Currency
+
+	err = putArticlePrivateDetails(stub, cfg, privateDetails.Name, privateDetails.CreatorID, privateDetails.Currency, oldPrice, privateDetails.Price)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	// ==== Append the change of price to the price history ====
+	err = putPriceRecord(stub, cfg, privateDetails.Name, oldPrice, privateDetails.Price)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	txLogger(stub).Infof("end updateArticlePrice (success)")
+	return changeResponse(true)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// VerifyArticleIntegrity - verify a full private document received off-channel against the
+// private data hash of the collection it is stored in. The docType of the document selects
+// the collection: "article" for collectionArticles, "articlePrivateDetails" for
+// collectionArticlePrivateDetails. Any org on the channel can call it.
+// ===============================================
+func VerifyArticleIntegrity(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	type articleIntegrity struct {
+		Name        string `json:"name"`
+		Collection  string `json:"collection"`
+		Match       bool   `json:"match"`
+		ClaimedHash string `json:"claimedHash"` //hex encoded SHA-256 of the canonical claimed document
+		OnChainHash string `json:"onChainHash"` //hex encoded private data hash
+	}
+
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Claimed article document must be passed in transient map.")
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articleDocumentJsonBytes, ok := transMap["article_document"]
+	if !ok {
+		return invalidInput("", "article_document must be a key in the transient map")
+	}
+
+	if len(articleDocumentJsonBytes) == 0 {
+		return invalidInput("", "article_document value in the transient map must be a non-empty JSON string")
+	}
+
+	// ==== Decode the document into the struct it was stored from ====
+	// re-marshaling the struct canonically gives exactly the bytes initArticle stored,
+	// whatever the field order of the claim and whatever fields it leaves out
+	var docType struct {
+		ObjectType string `json:"docType"`
+	}
+	err = json.Unmarshal(articleDocumentJsonBytes, &docType)
+	if err != nil {
+		return invalidInput("", "Failed to decode JSON of: "+string(articleDocumentJsonBytes))
+	}
+
+	var name, collection string
+	var claimedDocumentBytes []byte
+	switch docType.ObjectType {
+	case "article":
+		var claimedArticle model.Article
+		err = json.Unmarshal(articleDocumentJsonBytes, &claimedArticle)
+		if err != nil {
+			return invalidInput("", "Failed to decode JSON of: "+string(articleDocumentJsonBytes))
+		}
+		name, collection = claimedArticle.Name, cfg.CollectionArticles
+		claimedDocumentBytes, err = model.MarshalCanonical(&claimedArticle)
+	case "articlePrivateDetails":
+		var claimedPrivateDetails model.ArticlePrivateDetails
+		err = json.Unmarshal(articleDocumentJsonBytes, &claimedPrivateDetails)
+		if err != nil {
+			return invalidInput("", "Failed to decode JSON of: "+string(articleDocumentJsonBytes))
+		}
+		name, collection = claimedPrivateDetails.Name, cfg.CollectionArticlePrivateDetails
+		claimedDocumentBytes, err = model.MarshalCanonical(&claimedPrivateDetails)
+	default:
+		return invalidInput("", "docType field must be article or articlePrivateDetails")
+	}
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = model.ValidateKeyPart("name", name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+
+	onChainHash, err := stub.GetPrivateDataHash(collection, name)
+	if err != nil {
+		return internalError(name, "Failed to get private data hash for "+name+": "+err.Error())
+	} else if onChainHash == nil {
+		return notFound(name, "Private data hash does not exist: "+name)
+	}
+
+	claimedHash := sha256.Sum256(claimedDocumentBytes)
+	integrityJSONasBytes, err := json.Marshal(&articleIntegrity{
+		Name:        name,
+		Collection:  collection,
+		Match:       bytes.Equal(onChainHash, claimedHash[:]),
+		ClaimedHash: hex.EncodeToString(claimedHash[:]),
+		OnChainHash: hex.EncodeToString(onChainHash),
+	})
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return shim.Success(integrityJSONasBytes)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// VerifyArticleProperties - verify article properties claimed off-channel, including the salt,
+// against the private data hash of collectionArticles. Any org on the channel can call it.
+// ===============================================
+func VerifyArticleProperties(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Claimed article properties must be passed in transient map.")
+	}
+
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return internalError("", "Error getting transient: "+err.Error())
+	}
+
+	articlePropertiesJsonBytes, ok := transMap["article_properties"]
+	if !ok {
+		return invalidInput("", "article_properties must be a key in the transient map")
+	}
+
+	if len(articlePropertiesJsonBytes) == 0 {
+		return invalidInput("", "article_properties value in the transient map must be a non-empty JSON string")
+	}
+
+	var claimedArticle model.Article
+	err = json.Unmarshal(articlePropertiesJsonBytes, &claimedArticle)
+	if err != nil {
+		return invalidInput("", "Failed to decode JSON of: "+string(articlePropertiesJsonBytes))
+	}
+
+	err = claimedArticle.ValidateClaim(cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(claimedArticle.Name, err.Error())
+	}
+
+	// marshal the claimed properties exactly the way initArticle stores them
+	claimedArticle.ObjectType = "article"
+	claimedHash, err := model.ComputeArticleHash(&claimedArticle)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	onChainHash, err := stub.GetPrivateDataHash(cfg.CollectionArticles, claimedArticle.Name)
+	if err != nil {
+		return internalError(claimedArticle.Name, "Failed to get article private data hash for "+claimedArticle.Name+": "+err.Error())
+	} else if onChainHash == nil {
+		return notFound(claimedArticle.Name, "Article private article data hash does not exist: "+claimedArticle.Name)
+	}
+
+	if bytes.Equal(onChainHash, claimedHash) {
+		return shim.Success([]byte("{\"match\":true}"))
+	}
+	return shim.Success([]byte("{\"match\":false}"))
+}
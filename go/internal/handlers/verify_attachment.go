@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// ===============================================
+// VerifyAttachment - compare the SHA-256 hash of a document with the hash anchored to the
+// article by addArticleAttachment. The hex case of the hashes does not matter.
+// ===============================================
+func VerifyAttachment(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 3 {
+		return invalidInput("", "Incorrect number of arguments. Expecting name, attachmentID and sha256Hex")
+	}
+
+	name := args[0]
+	attachmentID := args[1]
+	err := model.ValidateName("name", &name, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	err = model.ValidateKeyPart("attachmentID", attachmentID, cfg.MaxNameLength)
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	err = model.ValidateSHA256Hex("sha256Hex", args[2])
+	if err != nil {
+		return invalidInput(name, err.Error())
+	}
+	claimedHash, _ := hex.DecodeString(args[2]) //checked by ValidateSHA256Hex
+
+	attachmentKey, err := stub.CreateCompositeKey(model.AttachmentIndex, []string{name, attachmentID})
+	if err != nil {
+		return errorResponse(err)
+	}
+	attachmentAsBytes, err := stub.GetPrivateData(cfg.CollectionArticlePrivateDetails, attachmentKey)
+	if err != nil {
+		return internalError(name, "Failed to get attachment: "+err.Error())
+	} else if attachmentAsBytes == nil {
+		return notFound(name, "Attachment "+attachmentID+" does not exist for article: "+name)
+	}
+
+	var attachment model.Attachment
+	err = json.Unmarshal(attachmentAsBytes, &attachment)
+	if err != nil {
+		return internalError(name, "Failed to decode JSON of: "+string(attachmentAsBytes))
+	}
+	storedHash, err := hex.DecodeString(attachment.SHA256Hex)
+	if err != nil {
+		return internalError(name, "Invalid hash of attachment "+attachmentID+": "+err.Error())
+	}
+
+	if bytes.Equal(storedHash, claimedHash) {
+		return shim.Success([]byte("{\"match\":true}"))
+	}
+	return shim.Success([]byte("{\"match\":false}"))
+}
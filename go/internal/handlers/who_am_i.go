@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package handlers
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+)
+
+// attributesOID is the X.509 extension in which Fabric CA stores the attributes of an identity
+var attributesOID = asn1.ObjectIdentifier{1, 2, 3, 4, 5, 6, 7, 8, 1}
+
+// identity is the result of whoAmI
+type identity struct {
+	MSPID      string            `json:"mspID"`
+	ID         string            `json:"id"`                   //enrollment ID as returned by cid.GetID
+	Subject    string            `json:"subject,omitempty"`    //empty for identities without an X.509 certificate
+	Issuer     string            `json:"issuer,omitempty"`     //empty for identities without an X.509 certificate
+	Attributes map[string]string `json:"attributes,omitempty"` //Fabric CA attributes of the certificate
+	PeerMSPID  string            `json:"peerMSPID"`            //local MSP of the endorsing peer
+}
+
+// ===============================================
+// WhoAmI - return the identity of the calling client as the chaincode sees it, to debug
+// access control. It neither reads nor writes state.
+// ===============================================
+func WhoAmI(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
+	if len(args) != 0 {
+		return invalidInput("", "Incorrect number of arguments. Expecting no arguments")
+	}
+
+	var caller identity
+	var err error
+	caller.MSPID, err = cid.GetMSPID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client MSP ID: "+err.Error())
+	}
+	caller.ID, err = cid.GetID(stub)
+	if err != nil {
+		return internalError("", "Failed to get client ID: "+err.Error())
+	}
+	caller.PeerMSPID, err = shim.GetMSPID()
+	if err != nil {
+		return internalError("", "Failed to get peer MSP ID: "+err.Error())
+	}
+
+	// idemix and other identities have no certificate, which is not an error here
+	cert, err := cid.GetX509Certificate(stub)
+	if err == nil && cert != nil {
+		caller.Subject = cert.Subject.String()
+		caller.Issuer = cert.Issuer.String()
+		caller.Attributes = certificateAttributes(cert)
+	}
+
+	identityJSONasBytes, err := json.Marshal(&caller)
+	if err != nil {
+		return errorResponse(err)
+	}
+	return shim.Success(identityJSONasBytes)
+}
+
+// certificateAttributes returns the Fabric CA attributes of the certificate, nil when it
+// has none or they cannot be decoded
+func certificateAttributes(cert *x509.Certificate) map[string]string {
+	for _, extension := range cert.Extensions {
+		if !extension.Id.Equal(attributesOID) {
+			continue
+		}
+		var attributes struct {
+			Attrs map[string]string `json:"attrs"`
+		}
+		if json.Unmarshal(extension.Value, &attributes) != nil {
+			return nil
+		}
+		return attributes.Attrs
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package logging is the leveled logger of the articles chaincode. Each line carries the
+// fields of the logger it was written with, such as the function and the transaction ID.
+// Private values, the prices and the transient inputs, must never be logged.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Level is the severity of a log line, lines below the level of the logger are dropped
+type Level int32
+
+// Levels in increasing severity
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug:   "DEBUG",
+	LevelInfo:    "INFO",
+	LevelWarning: "WARNING",
+	LevelError:   "ERROR",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel parses a level name such as "info", case-insensitively
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %s, expecting DEBUG, INFO, WARNING or ERROR", name)
+}
+
+// Logger writes leveled log lines prefixed with its fields. Loggers derived with With share
+// the level and the output of their parent.
+type Logger struct {
+	level  *int32
+	out    *log.Logger
+	fields string
+}
+
+var defaultLogger = New(log.New(os.Stderr, "", log.LstdFlags|log.Lmicroseconds|log.LUTC), LevelInfo)
+
+// Default returns the logger of the chaincode, which writes to stderr
+func Default() *Logger {
+	return defaultLogger
+}
+
+// New creates a logger writing to out lines at or above the level
+func New(out *log.Logger, level Level) *Logger {
+	l := int32(level)
+	return &Logger{level: &l, out: out}
+}
+
+// SetLevel changes the level of the logger and of the loggers derived from it
+func (l *Logger) SetLevel(level Level) {
+	atomic.StoreInt32(l.level, int32(level))
+}
+
+// SetOutput changes the writer of the logger and of the loggers derived from it
+func (l *Logger) SetOutput(w io.Writer) {
+	l.out.SetOutput(w)
+}
+
+// Enabled reports whether lines of the level are written
+func (l *Logger) Enabled(level Level) bool {
+	return int32(level) >= atomic.LoadInt32(l.level)
+}
+
+// WithLevel returns a logger with the fields and output of l but a level of its own
+func (l *Logger) WithLevel(level Level) *Logger {
+	lvl := int32(level)
+	return &Logger{level: &lvl, out: l.out, fields: l.fields}
+}
+
+// DebugDecoration is the transaction decoration that turns on debug logging for a single
+// invocation, whatever the level of the chaincode
+const DebugDecoration = "debug"
+
+// ForInvocation returns a logger of the default logger whose lines carry the function and
+// the transaction ID. It logs at the debug level when the decorations of the transaction,
+// set by the peer's decorators, include DebugDecoration.
+func ForInvocation(function string, txID string, decorations map[string][]byte) *Logger {
+	logger := defaultLogger.With("function", function).With("txId", txID)
+	if _, ok := decorations[DebugDecoration]; ok {
+		logger = logger.WithLevel(LevelDebug)
+	}
+	return logger
+}
+
+// With returns a logger that adds the key=value field to every line
+func (l *Logger) With(key string, value string) *Logger {
+	field := key + "=" + value
+	if len(l.fields) != 0 {
+		field = l.fields + " " + field
+	}
+	return &Logger{level: l.level, out: l.out, fields: field}
+}
+
+func (l *Logger) logf(level Level, format string, a ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	l.out.Printf("%-7s [%s] %s", level, l.fields, fmt.Sprintf(format, a...))
+}
+
+// Debugf logs at the debug level
+func (l *Logger) Debugf(format string, a ...interface{}) {
+	l.logf(LevelDebug, format, a...)
+}
+
+// Infof logs at the info level
+func (l *Logger) Infof(format string, a ...interface{}) {
+	l.logf(LevelInfo, format, a...)
+}
+
+// Warningf logs at the warning level
+func (l *Logger) Warningf(format string, a ...interface{}) {
+	l.logf(LevelWarning, format, a...)
+}
+
+// Errorf logs at the error level
+func (l *Logger) Errorf(format string, a ...interface{}) {
+	l.logf(LevelError, format, a...)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// MarshalCanonical returns the canonical JSON of v: object keys sorted, no insignificant
+// whitespace, numbers written as json.Marshal writes them and no HTML escaping. Clients in
+// other languages produce the same bytes by following these rules, so the hash of the
+// canonical JSON can be compared with GetPrivateDataHash.
+func MarshalCanonical(v interface{}) ([]byte, error) {
+	jsonAsBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// decoding into interface{} turns every object into a map, whose keys json sorts
+	decoder := json.NewDecoder(bytes.NewReader(jsonAsBytes))
+	decoder.UseNumber() //keep the numbers as written, without a float64 round trip
+	var value interface{}
+	err = decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	err = encoder.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// ComputeArticleHash returns the SHA-256 of the canonical JSON of the article, which is
+// the private data hash of the article as stored in collectionArticles
+func ComputeArticleHash(article *Article) ([]byte, error) {
+	articleJSONasBytes, err := MarshalCanonical(article)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(articleJSONasBytes)
+	return hash[:], nil
+}
+
+// ComputeExportHash returns the hex SHA-256 of the canonical JSON of an article package
+// without its HashHex, which importArticle compares with the HashHex it carries
+func ComputeExportHash(export *ArticleExport) (string, error) {
+	unsealed := *export
+	unsealed.HashHex = ""
+	exportJSONasBytes, err := MarshalCanonical(&unsealed)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(exportJSONasBytes)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// canonicalArticle is the canonical JSON of the sample article in TestMarshalCanonical:
+// keys sorted, no whitespace and no HTML escaping
+const canonicalArticle = `{"askingPriceVisible":false,"color":"blue","createdAt":"2024-01-01T00:00:00Z","deleted":false,"deletedAt":"","docType":"article","forSale":false,"lockExpiry":"","locked":false,"lockedBy":"","name":"Café <1>","owner":"tom","ownerOrg":"Org1MSP","quantity":2,"salt":"` + testSalt + `","schemaVersion":2,"size":35,"tags":["new","a&b"],"updatedAt":"2024-01-01T00:00:00Z"}`
+
+// canonicalArticleSHA256Hex is the SHA-256 of canonicalArticle
+const canonicalArticleSHA256Hex = "9464552fdd0b0a01e0b56ad3db6bfc7c4deb63c10a9460e3b048a0e61f6f61e1"
+
+func TestMarshalCanonical(t *testing.T) {
+	article := &Article{
+		ObjectType:    DefaultDocType,
+		Name:          "Café <1>",
+		Color:         "blue",
+		Size:          35,
+		Owner:         "tom",
+		OwnerOrg:      "Org1MSP",
+		Salt:          testSalt,
+		CreatedAt:     "2024-01-01T00:00:00Z",
+		UpdatedAt:     "2024-01-01T00:00:00Z",
+		Quantity:      2,
+		Tags:          []string{"new", "a&b"},
+		SchemaVersion: 2,
+	}
+	articleJSONasBytes, err := MarshalCanonical(article)
+	if err != nil {
+		t.Fatalf("failed to marshal the article: %v", err)
+	}
+	if string(articleJSONasBytes) != canonicalArticle {
+		t.Errorf("canonical JSON is\n%s\nexpected\n%s", articleJSONasBytes, canonicalArticle)
+	}
+
+	hash, err := ComputeArticleHash(article)
+	if err != nil {
+		t.Fatalf("failed to hash the article: %v", err)
+	}
+	expected := sha256.Sum256([]byte(canonicalArticle))
+	if hex.EncodeToString(hash) != hex.EncodeToString(expected[:]) || hex.EncodeToString(hash) != canonicalArticleSHA256Hex {
+		t.Errorf("article hash is %x, expected %s", hash, canonicalArticleSHA256Hex)
+	}
+
+	details := &ArticlePrivateDetails{ObjectType: "articlePrivateDetails", Name: "Café <1>", Price: 9900, Currency: "EUR", SchemaVersion: 2}
+	detailsJSONasBytes, err := MarshalCanonical(details)
+	if err != nil {
+		t.Fatalf("failed to marshal the private details: %v", err)
+	}
+	golden := `{"currency":"EUR","docType":"articlePrivateDetails","name":"Café <1>","price":9900,"schemaVersion":2}`
+	if string(detailsJSONasBytes) != golden {
+		t.Errorf("canonical JSON is\n%s\nexpected\n%s", detailsJSONasBytes, golden)
+	}
+}
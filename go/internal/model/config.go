@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxResults is the default maximum number of results of a range query
+const DefaultMaxResults = 1000
+
+// DefaultMaxTransientSize is the default maximum size in bytes of a transient map value
+const DefaultMaxTransientSize = 1024 * 1024
+
+// DefaultMaxBatchSize is the default maximum number of names of a batch read, see
+// readMultiplePrivateDetails and getArticleHashes
+const DefaultMaxBatchSize = 100
+
+// DefaultNonceWindowSeconds is the default time in seconds during which a request nonce
+// cannot be used again, see ReplayProtectionNonce
+const DefaultNonceWindowSeconds = 300
+
+// Config is the deployment specific configuration shared by all handlers
+type Config struct {
+	CollectionArticles              string   // collection holding the articles and their indexes
+	CollectionArticlePrivateDetails string   // collection holding the article prices
+	MaxNameLength                   int      // maximum length in bytes of article names and other key parts
+	DocTypes                        []string // doc types initArticle accepts besides the default "article"
+	MaxResults                      int      // default and upper limit of the results of a range query
+	MaxTransientSize                int      // maximum size in bytes of a transient map value
+	MaxBatchSize                    int      // maximum number of names of a batch read
+	ParticipantsChaincode           string   // participant directory chaincode checked on transfer, empty to disable
+	PriceFormat                     string   // PriceFormatNumber or PriceFormatString, how responses carry prices
+	NegotiationCollections          string   // NegotiationImplicit or NegotiationBilateral, where negotiated prices are kept
+	ReplayProtection                string   // ReplayProtectionOff, ReplayProtectionBinding or ReplayProtectionNonce
+	ChaincodeName                   string   // name proposals must address under replay protection, empty to accept any
+	NonceWindowSeconds              int      // time in seconds during which a request nonce cannot be used again
+	DevMode                         bool     // enables the functions for development networks, see loadSampleData
+}
+
+// Formats of the prices in responses. Prices are numbers by default, as they are stored;
+// clients that decode numbers as doubles can have them as strings instead.
+const (
+	PriceFormatNumber = "number"
+	PriceFormatString = "string"
+)
+
+// Collections negotiated prices are kept in. The implicit collection of the buyer org
+// always exists but only the buyer can read it, the seller only sees the hash. Bilateral
+// collections, named by BilateralCollectionPrefix and the MSP IDs of the two orgs, must
+// be defined in the collection config but both orgs can read them.
+const (
+	NegotiationImplicit  = "implicit"
+	NegotiationBilateral = "bilateral"
+)
+
+// Replay protection of the sensitive functions. With binding, the signed proposal must be
+// bound to the channel, transaction and chaincode it is executed for. With nonce, the
+// client must also pass a request nonce in the transient map, which is rejected when it
+// was already used within NonceWindowSeconds.
+const (
+	ReplayProtectionOff     = "off"
+	ReplayProtectionBinding = "binding"
+	ReplayProtectionNonce   = "nonce"
+)
+
+// DefaultConfig returns the configuration matching the collection config in the README
+func DefaultConfig() *Config {
+	return &Config{
+		CollectionArticles:              DefaultCollectionArticles,
+		CollectionArticlePrivateDetails: DefaultCollectionArticlePrivateDetails,
+		MaxNameLength:                   DefaultMaxNameLength,
+		DocTypes:                        []string{DefaultDocType},
+		MaxResults:                      DefaultMaxResults,
+		MaxTransientSize:                DefaultMaxTransientSize,
+		MaxBatchSize:                    DefaultMaxBatchSize,
+		PriceFormat:                     PriceFormatNumber,
+		NegotiationCollections:          NegotiationImplicit,
+		ReplayProtection:                ReplayProtectionOff,
+		NonceWindowSeconds:              DefaultNonceWindowSeconds,
+	}
+}
+
+// AllowsDocType reports whether objects of the doc type may be stored
+func (c *Config) AllowsDocType(docType string) bool {
+	if docType == DefaultDocType {
+		return true
+	}
+	for _, allowed := range c.DocTypes {
+		if docType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the configuration can be used by the handlers
+func (c *Config) Validate() error {
+	if len(c.CollectionArticles) == 0 {
+		return fmt.Errorf("articles collection name must be a non-empty string")
+	}
+	if len(c.CollectionArticlePrivateDetails) == 0 {
+		return fmt.Errorf("article private details collection name must be a non-empty string")
+	}
+	if c.CollectionArticles == c.CollectionArticlePrivateDetails {
+		return fmt.Errorf("articles and article private details collections must differ, both are %s", c.CollectionArticles)
+	}
+	if c.MaxNameLength <= 0 {
+		return fmt.Errorf("maximum name length must be a positive integer, got %d", c.MaxNameLength)
+	}
+	if c.MaxResults <= 0 {
+		return fmt.Errorf("maximum number of results must be a positive integer, got %d", c.MaxResults)
+	}
+	if c.MaxTransientSize <= 0 {
+		return fmt.Errorf("maximum transient value size must be a positive integer, got %d", c.MaxTransientSize)
+	}
+	if c.MaxBatchSize <= 0 {
+		return fmt.Errorf("maximum batch size must be a positive integer, got %d", c.MaxBatchSize)
+	}
+	if c.PriceFormat != PriceFormatNumber && c.PriceFormat != PriceFormatString {
+		return fmt.Errorf("price format must be %s or %s, got %q", PriceFormatNumber, PriceFormatString, c.PriceFormat)
+	}
+	if c.NegotiationCollections != NegotiationImplicit && c.NegotiationCollections != NegotiationBilateral {
+		return fmt.Errorf("negotiation collections must be %s or %s, got %q", NegotiationImplicit, NegotiationBilateral, c.NegotiationCollections)
+	}
+	if c.ReplayProtection != ReplayProtectionOff && c.ReplayProtection != ReplayProtectionBinding && c.ReplayProtection != ReplayProtectionNonce {
+		return fmt.Errorf("replay protection must be %s, %s or %s, got %q", ReplayProtectionOff, ReplayProtectionBinding, ReplayProtectionNonce, c.ReplayProtection)
+	}
+	if c.NonceWindowSeconds <= 0 {
+		return fmt.Errorf("nonce window must be a positive number of seconds, got %d", c.NonceWindowSeconds)
+	}
+	for _, docType := range c.DocTypes {
+		// doc types become the object type of composite keys, they must not clash with the indexes
+		err := ValidateKeyPart("docType", docType, c.MaxNameLength)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(docType, "~") {
+			return fmt.Errorf("docType %s must not contain ~", docType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1307 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MinSaltLength is the minimum number of random bytes in an article salt
+const MinSaltLength = 16
+
+// DefaultMaxNameLength is the default maximum length in bytes of an article name
+const DefaultMaxNameLength = 256
+
+// DecodeTransientInput strictly decodes the JSON value of a transient map entry into v.
+// Unknown fields, values of the wrong type, numbers that are not integers or overflow,
+// and any data after the JSON object are rejected with an error naming the problem.
+func DecodeTransientInput(inputName string, data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	dec.UseNumber()
+
+	err := dec.Decode(v)
+	if err != nil {
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+			got := strings.TrimPrefix(typeErr.Value, "number ")
+			switch typeErr.Type.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return fmt.Errorf("%s must be an integer, got %s", typeErr.Field, got)
+			default:
+				return fmt.Errorf("%s must be of type %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+			}
+		}
+		if priceErr, ok := err.(*PriceError); ok {
+			return priceErr
+		}
+		if strings.HasPrefix(err.Error(), "json: unknown field ") {
+			return fmt.Errorf("unknown field %s in %s input", strings.TrimPrefix(err.Error(), "json: unknown field "), inputName)
+		}
+		return fmt.Errorf("Failed to decode JSON of: %s", string(data))
+	}
+
+	if dec.Decode(&struct{}{}) != io.EOF {
+		return fmt.Errorf("unexpected data after the JSON object in %s input", inputName)
+	}
+
+	return nil
+}
+
+// ArticleTransientInput is the "article" transient input of initArticle
+type ArticleTransientInput struct {
+	Name     string   `json:"name"` //the fieldtags are needed to keep case from bouncing around
+	Color    string   `json:"color"`
+	Size     int      `json:"size"`
+	Owner    string   `json:"owner"`
+	Price    Price    `json:"price"`    //in minor units of the currency
+	Currency string   `json:"currency"` //ISO-4217 code, required with a price
+	Salt     string   `json:"salt"`
+	Quantity int      `json:"quantity"` //defaults to 1
+	DocType  string   `json:"docType"`  //defaults to "article"
+	Tags     []string `json:"tags"`     //optional
+	Category string   `json:"category"` //optional
+	SKU      string   `json:"sku"`      //optional, unique across the collection
+	Upsert   bool     `json:"upsert"`   //optional, update an existing article instead of failing
+
+	Weight     *WeightInput     `json:"weight"`     //optional, converted to grams
+	Dimensions *DimensionsInput `json:"dimensions"` //optional, converted to millimetres
+
+	StrictDuplicates bool `json:"strictDuplicates"` //optional, fail when articles share the fingerprint
+	PublicSummary    bool `json:"publicSummary"`    //optional, on creation tell the channel the article exists, see PublicSummary
+}
+
+// Validate checks the fields of a new article
+func (in *ArticleTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateKeyPart("color", in.Color, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.Size <= 0 {
+		return fmt.Errorf("size field must be a positive integer")
+	}
+	if in.Size > MaxSize {
+		return fmt.Errorf("size field must be at most %d", MaxSize)
+	}
+	err = ValidateName("owner", &in.Owner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	// the price is optional, without it no private details are written
+	if in.Price != 0 {
+		err = ValidatePrice(in.Price)
+		if err != nil {
+			return err
+		}
+		err = ValidateCurrency("currency", &in.Currency)
+		if err != nil {
+			return err
+		}
+	} else if len(in.Currency) != 0 {
+		return fmt.Errorf("currency field requires a price")
+	}
+	// a missing quantity is a single unit, as for articles stored before quantities
+	if in.Quantity == 0 {
+		in.Quantity = 1
+	} else if in.Quantity < 0 {
+		return fmt.Errorf("quantity field must be a positive integer")
+	}
+	err = ValidateTags(in.Tags, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if len(in.Category) != 0 {
+		err = ValidateCategory("category", in.Category, maxNameLength)
+		if err != nil {
+			return err
+		}
+	}
+	if len(in.SKU) != 0 {
+		err = ValidateSKU("sku", &in.SKU)
+		if err != nil {
+			return err
+		}
+	}
+	_, _, err = in.Measurements()
+	if err != nil {
+		return err
+	}
+	return ValidateSalt(in.Salt)
+}
+
+// Measurements returns the weight in grams and the dimensions in millimetres of the input,
+// 0 and nil for those it leaves out
+func (in *ArticleTransientInput) Measurements() (int, *Dimensions, error) {
+	weightGrams := 0
+	var err error
+	if in.Weight != nil {
+		weightGrams, err = in.Weight.Grams()
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	var dimensions *Dimensions
+	if in.Dimensions != nil {
+		dimensions, err = in.Dimensions.Millimetres()
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	return weightGrams, dimensions, nil
+}
+
+// ArticleSplitTransientInput is the "article_split" transient input of splitArticle
+type ArticleSplitTransientInput struct {
+	Name     string `json:"name"`
+	NewName  string `json:"newName"`
+	Quantity int    `json:"quantity"` //units moved to the new article
+	Salt     string `json:"salt"`     //salt of the new article
+}
+
+// Validate checks the fields of a split
+func (in *ArticleSplitTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("newName", &in.NewName, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.Quantity <= 0 {
+		return fmt.Errorf("quantity field must be a positive integer")
+	}
+	return ValidateSalt(in.Salt)
+}
+
+// ArticleCloneTransientInput is the "article_clone" transient input of cloneArticle
+type ArticleCloneTransientInput struct {
+	SourceName string           `json:"sourceName"`
+	NewName    string           `json:"newName"`
+	Salt       string           `json:"salt"` //salt of the new article
+	Overrides  ArticleOverrides `json:"overrides"`
+}
+
+// ArticleOverrides are the properties of a cloned article that differ from its source,
+// nil for the ones taken from the source
+type ArticleOverrides struct {
+	Color    *string `json:"color"`
+	Size     *int    `json:"size"`
+	Owner    *string `json:"owner"`
+	Price    *Price  `json:"price"`
+	Currency *string `json:"currency"` //defaults to the currency of the source price
+}
+
+// Validate checks the names of a clone. The cloned article is checked with the rules of
+// initArticle once the overrides are applied to the source.
+func (in *ArticleCloneTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("sourceName", &in.SourceName, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("newName", &in.NewName, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.SourceName == in.NewName {
+		return fmt.Errorf("sourceName and newName fields must name different articles")
+	}
+	return nil
+}
+
+// ArticleRenameTransientInput is the "article_rename" transient input of renameArticle
+type ArticleRenameTransientInput struct {
+	OldName      string `json:"oldName"`
+	NewName      string `json:"newName"`
+	GraceSeconds int    `json:"graceSeconds"` //lifetime of the redirect, defaults to DefaultRenameGraceSeconds
+}
+
+// Validate checks the fields of a rename
+func (in *ArticleRenameTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("oldName", &in.OldName, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("newName", &in.NewName, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.OldName == in.NewName {
+		return fmt.Errorf("oldName and newName fields must differ")
+	}
+	if in.GraceSeconds < 0 || in.GraceSeconds > MaxRenameGraceSeconds {
+		return fmt.Errorf("graceSeconds field must be between 0 and %d", MaxRenameGraceSeconds)
+	}
+	return nil
+}
+
+// ArticleRetireTransientInput is the "article_retire" transient input of retireArticle
+type ArticleRetireTransientInput struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Validate checks the fields of a retirement
+func (in *ArticleRetireTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateReason("reason", in.Reason)
+}
+
+// ArticleMergeTransientInput is the "article_merge" transient input of mergeArticles
+type ArticleMergeTransientInput struct {
+	Name       string `json:"name"`       //article that receives the units
+	MergedName string `json:"mergedName"` //article that is deleted
+}
+
+// Validate checks the fields of a merge
+func (in *ArticleMergeTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("mergedName", &in.MergedName, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.Name == in.MergedName {
+		return fmt.Errorf("name and mergedName fields must name different articles")
+	}
+	return nil
+}
+
+// ArticleTransferTransientInput is the "article_owner" transient input of transferArticle
+type ArticleTransferTransientInput struct {
+	Name     string `json:"name"`
+	Owner    string `json:"owner"`
+	OwnerOrg string `json:"ownerOrg"` //MSP ID of the buyer org, defaults to the submitting org
+	Strict   bool   `json:"strict"`   //optional, fail instead of succeeding when nothing changes
+
+	ExpectedCurrentOwner string `json:"expectedCurrentOwner"` //optional, the transfer fails unless it is the owner
+	TerminateLease       bool   `json:"terminateLease"`       //optional, end a running lease with the transfer
+}
+
+// Validate checks the fields of a transfer
+func (in *ArticleTransferTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if len(in.ExpectedCurrentOwner) != 0 {
+		err = ValidateName("expectedCurrentOwner", &in.ExpectedCurrentOwner, maxNameLength)
+		if err != nil {
+			return err
+		}
+	}
+	return ValidateName("owner", &in.Owner, maxNameLength)
+}
+
+// TransferProposalTransientInput is the "transfer_proposal" transient input of proposeTransfer
+type TransferProposalTransientInput struct {
+	Name       string `json:"name"`
+	NewOwner   string `json:"newOwner"`
+	OwnerOrg   string `json:"ownerOrg"`   //MSP ID of the org that has to accept the transfer
+	TTLSeconds int    `json:"ttlSeconds"` //time to accept, defaults to DefaultTransferTTLSeconds
+}
+
+// Validate checks the fields of a transfer proposal
+func (in *TransferProposalTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("newOwner", &in.NewOwner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateKeyPart("ownerOrg", in.OwnerOrg, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.TTLSeconds < 0 || in.TTLSeconds > MaxTransferTTLSeconds {
+		return fmt.Errorf("ttlSeconds field must be between 0 and %d", MaxTransferTTLSeconds)
+	}
+	return nil
+}
+
+// TransferResponseTransientInput is the "transfer_response" transient input of
+// acceptTransfer, rejectTransfer and cancelTransfer
+type TransferResponseTransientInput struct {
+	Name string `json:"name"`
+}
+
+// Validate checks the fields of a response to a transfer proposal
+func (in *TransferResponseTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// TransferInitiationTransientInput is the "transfer_initiation" transient input of initiateTransfer
+type TransferInitiationTransientInput struct {
+	Name      string   `json:"name"`
+	NewOwner  string   `json:"newOwner"`
+	OwnerOrg  string   `json:"ownerOrg"`  //MSP ID of the org of the new owner
+	Approvers []string `json:"approvers"` //MSP IDs of the orgs that have to approve the transfer
+}
+
+// Validate checks the fields of an initiated transfer
+func (in *TransferInitiationTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("newOwner", &in.NewOwner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateKeyPart("ownerOrg", in.OwnerOrg, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if len(in.Approvers) == 0 || len(in.Approvers) > MaxApprovers {
+		return fmt.Errorf("approvers field must list between 1 and %d MSP IDs", MaxApprovers)
+	}
+	seen := map[string]bool{}
+	for _, mspID := range in.Approvers {
+		err = ValidateKeyPart("approvers", mspID, maxNameLength)
+		if err != nil {
+			return err
+		}
+		if seen[mspID] {
+			return fmt.Errorf("approvers field lists %s twice", mspID)
+		}
+		seen[mspID] = true
+	}
+	return nil
+}
+
+// TransferApprovalTransientInput is the "transfer_approval" transient input of
+// approveTransfer, executeTransfer and cancelInitiatedTransfer
+type TransferApprovalTransientInput struct {
+	Name string `json:"name"`
+}
+
+// Validate checks the fields of an approval
+func (in *TransferApprovalTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// CertificationTransientInput is the "certification" transient input of certifyArticle
+type CertificationTransientInput struct {
+	Name     string `json:"name"`
+	Standard string `json:"standard"`
+	Result   string `json:"result"`
+}
+
+// Validate checks the fields of a certification
+func (in *CertificationTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateKeyPart("standard", in.Standard, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateKeyPart("result", in.Result, maxNameLength)
+}
+
+// DisputeTransientInput is the "dispute" transient input of raiseDispute
+type DisputeTransientInput struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Validate checks the fields of a dispute
+func (in *DisputeTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateReason("reason", in.Reason)
+}
+
+// DisputeResolutionTransientInput is the "dispute_resolution" transient input of resolveDispute
+type DisputeResolutionTransientInput struct {
+	Name    string `json:"name"`
+	Outcome string `json:"outcome"`
+}
+
+// Validate checks the fields of a dispute resolution
+func (in *DisputeResolutionTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateReason("outcome", in.Outcome)
+}
+
+// CertificationRevocationTransientInput is the "certification_revocation" transient input
+// of revokeCertification
+type CertificationRevocationTransientInput struct {
+	Name string `json:"name"`
+}
+
+// Validate checks the fields of a revocation
+func (in *CertificationRevocationTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// ArticleAttachmentTransientInput is the "article_attachment" transient input of addArticleAttachment
+type ArticleAttachmentTransientInput struct {
+	Name         string `json:"name"`
+	AttachmentID string `json:"attachmentID"`
+	SHA256Hex    string `json:"sha256Hex"`
+	Description  string `json:"description"` //optional
+}
+
+// Validate checks the fields of an attachment
+func (in *ArticleAttachmentTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateKeyPart("attachmentID", in.AttachmentID, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateSHA256Hex("sha256Hex", in.SHA256Hex)
+	if err != nil {
+		return err
+	}
+	if len(in.Description) > MaxDescriptionLength {
+		return fmt.Errorf("description field must be at most %d bytes long, got %d", MaxDescriptionLength, len(in.Description))
+	}
+	if !utf8.ValidString(in.Description) {
+		return fmt.Errorf("description field must be a valid UTF-8 string")
+	}
+	return nil
+}
+
+// ArticleTagTransientInput is the "article_tag" transient input of addArticleTag and removeArticleTag
+type ArticleTagTransientInput struct {
+	Name string `json:"name"`
+	Tag  string `json:"tag"`
+}
+
+// Validate checks the fields of a tag change
+func (in *ArticleTagTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateTag(in.Tag, maxNameLength)
+}
+
+// ArticleTransferWithPriceTransientInput is the "article_transfer" transient input of
+// transferArticleWithPrice
+type ArticleTransferWithPriceTransientInput struct {
+	Name     string `json:"name"`
+	NewOwner string `json:"newOwner"`
+	NewPrice Price  `json:"newPrice"` //optional when the buyer org negotiated a price
+	OwnerOrg string `json:"ownerOrg"` //MSP ID of the buyer org, defaults to the submitting org
+}
+
+// Validate checks the fields of a transfer with a new price
+func (in *ArticleTransferWithPriceTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("newOwner", &in.NewOwner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	// without a new price the one negotiated by the buyer org is used
+	if in.NewPrice != 0 {
+		return ValidatePrice(in.NewPrice)
+	}
+	return nil
+}
+
+// NegotiatedPriceTransientInput is the "negotiated_price" transient input of setNegotiatedPrice
+type NegotiatedPriceTransientInput struct {
+	Name      string `json:"name"`
+	BuyerMSP  string `json:"buyerMSP"`
+	SellerMSP string `json:"sellerMSP"` //MSP ID of the owner org of the article
+	Price     Price  `json:"price"`     //in minor units of the currency
+	Currency  string `json:"currency"`  //ISO-4217 code
+}
+
+// Validate checks the fields of a negotiated price
+func (in *NegotiatedPriceTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateKeyPart("buyerMSP", in.BuyerMSP, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateKeyPart("sellerMSP", in.SellerMSP, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.BuyerMSP == in.SellerMSP {
+		return fmt.Errorf("buyerMSP and sellerMSP fields must name different orgs")
+	}
+	err = ValidatePrice(in.Price)
+	if err != nil {
+		return err
+	}
+	return ValidateCurrency("currency", &in.Currency)
+}
+
+// ArticleSwapTransientInput is the "article_swap" transient input of swapArticles. Each
+// owner is the owner the caller expects the article to have before the swap.
+type ArticleSwapTransientInput struct {
+	Name1  string `json:"name1"`
+	Owner1 string `json:"owner1"`
+	Name2  string `json:"name2"`
+	Owner2 string `json:"owner2"`
+}
+
+// Validate checks the fields of a swap
+func (in *ArticleSwapTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name1", &in.Name1, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("owner1", &in.Owner1, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("name2", &in.Name2, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("owner2", &in.Owner2, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.Name1 == in.Name2 {
+		return fmt.Errorf("name1 and name2 fields must name different articles")
+	}
+	return nil
+}
+
+// ArticleAgreementTransientInput is the "article_agreement" transient input of agreeToTransfer
+type ArticleAgreementTransientInput struct {
+	Name     string `json:"name"`
+	Price    Price  `json:"price"`    //optional, left out for an article without private details
+	Currency string `json:"currency"` //must match the currency of the private details, empty for older records
+}
+
+// Validate checks the fields of a transfer agreement. A missing price agrees to an
+// article without private details and then cannot name a currency.
+func (in *ArticleAgreementTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.Price == 0 {
+		if len(in.Currency) != 0 {
+			return fmt.Errorf("currency field must be empty when no price is agreed")
+		}
+		return nil
+	}
+	err = ValidatePrice(in.Price)
+	if err != nil {
+		return err
+	}
+	if len(in.Currency) != 0 {
+		return ValidateCurrency("currency", &in.Currency)
+	}
+	return nil
+}
+
+// ArticlePriceTransientInput is the "article_price" transient input of updateArticlePrice
+// and addArticlePrivateDetails
+type ArticlePriceTransientInput struct {
+	Name     string `json:"name"`
+	Price    Price  `json:"price"`    //in minor units of the currency
+	Currency string `json:"currency"` //ISO-4217 code
+	Strict   bool   `json:"strict"`   //optional, fail instead of succeeding when nothing changes
+}
+
+// Validate checks the fields of a price update
+func (in *ArticlePriceTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidatePrice(in.Price)
+	if err != nil {
+		return err
+	}
+	return ValidateCurrency("currency", &in.Currency)
+}
+
+// Validate checks the "article_import" transient input of importArticle, an article
+// package as produced by exportArticle. The salt of the package must be the one of the
+// article, and the private details, if any, must belong to the article.
+func (in *ArticleExport) Validate(maxNameLength int) error {
+	if in.Article == nil {
+		return fmt.Errorf("article field is required")
+	}
+	err := in.Article.ValidateClaim(maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("owner", &in.Article.Owner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.Salt != in.Article.Salt {
+		return fmt.Errorf("salt field must be the salt of the article")
+	}
+	if len(in.ExportTxID) == 0 {
+		return fmt.Errorf("exportTxID field must be a non-empty string")
+	}
+	if in.PrivateDetails != nil {
+		if in.PrivateDetails.Name != in.Article.Name {
+			return fmt.Errorf("privateDetails.name field must be the name of the article, got %s", in.PrivateDetails.Name)
+		}
+		err = ValidatePrice(in.PrivateDetails.Price)
+		if err != nil {
+			return err
+		}
+		if len(in.PrivateDetails.Currency) != 0 {
+			return ValidateCurrency("privateDetails.currency", &in.PrivateDetails.Currency)
+		}
+	}
+	return nil
+}
+
+// ArticleSaleTransientInput is the "article_sale" transient input of setArticleForSale
+type ArticleSaleTransientInput struct {
+	Name               string `json:"name"`
+	ForSale            bool   `json:"forSale"`
+	AskingPriceVisible bool   `json:"askingPriceVisible"`
+}
+
+// Validate checks the fields of a sale listing
+func (in *ArticleSaleTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if !in.ForSale && in.AskingPriceVisible {
+		return fmt.Errorf("askingPriceVisible field requires forSale")
+	}
+	return nil
+}
+
+// ArticleLockTransientInput is the "article_lock" transient input of lockArticle and unlockArticle
+type ArticleLockTransientInput struct {
+	Name       string `json:"name"`
+	TTLSeconds int    `json:"ttlSeconds"` //lifetime of the lock, ignored by unlockArticle
+}
+
+// Validate checks the fields of a lock
+func (in *ArticleLockTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.TTLSeconds < 0 || in.TTLSeconds > MaxLockTTLSeconds {
+		return fmt.Errorf("ttlSeconds field must be between 0 and %d", MaxLockTTLSeconds)
+	}
+	return nil
+}
+
+// ShareTransferTransientInput is the "share_transfer" transient input of transferShare
+type ShareTransferTransientInput struct {
+	Name      string `json:"name"`
+	FromOwner string `json:"fromOwner"`
+	ToOwner   string `json:"toOwner"`
+	Percent   int    `json:"percent"`
+}
+
+// Validate checks the fields of a share transfer
+func (in *ShareTransferTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("fromOwner", &in.FromOwner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("toOwner", &in.ToOwner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	if in.FromOwner == in.ToOwner {
+		return fmt.Errorf("toOwner field must differ from fromOwner")
+	}
+	if in.Percent < 1 || in.Percent > 100 {
+		return fmt.Errorf("percent field must be between 1 and 100")
+	}
+	return nil
+}
+
+// ValidateShares checks the shares of the co-owners of an article: every owner is listed
+// once with a positive share and the shares add up to 100 percent
+func ValidateShares(shares []OwnerShare) error {
+	seen := make(map[string]bool)
+	total := 0
+	for _, share := range shares {
+		if seen[share.Owner] {
+			return fmt.Errorf("owner %s holds more than one share", share.Owner)
+		}
+		seen[share.Owner] = true
+		if share.Percent <= 0 {
+			return fmt.Errorf("share of owner %s must be positive, got %d", share.Owner, share.Percent)
+		}
+		total += share.Percent
+	}
+	if total != 100 {
+		return fmt.Errorf("shares must add up to 100 percent, got %d", total)
+	}
+	return nil
+}
+
+// ArticleLeaseTransientInput is the "article_lease" transient input of leaseArticle
+type ArticleLeaseTransientInput struct {
+	Name    string `json:"name"`
+	Lessee  string `json:"lessee"`
+	EndDate string `json:"endDate"` //RFC3339
+}
+
+// Validate checks the fields of a lease
+func (in *ArticleLeaseTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateName("lessee", &in.Lessee, maxNameLength)
+	if err != nil {
+		return err
+	}
+	_, err = time.Parse(time.RFC3339, in.EndDate)
+	if err != nil {
+		return fmt.Errorf("endDate field must be an RFC3339 timestamp: %v", err)
+	}
+	return nil
+}
+
+// LeaseEndTransientInput is the "lease_end" transient input of endLease
+type LeaseEndTransientInput struct {
+	Name string `json:"name"`
+}
+
+// Validate checks the fields of the end of a lease
+func (in *LeaseEndTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// PolicyResetTransientInput is the "policy_reset" transient input of
+// resetArticleEndorsementPolicy
+type PolicyResetTransientInput struct {
+	Name string `json:"name"`
+}
+
+// Validate checks the fields of an endorsement policy reset
+func (in *PolicyResetTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// ArticleDeleteTransientInput is the "article_delete" transient input of delete
+type ArticleDeleteTransientInput struct {
+	Name        string `json:"name"`
+	KeepHistory bool   `json:"keepHistory"` //retain the ownership, lease and price history of the deleted article
+	DocType     string `json:"docType"`     //defaults to "article"
+	Soft        bool   `json:"soft"`        //keep the article as a tombstone instead of removing it
+	Force       bool   `json:"force"`       //delete a retired article, admins only
+}
+
+// Validate checks the fields of a deletion
+func (in *ArticleDeleteTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// ArticlePurgeTransientInput is the "article_purge" transient input of purgeArticlePrivateDetails
+type ArticlePurgeTransientInput struct {
+	Name           string `json:"name"`
+	IncludeArticle bool   `json:"includeArticle"` //also purge the article and its indexes from collectionArticles
+}
+
+// Validate checks the fields of a purge
+func (in *ArticlePurgeTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// ArticlePrivateDetailsDeleteTransientInput is the "article_private_details_delete"
+// transient input of deleteArticlePrivateDetailsOnly
+type ArticlePrivateDetailsDeleteTransientInput struct {
+	Name string `json:"name"`
+}
+
+// Validate checks the fields of a deletion of private details
+func (in *ArticlePrivateDetailsDeleteTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// OwnerRegistrationTransientInput is the "owner_registration" transient input of registerOwner
+type OwnerRegistrationTransientInput struct {
+	Name  string `json:"name"`
+	MSPID string `json:"mspID"`
+}
+
+// Validate checks the fields of an owner registration
+func (in *OwnerRegistrationTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateKeyPart("mspID", in.MSPID, maxNameLength)
+}
+
+// OwnerDeactivationTransientInput is the "owner_deactivation" transient input of deactivateOwner
+type OwnerDeactivationTransientInput struct {
+	Name string `json:"name"`
+}
+
+// Validate checks the fields of an owner deactivation
+func (in *OwnerDeactivationTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// Validate checks the function names and MSP IDs of an access-control map
+func (acl FunctionACL) Validate(maxNameLength int) error {
+	for function, mspIDs := range acl {
+		err := ValidateKeyPart("function", function, maxNameLength)
+		if err != nil {
+			return err
+		}
+		for _, mspID := range mspIDs {
+			err = ValidateKeyPart("mspID", mspID, maxNameLength)
+			if err != nil {
+				return fmt.Errorf("%s: %v", function, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MaxClientIDLength is the maximum length in bytes of an enrollment ID. cid.GetID encodes
+// the subject and issuer of the certificate, which are much longer than article names.
+const MaxClientIDLength = 4096
+
+// PriceAccessTransientInput is the "price_access" transient input of grantPriceAccess
+type PriceAccessTransientInput struct {
+	Name     string `json:"name"`
+	ClientID string `json:"clientID"` //enrollment ID as returned by cid.GetID, e.g. by whoAmI
+}
+
+// Validate checks the fields of a grant of price access
+func (in *PriceAccessTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateKeyPart("clientID", in.ClientID, MaxClientIDLength)
+}
+
+// DetailsAccessTransientInput is the "details_access" transient input of grantDetailsAccess
+type DetailsAccessTransientInput struct {
+	Name       string `json:"name"`
+	GranteeMSP string `json:"granteeMSP"`
+	ExpiresAt  string `json:"expiresAt"` //RFC3339
+}
+
+// Validate checks the fields of a grant of access to private details
+func (in *DetailsAccessTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateKeyPart("granteeMSP", in.GranteeMSP, maxNameLength)
+	if err != nil {
+		return err
+	}
+	_, err = time.Parse(time.RFC3339, in.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("expiresAt field must be an RFC3339 timestamp: %v", err)
+	}
+	return nil
+}
+
+// DetailsAccessRevokeTransientInput is the "details_access_revoke" transient input of
+// revokeDetailsAccess
+type DetailsAccessRevokeTransientInput struct {
+	Name       string `json:"name"`
+	GranteeMSP string `json:"granteeMSP"`
+}
+
+// Validate checks the fields of a revocation of access to private details
+func (in *DetailsAccessRevokeTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateKeyPart("granteeMSP", in.GranteeMSP, maxNameLength)
+}
+
+// AuctionTransientInput is the "auction" transient input of openAuction
+type AuctionTransientInput struct {
+	Name           string `json:"name"`
+	MinPrice       Price  `json:"minPrice"`
+	CloseTime      string `json:"closeTime"`      //RFC3339
+	RevealDeadline string `json:"revealDeadline"` //RFC3339, defaults to DefaultRevealPeriodSeconds after closeTime
+}
+
+// Validate checks the fields of an auction
+func (in *AuctionTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidatePrice(in.MinPrice)
+	if err != nil {
+		return fmt.Errorf("minPrice: %v", err)
+	}
+	closeTime, err := time.Parse(time.RFC3339, in.CloseTime)
+	if err != nil {
+		return fmt.Errorf("closeTime field must be an RFC3339 timestamp: %v", err)
+	}
+	if len(in.RevealDeadline) != 0 {
+		revealDeadline, err := time.Parse(time.RFC3339, in.RevealDeadline)
+		if err != nil {
+			return fmt.Errorf("revealDeadline field must be an RFC3339 timestamp: %v", err)
+		}
+		if !revealDeadline.After(closeTime) {
+			return fmt.Errorf("revealDeadline field must be after closeTime")
+		}
+	}
+	return nil
+}
+
+// BidTransientInput is the "bid" transient input of placeBid
+type BidTransientInput struct {
+	Name   string `json:"name"`
+	Amount Price  `json:"amount"`
+	Owner  string `json:"owner"`
+	Salt   string `json:"salt"`
+}
+
+// Validate checks the fields of a bid
+func (in *BidTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidatePrice(in.Amount)
+	if err != nil {
+		return fmt.Errorf("amount: %v", err)
+	}
+	err = ValidateName("owner", &in.Owner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateSalt(in.Salt)
+}
+
+// OfferTransientInput is the "offer" transient input of makeOffer
+type OfferTransientInput struct {
+	Name      string `json:"name"`
+	Price     Price  `json:"price"`
+	Owner     string `json:"owner"`
+	ExpiresAt string `json:"expiresAt"` //RFC3339
+	Salt      string `json:"salt"`
+}
+
+// Validate checks the fields of a purchase offer
+func (in *OfferTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidatePrice(in.Price)
+	if err != nil {
+		return fmt.Errorf("price: %v", err)
+	}
+	err = ValidateName("owner", &in.Owner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	_, err = time.Parse(time.RFC3339, in.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("expiresAt field must be an RFC3339 timestamp: %v", err)
+	}
+	return ValidateSalt(in.Salt)
+}
+
+// OfferAcceptTransientInput is the "offer_accept" transient input of acceptOffer: the
+// offer as the buyer org made it, handed over by the buyer, and the MSP ID of the buyer org
+type OfferAcceptTransientInput struct {
+	Name      string `json:"name"`
+	BuyerOrg  string `json:"buyerOrg"`
+	Price     Price  `json:"price"`
+	Owner     string `json:"owner"`
+	ExpiresAt string `json:"expiresAt"`
+	Salt      string `json:"salt"`
+}
+
+// Validate checks the fields of an offer to accept
+func (in *OfferAcceptTransientInput) Validate(maxNameLength int) error {
+	err := ValidateName("name", &in.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidateKeyPart("buyerOrg", in.BuyerOrg, maxNameLength)
+	if err != nil {
+		return err
+	}
+	err = ValidatePrice(in.Price)
+	if err != nil {
+		return fmt.Errorf("price: %v", err)
+	}
+	err = ValidateName("owner", &in.Owner, maxNameLength)
+	if err != nil {
+		return err
+	}
+	_, err = time.Parse(time.RFC3339, in.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("expiresAt field must be an RFC3339 timestamp: %v", err)
+	}
+	return ValidateSalt(in.Salt)
+}
+
+// AuctionNameTransientInput is the transient input of revealBid, "bid_reveal", and of
+// closeAuction, "auction_close"
+type AuctionNameTransientInput struct {
+	Name string `json:"name"`
+}
+
+// Validate checks the fields of an auction name input
+func (in *AuctionNameTransientInput) Validate(maxNameLength int) error {
+	return ValidateName("name", &in.Name, maxNameLength)
+}
+
+// ValidateClaim checks the fields of article properties claimed off-channel
+func (a *Article) ValidateClaim(maxNameLength int) error {
+	err := ValidateName("name", &a.Name, maxNameLength)
+	if err != nil {
+		return err
+	}
+	return ValidateSalt(a.Salt)
+}
+
+// ValidatePrice checks that a price is positive and fits the price~name index
+func ValidatePrice(price Price) error {
+	if price <= 0 {
+		return fmt.Errorf("price field must be a positive integer")
+	}
+	if price > MaxPrice {
+		return fmt.Errorf("price field must be at most %d", MaxPrice)
+	}
+	return nil
+}
+
+// MaxNameCharacters bounds the number of characters of article and owner names
+const MaxNameCharacters = 128
+
+// NormalizeName returns the form article and owner names are stored and looked up in:
+// without surrounding whitespace and in Unicode normalization form C, so "Café" matches
+// whether the client composed the é or not
+func NormalizeName(value string) string {
+	return norm.NFC.String(strings.TrimSpace(value))
+}
+
+// ValidateName normalizes an article or owner name in place with NormalizeName and checks
+// it: 1 to MaxNameCharacters characters, no control characters, and a valid key part.
+func ValidateName(field string, value *string, maxLength int) error {
+	*value = NormalizeName(*value)
+	err := ValidateKeyPart(field, *value, maxLength)
+	if err != nil {
+		return err
+	}
+	if n := utf8.RuneCountInString(*value); n > MaxNameCharacters {
+		return fmt.Errorf("%s field must be at most %d characters long, got %d", field, MaxNameCharacters, n)
+	}
+	for i, r := range *value {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%s field must not contain the control character %U, found at byte %d", field, r, i)
+		}
+	}
+	return nil
+}
+
+// ValidateKeyPart checks a value that becomes a state key or a composite key attribute.
+// The null rune delimits composite key attributes and the max rune ends partial
+// composite key ranges, so a value containing either could corrupt or shadow index
+// entries. The value must also be valid UTF-8 and at most maxLength bytes long.
+func ValidateKeyPart(field string, value string, maxLength int) error {
+	if len(value) == 0 {
+		return fmt.Errorf("%s field must be a non-empty string", field)
+	}
+	if len(value) > maxLength {
+		return fmt.Errorf("%s field must be at most %d bytes long, got %d", field, maxLength, len(value))
+	}
+	if !utf8.ValidString(value) {
+		return fmt.Errorf("%s field must be a valid UTF-8 string", field)
+	}
+	for i, r := range value {
+		if r == 0x00 || r == utf8.MaxRune {
+			return fmt.Errorf("%s field must not contain the reserved character %U, found at byte %d", field, r, i)
+		}
+	}
+	return nil
+}
+
+// ValidateTag checks that a tag is a valid lowercase key part
+func ValidateTag(tag string, maxLength int) error {
+	err := ValidateKeyPart("tag", tag, maxLength)
+	if err != nil {
+		return err
+	}
+	if tag != strings.ToLower(tag) {
+		return fmt.Errorf("tag %q must be lowercase", tag)
+	}
+	return nil
+}
+
+// ValidateTags checks the tags of an article: at most MaxTags valid tags without duplicates
+func ValidateTags(tags []string, maxLength int) error {
+	if len(tags) > MaxTags {
+		return fmt.Errorf("tags field must list at most %d tags, got %d", MaxTags, len(tags))
+	}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		err := ValidateTag(tag, maxLength)
+		if err != nil {
+			return err
+		}
+		if seen[tag] {
+			return fmt.Errorf("tags field lists %s twice", tag)
+		}
+		seen[tag] = true
+	}
+	return nil
+}
+
+// ValidateCategory checks that a category is a slash-separated path of segments made of
+// lowercase letters, digits, '-' and '_', e.g. media/journal/medical
+func ValidateCategory(field string, category string, maxLength int) error {
+	if len(category) == 0 {
+		return fmt.Errorf("%s field must be a non-empty string", field)
+	}
+	if len(category) > maxLength {
+		return fmt.Errorf("%s field must be at most %d bytes long, got %d", field, maxLength, len(category))
+	}
+	for _, segment := range strings.Split(category, "/") {
+		if len(segment) == 0 {
+			return fmt.Errorf("%s field %q must not have empty path segments", field, category)
+		}
+		for _, r := range segment {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return fmt.Errorf("%s field %q must only contain lowercase letters, digits, '-', '_' and '/' separators", field, category)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateAllowedColors checks a color allow-list: valid colors in normalized form without duplicates
+func ValidateAllowedColors(colors []string, maxLength int) error {
+	seen := map[string]bool{}
+	for _, color := range colors {
+		err := ValidateKeyPart("color", color, maxLength)
+		if err != nil {
+			return err
+		}
+		if color != NormalizeColor(color) {
+			return fmt.Errorf("color %q must be lowercase", color)
+		}
+		if seen[color] {
+			return fmt.Errorf("color %s is listed twice", color)
+		}
+		seen[color] = true
+	}
+	return nil
+}
+
+// ValidateSHA256Hex checks that a value is the hex encoding of a SHA-256 hash, in either case
+func ValidateSHA256Hex(field string, value string) error {
+	hash, err := hex.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("%s field must be hex encoded: %v", field, err)
+	}
+	if len(hash) != sha256.Size {
+		return fmt.Errorf("%s field must be a SHA-256 hash of %d bytes, got %d", field, sha256.Size, len(hash))
+	}
+	return nil
+}
+
+// ValidateReason checks a required free-text reason: valid UTF-8 of at most MaxReasonLength
+// bytes, not blank
+func ValidateReason(field string, reason string) error {
+	if len(strings.TrimSpace(reason)) == 0 {
+		return fmt.Errorf("%s field must be a non-empty string", field)
+	}
+	if len(reason) > MaxReasonLength {
+		return fmt.Errorf("%s field must be at most %d bytes long, got %d", field, MaxReasonLength, len(reason))
+	}
+	if !utf8.ValidString(reason) {
+		return fmt.Errorf("%s field must be a valid UTF-8 string", field)
+	}
+	return nil
+}
+
+// ValidateSKU checks an SKU: letters, digits and dashes, at most MaxSKULength of them.
+// SKUs are stored in uppercase, so "ab-1" and "AB-1" are the same SKU.
+func ValidateSKU(field string, sku *string) error {
+	*sku = strings.ToUpper(*sku)
+	if len(*sku) == 0 {
+		return fmt.Errorf("%s field must be a non-empty string", field)
+	}
+	if len(*sku) > MaxSKULength {
+		return fmt.Errorf("%s field must be at most %d characters long, got %d", field, MaxSKULength, len(*sku))
+	}
+	for i, r := range *sku {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return fmt.Errorf("%s field must only contain letters, digits and dashes, found %q at byte %d", field, r, i)
+		}
+	}
+	return nil
+}
+
+// ValidateSalt checks that a salt is base64 encoded and long enough to keep the
+// low-entropy article properties from being brute-forced out of the private data hash
+func ValidateSalt(salt string) error {
+	if len(salt) == 0 {
+		return fmt.Errorf("salt field must be a non-empty base64 string")
+	}
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return fmt.Errorf("salt field must be base64 encoded: %v", err)
+	}
+	if len(saltBytes) < MinSaltLength {
+		return fmt.Errorf("salt field must contain at least %d random bytes, got %d", MinSaltLength, len(saltBytes))
+	}
+	return nil
+}
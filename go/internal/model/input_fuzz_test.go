@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+// transientInput is a transient input the handlers decode and validate
+type transientInput interface {
+	Validate(maxNameLength int) error
+}
+
+// transientInputs are the transient inputs by transient map key, each with a valid example
+var transientInputs = []struct {
+	key   string
+	new   func() transientInput
+	valid string
+}{
+	{"article", func() transientInput { return &ArticleTransientInput{} }, `{"name":"article1","color":"blue","size":35,"owner":"tom","price":99,"currency":"EUR","salt":"` + testSalt + `","quantity":2,"tags":["new"],"weight":{"value":1.5,"unit":"kg"},"dimensions":{"l":10,"w":20,"h":30,"unit":"cm"}}`},
+	{"article_split", func() transientInput { return &ArticleSplitTransientInput{} }, `{"name":"article1","newName":"article2","quantity":1,"salt":"` + testSalt + `"}`},
+	{"article_clone", func() transientInput { return &ArticleCloneTransientInput{} }, `{"sourceName":"article1","newName":"article2","salt":"` + testSalt + `","overrides":{"color":"red"}}`},
+	{"article_rename", func() transientInput { return &ArticleRenameTransientInput{} }, `{"oldName":"article1","newName":"article2","graceSeconds":60}`},
+	{"article_retire", func() transientInput { return &ArticleRetireTransientInput{} }, `{"name":"article1","reason":"worn out"}`},
+	{"article_merge", func() transientInput { return &ArticleMergeTransientInput{} }, `{"name":"article1","mergedName":"article2"}`},
+	{"article_owner", func() transientInput { return &ArticleTransferTransientInput{} }, `{"name":"article1","owner":"jerry","ownerOrg":"Org2MSP","expectedCurrentOwner":"tom"}`},
+	{"transfer_proposal", func() transientInput { return &TransferProposalTransientInput{} }, `{"name":"article1","newOwner":"jerry","ownerOrg":"Org2MSP","ttlSeconds":3600}`},
+	{"transfer_response", func() transientInput { return &TransferResponseTransientInput{} }, `{"name":"article1"}`},
+	{"transfer_initiation", func() transientInput { return &TransferInitiationTransientInput{} }, `{"name":"article1","newOwner":"jerry","ownerOrg":"Org2MSP","approvers":["Org1MSP"]}`},
+	{"transfer_approval", func() transientInput { return &TransferApprovalTransientInput{} }, `{"name":"article1"}`},
+	{"certification", func() transientInput { return &CertificationTransientInput{} }, `{"name":"article1","standard":"ISO-9001","result":"pass"}`},
+	{"dispute", func() transientInput { return &DisputeTransientInput{} }, `{"name":"article1","reason":"not delivered"}`},
+	{"dispute_resolution", func() transientInput { return &DisputeResolutionTransientInput{} }, `{"name":"article1","outcome":"upheld"}`},
+	{"certification_revocation", func() transientInput { return &CertificationRevocationTransientInput{} }, `{"name":"article1"}`},
+	{"article_attachment", func() transientInput { return &ArticleAttachmentTransientInput{} }, `{"name":"article1","attachmentID":"manual","sha256Hex":"` + strings.Repeat("ab", 32) + `","description":"user manual"}`},
+	{"article_tag", func() transientInput { return &ArticleTagTransientInput{} }, `{"name":"article1","tag":"new"}`},
+	{"article_transfer", func() transientInput { return &ArticleTransferWithPriceTransientInput{} }, `{"name":"article1","newOwner":"jerry","newPrice":120,"ownerOrg":"Org2MSP"}`},
+	{"negotiated_price", func() transientInput { return &NegotiatedPriceTransientInput{} }, `{"name":"article1","buyerMSP":"Org2MSP","sellerMSP":"Org1MSP","price":99,"currency":"EUR"}`},
+	{"article_swap", func() transientInput { return &ArticleSwapTransientInput{} }, `{"name1":"article1","owner1":"tom","name2":"article2","owner2":"jerry"}`},
+	{"article_agreement", func() transientInput { return &ArticleAgreementTransientInput{} }, `{"name":"article1","price":99,"currency":"EUR"}`},
+	{"article_price", func() transientInput { return &ArticlePriceTransientInput{} }, `{"name":"article1","price":99,"currency":"EUR"}`},
+	{"article_sale", func() transientInput { return &ArticleSaleTransientInput{} }, `{"name":"article1","forSale":true,"askingPriceVisible":true}`},
+	{"article_lock", func() transientInput { return &ArticleLockTransientInput{} }, `{"name":"article1","ttlSeconds":600}`},
+	{"share_transfer", func() transientInput { return &ShareTransferTransientInput{} }, `{"name":"article1","fromOwner":"tom","toOwner":"jerry","percent":25}`},
+	{"article_lease", func() transientInput { return &ArticleLeaseTransientInput{} }, `{"name":"article1","lessee":"jerry","endDate":"2030-01-01T00:00:00Z"}`},
+	{"lease_end", func() transientInput { return &LeaseEndTransientInput{} }, `{"name":"article1"}`},
+	{"policy_reset", func() transientInput { return &PolicyResetTransientInput{} }, `{"name":"article1"}`},
+	{"article_delete", func() transientInput { return &ArticleDeleteTransientInput{} }, `{"name":"article1","keepHistory":true}`},
+	{"article_purge", func() transientInput { return &ArticlePurgeTransientInput{} }, `{"name":"article1","includeArticle":true}`},
+	{"article_private_details_delete", func() transientInput { return &ArticlePrivateDetailsDeleteTransientInput{} }, `{"name":"article1"}`},
+	{"owner_registration", func() transientInput { return &OwnerRegistrationTransientInput{} }, `{"name":"tom","mspID":"Org1MSP"}`},
+	{"owner_deactivation", func() transientInput { return &OwnerDeactivationTransientInput{} }, `{"name":"tom"}`},
+	{"price_access", func() transientInput { return &PriceAccessTransientInput{} }, `{"name":"article1","clientID":"x509::CN=user1"}`},
+	{"details_access", func() transientInput { return &DetailsAccessTransientInput{} }, `{"name":"article1","granteeMSP":"Org2MSP","expiresAt":"2030-01-01T00:00:00Z"}`},
+	{"details_access_revoke", func() transientInput { return &DetailsAccessRevokeTransientInput{} }, `{"name":"article1","granteeMSP":"Org2MSP"}`},
+	{"auction", func() transientInput { return &AuctionTransientInput{} }, `{"name":"article1","minPrice":100,"closeTime":"2030-01-01T00:00:00Z","revealDeadline":"2030-01-02T00:00:00Z"}`},
+	{"bid", func() transientInput { return &BidTransientInput{} }, `{"name":"article1","amount":150,"owner":"jerry","salt":"` + testSalt + `"}`},
+	{"offer", func() transientInput { return &OfferTransientInput{} }, `{"name":"article1","price":150,"owner":"jerry","expiresAt":"2030-01-01T00:00:00Z","salt":"` + testSalt + `"}`},
+	{"offer_accept", func() transientInput { return &OfferAcceptTransientInput{} }, `{"name":"article1","buyerOrg":"Org2MSP","price":150,"owner":"jerry","expiresAt":"2030-01-01T00:00:00Z","salt":"` + testSalt + `"}`},
+	{"auction_close", func() transientInput { return &AuctionNameTransientInput{} }, `{"name":"article1"}`},
+}
+
+// malformedInputs are seeds that decoders tend to get wrong
+var malformedInputs = []string{
+	"",
+	"null",
+	"{}",
+	`{"name":"article1"} {}`,
+	`{"name":"article1","name":"article2"}`,
+	`{"name":"\xff\xfe\xfd"}`,
+	`{"name":"article1\u0000"}`,
+	`{"name":"` + strings.Repeat("a", 100000) + `"}`,
+	`{"name":"article1","size":99999999999999999999999999999}`,
+	`{"name":"article1","price":1e400,"currency":"EUR"}`,
+	`{"name":"article1","price":-1,"currency":"EUR"}`,
+	`{"name":"article1","price":0.001,"currency":"EUR"}`,
+	`{"name":"article1","quantity":1.5}`,
+	strings.Repeat("[", 100000) + strings.Repeat("]", 100000),
+	`{"name":` + strings.Repeat(`{"a":`, 10000) + "1" + strings.Repeat("}", 10000) + "}",
+	`{"tags":` + strings.Repeat("[", 10000) + strings.Repeat("]", 10000) + "}",
+}
+
+func TestTransientInputsAreValid(t *testing.T) {
+	for _, input := range transientInputs {
+		in := input.new()
+		err := DecodeTransientInput(input.key, []byte(input.valid), in)
+		if err == nil {
+			err = in.Validate(DefaultMaxNameLength)
+		}
+		if err != nil {
+			t.Errorf("example %s input is invalid: %v", input.key, err)
+		}
+	}
+}
+
+func FuzzDecodeTransientInput(f *testing.F) {
+	for _, input := range transientInputs {
+		f.Add([]byte(input.valid))
+	}
+	for _, data := range malformedInputs {
+		f.Add([]byte(data))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, input := range transientInputs {
+			_ = DecodeTransientInput(input.key, data, input.new())
+		}
+	})
+}
+
+func FuzzTransientInputValidate(f *testing.F) {
+	for i, input := range transientInputs {
+		f.Add(uint8(i), []byte(input.valid))
+		for _, data := range malformedInputs {
+			f.Add(uint8(i), []byte(data))
+		}
+	}
+	f.Fuzz(func(t *testing.T, kind uint8, data []byte) {
+		input := transientInputs[int(kind)%len(transientInputs)]
+		in := input.new()
+		if DecodeTransientInput(input.key, data, in) != nil {
+			return
+		}
+		if in.Validate(DefaultMaxNameLength) != nil {
+			return
+		}
+		// a valid input stays valid, validation only normalizes it
+		err := in.Validate(DefaultMaxNameLength)
+		if err != nil {
+			t.Errorf("%s input %q is invalid once validated: %v", input.key, data, err)
+		}
+	})
+}
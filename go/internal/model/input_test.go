@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSalt = "c2FsdHNhbHRzYWx0c2FsdHNhbHQ="
+
+func TestArticleTransientInputQuantity(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		quantity int
+		valid    bool
+	}{
+		{`{"name":"a","color":"blue","size":1,"owner":"tom","salt":"` + testSalt + `"}`, 1, true},
+		{`{"name":"a","color":"blue","size":1,"owner":"tom","salt":"` + testSalt + `","quantity":0}`, 1, true},
+		{`{"name":"a","color":"blue","size":1,"owner":"tom","salt":"` + testSalt + `","quantity":7}`, 7, true},
+		{`{"name":"a","color":"blue","size":1,"owner":"tom","salt":"` + testSalt + `","quantity":-1}`, 0, false},
+	} {
+		var in ArticleTransientInput
+		err := DecodeTransientInput("article", []byte(test.input), &in)
+		if err != nil {
+			t.Fatalf("failed to decode %s: %v", test.input, err)
+		}
+		err = in.Validate(DefaultMaxNameLength)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("%s is valid", test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s is invalid: %v", test.input, err)
+		} else if in.Quantity != test.quantity {
+			t.Errorf("%s has quantity %d, expected %d", test.input, in.Quantity, test.quantity)
+		}
+	}
+}
+
+func TestDecodeTransientInputErrors(t *testing.T) {
+	for _, test := range []struct {
+		input   string
+		message string
+	}{
+		{`{"name":"a","colour":"blue"}`, `unknown field "colour" in article input`},
+		{`{"name":"a","size":4.5}`, "size must be an integer, got 4.5"},
+		{`{"name":"a","size":"35"}`, "size must be an integer, got string"},
+		{`{"name":"a","upsert":"true"}`, "upsert must be of type bool, got string"},
+		{`{"name":"a","size":99999999999999999999}`, "size must be an integer, got 99999999999999999999"},
+		{`{"name":"a","price":99999999999999999999,"currency":"EUR"}`, "price 99999999999999999999 overflows a 64-bit integer"},
+		{`{"name":"a"} {"name":"b"}`, "unexpected data after the JSON object in article input"},
+		{`{"name":"a"}x`, "unexpected data after the JSON object in article input"},
+	} {
+		var in ArticleTransientInput
+		err := DecodeTransientInput("article", []byte(test.input), &in)
+		if err == nil {
+			t.Errorf("%s was decoded", test.input)
+		} else if err.Error() != test.message {
+			t.Errorf("%s failed with %q, expected %q", test.input, err, test.message)
+		}
+	}
+}
+
+func TestValidateNameNormalization(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		name  string
+	}{
+		{"Caf\u00e9", "Caf\u00e9"},      // precomposed e with acute
+		{"Cafe\u0301", "Caf\u00e9"},     // e followed by the combining acute accent
+		{" Caf\u00e9 ", "Caf\u00e9"},    // surrounding spaces
+		{"\tCafe\u0301\n", "Caf\u00e9"}, // whitespace around the decomposed form
+		{"A\u030a", "\u00c5"},           // A with the combining ring above
+		{"Cafe \u0301", "Cafe \u0301"},  // a mark after a space has nothing to compose with
+		{"Caf\u00e9 au lait", "Caf\u00e9 au lait"},
+	} {
+		name := test.input
+		err := ValidateName("name", &name, DefaultMaxNameLength)
+		if err != nil {
+			t.Errorf("%q is invalid: %v", test.input, err)
+		} else if name != test.name {
+			t.Errorf("%q normalizes to %q, expected %q", test.input, name, test.name)
+		}
+	}
+
+	for _, input := range []string{"", "   ", "Caf\u00e9\x07", strings.Repeat("e\u0301", MaxNameCharacters+1)} {
+		name := input
+		if ValidateName("name", &name, 4*MaxNameCharacters) == nil {
+			t.Errorf("%q is valid", input)
+		}
+	}
+	// the limit counts characters after the composition, not the runes sent
+	name := strings.Repeat("e\u0301", MaxNameCharacters)
+	if err := ValidateName("name", &name, 4*MaxNameCharacters); err != nil {
+		t.Errorf("%d decomposed characters are invalid: %v", MaxNameCharacters, err)
+	}
+}
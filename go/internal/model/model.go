@@ -0,0 +1,844 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package model holds the objects stored by the articles chaincode and the
+// names of the collections and indexes they are stored under.
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Default names of the private data collections, see the collection config in the README
+const (
+	DefaultCollectionArticles              = "collectionArticles"
+	DefaultCollectionArticlePrivateDetails = "collectionArticlePrivateDetails"
+
+	// DefaultDocType is the docType of articles. Articles are stored under their bare
+	// name, objects of other doc types under a docType~name composite key.
+	DefaultDocType = "article"
+
+	// ImplicitOrgPrefix prefixes the MSP ID in the name of an org's implicit collection
+	ImplicitOrgPrefix = "_implicit_org_"
+
+	// SelfTestPrefix starts the names of the temporary articles of selfTest, which the list
+	// queries leave out and initArticle refuses
+	SelfTestPrefix = "__selftest__"
+
+	// BilateralCollectionPrefix prefixes the sorted MSP IDs of two orgs, separated by an
+	// underscore, in the name of the collection the two of them share
+	BilateralCollectionPrefix = "bilateral_"
+)
+
+// Object types of the composite key indexes
+const (
+	ColorNameIndex = "color~name"
+	ForSaleIndex   = "forsale~name"
+	OwnerNameIndex = "owner~name"
+	SizeNameIndex  = "size~name"
+	TagNameIndex   = "tag~name"
+	// CategoryNameIndex keys an article by the segments of its category path followed by
+	// its name, so a partial composite key on the leading segments scans a subtree
+	CategoryNameIndex = "category~name"
+	// FingerprintIndex keys an article by the fingerprint of its physical properties, see
+	// Article.Fingerprint, so articles created twice under different names share a prefix
+	FingerprintIndex  = "fingerprint~hash~name"
+	AgreementIndex    = "agreement~name~terms"
+	AuditIndex        = "audit~name~txid"
+	HistoryIndex      = "history~name~seq"
+	LeaseIndex        = "lease~name~seq"
+	PriceHistoryIndex = "priceHistory~name~seq"
+	PriceNameIndex    = "price~name"
+	// DetailsWriterIndex maps an article to the MSP ID of the org that last wrote its private details
+	DetailsWriterIndex = "detailsWriter~name"
+	// PriceAccessIndex lists the clients the creator of the private details granted read access
+	PriceAccessIndex = "priceAccess~name~id"
+	// AuctionIndex keys the auction of an article, auction~name maps to an Auction
+	AuctionIndex = "auction~name"
+	// BidCommitmentIndex keys the hash of the sealed bid of an org, in collectionArticles
+	BidCommitmentIndex = "bidCommitment~name~org"
+	// RevealedBidIndex keys the revealed bid of an org, in collectionArticles
+	RevealedBidIndex = "revealedBid~name~org"
+	// BidIndex keys the sealed bid of an org in its implicit collection
+	BidIndex = "bid~name"
+	// OfferIndex keys the purchase offer of an org in its implicit collection
+	OfferIndex = "offer~name"
+	// OfferCommitmentIndex keys the hash of the purchase offer of an org, in collectionArticles
+	OfferCommitmentIndex = "offerCommitment~name~org"
+	// PendingTransferIndex keys the transfer proposed for an article, pendingTransfer~name
+	// maps to a PendingTransfer
+	PendingTransferIndex = "pendingTransfer~name"
+	// TransferRequestIndex keys the transfer initiated for an article, transferRequest~name
+	// maps to a TransferRequest
+	TransferRequestIndex = "transferRequest~name"
+	// ApprovalIndex keys the approval of an org for the transfer initiated for an article
+	ApprovalIndex = "approval~name~msp"
+	// CertificationIndex keys the certification of an article by a certifier org
+	CertificationIndex = "cert~name~msp"
+	// AttachmentIndex keys the hash of an off-chain document of an article, in
+	// collectionArticlePrivateDetails
+	AttachmentIndex = "attach~name~id"
+	// NegotiatedPriceIndex keys the price a buyer org negotiated for an article, in the
+	// collection of the buyer and seller orgs
+	NegotiatedPriceIndex = "negotiatedPrice~name~buyer"
+	// OwnerCountIndex keys a shard of the number of articles of an owner, count~owner~shard
+	// maps to a decimal integer in collectionArticles
+	OwnerCountIndex = "count~owner~shard"
+	// SKUIndex keys the SKU of an article, sku~code maps to the name of the article, which
+	// keeps SKUs unique across collectionArticles
+	SKUIndex = "sku~code"
+	// DisputeIndex keys the disputes raised about an article in collectionArticles, numbered
+	// in the order they were raised
+	DisputeIndex = "dispute~name~seq"
+	// DetailsAccessIndex keys the grant of an org outside collectionArticlePrivateDetails
+	// to read the private details of an article, in that collection
+	DetailsAccessIndex = "detailsAccess~name~msp"
+	// NonceIndex keys the hex SHA-256 of a request nonce in collectionArticles, mapping to
+	// the RFC3339 timestamp of the transaction that used it
+	NonceIndex = "nonce~hash"
+	// OwnerRegistryIndex keys the owner registry, registry~ownerName maps to an OwnerRecord
+	OwnerRegistryIndex = "registry"
+)
+
+// CurrentSchemaVersion is the version of the article and private details JSON written by
+// this chaincode. Records without a schemaVersion predate versioning and are upgraded by
+// migrateArticles. Version 2 stores colors in lowercase.
+const CurrentSchemaVersion = 2
+
+// AdminAttribute is the client certificate attribute that must be "true" to call the
+// maintenance functions such as migrateArticles
+const AdminAttribute = "articles.admin"
+
+// CertifierAttribute is the client certificate attribute that must be "true" to certify articles
+const CertifierAttribute = "articles.certifier"
+
+// ArbiterAttribute is the client certificate attribute that must be "true" to resolve disputes
+const ArbiterAttribute = "articles.arbiter"
+
+// SequenceWidth is the number of digits of the zero-padded sequence numbers in composite
+// keys, so the lexical order of the keys matches the order in which they were written
+const SequenceWidth = 10
+
+// PriceWidth is the number of digits of the zero-padded prices in the price~name index.
+// Larger prices are written with WidePricePrefix, which sorts after all digits, followed
+// by WidePriceWidth digits, which makes MaxPrice the highest price an article can have.
+const (
+	PriceWidth      = 9
+	WidePriceWidth  = 18
+	WidePricePrefix = ":"
+
+	maxNarrowPrice Price = 999999999
+	MaxPrice       Price = 999999999999999999
+)
+
+// SizeWidth is the number of digits of the zero-padded sizes in the size~name index,
+// which makes MaxSize the largest size an article can have
+const (
+	SizeWidth = 9
+	MaxSize   = 999999999
+)
+
+// MaxLockTTLSeconds bounds the time an article can be locked, so an org cannot hold an
+// article it does not own indefinitely
+const MaxLockTTLSeconds = 24 * 60 * 60
+
+// MaxTags bounds the number of tags of an article
+const MaxTags = 10
+
+// MaxReasonLength bounds the length in bytes of the free-text reasons given for changes
+const MaxReasonLength = 1024
+
+// MaxSKULength bounds the length of the SKU of an article
+const MaxSKULength = 64
+
+// DefaultRenameGraceSeconds is the time the tombstone of a renamed article redirects to
+// its new name when the rename sets none, MaxRenameGraceSeconds the longest time it can be given
+const (
+	DefaultRenameGraceSeconds = 7 * 24 * 60 * 60
+	MaxRenameGraceSeconds     = 90 * 24 * 60 * 60
+)
+
+// DefaultTransferTTLSeconds is the time the receiving org has to accept a proposed transfer
+// when the proposal sets none, MaxTransferTTLSeconds the longest time it can be given
+const (
+	DefaultTransferTTLSeconds = 24 * 60 * 60
+	MaxTransferTTLSeconds     = 7 * 24 * 60 * 60
+)
+
+// Article is the record stored in collectionArticles
+type Article struct {
+	ObjectType string `json:"docType"` //docType is used to distinguish the various types of objects in state database
+	Name       string `json:"name"`    //the fieldtags are needed to keep case from bouncing around
+	Color      string `json:"color"`
+	Size       int    `json:"size"`
+	Owner      string `json:"owner"`
+	OwnerOrg   string `json:"ownerOrg"`  //MSP ID of the organization the owner belongs to
+	Salt       string `json:"salt"`      //random base64 bytes that keep the private data hash from being guessed
+	CreatedAt  string `json:"createdAt"` //RFC3339 transaction timestamp of the creation, empty for older records
+	UpdatedAt  string `json:"updatedAt"` //RFC3339 transaction timestamp of the last change, empty for older records
+	Quantity   int    `json:"quantity"`  //number of units in the lot, 0 for older records holding a single unit
+
+	Tags     []string `json:"tags,omitempty"`     //lowercase tags, each indexed under tag~name
+	SKU      string   `json:"sku,omitempty"`      //uppercase external identifier, unique across the collection
+	Category string   `json:"category,omitempty"` //slash-separated path, e.g. media/journal/medical
+
+	WeightGrams int         `json:"weightGrams,omitempty"` //weight of a unit in grams, 0 when unknown
+	Dimensions  *Dimensions `json:"dimensions,omitempty"`  //of a unit, in millimetres
+
+	ForSale            bool `json:"forSale"`            //listed by getArticlesForSale, cleared by a transfer
+	AskingPriceVisible bool `json:"askingPriceVisible"` //list the price of the private details with the article
+
+	SchemaVersion int `json:"schemaVersion"` //0 for records written before versioning
+
+	Locked     bool   `json:"locked"`     //only LockedBy may transfer or delete the article until LockExpiry
+	LockedBy   string `json:"lockedBy"`   //MSP ID of the org holding the lock
+	LockExpiry string `json:"lockExpiry"` //RFC3339 transaction timestamp plus the TTL of the lock
+
+	Deleted   bool   `json:"deleted"`   //soft-deleted, the record is kept as a tombstone without indexes
+	DeletedAt string `json:"deletedAt"` //RFC3339 transaction timestamp of the soft delete
+
+	PendingTransferTo string `json:"pendingTransferTo,omitempty"` //MSP ID of the org a proposed transfer awaits
+
+	// RenamedTo is set on the tombstone renameArticle leaves under the old name, which
+	// points clients to the new name until RedirectExpiry, an RFC3339 timestamp
+	RenamedTo      string `json:"renamedTo,omitempty"`
+	RedirectExpiry string `json:"redirectExpiry,omitempty"`
+
+	// Retired is set by retireArticle on an article that no longer exists physically. The
+	// record stays readable but leaves the color~name and forsale~name indexes for good.
+	Retired      bool   `json:"retired,omitempty"`
+	RetiredAt    string `json:"retiredAt,omitempty"` //RFC3339 transaction timestamp of the retirement
+	RetireReason string `json:"retireReason,omitempty"`
+
+	// Disputed is set by raiseDispute while a dispute about the article is open and
+	// cleared by resolveDispute. The dispute~name~seq records hold the disputes themselves.
+	Disputed bool `json:"disputed,omitempty"`
+
+	// Lessee rents the article from its owner until LeaseEndDate, an RFC3339 timestamp.
+	// While the lease runs the article cannot change hands, see leaseArticle.
+	Lessee       string `json:"lessee,omitempty"`
+	LeaseEndDate string `json:"leaseEndDate,omitempty"`
+
+	// Owners lists the shares of a jointly owned article, which add up to 100 percent;
+	// Owner is then the holder of the largest share. Articles of a single owner leave it
+	// empty, see Shares. All co-owners belong to OwnerOrg.
+	Owners []OwnerShare `json:"owners,omitempty"`
+
+	// ImportedFrom is the ID of the exportArticle transaction on the channel an article
+	// was migrated from. DetailsPending is set when it was imported without its private
+	// details, until addArticlePrivateDetails adds them.
+	ImportedFrom   string `json:"importedFrom,omitempty"`
+	DetailsPending bool   `json:"detailsPending,omitempty"`
+}
+
+// OwnerShare is the share of a co-owner in an article
+type OwnerShare struct {
+	Owner   string `json:"owner"`
+	Percent int    `json:"percent"`
+}
+
+// Shares returns the shares of the owners of the article, a single share of 100 percent
+// for an article of a single owner
+func (a *Article) Shares() []OwnerShare {
+	if len(a.Owners) == 0 {
+		return []OwnerShare{{Owner: a.Owner, Percent: 100}}
+	}
+	return a.Owners
+}
+
+// Fingerprint returns the hex SHA-256 of the normalized color, size and SKU of the article
+// joined by "|", which articles describing the same physical article have in common
+func (a *Article) Fingerprint() string {
+	fingerprint := sha256.Sum256([]byte(NormalizeColor(a.Color) + "|" + strconv.Itoa(a.Size) + "|" + strings.ToUpper(a.SKU)))
+	return hex.EncodeToString(fingerprint[:])
+}
+
+// ShareOf returns the percentage of the article held by an owner, 0 when it holds none
+func (a *Article) ShareOf(owner string) int {
+	for _, share := range a.Shares() {
+		if share.Owner == owner {
+			return share.Percent
+		}
+	}
+	return 0
+}
+
+// SetShares sets the owners of the article from shares that passed ValidateShares. A single
+// share of 100 percent makes the article one of a single owner again; otherwise Owner
+// becomes the holder of the largest share, the first one listed among equal shares.
+func (a *Article) SetShares(shares []OwnerShare) {
+	if len(shares) == 1 {
+		a.Owner = shares[0].Owner
+		a.Owners = nil
+		return
+	}
+	primary := shares[0]
+	for _, share := range shares[1:] {
+		if share.Percent > primary.Percent {
+			primary = share
+		}
+	}
+	a.Owner = primary.Owner
+	a.Owners = shares
+}
+
+// Units returns the number of units of the article. Articles created before quantities
+// were recorded hold a single unit.
+func (a *Article) Units() int {
+	if a.Quantity == 0 {
+		return 1
+	}
+	return a.Quantity
+}
+
+// ArticlePrivateDetails is the record stored in collectionArticlePrivateDetails
+type ArticlePrivateDetails struct {
+	ObjectType string `json:"docType"` //docType is used to distinguish the various types of objects in state database
+	Name       string `json:"name"`    //the fieldtags are needed to keep case from bouncing around
+	Price      Price  `json:"price"`   //in minor units of the currency, e.g. cents
+
+	// Currency is the ISO-4217 code of the price. Empty for records written before prices
+	// carried a currency, which are reported as UnknownCurrency.
+	Currency string `json:"currency,omitempty"`
+
+	SchemaVersion int `json:"schemaVersion"` //0 for records written before versioning
+
+	// CreatorID is the enrollment ID of the client that wrote the price first, as returned by
+	// cid.GetID. Only it and the clients it granted access to may read the details. Empty for
+	// older records, which every member of the collection may read.
+	CreatorID string `json:"creatorID,omitempty"`
+}
+
+// ArticleEvent is the payload of the chaincode events emitted when articles change.
+// Only one event can be set per transaction, so the payload always carries a list of
+// articles and functions touching several articles emit a single aggregated event.
+// It must only contain non-confidential data, the price is never part of an event.
+type ArticleEvent struct {
+	Articles []ArticleEventEntry `json:"articles"`
+}
+
+// DuplicateWarning is the response of initArticle when existing articles share the
+// fingerprint of the new one
+type DuplicateWarning struct {
+	Fingerprint string   `json:"fingerprint"`
+	Duplicates  []string `json:"duplicates"` //names of the existing articles
+}
+
+// DuplicateGroup is a group of articles sharing a fingerprint, as reported by
+// findDuplicateArticles
+type DuplicateGroup struct {
+	Fingerprint string   `json:"fingerprint"`
+	Names       []string `json:"names"`
+}
+
+// ProvenanceExport is the lifecycle of an article as exported by exportProvenance. The
+// ContentHash is the hex SHA-256 of the canonical JSON of the export without it, so the
+// document can be checked off-chain. History holds one page of the ownership history,
+// starting at the sequence number HistoryOffset; NextOffset is the start of the next page.
+type ProvenanceExport struct {
+	Name           string                 `json:"name"`
+	Article        *Article               `json:"article"`
+	History        []OwnershipRecord      `json:"history"`
+	HistoryOffset  int                    `json:"historyOffset"`
+	Truncated      bool                   `json:"truncated"`
+	NextOffset     int                    `json:"nextOffset,omitempty"`
+	Certifications []Certification        `json:"certifications"`
+	ArticleHash    string                 `json:"articleHash"`              //hex private data hash in collectionArticles
+	PrivateDetails *ArticlePrivateDetails `json:"privateDetails,omitempty"` //only for clients allowed to read the price
+
+	// PrivateDetailsHash is the hex private data hash of the details in
+	// collectionArticlePrivateDetails, all that clients without access to the price get.
+	// Empty when the article has no private details.
+	PrivateDetailsHash string `json:"privateDetailsHash,omitempty"`
+
+	ContentHash string `json:"contentHash,omitempty"`
+}
+
+// ArticleExport is the package exportArticle hands over to importArticle on another
+// channel. PrivateDetails are included only for clients allowed to read the price,
+// otherwise PrivateDetailsHash stands in for them. HashHex is the hex SHA-256 of the
+// canonical JSON of the package without it, see ComputeExportHash.
+type ArticleExport struct {
+	Article            *Article               `json:"article"`
+	PrivateDetails     *ArticlePrivateDetails `json:"privateDetails,omitempty"`
+	PrivateDetailsHash string                 `json:"privateDetailsHash,omitempty"`
+	Salt               string                 `json:"salt"`
+	ExportTxID         string                 `json:"exportTxID"`
+	HashHex            string                 `json:"hashHex"`
+}
+
+// ArticleEventEntry describes a single article in an ArticleEvent. Only the name is always
+// set; the other fields appear in the events of the changes they describe.
+type ArticleEventEntry struct {
+	Name     string `json:"name"`               //name of the article, the new name after a rename
+	OldName  string `json:"oldName,omitempty"`  //name before the rename, ArticleRenamed only
+	OldOwner string `json:"oldOwner,omitempty"` //owner before the transfer, ArticleTransferred only
+	NewOwner string `json:"newOwner,omitempty"` //owner after the transfer, ArticleTransferred only
+}
+
+// AuditRecord records which client and transaction changed an article. It is stored
+// in collectionArticles under an audit~name~txid composite key.
+type AuditRecord struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	TxID       string `json:"txId"`
+	Function   string `json:"function"`
+	ModifiedBy string `json:"modifiedBy"` //ID of the invoking client identity as returned by cid.GetID
+	Timestamp  string `json:"timestamp"`  //RFC3339 transaction timestamp
+}
+
+// PublicAuditRecord records a change of an article in the public world state, where every
+// member of the channel can read it. It is stored under the same audit~name~txid
+// composite key as an AuditRecord, but holds no client identity and no private values,
+// only the hashes the private data has after the change.
+type PublicAuditRecord struct {
+	ObjectType         string `json:"docType"`
+	Name               string `json:"name"`
+	TxID               string `json:"txId"`
+	Function           string `json:"function"`
+	MSPID              string `json:"mspId"`              //MSP ID of the invoking client
+	Timestamp          string `json:"timestamp"`          //RFC3339 transaction timestamp
+	ArticleHash        string `json:"articleHash"`        //hex SHA-256 of the article after the change, empty once deleted
+	PrivateDetailsHash string `json:"privateDetailsHash"` //hex SHA-256 of the private details after the change, empty when there are none
+}
+
+// OwnerRecord is an entry of the owner registry. It is stored in collectionArticles under
+// a registry~ownerName composite key. Articles can only be created for or transferred to
+// active owners.
+type OwnerRecord struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	MSPID      string `json:"mspID"` //MSP ID of the organization the owner belongs to
+	Active     bool   `json:"active"`
+}
+
+// PendingTransfer is a transfer proposed by the owner org that the receiving org has not
+// accepted or rejected yet. It is stored in collectionArticles under a pendingTransfer~name
+// composite key.
+type PendingTransfer struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	NewOwner   string `json:"newOwner"`
+	TargetOrg  string `json:"targetOrg"`  //MSP ID of the org that has to accept the transfer
+	ProposedBy string `json:"proposedBy"` //MSP ID of the owner org that proposed it
+	Expiry     string `json:"expiry"`     //RFC3339, the proposal can no longer be accepted after it
+}
+
+// Certification is the result of a certifier org checking an article against a standard.
+// It is stored in collectionArticles under a cert~name~msp composite key, so each certifier
+// keeps one certification per article.
+type Certification struct {
+	ObjectType   string `json:"docType"`
+	ArticleName  string `json:"articleName"`
+	CertifierMSP string `json:"certifierMSP"`
+	Standard     string `json:"standard"`
+	Result       string `json:"result"`
+	Timestamp    string `json:"timestamp"` //RFC3339 transaction timestamp
+}
+
+// DetailsAccessGrant lets the clients of an org read the private details of an article
+// until it expires, see grantDetailsAccess
+type DetailsAccessGrant struct {
+	ObjectType string `json:"docType"`
+	GranteeMSP string `json:"granteeMSP"`
+	ExpiresAt  string `json:"expiresAt"` //RFC3339
+	GrantedBy  string `json:"grantedBy"` //MSP ID of the owner org that granted access
+	GrantedAt  string `json:"grantedAt"` //RFC3339 transaction timestamp
+}
+
+// Statuses of a dispute
+const (
+	DisputeOpen     = "open"
+	DisputeResolved = "resolved"
+)
+
+// Dispute is a contest of the properties of an article by a collection member. It is
+// stored in collectionArticles under a dispute~name~seq composite key; while it is open
+// the article is disputed and cannot be transferred or deleted.
+type Dispute struct {
+	ObjectType  string `json:"docType"`
+	ArticleName string `json:"articleName"`
+	Status      string `json:"status"` //open or resolved
+	Reason      string `json:"reason"`
+	RaisedBy    string `json:"raisedBy"` //MSP ID of the org that raised the dispute
+	RaisedAt    string `json:"raisedAt"` //RFC3339 transaction timestamp
+	Outcome     string `json:"outcome,omitempty"`
+	ResolvedBy  string `json:"resolvedBy,omitempty"` //MSP ID of the arbiter org
+	ResolvedAt  string `json:"resolvedAt,omitempty"`
+}
+
+// MaxDescriptionLength bounds the description of an attachment in bytes
+const MaxDescriptionLength = 1024
+
+// Attachment binds an off-chain document, e.g. an invoice or a photo, to an article by its
+// SHA-256 hash. It is stored in collectionArticlePrivateDetails under an attach~name~id
+// composite key.
+type Attachment struct {
+	ObjectType   string `json:"docType"`
+	Name         string `json:"name"`
+	AttachmentID string `json:"attachmentID"`
+	SHA256Hex    string `json:"sha256Hex"` //lowercase hex SHA-256 of the document
+	Description  string `json:"description"`
+	AddedAt      string `json:"addedAt"` //RFC3339 transaction timestamp
+}
+
+// MaxApprovers bounds the number of orgs that have to approve an initiated transfer
+const MaxApprovers = 16
+
+// TransferRequest is a transfer that executeTransfer completes once every listed org has
+// approved it. It is stored in collectionArticles under a transferRequest~name composite key.
+type TransferRequest struct {
+	ObjectType  string   `json:"docType"`
+	Name        string   `json:"name"`
+	NewOwner    string   `json:"newOwner"`
+	NewOwnerOrg string   `json:"newOwnerOrg"`
+	Approvers   []string `json:"approvers"`   //MSP IDs of the orgs that have to approve the transfer
+	InitiatedBy string   `json:"initiatedBy"` //MSP ID of the owner org that initiated it
+	InitiatedAt string   `json:"initiatedAt"` //RFC3339 transaction timestamp
+}
+
+// Approval is the approval of an org for an initiated transfer, stored in collectionArticles
+// under an approval~name~msp composite key
+type Approval struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	MSPID      string `json:"mspID"`
+	TxID       string `json:"txID"`
+	Timestamp  string `json:"timestamp"` //RFC3339 transaction timestamp
+}
+
+// Auction states
+const (
+	AuctionOpen  = "open"
+	AuctionEnded = "ended"
+)
+
+// DefaultRevealPeriodSeconds is the time bidders have after the close of an auction to
+// reveal their bids when the auction sets no reveal deadline
+const DefaultRevealPeriodSeconds = 3600
+
+// Auction is the auction of an article. It is stored in collectionArticles under an
+// auction~name composite key.
+type Auction struct {
+	ObjectType     string `json:"docType"`
+	Name           string `json:"name"`
+	SellerOrg      string `json:"sellerOrg"`      //MSP ID of the owner org that opened the auction
+	MinPrice       Price  `json:"minPrice"`       //bids below it are ignored
+	CloseTime      string `json:"closeTime"`      //RFC3339, bids are accepted until then
+	RevealDeadline string `json:"revealDeadline"` //RFC3339, bids are revealed between closeTime and then
+	Status         string `json:"status"`         //open or ended
+	Winner         string `json:"winner,omitempty"`
+	WinnerOrg      string `json:"winnerOrg,omitempty"`
+}
+
+// Bid is a sealed bid. It is stored in the implicit collection of the bidder org under a
+// bid~name composite key, only its hash is visible to the seller until it is revealed.
+type Bid struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	Amount     Price  `json:"amount"`
+	Owner      string `json:"owner"` //owner the article goes to if the bid wins
+	Salt       string `json:"salt"`  //random base64 bytes that keep the amount from being guessed
+}
+
+// BidCommitment is the hash of a sealed bid, stored in collectionArticles under a
+// bidCommitment~name~org composite key
+type BidCommitment struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	BidderOrg  string `json:"bidderOrg"`
+	Hash       string `json:"hash"`     //hex SHA-256 of the bid as stored in the implicit collection
+	PlacedAt   string `json:"placedAt"` //RFC3339 transaction timestamp, earlier bids win ties
+}
+
+// RevealedBid is a bid revealed after the close of its auction, stored in collectionArticles
+// under a revealedBid~name~org composite key
+type RevealedBid struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	BidderOrg  string `json:"bidderOrg"`
+	Amount     Price  `json:"amount"`
+	Owner      string `json:"owner"`
+	PlacedAt   string `json:"placedAt"`
+}
+
+// Offer is a purchase offer of a buyer org that lapses at ExpiresAt. It is stored in the
+// implicit collection of the buyer org under an offer~name composite key; the owner org
+// only sees its hash until the buyer hands it the offer to accept.
+type Offer struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	Price      Price  `json:"price"`
+	Owner      string `json:"owner"`     //owner the article goes to if the offer is accepted
+	ExpiresAt  string `json:"expiresAt"` //RFC3339, the offer cannot be accepted from then on
+	Salt       string `json:"salt"`      //random base64 bytes that keep the price from being guessed
+}
+
+// OfferCommitment is the hash of a purchase offer, stored in collectionArticles under an
+// offerCommitment~name~org composite key
+type OfferCommitment struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	BuyerOrg   string `json:"buyerOrg"`
+	Hash       string `json:"hash"`      //hex SHA-256 of the offer as stored in the implicit collection
+	ExpiresAt  string `json:"expiresAt"` //RFC3339, copied from the offer
+	MadeAt     string `json:"madeAt"`    //RFC3339 transaction timestamp
+}
+
+// OfferListing is an offer as listed by listOffers. The price is only set for the offers
+// of the org of the client, the only ones its peer can read.
+type OfferListing struct {
+	Name      string `json:"name"`
+	BuyerOrg  string `json:"buyerOrg"`
+	ExpiresAt string `json:"expiresAt"`
+	MadeAt    string `json:"madeAt"`
+	Expired   bool   `json:"expired"`
+	Price     *Price `json:"price,omitempty"`
+}
+
+// NegotiatedPrice is the price a buyer org negotiated with the seller org, the owner org
+// of the article. It is stored in the collection of the two orgs under a
+// negotiatedPrice~name~buyer composite key.
+type NegotiatedPrice struct {
+	ObjectType  string `json:"docType"`
+	ArticleName string `json:"articleName"`
+	BuyerMSP    string `json:"buyerMSP"`
+	SellerMSP   string `json:"sellerMSP"`
+	Price       Price  `json:"price"`    //in minor units of the currency
+	Currency    string `json:"currency"` //ISO-4217 code, the one of the article private details
+}
+
+// OwnershipRecord records a single change of owner. It is stored in collectionArticles
+// under a history~name~seq composite key.
+type OwnershipRecord struct {
+	ObjectType    string `json:"docType"`
+	Name          string `json:"name"`
+	PreviousOwner string `json:"previousOwner"`
+	NewOwner      string `json:"newOwner"`
+	Percent       int    `json:"percent,omitempty"` //share passed on by transferShare, 0 for the whole article
+	TxID          string `json:"txId"`
+	Timestamp     string `json:"timestamp"` //RFC3339 transaction timestamp
+}
+
+// Actions of a lease record
+const (
+	LeaseStarted = "started"
+	LeaseEnded   = "ended"
+)
+
+// LeaseRecord records the start or the end of a lease of an article. It is stored in
+// collectionArticles under a lease~name~seq composite key.
+type LeaseRecord struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	Action     string `json:"action"` //started or ended
+	Lessee     string `json:"lessee"`
+	EndDate    string `json:"endDate"` //RFC3339 end of the lease as agreed
+	TxID       string `json:"txId"`
+	Timestamp  string `json:"timestamp"` //RFC3339 transaction timestamp
+}
+
+// PriceRecord records a single change of price. It is stored in
+// collectionArticlePrivateDetails under a priceHistory~name~seq composite key.
+type PriceRecord struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+	OldPrice   Price  `json:"oldPrice"`
+	NewPrice   Price  `json:"newPrice"`
+	TxID       string `json:"txId"`
+	Timestamp  string `json:"timestamp"` //RFC3339 transaction timestamp
+}
+
+// MigrationReport is the result of migrateArticles
+type MigrationReport struct {
+	Migrated int    `json:"migrated"`
+	Skipped  int    `json:"skipped"` //records already in the current version or not decodable
+	LastKey  string `json:"lastKey"` //last key scanned, the start of the next batch follows it
+}
+
+// RecategorizationReport is the result of recategorizeArticles
+type RecategorizationReport struct {
+	Recategorized int  `json:"recategorized"`
+	Remaining     bool `json:"remaining"` //more articles are left under the old prefix, call again
+}
+
+// PurgeReport is the result of purgeDeletedArticles
+type PurgeReport struct {
+	Purged  int `json:"purged"`  //tombstones removed
+	Scanned int `json:"scanned"` //articles read
+}
+
+// OwnerCountShards is the number of keys the article count of an owner is spread over, so
+// concurrent transactions for the same owner rarely write the same key
+const OwnerCountShards = 8
+
+// OwnerArticleCount is the result of getOwnerArticleCount
+type OwnerArticleCount struct {
+	Owner string `json:"owner"`
+	Count int    `json:"count"`
+}
+
+// CountReport is the result of recalculateCounts
+type CountReport struct {
+	Owners   int `json:"owners"`   //owners holding at least one article
+	Articles int `json:"articles"` //articles counted, soft-deleted ones excluded
+	Changed  int `json:"changed"`  //owners whose stored count was wrong
+}
+
+// DefaultScanLimit is the number of records getCollectionSummary reads by default
+const DefaultScanLimit = 100000
+
+// CollectionSummary is the result of getCollectionSummary. When ScanLimitReached is true
+// the totals only cover the records up to LastKey.
+type CollectionSummary struct {
+	TotalArticles    int            `json:"totalArticles"` //soft-deleted articles excluded
+	Colors           map[string]int `json:"colors"`
+	ForSale          int            `json:"forSale"`
+	Scanned          int            `json:"scanned"` //records read, index entries excluded
+	ScanLimitReached bool           `json:"scanLimitReached"`
+	LastKey          string         `json:"lastKey,omitempty"`
+}
+
+// ChangeResult is the result of the updates that succeed without writing anything when
+// the article already is in the requested state
+type ChangeResult struct {
+	Changed bool `json:"changed"`
+}
+
+// ReindexReport is the result of reindexArticles
+type ReindexReport struct {
+	Added   int  `json:"added"`   //missing index entries written
+	Removed int  `json:"removed"` //orphan index entries deleted
+	Scanned int  `json:"scanned"` //articles scanned
+	DryRun  bool `json:"dryRun"`  //nothing was written, the counts are what would change
+}
+
+// ConsistencyReport is the result of checkCollectionConsistency. A partial report comes
+// from a peer outside collectionArticlePrivateDetails, which can only tell from the private
+// data hashes which articles have details and cannot list details without an article.
+type ConsistencyReport struct {
+	Partial                     bool     `json:"partial"`
+	ArticlesScanned             int      `json:"articlesScanned"`
+	DetailsScanned              int      `json:"detailsScanned"`
+	ArticlesWithoutDetails      []string `json:"articlesWithoutDetails"`
+	ArticlesWithoutDetailsCount int      `json:"articlesWithoutDetailsCount"`
+	DetailsWithoutArticle       []string `json:"detailsWithoutArticle"`
+	DetailsWithoutArticleCount  int      `json:"detailsWithoutArticleCount"`
+}
+
+// PriceStatistics is the result of getPriceStatistics. The statistics are null when
+// there are no articles.
+type PriceStatistics struct {
+	Currency string   `json:"currency"` //ISO-4217 code of the prices, empty when there are no articles
+	Count    int      `json:"count"`
+	Skipped  int      `json:"skipped"` //records that are not valid article private details
+	Min      *Price   `json:"min"`
+	Max      *Price   `json:"max"`
+	Sum      *Price   `json:"sum"`
+	Avg      *float64 `json:"avg"`
+}
+
+// ChaincodeMetadata is the result of the metadata function
+type ChaincodeMetadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`     //set at build time, "dev" otherwise
+	Functions   []string `json:"functions"`   //invoke functions in alphabetical order
+	Collections []string `json:"collections"` //configured articles and private details collections
+}
+
+// AllowedColorsKey is the public state key of the JSON array of colors new articles may
+// have. Without one any color is allowed.
+const AllowedColorsKey = "allowedColors"
+
+// NormalizeColor returns the form colors are stored and indexed in, so "Blue" and "blue"
+// share a color~name bucket
+func NormalizeColor(color string) string {
+	return strings.ToLower(color)
+}
+
+// ValidationParameter is the key-level endorsement policy of a private data key as
+// returned by getPrivateDataValidationParameter. Without a key-level policy PolicySet is
+// false and the collection or chaincode endorsement policy applies.
+type ValidationParameter struct {
+	Name       string            `json:"name"`
+	Collection string            `json:"collection"`
+	PolicySet  bool              `json:"policySet"`
+	Message    string            `json:"message,omitempty"`
+	Version    int               `json:"version,omitempty"`
+	Principals []PolicyPrincipal `json:"principals,omitempty"`
+	Rule       *PolicyRule       `json:"rule,omitempty"`
+	Expression string            `json:"expression,omitempty"` //the rule in the syntax of the peer CLI, e.g. OutOf(1, 'Org1MSP.peer')
+}
+
+// PolicyPrincipal is a principal of a signature policy. MSPID and Role are only set for
+// principals of the ROLE classification.
+type PolicyPrincipal struct {
+	Classification string `json:"classification"` //ROLE, ORGANIZATION_UNIT or IDENTITY
+	MSPID          string `json:"mspId,omitempty"`
+	Role           string `json:"role,omitempty"` //MEMBER, ADMIN, CLIENT or PEER
+}
+
+// PolicyRule is a rule of a signature policy: either the signature of the principal at
+// index SignedBy, or N of the Rules
+type PolicyRule struct {
+	SignedBy *int         `json:"signedBy,omitempty"`
+	N        *int         `json:"n,omitempty"`
+	Rules    []PolicyRule `json:"rules,omitempty"`
+}
+
+// PublicSummaryKeyPrefix starts the public state key of the PublicSummary of an article,
+// which is followed by the key of the article in collectionArticles: its name for the doc
+// type "article", the docType~name composite key for others. The keys are simple keys,
+// so listPublicArticles can range over them.
+const PublicSummaryKeyPrefix = "summary:"
+
+// PublicSummary is the record of an article in the public world state, written for
+// articles created with publicSummary set. It tells channel members outside the
+// collections that the article exists and must never hold more than its name and doc type.
+type PublicSummary struct {
+	ObjectType string `json:"docType"`
+	Name       string `json:"name"`
+}
+
+// PublicHistoryEntry is a change of the public summary of an article as returned by
+// getArticlePublicHistory: its appearance, or its disappearance when IsDelete is set
+type PublicHistoryEntry struct {
+	TxID      string `json:"txId"`
+	Timestamp string `json:"timestamp"` //RFC3339 timestamp of the transaction
+	IsDelete  bool   `json:"isDelete"`
+}
+
+// PublicHistory is a page of the history of the public summary of an article. Next is the
+// continuation token to pass for the following page, empty on the last one.
+type PublicHistory struct {
+	Name    string               `json:"name"`
+	Entries []PublicHistoryEntry `json:"entries"`
+	Next    string               `json:"next,omitempty"`
+}
+
+// FunctionACLKey is the public state key of the FunctionACL
+const FunctionACLKey = "functionACL"
+
+// FunctionACL maps invoke function names to the MSP IDs allowed to call them. Functions
+// that are not listed, or listed with no MSP IDs, may be called by any member.
+type FunctionACL map[string][]string
+
+// Allows reports whether clients of the MSP may call the function
+func (acl FunctionACL) Allows(function string, mspID string) bool {
+	allowed := acl[function]
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, allowedMSPID := range allowed {
+		if allowedMSPID == mspID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Price is an amount in minor units of a currency. It is 64 bits wide on every platform
+// and stored as a JSON number. Inputs may also pass it as a JSON string of digits, which
+// clients that keep numbers as doubles cannot round above 2^53.
+type Price int64
+
+// PriceError reports a price that is not an integer or does not fit in 64 bits
+type PriceError struct {
+	Value    string
+	Overflow bool
+}
+
+func (e *PriceError) Error() string {
+	if e.Overflow {
+		return fmt.Sprintf("price %s overflows a 64-bit integer", e.Value)
+	}
+	return fmt.Sprintf("price must be an integer, got %s", e.Value)
+}
+
+// UnmarshalJSON decodes a price from a JSON number or a JSON string holding one. The
+// value goes through json.Number so it is never rounded through a float64, and values
+// beyond the int64 range fail with a PriceError instead of wrapping.
+func (p *Price) UnmarshalJSON(data []byte) error {
+	value := strings.TrimSpace(string(data))
+	if strings.HasPrefix(value, `"`) {
+		err := json.Unmarshal(data, &value)
+		if err != nil {
+			return &PriceError{Value: string(data)}
+		}
+	}
+	n, err := json.Number(value).Int64()
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return &PriceError{Value: value, Overflow: true}
+		}
+		return &PriceError{Value: value}
+	}
+	*p = Price(n)
+	return nil
+}
+
+// FormatPriceKey returns the price attribute of a price~name index key. Prices up to
+// PriceWidth digits are zero-padded to PriceWidth digits as they always were, larger
+// ones are zero-padded to WidePriceWidth digits after WidePricePrefix, so all keys sort by
+// price and no index entry has to be rewritten.
+func FormatPriceKey(price Price) string {
+	if price <= maxNarrowPrice {
+		return fmt.Sprintf("%0*d", PriceWidth, price)
+	}
+	return fmt.Sprintf("%s%0*d", WidePricePrefix, WidePriceWidth, price)
+}
+
+// ParsePriceKey returns the price of the price attribute of a price~name index key
+func ParsePriceKey(key string) (Price, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(key, WidePricePrefix), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price in index key: %s", key)
+	}
+	return Price(n), nil
+}
+
+// ScalePrice returns price * numerator / denominator rounded down, without overflowing
+// the intermediate product. The result must fit in a Price, as it does for a fraction
+// of at most 1.
+func ScalePrice(price Price, numerator int, denominator int) Price {
+	scaled := new(big.Int).Mul(big.NewInt(int64(price)), big.NewInt(int64(numerator)))
+	scaled.Quo(scaled, big.NewInt(int64(denominator)))
+	return Price(scaled.Int64())
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPriceRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		price Price
+	}{
+		{`2147483648`, 2147483648}, // 2^31, past a 32-bit int
+		{`"2147483648"`, 2147483648},
+		{`9007199254740993`, 9007199254740993}, // 2^53+1, the first integer a float64 rounds
+		{`"9007199254740993"`, 9007199254740993},
+		{`999999999999999999`, MaxPrice},
+	} {
+		var details ArticlePrivateDetails
+		err := json.Unmarshal([]byte(`{"name":"article1","price":`+test.input+`}`), &details)
+		if err != nil {
+			t.Fatalf("failed to decode price %s: %v", test.input, err)
+		}
+		if details.Price != test.price {
+			t.Errorf("price %s decodes to %d, expected %d", test.input, details.Price, test.price)
+		}
+
+		detailsJSON, err := json.Marshal(&details)
+		if err != nil {
+			t.Fatalf("failed to encode price %d: %v", details.Price, err)
+		}
+		expected := `"price":` + strings.Trim(test.input, `"`) + `,`
+		if !strings.Contains(string(detailsJSON), expected) {
+			t.Errorf("price %s encodes as %s, expected %s", test.input, detailsJSON, expected)
+		}
+
+		key := FormatPriceKey(details.Price)
+		price, err := ParsePriceKey(key)
+		if err != nil || price != test.price {
+			t.Errorf("index key %q of price %d parses to %d: %v", key, test.price, price, err)
+		}
+	}
+
+	var price Price
+	for _, input := range []string{`9223372036854775808`, `"9223372036854775808"`, `9007199254740993.0`, `1e3`, `"12a"`} {
+		if json.Unmarshal([]byte(input), &price) == nil {
+			t.Errorf("price %s decodes to %d", input, price)
+		}
+	}
+}
+
+func TestPriceKeysSortByPrice(t *testing.T) {
+	prices := []Price{0, 9900, 999999999, 1000000000, 2147483648, 9007199254740992, 9007199254740993, MaxPrice}
+	for i := 1; i < len(prices); i++ {
+		if low, high := FormatPriceKey(prices[i-1]), FormatPriceKey(prices[i]); low >= high {
+			t.Errorf("index key %q of %d does not sort before %q of %d", low, prices[i-1], high, prices[i])
+		}
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Bounds of the canonical physical attributes of an article
+const (
+	MaxWeightGrams          = 100000000 //100 tonnes
+	MaxDimensionMillimetres = 100000    //100 metres
+)
+
+// weightUnits and lengthUnits map the accepted input units to their exact factor to grams
+// and millimetres. The pound and the inch are defined as exact decimals.
+var (
+	weightUnits = map[string]string{"g": "1", "kg": "1000", "lb": "453.59237"}
+	lengthUnits = map[string]string{"mm": "1", "cm": "10", "in": "25.4"}
+)
+
+// Dimensions are the length, width and height of an article in millimetres
+type Dimensions struct {
+	L int `json:"l"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// WeightInput is the weight of an article as given in a transient input
+type WeightInput struct {
+	Value json.Number `json:"value"`
+	Unit  string      `json:"unit"` //g, kg or lb, defaults to g
+}
+
+// Grams returns the weight in whole grams, see toCanonicalUnit
+func (in *WeightInput) Grams() (int, error) {
+	return toCanonicalUnit("weight", in.Value, in.Unit, "g", weightUnits, MaxWeightGrams)
+}
+
+// DimensionsInput are the dimensions of an article as given in a transient input
+type DimensionsInput struct {
+	L    json.Number `json:"l"`
+	W    json.Number `json:"w"`
+	H    json.Number `json:"h"`
+	Unit string      `json:"unit"` //mm, cm or in, defaults to mm
+}
+
+// Millimetres returns the dimensions in whole millimetres, see toCanonicalUnit
+func (in *DimensionsInput) Millimetres() (*Dimensions, error) {
+	l, err := toCanonicalUnit("dimensions.l", in.L, in.Unit, "mm", lengthUnits, MaxDimensionMillimetres)
+	if err != nil {
+		return nil, err
+	}
+	w, err := toCanonicalUnit("dimensions.w", in.W, in.Unit, "mm", lengthUnits, MaxDimensionMillimetres)
+	if err != nil {
+		return nil, err
+	}
+	h, err := toCanonicalUnit("dimensions.h", in.H, in.Unit, "mm", lengthUnits, MaxDimensionMillimetres)
+	if err != nil {
+		return nil, err
+	}
+	return &Dimensions{L: l, W: w, H: h}, nil
+}
+
+// toCanonicalUnit converts a decimal value in one of the units to a whole number of the
+// canonical unit. The conversion is exact rational arithmetic, never floating point, and
+// the result is rounded half away from zero, so 0.0005 kg is 1 g and 0.1 in is 3 mm on every
+// endorser. Exponents are rejected, they would let a short input demand a huge number.
+// The result must be between 1 and max.
+func toCanonicalUnit(field string, value json.Number, unit string, canonical string, factors map[string]string, max int) (int, error) {
+	unit = strings.ToLower(unit)
+	if len(unit) == 0 {
+		unit = canonical
+	}
+	factor, ok := factors[unit]
+	if !ok {
+		return 0, fmt.Errorf("%s unit must be one of %s, got %q", field, unitNames(factors), unit)
+	}
+	if len(value) == 0 {
+		return 0, fmt.Errorf("%s field must be a number", field)
+	}
+	if strings.ContainsAny(string(value), "eE") {
+		return 0, fmt.Errorf("%s field must be a decimal number without exponent, got %s", field, value)
+	}
+	amount, ok := new(big.Rat).SetString(string(value))
+	if !ok {
+		return 0, fmt.Errorf("%s field must be a decimal number, got %s", field, value)
+	}
+	if amount.Sign() <= 0 {
+		return 0, fmt.Errorf("%s field must be positive, got %s", field, value)
+	}
+	factorRat, _ := new(big.Rat).SetString(factor)
+	amount.Mul(amount, factorRat)
+
+	// round half away from zero: floor(amount + 1/2) for a positive amount
+	numerator := new(big.Int).Mul(amount.Num(), big.NewInt(2))
+	numerator.Add(numerator, amount.Denom())
+	denominator := new(big.Int).Mul(amount.Denom(), big.NewInt(2))
+	rounded := numerator.Quo(numerator, denominator)
+
+	if rounded.Sign() == 0 {
+		return 0, fmt.Errorf("%s field must be at least 1 %s once converted, got %s %s", field, canonical, value, unit)
+	}
+	if rounded.Cmp(big.NewInt(int64(max))) > 0 {
+		return 0, fmt.Errorf("%s field must be at most %d %s once converted, got %s %s", field, max, canonical, value, unit)
+	}
+	return int(rounded.Int64()), nil
+}
+
+// unitNames lists the units of a factor table in a stable order for error messages
+func unitNames(factors map[string]string) string {
+	names := make([]string, 0, len(factors))
+	for name := range factors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
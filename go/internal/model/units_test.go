@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWeightConversion(t *testing.T) {
+	for _, test := range []struct {
+		value string
+		unit  string
+		grams int
+		err   string // part of the expected error, or empty for none
+	}{
+		{"1500", "", 1500, ""},
+		{"1500", "g", 1500, ""},
+		{"1.5", "kg", 1500, ""},
+		{"1.5", "KG", 1500, ""},
+		{"0.29", "kg", 290, ""}, //289.99999999999997 in float64
+		{"1", "lb", 454, ""},    //453.59237
+		{"2", "lb", 907, ""},    //907.18474
+		// half away from zero, not to even
+		{"2.5", "g", 3, ""},
+		{"3.5", "g", 4, ""},
+		{"1.4999", "g", 1, ""},
+		{"0.5", "g", 1, ""},
+		{"0.0005", "kg", 1, ""},
+		{"0.0004999", "kg", 0, "must be at least 1 g once converted"},
+		{"100000", "kg", MaxWeightGrams, ""},
+		{"100000.0005", "kg", 0, "must be at most 100000000 g once converted"},
+		{"0", "g", 0, "must be positive"},
+		{"-1", "kg", 0, "must be positive"},
+		{"1e3", "g", 0, "without exponent"},
+		{"abc", "g", 0, "must be a decimal number"},
+		{"", "g", 0, "must be a number"},
+		{"1", "oz", 0, `weight unit must be one of g, kg, lb, got "oz"`},
+	} {
+		in := &WeightInput{Value: json.Number(test.value), Unit: test.unit}
+		grams, err := in.Grams()
+		if len(test.err) != 0 {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s %s: expected an error containing %q, got %d grams and %v", test.value, test.unit, test.err, grams, err)
+			}
+			continue
+		}
+		if err != nil || grams != test.grams {
+			t.Errorf("%s %s is %d grams (%v), expected %d", test.value, test.unit, grams, err, test.grams)
+		}
+	}
+}
+
+func TestDimensionsConversion(t *testing.T) {
+	for _, test := range []struct {
+		in         DimensionsInput
+		dimensions Dimensions
+		err        string // part of the expected error, or empty for none
+	}{
+		{DimensionsInput{L: "10", W: "20", H: "30"}, Dimensions{10, 20, 30}, ""},
+		{DimensionsInput{L: "10", W: "20", H: "30", Unit: "cm"}, Dimensions{100, 200, 300}, ""},
+		{DimensionsInput{L: "0.05", W: "0.04", H: "1.25", Unit: "cm"}, Dimensions{1, 0, 13}, "dimensions.w field must be at least 1 mm"},
+		{DimensionsInput{L: "0.05", W: "0.15", H: "1.25", Unit: "cm"}, Dimensions{1, 2, 13}, ""},
+		{DimensionsInput{L: "1", W: "1.5", H: "0.1", Unit: "in"}, Dimensions{25, 38, 3}, ""},                               //25.4, 38.1, 2.54
+		{DimensionsInput{L: "0.02", W: "0.0197", H: "3937.01", Unit: "in"}, Dimensions{1, 1, MaxDimensionMillimetres}, ""}, //0.508, 0.50038, 100000.054
+		{DimensionsInput{L: "1", W: "1", H: "3937.03", Unit: "in"}, Dimensions{}, "dimensions.h field must be at most 100000 mm"},
+		{DimensionsInput{L: "1", W: "1", H: "1", Unit: "ft"}, Dimensions{}, `dimensions.l unit must be one of cm, in, mm, got "ft"`},
+	} {
+		dimensions, err := test.in.Millimetres()
+		if len(test.err) != 0 {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%+v: expected an error containing %q, got %+v and %v", test.in, test.err, dimensions, err)
+			}
+			continue
+		}
+		if err != nil || *dimensions != test.dimensions {
+			t.Errorf("%+v is %+v millimetres (%v), expected %+v", test.in, dimensions, err, test.dimensions)
+		}
+	}
+}
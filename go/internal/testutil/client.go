@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// Client is a client identity of an org on the network
+type Client struct {
+	MSPID   string
+	Name    string
+	Creator []byte // serialized identity, see newCreator
+
+	network *Network
+}
+
+// Invocation is a proposal of a client
+type Invocation struct {
+	Function    string
+	Args        []string
+	Transient   map[string][]byte
+	Peer        string            // MSP ID of the endorsing peer, the org of the client when empty
+	Decorations map[string][]byte // decorations added by the peer
+	Init        bool              // call Init instead of Invoke
+	Evaluate    bool              // do not commit the writes, as for a query
+
+	// Forge changes the headers of the signed proposal after the transaction ID and the
+	// binding were derived from them, to present a proposal that does not match them
+	Forge func(channelHeader *common.ChannelHeader, signatureHeader *common.SignatureHeader)
+}
+
+// Transaction is the outcome of an invocation
+type Transaction struct {
+	ID        string
+	Timestamp time.Time
+	Response  pb.Response
+	Events    []*pb.ChaincodeEvent
+	Committed bool
+	Writes    int // keys and validation parameters written or deleted, committed or not
+}
+
+// Invoke submits the function with the arguments
+func (c *Client) Invoke(function string, args ...string) pb.Response {
+	return c.Submit(Invocation{Function: function, Args: args}).Response
+}
+
+// InvokeTransient submits the function with the transient map and the arguments
+func (c *Client) InvokeTransient(function string, transient map[string][]byte, args ...string) pb.Response {
+	return c.Submit(Invocation{Function: function, Args: args, Transient: transient}).Response
+}
+
+// Query evaluates the function without committing anything
+func (c *Client) Query(function string, args ...string) pb.Response {
+	return c.Submit(Invocation{Function: function, Args: args, Evaluate: true}).Response
+}
+
+// Submit endorses the invocation on the peer and commits its writes when it succeeds
+func (c *Client) Submit(inv Invocation) *Transaction {
+	n := c.network
+	n.t.Helper()
+	peer := inv.Peer
+	if len(peer) == 0 {
+		peer = c.MSPID
+	}
+	err := os.Setenv("CORE_PEER_LOCALMSPID", peer)
+	if err != nil {
+		n.t.Fatalf("failed to set the MSP ID of the peer: %v", err)
+	}
+
+	n.txCount++
+	txTime := n.now
+	n.now = n.now.Add(time.Second)
+	txTimestamp := &timestamp.Timestamp{Seconds: txTime.Unix(), Nanos: int32(txTime.Nanosecond())}
+
+	// the transaction ID and the binding are derived from the nonce and the creator,
+	// as the SDKs and the peer do
+	nonceHash := sha256.Sum256([]byte{byte(n.txCount >> 24), byte(n.txCount >> 16), byte(n.txCount >> 8), byte(n.txCount)})
+	nonce := nonceHash[:24]
+	txIDHash := sha256.Sum256(append(append([]byte{}, nonce...), c.Creator...))
+	txID := hex.EncodeToString(txIDHash[:])
+
+	extension, err := proto.Marshal(&pb.ChaincodeHeaderExtension{ChaincodeId: &pb.ChaincodeID{Name: n.ChaincodeName}})
+	if err != nil {
+		n.t.Fatalf("failed to marshal chaincode header extension: %v", err)
+	}
+	channelHeader := &common.ChannelHeader{
+		Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+		ChannelId: n.ChannelID,
+		TxId:      txID,
+		Timestamp: txTimestamp,
+		Extension: extension,
+	}
+	signatureHeader := &common.SignatureHeader{Creator: c.Creator, Nonce: nonce}
+	epoch := make([]byte, 8)
+	binary.LittleEndian.PutUint64(epoch, channelHeader.Epoch)
+	binding := sha256.New()
+	binding.Write(nonce)
+	binding.Write(c.Creator)
+	binding.Write(epoch)
+
+	if inv.Forge != nil {
+		inv.Forge(channelHeader, signatureHeader)
+	}
+	signedProposal, err := newSignedProposal(channelHeader, signatureHeader)
+	if err != nil {
+		n.t.Fatalf("failed to create signed proposal: %v", err)
+	}
+
+	mock := shimtest.NewMockStub(n.ChaincodeName, n.cc)
+	mock.State = n.ledger.State
+	mock.Keys = n.ledger.Keys
+	mock.ChannelID = n.ChannelID
+	mock.TxID = txID
+	mock.TxTimestamp = txTimestamp
+	mock.Creator = c.Creator
+	if inv.Decorations != nil {
+		mock.Decorations = inv.Decorations
+	}
+
+	args := make([][]byte, 0, len(inv.Args)+1)
+	args = append(args, []byte(inv.Function))
+	for _, arg := range inv.Args {
+		args = append(args, []byte(arg))
+	}
+	stub := &Stub{
+		MockStub:       mock,
+		network:        n,
+		peerMSPID:      peer,
+		creatorMSPID:   c.MSPID,
+		args:           args,
+		transient:      inv.Transient,
+		binding:        binding.Sum(nil),
+		signedProposal: signedProposal,
+		stateWrites:    map[string][]byte{},
+		privateWrites:  map[string]map[string][]byte{},
+		policyWrites:   map[string]map[string][]byte{},
+	}
+
+	tx := &Transaction{ID: txID, Timestamp: txTime}
+	if inv.Init {
+		tx.Response = n.cc.Init(stub)
+	} else {
+		tx.Response = n.cc.Invoke(stub)
+	}
+	tx.Events = stub.events
+	tx.Writes = stub.writes()
+	if tx.Response.Status < shim.ERRORTHRESHOLD && !inv.Evaluate {
+		n.commit(stub)
+		tx.Committed = true
+	}
+	return tx
+}
+
+func newSignedProposal(channelHeader *common.ChannelHeader, signatureHeader *common.SignatureHeader) (*pb.SignedProposal, error) {
+	channelHeaderBytes, err := proto.Marshal(channelHeader)
+	if err != nil {
+		return nil, err
+	}
+	signatureHeaderBytes, err := proto.Marshal(signatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	headerBytes, err := proto.Marshal(&common.Header{ChannelHeader: channelHeaderBytes, SignatureHeader: signatureHeaderBytes})
+	if err != nil {
+		return nil, err
+	}
+	proposalBytes, err := proto.Marshal(&pb.Proposal{Header: headerBytes})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SignedProposal{ProposalBytes: proposalBytes}, nil
+}
+
+// Transient returns a transient map of the keys and values, given in pairs
+func Transient(keysAndValues ...string) map[string][]byte {
+	transient := map[string][]byte{}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		transient[keysAndValues[i]] = []byte(keysAndValues[i+1])
+	}
+	return transient
+}
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/pkg/attrmgr"
+	"github.com/hyperledger/fabric-protos-go/msp"
+)
+
+// newCreator returns the serialized identity of a client of the org: a self-signed
+// certificate with the common name and, when attrs is not empty, the attribute extension
+// of the Fabric CA that cid.GetAttributeValue reads
+func newCreator(mspID string, name string, attrs map[string]string) ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	subject := pkix.Name{CommonName: name, Organization: []string{mspID}}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      subject,
+		Issuer:       subject,
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Now().AddDate(10, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if len(attrs) != 0 {
+		err = attrmgr.New().AddAttributesToCert(&attrmgr.Attributes{Attrs: attrs}, template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add attributes: %v", err)
+		}
+		// the extension is added to the parsed extensions, which CreateCertificate ignores
+		template.ExtraExtensions = template.Extensions
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate of %s: %v", name, err)
+	}
+
+	return proto.Marshal(&msp.SerializedIdentity{
+		Mspid:   mspID,
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	})
+}
@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"errors"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+)
+
+// kvIterator iterates over a snapshot of key-value pairs sorted by key
+type kvIterator struct {
+	kvs    []*queryresult.KV
+	closed bool
+}
+
+// NewIterator returns an iterator over the pairs, which have to be sorted by key. It
+// serves stubs that feed a handler synthetic results without a network.
+func NewIterator(kvs []*queryresult.KV) shim.StateQueryIteratorInterface {
+	return &kvIterator{kvs: kvs}
+}
+
+// HasNext returns true while the iterator has pairs left
+func (it *kvIterator) HasNext() bool {
+	return !it.closed && len(it.kvs) != 0
+}
+
+// Next returns the next pair
+func (it *kvIterator) Next() (*queryresult.KV, error) {
+	if !it.HasNext() {
+		return nil, errors.New("iterator has no more results")
+	}
+	kv := it.kvs[0]
+	it.kvs = it.kvs[1:]
+	return kv, nil
+}
+
+// Close ends the iteration
+func (it *kvIterator) Close() error {
+	it.closed = true
+	return nil
+}
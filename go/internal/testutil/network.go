@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package testutil runs the chaincode in plain go test on a channel shared by several
+// organizations. Every invocation gets a stub of its own wrapping a shimtest.MockStub,
+// with the creator certificate of the submitting client and the MSP ID of the endorsing
+// peer. The stub enforces the collection membership of both: a peer outside a collection
+// cannot read the private data of others, and with memberOnlyRead or memberOnlyWrite a
+// client outside it cannot read or write. Writes only become visible once the invocation
+// succeeds and commits, as on a peer, so private data has no read-your-writes.
+//
+// shim.GetMSPID reads the MSP ID of the peer from CORE_PEER_LOCALMSPID, which every
+// invocation sets. Tests using a network must therefore not run in parallel.
+package testutil
+
+import (
+	"crypto/sha256"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+)
+
+// implicitCollectionPrefix prefixes the MSP ID in the name of the implicit collection of
+// an org, which exists without being configured and has the org as only member
+const implicitCollectionPrefix = "_implicit_org_"
+
+// DefaultChannelID and DefaultChaincodeName are the channel and chaincode a network is
+// created with
+const (
+	DefaultChannelID     = "mychannel"
+	DefaultChaincodeName = "privatearticles"
+)
+
+// CollectionConfig is the part of a private data collection definition the network enforces
+type CollectionConfig struct {
+	Name            string
+	Members         []string // MSP IDs of the member orgs
+	MemberOnlyRead  bool
+	MemberOnlyWrite bool
+}
+
+func (c *CollectionConfig) isMember(mspID string) bool {
+	for _, member := range c.Members {
+		if member == mspID {
+			return true
+		}
+	}
+	return false
+}
+
+// Network is a channel with the chaincode installed on a peer of every org. It keeps the
+// committed public state, private data and key-level endorsement policies.
+type Network struct {
+	ChannelID     string
+	ChaincodeName string
+
+	t           testing.TB
+	cc          shim.Chaincode
+	collections map[string]*CollectionConfig
+	ledger      *shimtest.MockStub           // committed public state
+	private     map[string]map[string][]byte // collection to key to committed value
+	policies    map[string]map[string][]byte // collection to key to validation parameter, "" for public state
+	now         time.Time
+	txCount     int
+}
+
+// NewNetwork deploys the chaincode on a channel with the collections. Transactions get
+// timestamps one second apart, starting at 2024-01-01T00:00:00Z.
+func NewNetwork(t testing.TB, cc shim.Chaincode, collections ...CollectionConfig) *Network {
+	n := &Network{
+		ChannelID:     DefaultChannelID,
+		ChaincodeName: DefaultChaincodeName,
+		t:             t,
+		cc:            cc,
+		collections:   map[string]*CollectionConfig{},
+		ledger:        shimtest.NewMockStub(DefaultChaincodeName, cc),
+		private:       map[string]map[string][]byte{},
+		policies:      map[string]map[string][]byte{},
+		now:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for i := range collections {
+		n.collections[collections[i].Name] = &collections[i]
+	}
+	return n
+}
+
+// Client creates a client of the org, with the attributes in its certificate
+func (n *Network) Client(mspID string, name string, attrs map[string]string) *Client {
+	n.t.Helper()
+	creator, err := newCreator(mspID, name, attrs)
+	if err != nil {
+		n.t.Fatalf("failed to create client %s of %s: %v", name, mspID, err)
+	}
+	return &Client{MSPID: mspID, Name: name, Creator: creator, network: n}
+}
+
+// Now returns the timestamp of the next transaction
+func (n *Network) Now() time.Time {
+	return n.now
+}
+
+// Advance moves the clock of the network forward
+func (n *Network) Advance(d time.Duration) {
+	n.now = n.now.Add(d)
+}
+
+// State returns the committed public value of the key
+func (n *Network) State(key string) []byte {
+	return n.ledger.State[key]
+}
+
+// PrivateData returns the committed value of the key in the collection, whatever org holds it
+func (n *Network) PrivateData(collection string, key string) []byte {
+	return n.private[collection][key]
+}
+
+// PrivateDataHash returns the SHA-256 of the committed value, nil when the key does not exist
+func (n *Network) PrivateDataHash(collection string, key string) []byte {
+	value, ok := n.private[collection][key]
+	if !ok {
+		return nil
+	}
+	hash := sha256.Sum256(value)
+	return hash[:]
+}
+
+// PrivateKeys returns the committed keys of the collection in order
+func (n *Network) PrivateKeys(collection string) []string {
+	keys := make([]string, 0, len(n.private[collection]))
+	for key := range n.private[collection] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PrivateDataValidationParameter returns the committed key-level endorsement policy of the
+// key in the collection
+func (n *Network) PrivateDataValidationParameter(collection string, key string) []byte {
+	return n.policies[collection][key]
+}
+
+// collection returns the definition of the collection, made up for implicit collections
+func (n *Network) collection(name string) *CollectionConfig {
+	if strings.HasPrefix(name, implicitCollectionPrefix) {
+		return &CollectionConfig{
+			Name:            name,
+			Members:         []string{strings.TrimPrefix(name, implicitCollectionPrefix)},
+			MemberOnlyRead:  true,
+			MemberOnlyWrite: true,
+		}
+	}
+	return n.collections[name]
+}
+
+// privateRange returns the committed pairs of the collection with startKey <= key < endKey,
+// endKey "" leaving the range open
+func (n *Network) privateRange(collection string, startKey string, endKey string) []*queryresult.KV {
+	var kvs []*queryresult.KV
+	for _, key := range n.PrivateKeys(collection) {
+		if key >= startKey && (len(endKey) == 0 || key < endKey) {
+			kvs = append(kvs, &queryresult.KV{Namespace: n.ChaincodeName, Key: key, Value: n.private[collection][key]})
+		}
+	}
+	return kvs
+}
+
+// commit applies the writes of a successful invocation. Deleting a key also deletes its
+// key-level endorsement policy, unless the invocation set a new one.
+func (n *Network) commit(stub *Stub) {
+	n.ledger.MockTransactionStart(stub.TxID)
+	for key, value := range stub.stateWrites {
+		if value == nil {
+			n.ledger.DelState(key)
+			n.setPolicy("", key, nil)
+		} else {
+			n.ledger.PutState(key, value)
+		}
+	}
+	n.ledger.MockTransactionEnd(stub.TxID)
+
+	for collection, writes := range stub.privateWrites {
+		if n.private[collection] == nil {
+			n.private[collection] = map[string][]byte{}
+		}
+		for key, value := range writes {
+			if value == nil {
+				delete(n.private[collection], key)
+				n.setPolicy(collection, key, nil)
+			} else {
+				n.private[collection][key] = value
+			}
+		}
+	}
+	for collection, policies := range stub.policyWrites {
+		for key, policy := range policies {
+			n.setPolicy(collection, key, policy)
+		}
+	}
+}
+
+func (n *Network) setPolicy(collection string, key string, policy []byte) {
+	if len(policy) == 0 {
+		delete(n.policies[collection], key)
+		return
+	}
+	if n.policies[collection] == nil {
+		n.policies[collection] = map[string][]byte{}
+	}
+	n.policies[collection][key] = policy
+}
@@ -0,0 +1,261 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// maxUnicodeRune closes the range of the keys of a partial composite key
+const maxUnicodeRune = "\U0010FFFF"
+
+// emptyKeySubstitute replaces an empty start key of a range, which keeps composite keys,
+// starting with 0x00, out of range queries as on a peer
+const emptyKeySubstitute = "\x01"
+
+// Stub is the stub of one invocation on the peer of an org. It reads the committed state
+// of the network and keeps its writes until the network commits them.
+type Stub struct {
+	*shimtest.MockStub
+
+	network        *Network
+	peerMSPID      string
+	creatorMSPID   string
+	args           [][]byte
+	transient      map[string][]byte
+	binding        []byte
+	signedProposal *pb.SignedProposal
+	events         []*pb.ChaincodeEvent
+
+	stateWrites   map[string][]byte            // key to value, nil once deleted
+	privateWrites map[string]map[string][]byte // collection to key to value, nil once deleted
+	policyWrites  map[string]map[string][]byte // collection to key to validation parameter, "" for public state
+}
+
+// writes returns the number of keys and validation parameters the invocation wrote or deleted
+func (s *Stub) writes() int {
+	count := len(s.stateWrites)
+	for _, writes := range s.privateWrites {
+		count += len(writes)
+	}
+	for _, writes := range s.policyWrites {
+		count += len(writes)
+	}
+	return count
+}
+
+// GetArgs returns the function name and the arguments
+func (s *Stub) GetArgs() [][]byte {
+	return s.args
+}
+
+// GetStringArgs returns the function name and the arguments as strings
+func (s *Stub) GetStringArgs() []string {
+	args := make([]string, len(s.args))
+	for i, arg := range s.args {
+		args[i] = string(arg)
+	}
+	return args
+}
+
+// GetFunctionAndParameters returns the function name and its arguments
+func (s *Stub) GetFunctionAndParameters() (string, []string) {
+	args := s.GetStringArgs()
+	if len(args) == 0 {
+		return "", []string{}
+	}
+	return args[0], args[1:]
+}
+
+// GetTransient returns the transient map of the proposal
+func (s *Stub) GetTransient() (map[string][]byte, error) {
+	return s.transient, nil
+}
+
+// GetBinding returns the binding of the proposal
+func (s *Stub) GetBinding() ([]byte, error) {
+	return s.binding, nil
+}
+
+// GetSignedProposal returns the signed proposal
+func (s *Stub) GetSignedProposal() (*pb.SignedProposal, error) {
+	return s.signedProposal, nil
+}
+
+// SetEvent records the event of the transaction
+func (s *Stub) SetEvent(name string, payload []byte) error {
+	if len(name) == 0 {
+		return errors.New("event name can not be empty string")
+	}
+	s.events = append(s.events, &pb.ChaincodeEvent{EventName: name, Payload: payload, TxId: s.TxID})
+	return nil
+}
+
+// InvokeChaincode fails, the network runs a single chaincode
+func (s *Stub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response {
+	return shim.Error(fmt.Sprintf("chaincode %s is not deployed on the test network", chaincodeName))
+}
+
+// PutState writes the value once the invocation commits
+func (s *Stub) PutState(key string, value []byte) error {
+	if len(key) == 0 {
+		return errors.New("key must not be an empty string")
+	}
+	s.stateWrites[key] = value
+	return nil
+}
+
+// DelState deletes the key once the invocation commits
+func (s *Stub) DelState(key string) error {
+	s.stateWrites[key] = nil
+	return nil
+}
+
+// SetStateValidationParameter sets the key-level endorsement policy of a public key
+func (s *Stub) SetStateValidationParameter(key string, ep []byte) error {
+	return s.SetPrivateDataValidationParameter("", key, ep)
+}
+
+// GetStateValidationParameter returns the committed key-level endorsement policy of a public key
+func (s *Stub) GetStateValidationParameter(key string) ([]byte, error) {
+	return s.network.policies[""][key], nil
+}
+
+// readableCollection returns the collection when the client may read it
+func (s *Stub) readableCollection(collection string) (*CollectionConfig, error) {
+	config := s.network.collection(collection)
+	if config == nil {
+		return nil, fmt.Errorf("collection %s could not be found", collection)
+	}
+	if config.MemberOnlyRead && !config.isMember(s.creatorMSPID) {
+		return nil, fmt.Errorf("tx creator does not have read access permission on privatedata in chaincodeName:%s collectionName: %s", s.network.ChaincodeName, collection)
+	}
+	return config, nil
+}
+
+// writableCollection returns the collection when the client may write it
+func (s *Stub) writableCollection(collection string) (*CollectionConfig, error) {
+	config := s.network.collection(collection)
+	if config == nil {
+		return nil, fmt.Errorf("collection %s could not be found", collection)
+	}
+	if config.MemberOnlyWrite && !config.isMember(s.creatorMSPID) {
+		return nil, fmt.Errorf("tx creator does not have write access permission on privatedata in chaincodeName:%s collectionName: %s", s.network.ChaincodeName, collection)
+	}
+	return config, nil
+}
+
+// GetPrivateData returns the committed value. A peer outside the collection only has the
+// hash, reading a key that exists fails there.
+func (s *Stub) GetPrivateData(collection string, key string) ([]byte, error) {
+	config, err := s.readableCollection(collection)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := s.network.private[collection][key]
+	if ok && !config.isMember(s.peerMSPID) {
+		return nil, fmt.Errorf("private data matching public hash version is not available: peer of %s is not a member of collection %s", s.peerMSPID, collection)
+	}
+	return value, nil
+}
+
+// GetPrivateDataHash returns the hash of the committed value, which every peer has
+func (s *Stub) GetPrivateDataHash(collection string, key string) ([]byte, error) {
+	if s.network.collection(collection) == nil {
+		return nil, fmt.Errorf("collection %s could not be found", collection)
+	}
+	return s.network.PrivateDataHash(collection, key), nil
+}
+
+// PutPrivateData writes the value once the invocation commits
+func (s *Stub) PutPrivateData(collection string, key string, value []byte) error {
+	if len(key) == 0 {
+		return errors.New("key must not be an empty string")
+	}
+	if len(value) == 0 {
+		return errors.New("value must not be empty")
+	}
+	return s.writePrivateData(collection, key, value)
+}
+
+// DelPrivateData deletes the key once the invocation commits
+func (s *Stub) DelPrivateData(collection string, key string) error {
+	return s.writePrivateData(collection, key, nil)
+}
+
+func (s *Stub) writePrivateData(collection string, key string, value []byte) error {
+	_, err := s.writableCollection(collection)
+	if err != nil {
+		return err
+	}
+	if s.privateWrites[collection] == nil {
+		s.privateWrites[collection] = map[string][]byte{}
+	}
+	s.privateWrites[collection][key] = value
+	return nil
+}
+
+// GetPrivateDataByRange iterates over the committed keys of the range, none on a peer
+// outside the collection
+func (s *Stub) GetPrivateDataByRange(collection string, startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
+	if len(startKey) == 0 {
+		startKey = emptyKeySubstitute
+	}
+	return s.privateDataIterator(collection, startKey, endKey)
+}
+
+// GetPrivateDataByPartialCompositeKey iterates over the committed keys starting with the
+// partial composite key, none on a peer outside the collection
+func (s *Stub) GetPrivateDataByPartialCompositeKey(collection string, objectType string, attributes []string) (shim.StateQueryIteratorInterface, error) {
+	partialKey, err := s.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return s.privateDataIterator(collection, partialKey, partialKey+maxUnicodeRune)
+}
+
+func (s *Stub) privateDataIterator(collection string, startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
+	config, err := s.readableCollection(collection)
+	if err != nil {
+		return nil, err
+	}
+	if !config.isMember(s.peerMSPID) {
+		return &kvIterator{}, nil
+	}
+	return &kvIterator{kvs: s.network.privateRange(collection, startKey, endKey)}, nil
+}
+
+// GetPrivateDataQueryResult fails, rich queries need CouchDB
+func (s *Stub) GetPrivateDataQueryResult(collection string, query string) (shim.StateQueryIteratorInterface, error) {
+	return nil, errors.New("rich queries are not supported by the test network")
+}
+
+// SetPrivateDataValidationParameter sets the key-level endorsement policy once the
+// invocation commits
+func (s *Stub) SetPrivateDataValidationParameter(collection string, key string, ep []byte) error {
+	if len(collection) != 0 && s.network.collection(collection) == nil {
+		return fmt.Errorf("collection %s could not be found", collection)
+	}
+	if s.policyWrites[collection] == nil {
+		s.policyWrites[collection] = map[string][]byte{}
+	}
+	s.policyWrites[collection][key] = ep
+	return nil
+}
+
+// GetPrivateDataValidationParameter returns the committed key-level endorsement policy
+func (s *Stub) GetPrivateDataValidationParameter(collection string, key string) ([]byte, error) {
+	if s.network.collection(collection) == nil {
+		return nil, fmt.Errorf("collection %s could not be found", collection)
+	}
+	return s.network.policies[collection][key], nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/logging"
+	"privatemarbles/internal/testutil"
+)
+
+// captureLogs writes the lines of the chaincode logger at or above the level to the
+// returned buffer until the end of the test
+func captureLogs(t *testing.T, level logging.Level) *bytes.Buffer {
+	var buffer bytes.Buffer
+	logging.Default().SetOutput(&buffer)
+	logging.Default().SetLevel(level)
+	t.Cleanup(func() {
+		logging.Default().SetOutput(os.Stderr)
+		logging.Default().SetLevel(logging.LevelInfo)
+	})
+	return &buffer
+}
+
+func TestLogsNeverContainPrice(t *testing.T) {
+	s := newScenario(t)
+	logs := captureLogs(t, logging.LevelDebug)
+
+	article := `{"name":"article1","color":"blue","size":35,"owner":"tom","price":987654321,"currency":"EUR","salt":"c2FsdHNhbHRzYWx0c2FsdHNhbHQ=","quantity":1}`
+	expectStatus(t, s.user1.InvokeTransient("initArticle", testutil.Transient("article", article)), shim.OK)
+	expectStatus(t, s.user1.InvokeTransient("updateArticlePrice", testutil.Transient("article_price", `{"name":"article1","price":123456789,"currency":"EUR"}`)), shim.OK)
+	expectStatus(t, s.user1.Query("readArticlePrivateDetails", "article1"), shim.OK)
+	expectStatus(t, s.user1.Query("getPriceHistory", "article1"), shim.OK)
+	// rejected inputs are not logged either
+	s.user1.InvokeTransient("updateArticlePrice", testutil.Transient("article_price", `{"name":"article1","price":555444333,"currency":"???"}`))
+	s.user1.InvokeTransient("initArticle", testutil.Transient("article", strings.Replace(article, `"size":35`, `"size":"big"`, 1)))
+
+	output := logs.String()
+	if !strings.Contains(output, "function=updateArticlePrice txId=") {
+		t.Fatalf("the invocations were not logged with their function and transaction ID:\n%s", output)
+	}
+	for _, private := range []string{"987654321", "123456789", "555444333", "c2FsdHNh", `"currency"`} {
+		if strings.Contains(output, private) {
+			t.Errorf("logs contain %s:\n%s", private, output)
+		}
+	}
+}
+
+func TestDebugDecorationLogsOneInvocation(t *testing.T) {
+	s := newScenario(t)
+	expectStatus(t, s.user1.InvokeTransient("initArticle", testutil.Transient("article", scenarioArticle)), shim.OK)
+	logs := captureLogs(t, logging.LevelInfo)
+
+	expectStatus(t, s.user1.Query("readArticle", "article1"), shim.OK)
+	if strings.Contains(logs.String(), "DEBUG") {
+		t.Errorf("an undecorated invocation logged at the debug level:\n%s", logs)
+	}
+
+	tx := s.user1.Submit(testutil.Invocation{
+		Function:    "readArticle",
+		Args:        []string{"article1"},
+		Evaluate:    true,
+		Decorations: map[string][]byte{logging.DebugDecoration: nil},
+	})
+	expectStatus(t, tx.Response, shim.OK)
+	output := logs.String()
+	if !strings.Contains(output, "DEBUG   [function=readArticle txId="+tx.ID+"] invoke is running readArticle with 1 args") ||
+		!strings.Contains(output, "arg 0: 8 bytes") {
+		t.Errorf("the decorated invocation was not logged at the debug level:\n%s", output)
+	}
+
+	logs.Reset()
+	expectStatus(t, s.user1.Query("readArticle", "article1"), shim.OK)
+	if strings.Contains(logs.String(), "DEBUG") {
+		t.Errorf("debug logging outlived the decorated invocation:\n%s", logs)
+	}
+}
@@ -7,497 +7,440 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/handlers"
+	"privatemarbles/internal/logging"
+	"privatemarbles/internal/model"
 )
 
-// ArticlesPrivateChaincode example Chaincode implementation
-type ArticlesPrivateChaincode struct {
+// stateless are the functions that neither read nor write state
+var stateless = map[string]bool{"ping": true, "metadata": true, "whoAmI": true}
+
+// publicAudited are the functions whose changes of articles are recorded in the public
+// audit trail, see getPublicAuditTrail: every function that creates or removes an article
+// or changes its owner or its price
+var publicAudited = map[string]bool{
+	"initArticle": true, "initArticles": true, "cloneArticle": true, "importArticle": true, "splitArticle": true, "mergeArticles": true,
+	"transferArticle": true, "transferArticleWithPrice": true, "transferShare": true, "acceptTransfer": true, "executeTransfer": true,
+	"swapArticles": true, "closeAuction": true, "acceptOffer": true, "renameArticle": true,
+	"updateArticlePrice": true, "addArticlePrivateDetails": true, "delete": true,
 }
 
-type article struct {
-	ObjectType string `json:"docType"` //docType is used to distinguish the various types of objects in state database
-	Name       string `json:"name"`    //the fieldtags are needed to keep case from bouncing around
-	Color      string `json:"color"`
-	Size       int    `json:"size"`
-	Owner      string `json:"owner"`
+// replayProtected are the functions checked against replayed proposals when
+// ARTICLE_REPLAY_PROTECTION is set, see WithReplayProtection
+var replayProtected = map[string]bool{"transferArticle": true, "delete": true, "purgeArticlePrivateDetails": true, "purgeDeletedArticles": true}
+
+// listQueries are the functions returning lists, which take the optional compress
+// argument, with their number of required arguments
+var listQueries = map[string]int{
+	"getArticlesByRange":              2,
+	"getArticlesByNamePrefix":         1,
+	"getArticlePrivateDetailsByRange": 2,
+	"queryArticles":                   1,
+	"getArticlesByOwner":              1,
+	"getArticlesByTag":                1,
+	"getArticlesByCategoryPrefix":     1,
+	"getArticlesByPriceRange":         2,
+	"getArticlesBySizeRange":          2,
+	"getArticlesForSale":              0,
+	"getArticlesByDocType":            1,
+	"getArticlesModifiedSince":        1,
+	"listOwners":                      0,
+	"listPublicArticles":              0,
 }
 
-type articlePrivateDetails struct {
-	ObjectType string `json:"docType"` //docType is used to distinguish the various types of objects in state database
-	Name       string `json:"name"`    //the fieldtags are needed to keep case from bouncing around
-	Price      int    `json:"price"`
-}
+// chaincodeName is the name the chaincode reports in its metadata
+const chaincodeName = "privatearticles"
 
-// Init initializes chaincode
-// ===========================
-func (t *ArticlesPrivateChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
-	return shim.Success(nil)
-}
+// version is the build of the chaincode reported in its metadata, set at build time with
+// go build -ldflags "-X main.version=1.2.0"
+var version = "dev"
 
-// Invoke - Our entry point for Invocations
-// ========================================
-func (t *ArticlesPrivateChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
-	function, args := stub.GetFunctionAndParameters()
-	fmt.Println("invoke is running " + function)
-
-	// Handle different functions
-	switch function {
-	case "initArticle":
-		//create a new article
-		return t.initArticle(stub, args)
-	case "readArticle":
-		//read a article
-		return t.readArticle(stub, args)
-	case "readArticlePrivateDetails":
-		//read a article private details
-		return t.readArticlePrivateDetails(stub, args)
-	case "transferArticle":
-		//change owner of a specific article
-		return t.transferArticle(stub, args)
-	case "delete":
-		//delete a article
-		return t.delete(stub, args)
-	case "getArticlesByRange":
-		//get articles based on range query
-		return t.getArticlesByRange(stub, args)
-	case "getArticleHash":
-		// get private data hash for collectionArticles
-		return t.getArticleHash(stub, args)
-	case "getArticlePrivateDetailsHash":
-		// get private data hash for collectionArticlePrivateDetails
-		return t.getArticlePrivateDetailsHash(stub, args)
-	default:
-		//error
-		fmt.Println("invoke did not find func: " + function)
-		return shim.Error("Received unknown function invocation")
-	}
+// ArticlesPrivateChaincode example Chaincode implementation
+type ArticlesPrivateChaincode struct {
+	cfg        *model.Config
+	functions  map[string]handlers.HandlerFunc // invoke functions by name, wrapped in the middleware
+	aclJSON    string                          // function ACL stored by Init when it is not passed as argument
+	colorsJSON string                          // color allow-list stored by Init when it is not passed as argument
 }
 
-// ============================================================
-// initArticle - create a new article, store into chaincode state
-// ============================================================
-func (t *ArticlesPrivateChaincode) initArticle(stub shim.ChaincodeStubInterface, args []string) pb.Response {
-	var err error
-
-	type articleTransientInput struct {
-		Name  string `json:"name"` //the fieldtags are needed to keep case from bouncing around
-		Color string `json:"color"`
-		Size  int    `json:"size"`
-		Owner string `json:"owner"`
-		Price int    `json:"price"`
-	}
-
-	// ==== Input sanitation ====
-	fmt.Println("- start init article")
-
-	if len(args) != 0 {
-		return shim.Error("Incorrect number of arguments. Private article data must be passed in transient map.")
-	}
-
-	transMap, err := stub.GetTransient()
-	if err != nil {
-		return shim.Error("Error getting transient: " + err.Error())
-	}
-
-	articleJsonBytes, ok := transMap["article"]
-	if !ok {
-		return shim.Error("article must be a key in the transient map")
-	}
-
-	if len(articleJsonBytes) == 0 {
-		return shim.Error("article value in the transient map must be a non-empty JSON string")
-	}
-
-	var articleInput articleTransientInput
-	err = json.Unmarshal(articleJsonBytes, &articleInput)
-	if err != nil {
-		return shim.Error("Failed to decode JSON of: " + string(articleJsonBytes))
-	}
-
-	if len(articleInput.Name) == 0 {
-		return shim.Error("name field must be a non-empty string")
-	}
-	if len(articleInput.Color) == 0 {
-		return shim.Error("color field must be a non-empty string")
-	}
-	if articleInput.Size <= 0 {
-		return shim.Error("size field must be a positive integer")
+// newArticlesPrivateChaincode creates the chaincode with the configuration taken from
+// the environment, falling back to the defaults for unset variables:
+//
+//	ARTICLES_COLLECTION                 name of the articles collection
+//	ARTICLE_PRIVATE_DETAILS_COLLECTION  name of the article private details collection
+//	ARTICLE_NAME_MAX_LENGTH             maximum length in bytes of article names
+//	ARTICLE_DOC_TYPES                   comma separated doc types allowed besides "article"
+//	ARTICLE_MAX_RESULTS                 default and maximum number of results of getArticlesByRange
+//	ARTICLE_MAX_TRANSIENT_SIZE          maximum size in bytes of a transient map value, 1 MiB by default
+//	ARTICLE_MAX_BATCH_SIZE              maximum number of names of the batch reads, 100 by default
+//	PARTICIPANTS_CHAINCODE              participant directory chaincode checked by transferArticle
+//	ARTICLE_LOG_LEVEL                   DEBUG, INFO, WARNING or ERROR, INFO by default
+//	ARTICLE_FUNCTION_ACL                JSON object of function names to allowed MSP IDs, stored by Init
+//	ARTICLE_ALLOWED_COLORS              JSON array of the colors new articles may have, stored by Init
+//	ARTICLE_PRICE_FORMAT                "number" or "string", the JSON type of prices in responses
+//	ARTICLE_NEGOTIATION_COLLECTIONS     "implicit" or "bilateral", the collections of negotiated prices
+//	ARTICLE_REPLAY_PROTECTION           "off", "binding" or "nonce", the replay checks of the replayProtected functions, off by default
+//	ARTICLE_CHAINCODE_NAME              chaincode name proposals must address under replay protection
+//	ARTICLE_NONCE_WINDOW_SECONDS        time during which a request nonce cannot be used again, 300 by default
+//	ARTICLES_DEV_MODE                   "true" enables loadSampleData, never set it in production
+func newArticlesPrivateChaincode() (*ArticlesPrivateChaincode, error) {
+	cfg := model.DefaultConfig()
+	if name, ok := os.LookupEnv("ARTICLES_COLLECTION"); ok {
+		cfg.CollectionArticles = name
+	}
+	if name, ok := os.LookupEnv("ARTICLE_PRIVATE_DETAILS_COLLECTION"); ok {
+		cfg.CollectionArticlePrivateDetails = name
+	}
+	if maxLength, ok := os.LookupEnv("ARTICLE_NAME_MAX_LENGTH"); ok {
+		n, err := strconv.Atoi(maxLength)
+		if err != nil {
+			return nil, fmt.Errorf("ARTICLE_NAME_MAX_LENGTH must be an integer: %v", err)
+		}
+		cfg.MaxNameLength = n
 	}
-	if len(articleInput.Owner) == 0 {
-		return shim.Error("owner field must be a non-empty string")
+	if maxResults, ok := os.LookupEnv("ARTICLE_MAX_RESULTS"); ok {
+		n, err := strconv.Atoi(maxResults)
+		if err != nil {
+			return nil, fmt.Errorf("ARTICLE_MAX_RESULTS must be an integer: %v", err)
+		}
+		cfg.MaxResults = n
 	}
-	if articleInput.Price <= 0 {
-		return shim.Error("price field must be a positive integer")
+	if maxTransientSize, ok := os.LookupEnv("ARTICLE_MAX_TRANSIENT_SIZE"); ok {
+		n, err := strconv.Atoi(maxTransientSize)
+		if err != nil {
+			return nil, fmt.Errorf("ARTICLE_MAX_TRANSIENT_SIZE must be an integer: %v", err)
+		}
+		cfg.MaxTransientSize = n
 	}
-
-	// ==== Check if article already exists ====
-	articleAsBytes, err := stub.GetPrivateData("collectionArticles", articleInput.Name)
-	if err != nil {
-		return shim.Error("Failed to get article: " + err.Error())
-	} else if articleAsBytes != nil {
-		fmt.Println("This article already exists: " + articleInput.Name)
-		return shim.Error("This article already exists: " + articleInput.Name)
+	if maxBatchSize, ok := os.LookupEnv("ARTICLE_MAX_BATCH_SIZE"); ok {
+		n, err := strconv.Atoi(maxBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("ARTICLE_MAX_BATCH_SIZE must be an integer: %v", err)
+		}
+		cfg.MaxBatchSize = n
 	}
-
-	// ==== Create article object and marshal to JSON ====
-	article := &article{
-		ObjectType: "article",
-		Name:       articleInput.Name,
-		Color:      articleInput.Color,
-		Size:       articleInput.Size,
-		Owner:      articleInput.Owner,
+	if docTypes, ok := os.LookupEnv("ARTICLE_DOC_TYPES"); ok {
+		cfg.DocTypes = strings.Split(docTypes, ",")
 	}
-	articleJSONasBytes, err := json.Marshal(article)
-	if err != nil {
-		return shim.Error(err.Error())
+	cfg.ParticipantsChaincode = os.Getenv("PARTICIPANTS_CHAINCODE")
+	if priceFormat, ok := os.LookupEnv("ARTICLE_PRICE_FORMAT"); ok {
+		if priceFormat != model.PriceFormatNumber && priceFormat != model.PriceFormatString {
+			return nil, fmt.Errorf("ARTICLE_PRICE_FORMAT must be %s or %s, got %q", model.PriceFormatNumber, model.PriceFormatString, priceFormat)
+		}
+		cfg.PriceFormat = priceFormat
 	}
-
-	// === Save article to state ===
-	err = stub.PutPrivateData("collectionArticles", articleInput.Name, articleJSONasBytes)
-	if err != nil {
-		return shim.Error(err.Error())
+	if negotiationCollections, ok := os.LookupEnv("ARTICLE_NEGOTIATION_COLLECTIONS"); ok {
+		if negotiationCollections != model.NegotiationImplicit && negotiationCollections != model.NegotiationBilateral {
+			return nil, fmt.Errorf("ARTICLE_NEGOTIATION_COLLECTIONS must be %s or %s, got %q", model.NegotiationImplicit, model.NegotiationBilateral, negotiationCollections)
+		}
+		cfg.NegotiationCollections = negotiationCollections
 	}
-
-	// ==== Create article private details object with price, marshal to JSON, and save to state ====
-	articlePrivateDetails := &articlePrivateDetails{
-		ObjectType: "articlePrivateDetails",
-		Name:       articleInput.Name,
-		Price:      articleInput.Price,
+	if replayProtection, ok := os.LookupEnv("ARTICLE_REPLAY_PROTECTION"); ok {
+		if replayProtection != model.ReplayProtectionOff && replayProtection != model.ReplayProtectionBinding && replayProtection != model.ReplayProtectionNonce {
+			return nil, fmt.Errorf("ARTICLE_REPLAY_PROTECTION must be %s, %s or %s, got %q", model.ReplayProtectionOff, model.ReplayProtectionBinding, model.ReplayProtectionNonce, replayProtection)
+		}
+		cfg.ReplayProtection = replayProtection
 	}
-	articlePrivateDetailsBytes, err := json.Marshal(articlePrivateDetails)
-	if err != nil {
-		return shim.Error(err.Error())
+	cfg.ChaincodeName = os.Getenv("ARTICLE_CHAINCODE_NAME")
+	if nonceWindow, ok := os.LookupEnv("ARTICLE_NONCE_WINDOW_SECONDS"); ok {
+		n, err := strconv.Atoi(nonceWindow)
+		if err != nil {
+			return nil, fmt.Errorf("ARTICLE_NONCE_WINDOW_SECONDS must be an integer: %v", err)
+		}
+		if n <= 0 {
+			return nil, fmt.Errorf("ARTICLE_NONCE_WINDOW_SECONDS must be a positive number of seconds, got %d", n)
+		}
+		cfg.NonceWindowSeconds = n
 	}
-	err = stub.PutPrivateData("collectionArticlePrivateDetails", articleInput.Name, articlePrivateDetailsBytes)
-	if err != nil {
-		return shim.Error(err.Error())
+	if devMode, ok := os.LookupEnv("ARTICLES_DEV_MODE"); ok {
+		b, err := strconv.ParseBool(devMode)
+		if err != nil {
+			return nil, fmt.Errorf("ARTICLES_DEV_MODE must be true or false: %v", err)
+		}
+		cfg.DevMode = b
 	}
-
-	//  ==== Index the article to enable color-based range queries, e.g. return all blue articles ====
-	//  An 'index' is a normal key/value entry in state.
-	//  The key is a composite key, with the elements that you want to range query on listed first.
-	//  In our case, the composite key is based on indexName~color~name.
-	//  This will enable very efficient state range queries based on composite keys matching indexName~color~*
-	indexName := "color~name"
-	colorNameIndexKey, err := stub.CreateCompositeKey(indexName, []string{article.Color, article.Name})
-	if err != nil {
-		return shim.Error(err.Error())
+	if levelName, ok := os.LookupEnv("ARTICLE_LOG_LEVEL"); ok {
+		level, err := logging.ParseLevel(levelName)
+		if err != nil {
+			return nil, fmt.Errorf("ARTICLE_LOG_LEVEL: %v", err)
+		}
+		logging.Default().SetLevel(level)
+	}
+
+	cc := &ArticlesPrivateChaincode{cfg: cfg, aclJSON: os.Getenv("ARTICLE_FUNCTION_ACL"), colorsJSON: os.Getenv("ARTICLE_ALLOWED_COLORS")}
+	cc.functions = map[string]handlers.HandlerFunc{
+		"initArticle":                       handlers.InitArticle,                       //create a new article
+		"initArticles":                      handlers.InitArticles,                      //create several articles, optionally gzip-compressed
+		"selfTest":                          handlers.SelfTest,                          //write, read back and delete a temporary article
+		"loadSampleData":                    handlers.LoadSampleData,                    //create the sample articles, only with ARTICLES_DEV_MODE=true
+		"readArticle":                       handlers.ReadArticle,                       //read a article
+		"getArticleBySKU":                   handlers.GetArticleBySKU,                   //get the article with an SKU
+		"readArticlePrivateDetails":         handlers.ReadArticlePrivateDetails,         //read a article private details
+		"readMultiplePrivateDetails":        handlers.ReadMultiplePrivateDetails,        //read the private details of several articles at once
+		"readArticleSummary":                handlers.ReadArticleSummary,                //read the public summary of a article
+		"listPublicArticles":                handlers.ListPublicArticles,                //get the public summaries of the articles
+		"getArticlePublicHistory":           handlers.GetArticlePublicHistory,           //get when the public summary of a article was written and deleted
+		"transferArticle":                   handlers.TransferArticle,                   //change owner of a specific article
+		"transferArticleWithPrice":          handlers.TransferArticleWithPrice,          //change owner and price of a article in one transaction
+		"transferShare":                     handlers.TransferShare,                     //pass on a percentage of a article to a co-owner
+		"proposeTransfer":                   handlers.ProposeTransfer,                   //propose a transfer the receiving org has to accept
+		"acceptTransfer":                    handlers.AcceptTransfer,                    //accept and complete a transfer proposed to the org
+		"rejectTransfer":                    handlers.RejectTransfer,                    //reject a transfer proposed to the org
+		"cancelTransfer":                    handlers.CancelTransfer,                    //withdraw an expired transfer proposal
+		"initiateTransfer":                  handlers.InitiateTransfer,                  //record a transfer that a list of orgs has to approve
+		"approveTransfer":                   handlers.ApproveTransfer,                   //approve an initiated transfer for the org
+		"executeTransfer":                   handlers.ExecuteTransfer,                   //complete an initiated transfer approved by every listed org
+		"cancelInitiatedTransfer":           handlers.CancelInitiatedTransfer,           //withdraw an initiated transfer and its approvals
+		"addArticlePrivateDetails":          handlers.AddArticlePrivateDetails,          //add the price of a article created without one
+		"grantDetailsAccess":                handlers.GrantDetailsAccess,                //let another org read the private details of an article until a deadline
+		"revokeDetailsAccess":               handlers.RevokeDetailsAccess,               //withdraw the access of another org to the private details of an article
+		"grantPriceAccess":                  handlers.GrantPriceAccess,                  //let another client read the price of a article
+		"updateArticlePrice":                handlers.UpdateArticlePrice,                //change the price of a article
+		"setArticleForSale":                 handlers.SetArticleForSale,                 //list a article for sale or take it off sale
+		"swapArticles":                      handlers.SwapArticles,                      //exchange the owners of two articles
+		"agreeToTransfer":                   handlers.AgreeToTransfer,                   //record the buyer's agreement to a transfer in its implicit collection
+		"setNegotiatedPrice":                handlers.SetNegotiatedPrice,                //record the price a buyer org negotiated with the owner org
+		"readNegotiatedPrice":               handlers.ReadNegotiatedPrice,               //read the price a buyer org negotiated with the owner org
+		"openAuction":                       handlers.OpenAuction,                       //put a article up for auction with sealed bids
+		"placeBid":                          handlers.PlaceBid,                          //place a sealed bid in the implicit collection of the bidder org
+		"revealBid":                         handlers.RevealBid,                         //reveal a sealed bid after the close of the auction
+		"closeAuction":                      handlers.CloseAuction,                      //transfer a article to the highest revealed bid
+		"makeOffer":                         handlers.MakeOffer,                         //offer to buy a article at a sealed price until an expiry time
+		"acceptOffer":                       handlers.AcceptOffer,                       //transfer a article at the price of an unexpired offer
+		"certifyArticle":                    handlers.CertifyArticle,                    //record the certification of a article by a certifier org
+		"revokeCertification":               handlers.RevokeCertification,               //remove the certification of a article by the org
+		"raiseDispute":                      handlers.RaiseDispute,                      //contest a article, blocking its transfer and deletion
+		"resolveDispute":                    handlers.ResolveDispute,                    //close the open dispute of a article with an outcome
+		"addArticleAttachment":              handlers.AddArticleAttachment,              //anchor the hash of an off-chain document to a article
+		"addArticleTag":                     handlers.AddArticleTag,                     //tag a article
+		"removeArticleTag":                  handlers.RemoveArticleTag,                  //remove a tag of a article
+		"lockArticle":                       handlers.LockArticle,                       //reserve a article for the submitting org
+		"unlockArticle":                     handlers.UnlockArticle,                     //release the lock of a article
+		"leaseArticle":                      handlers.LeaseArticle,                      //rent a article out until an end date, keeping its owner
+		"endLease":                          handlers.EndLease,                          //end the lease of a article
+		"cloneArticle":                      handlers.CloneArticle,                      //create a new article from an existing one used as template
+		"renameArticle":                     handlers.RenameArticle,                     //move a article to a new name, leaving a redirect under the old one
+		"exportArticle":                     handlers.ExportArticle,                     //package a article for the import on another channel
+		"importArticle":                     handlers.ImportArticle,                     //create a article from the package exported on another channel
+		"splitArticle":                      handlers.SplitArticle,                      //move part of the quantity of a article into a new article
+		"mergeArticles":                     handlers.MergeArticles,                     //combine two articles of the same color and owner
+		"delete":                            handlers.Delete,                            //delete a article
+		"retireArticle":                     handlers.RetireArticle,                     //mark a destroyed article as retired, keeping its record
+		"purgeDeletedArticles":              handlers.PurgeDeletedArticles,              //remove the tombstones of soft-deleted articles
+		"recalculateCounts":                 handlers.RecalculateCounts,                 //rebuild the article counts of the owners
+		"deleteArticlePrivateDetailsOnly":   handlers.DeleteArticlePrivateDetailsOnly,   //delete the private details of a article but keep the article
+		"purgeArticlePrivateDetails":        handlers.PurgeArticlePrivateDetails,        //purge the private details of a article from the peers
+		"getArticlesByRange":                handlers.GetArticlesByRange,                //get articles based on range query
+		"getArticlesByNamePrefix":           handlers.GetArticlesByNamePrefix,           //get articles whose name starts with a prefix
+		"getArticlePrivateDetailsByRange":   handlers.GetArticlePrivateDetailsByRange,   //get article private details based on range query
+		"queryArticles":                     handlers.QueryArticles,                     //get articles with a CouchDB rich query
+		"getArticlesByOwner":                handlers.GetArticlesByOwner,                //get articles of a specific owner using the owner~name index
+		"getArticlesByTag":                  handlers.GetArticlesByTag,                  //get articles with a tag using the tag~name index
+		"getArticlesByCategoryPrefix":       handlers.GetArticlesByCategoryPrefix,       //get articles of a category subtree using the category~name index
+		"getArticlesByPriceRange":           handlers.GetArticlesByPriceRange,           //get articles priced within a range using the price~name index
+		"getArticlesBySizeRange":            handlers.GetArticlesBySizeRange,            //get articles within a size range using the size~name index
+		"getArticlesForSale":                handlers.GetArticlesForSale,                //get articles listed for sale using the forsale~name index
+		"getArticlesByDocType":              handlers.GetArticlesByDocType,              //get all objects of a doc type
+		"getArticlesModifiedSince":          handlers.GetArticlesModifiedSince,          //get articles changed at or after a timestamp
+		"getArticleAuditTrail":              handlers.GetArticleAuditTrail,              //get the audit records of a article
+		"getPublicAuditTrail":               handlers.GetPublicAuditTrail,               //get the public record of the changes of a article
+		"getOwnershipHistory":               handlers.GetOwnershipHistory,               //get the previous owners of a article
+		"getPriceHistory":                   handlers.GetPriceHistory,                   //get the previous prices of a article
+		"getLeaseHistory":                   handlers.GetLeaseHistory,                   //get the leases of a article
+		"getPriceStatistics":                handlers.GetPriceStatistics,                //get the minimum, maximum and average price of all articles
+		"findDuplicateArticles":             handlers.FindDuplicateArticles,             //get the groups of articles with the same color, size and SKU
+		"getCollectionSummary":              handlers.GetCollectionSummary,              //get the number of articles per color and for sale
+		"getOwnerArticleCount":              handlers.GetOwnerArticleCount,              //get the number of articles of an owner from its counters
+		"getCertifications":                 handlers.GetCertifications,                 //get the certifications of a article
+		"exportProvenance":                  handlers.ExportProvenance,                  //get the record, history, certifications and hashes of a article in one document
+		"getDisputes":                       handlers.GetDisputes,                       //get the disputes of a article
+		"listOffers":                        handlers.ListOffers,                        //get the offers made on a article and their expiry
+		"listArticleAttachments":            handlers.ListArticleAttachments,            //get the attachments of a article
+		"verifyAttachment":                  handlers.VerifyAttachment,                  //compare the hash of a document with the one attached to a article
+		"articleExists":                     handlers.ArticleExists,                     //check whether a article exists
+		"verifyArticleProperties":           handlers.VerifyArticleProperties,           //verify claimed article properties against the private data hash
+		"verifyArticleIntegrity":            handlers.VerifyArticleIntegrity,            //verify a full private document against the private data hash
+		"resetArticleEndorsementPolicy":     handlers.ResetArticleEndorsementPolicy,     //remove the key-level endorsement policies of a article, admins only
+		"getPrivateDataValidationParameter": handlers.GetPrivateDataValidationParameter, //get the decoded key-level endorsement policy of a article in a collection
+		"getArticleEndorsementPolicy":       handlers.GetArticleEndorsementPolicy,       //get the key-level endorsement policy of a article
+		"getArticleHash":                    handlers.GetArticleHash,                    //get private data hash for collectionArticles
+		"computeArticleHash":                handlers.ComputeArticleHash,                //compute the private data hash of article properties
+		"getArticleHashes":                  handlers.GetArticleHashes,                  //get the private data hashes of several articles at once
+		"getArticlePrivateDetailsHash":      handlers.GetArticlePrivateDetailsHash,      //get private data hash for collectionArticlePrivateDetails
+		"ensureIndexes":                     handlers.EnsureIndexes,                     //check that the CouchDB indexes of the articles collection are deployed
+		"checkCollectionConsistency":        handlers.CheckCollectionConsistency,        //list articles without private details and private details without article
+		"registerOwner":                     handlers.RegisterOwner,                     //add an owner to the owner registry
+		"deactivateOwner":                   handlers.DeactivateOwner,                   //deactivate an owner of the owner registry
+		"listOwners":                        handlers.ListOwners,                        //list the owners of the owner registry
+		"migrateArticles":                   handlers.MigrateArticles,                   //upgrade articles to the current schema version
+		"recategorizeArticles":              handlers.RecategorizeArticles,              //move the articles of a category subtree to another category
+		"reindexArticles":                   handlers.ReindexArticles,                   //rebuild the composite key indexes of the articles
+		"setAllowedColors":                  handlers.SetAllowedColors,                  //set the colors new articles may have
+		"getAllowedColors":                  handlers.GetAllowedColors,                  //get the colors new articles may have
+		"setFunctionACL":                    handlers.SetFunctionACL,                    //set the MSP IDs allowed to call a function
+		"getFunctionACL":                    handlers.GetFunctionACL,                    //get the MSP IDs allowed to call the functions
+		"whoAmI":                            handlers.WhoAmI,                            //get the identity of the caller as the chaincode sees it
+		"ping":                              handlers.Ping,                              //check that the chaincode is alive
+		"metadata":                          cc.metadata,                                //get the chaincode version, functions and collections
+	}
+	for function, handler := range cc.functions {
+		if minArgs, ok := listQueries[function]; ok {
+			handler = handlers.WithCompression(minArgs, handler)
+		}
+		if publicAudited[function] {
+			handler = handlers.WithPublicAudit(function, handler)
+		}
+		if replayProtected[function] {
+			handler = handlers.WithReplayProtection(handler)
+		}
+		// ping, metadata and whoAmI must not touch the state, not even to read the ACL
+		if !stateless[function] {
+			handler = handlers.WithFunctionACL(function, handler)
+		}
+		cc.functions[function] = handlers.Wrap(function, handler)
 	}
-	//  Save index entry to state. Only the key name is needed, no need to store a duplicate copy of the article.
-	//  Note - passing a 'nil' value will effectively delete the key from state, therefore we pass null character as value
-	value := []byte{0x00}
-	stub.PutPrivateData("collectionArticles", colorNameIndexKey, value)
-
-	// ==== Article saved and indexed. Return success ====
-	fmt.Println("- end init article")
-	return shim.Success(nil)
+	return cc, nil
 }
 
-// ===============================================
-// readArticle - read a article from chaincode state
-// ===============================================
-func (t *ArticlesPrivateChaincode) readArticle(stub shim.ChaincodeStubInterface, args []string) pb.Response {
-	var name, jsonResp string
-	var err error
-
-	if len(args) != 1 {
-		return shim.Error("Incorrect number of arguments. Expecting name of the article to query")
+// Init initializes chaincode
+// Optional args are the function ACL as JSON object of function names to allowed MSP IDs
+// and the allowed colors as JSON array, empty ones falling back to the environment. Both are kept in public state,
+// an upgrade with new ones replaces them. The rest of the configuration comes from the
+// environment only: Init only runs on the peers endorsing the init transaction, anything
+// it kept in memory would differ between peers.
+// ===========================
+func (t *ArticlesPrivateChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	_, args := stub.GetFunctionAndParameters()
+
+	aclJSON := t.aclJSON
+	colorsJSON := t.colorsJSON
+	switch len(args) {
+	case 0:
+	case 1, 2:
+		if len(args[0]) != 0 {
+			aclJSON = args[0]
+		}
+		if len(args) == 2 && len(args[1]) != 0 {
+			colorsJSON = args[1]
+		}
+	default:
+		return shim.Error("Incorrect number of arguments. Expecting no arguments or an optional function ACL and optional allowed colors")
 	}
 
-	name = args[0]
-	valAsbytes, err := stub.GetPrivateData("collectionArticles", name) //get the article from chaincode state
+	err := t.cfg.Validate()
 	if err != nil {
-		jsonResp = "{\"Error\":\"Failed to get state for " + name + ": " + err.Error() + "\"}"
-		return shim.Error(jsonResp)
-	} else if valAsbytes == nil {
-		jsonResp = "{\"Error\":\"Article does not exist: " + name + "\"}"
-		return shim.Error(jsonResp)
+		return shim.Error("Invalid chaincode configuration: " + err.Error())
 	}
 
-	return shim.Success(valAsbytes)
-}
-
-// ===============================================
-// readArticlereadArticlePrivateDetails - read a article private details from chaincode state
-// ===============================================
-func (t *ArticlesPrivateChaincode) readArticlePrivateDetails(stub shim.ChaincodeStubInterface, args []string) pb.Response {
-	var name, jsonResp string
-	var err error
-
-	if len(args) != 1 {
-		return shim.Error("Incorrect number of arguments. Expecting name of the article to query")
+	if len(aclJSON) != 0 {
+		err = handlers.InitFunctionACL(stub, t.cfg, aclJSON)
+		if err != nil {
+			return shim.Error("Invalid function ACL: " + err.Error())
+		}
 	}
 
-	name = args[0]
-	valAsbytes, err := stub.GetPrivateData("collectionArticlePrivateDetails", name) //get the article private details from chaincode state
-	if err != nil {
-		jsonResp = "{\"Error\":\"Failed to get private details for " + name + ": " + err.Error() + "\"}"
-		return shim.Error(jsonResp)
-	} else if valAsbytes == nil {
-		jsonResp = "{\"Error\":\"Article private details does not exist: " + name + "\"}"
-		return shim.Error(jsonResp)
+	if len(colorsJSON) != 0 {
+		err = handlers.InitAllowedColors(stub, t.cfg, colorsJSON)
+		if err != nil {
+			return shim.Error("Invalid allowed colors: " + err.Error())
+		}
 	}
 
-	return shim.Success(valAsbytes)
+	return shim.Success(nil)
 }
 
-// ===============================================
-// getArticleHash - get article private data hash for collectionArticles from chaincode state
-// ===============================================
-func (t *ArticlesPrivateChaincode) getArticleHash(stub shim.ChaincodeStubInterface, args []string) pb.Response {
-	var name, jsonResp string
-	var err error
-
-	if len(args) != 1 {
-		return shim.Error("Incorrect number of arguments. Expecting name of the article to query")
+// Invoke - Our entry point for Invocations
+// ========================================
+func (t *ArticlesPrivateChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	function, args := stub.GetFunctionAndParameters()
+	logger := logging.ForInvocation(function, stub.GetTxID(), stub.GetDecorations())
+	logger.Debugf("invoke is running %s with %d args", function, len(args))
+	if logger.Enabled(logging.LevelDebug) {
+		// sizes only, the values may be private
+		for i, arg := range args {
+			logger.Debugf("arg %d: %d bytes", i, len(arg))
+		}
+		transMap, err := stub.GetTransient()
+		if err == nil {
+			for key, value := range transMap {
+				logger.Debugf("transient %s: %d bytes", key, len(value))
+			}
+		}
 	}
 
-	name = args[0]
-	valAsbytes, err := stub.GetPrivateDataHash("collectionArticles", name)
-	if err != nil {
-		jsonResp = "{\"Error\":\"Failed to get article private data hash for " + name + "\"}"
-		return shim.Error(jsonResp)
-	} else if valAsbytes == nil {
-		jsonResp = "{\"Error\":\"Article private article data hash does not exist: " + name + "\"}"
-		return shim.Error(jsonResp)
+	// Handle different functions
+	invoke, ok := t.functions[function]
+	if !ok {
+		//error
+		logger.Warningf("invoke did not find func: %s", function)
+		return shim.Error("Received unknown function invocation " + function + ". Available functions: " + strings.Join(t.functionNames(), ", "))
 	}
-
-	return shim.Success(valAsbytes)
+	return invoke(stub, t.cfg, args)
 }
 
-// ===============================================
-// getArticlePrivateDetailsHash - get article private data hash for collectionArticlePrivateDetails from chaincode state
-// ===============================================
-func (t *ArticlesPrivateChaincode) getArticlePrivateDetailsHash(stub shim.ChaincodeStubInterface, args []string) pb.Response {
-	var name, jsonResp string
-	var err error
-
-	if len(args) != 1 {
-		return shim.Error("Incorrect number of arguments. Expecting name of the article to query")
+// functionNames returns the names of the invoke functions in alphabetical order
+func (t *ArticlesPrivateChaincode) functionNames() []string {
+	names := make([]string, 0, len(t.functions))
+	for name := range t.functions {
+		names = append(names, name)
 	}
-
-	name = args[0]
-	valAsbytes, err := stub.GetPrivateDataHash("collectionArticlePrivateDetails", name)
-	if err != nil {
-		jsonResp = "{\"Error\":\"Failed to get article private details hash for " + name + ": " + err.Error() + "\"}"
-		return shim.Error(jsonResp)
-	} else if valAsbytes == nil {
-		jsonResp = "{\"Error\":\"Article private details hash does not exist: " + name + "\"}"
-		return shim.Error(jsonResp)
-	}
-
-	return shim.Success(valAsbytes)
+	sort.Strings(names)
+	return names
 }
 
-// ==================================================
-// delete - remove a article key/value pair from state
-// ==================================================
-func (t *ArticlesPrivateChaincode) delete(stub shim.ChaincodeStubInterface, args []string) pb.Response {
-	fmt.Println("- start delete article")
-
-	type articleDeleteTransientInput struct {
-		Name string `json:"name"`
-	}
-
+// metadata returns the name and version of the chaincode, its invoke functions and
+// the collections it is configured with, without touching the state
+func (t *ArticlesPrivateChaincode) metadata(stub shim.ChaincodeStubInterface, cfg *model.Config, args []string) pb.Response {
 	if len(args) != 0 {
-		return shim.Error("Incorrect number of arguments. Private article name must be passed in transient map.")
+		return shim.Error("Incorrect number of arguments. Expecting no arguments")
 	}
 
-	transMap, err := stub.GetTransient()
-	if err != nil {
-		return shim.Error("Error getting transient: " + err.Error())
-	}
-
-	articleDeleteJsonBytes, ok := transMap["article_delete"]
-	if !ok {
-		return shim.Error("article_delete must be a key in the transient map")
-	}
-
-	if len(articleDeleteJsonBytes) == 0 {
-		return shim.Error("article_delete value in the transient map must be a non-empty JSON string")
-	}
-
-	var articleDeleteInput articleDeleteTransientInput
-	err = json.Unmarshal(articleDeleteJsonBytes, &articleDeleteInput)
-	if err != nil {
-		return shim.Error("Failed to decode JSON of: " + string(articleDeleteJsonBytes))
-	}
-
-	if len(articleDeleteInput.Name) == 0 {
-		return shim.Error("name field must be a non-empty string")
-	}
-
-	// to maintain the color~name index, we need to read the article first and get its color
-	valAsbytes, err := stub.GetPrivateData("collectionArticles", articleDeleteInput.Name) //get the article from chaincode state
-	if err != nil {
-		return shim.Error("Failed to get state for " + articleDeleteInput.Name)
-	} else if valAsbytes == nil {
-		return shim.Error("Article does not exist: " + articleDeleteInput.Name)
-	}
-
-	var articleToDelete article
-	err = json.Unmarshal([]byte(valAsbytes), &articleToDelete)
-	if err != nil {
-		return shim.Error("Failed to decode JSON of: " + string(valAsbytes))
-	}
-
-	// delete the article from state
-	err = stub.DelPrivateData("collectionArticles", articleDeleteInput.Name)
-	if err != nil {
-		return shim.Error("Failed to delete state:" + err.Error())
-	}
-
-	// Also delete the article from the color~name index
-	indexName := "color~name"
-	colorNameIndexKey, err := stub.CreateCompositeKey(indexName, []string{articleToDelete.Color, articleToDelete.Name})
-	if err != nil {
-		return shim.Error(err.Error())
-	}
-	err = stub.DelPrivateData("collectionArticles", colorNameIndexKey)
-	if err != nil {
-		return shim.Error("Failed to delete state:" + err.Error())
-	}
-
-	// Finally, delete private details of article
-	err = stub.DelPrivateData("collectionArticlePrivateDetails", articleDeleteInput.Name)
+	metadataJSONasBytes, err := json.Marshal(&model.ChaincodeMetadata{
+		Name:        chaincodeName,
+		Version:     version,
+		Functions:   t.functionNames(),
+		Collections: []string{cfg.CollectionArticles, cfg.CollectionArticlePrivateDetails},
+	})
 	if err != nil {
 		return shim.Error(err.Error())
 	}
-
-	return shim.Success(nil)
+	return shim.Success(metadataJSONasBytes)
 }
 
-// ===========================================================
-// transfer a article by setting a new owner name on the article
-// ===========================================================
-func (t *ArticlesPrivateChaincode) transferArticle(stub shim.ChaincodeStubInterface, args []string) pb.Response {
-
-	fmt.Println("- start transfer article")
-
-	type articleTransferTransientInput struct {
-		Name  string `json:"name"`
-		Owner string `json:"owner"`
-	}
-
-	if len(args) != 0 {
-		return shim.Error("Incorrect number of arguments. Private article data must be passed in transient map.")
-	}
-
-	transMap, err := stub.GetTransient()
-	if err != nil {
-		return shim.Error("Error getting transient: " + err.Error())
-	}
-
-	articleOwnerJsonBytes, ok := transMap["article_owner"]
-	if !ok {
-		return shim.Error("article_owner must be a key in the transient map")
-	}
-
-	if len(articleOwnerJsonBytes) == 0 {
-		return shim.Error("article_owner value in the transient map must be a non-empty JSON string")
-	}
-
-	var articleTransferInput articleTransferTransientInput
-	err = json.Unmarshal(articleOwnerJsonBytes, &articleTransferInput)
-	if err != nil {
-		return shim.Error("Failed to decode JSON of: " + string(articleOwnerJsonBytes))
-	}
-
-	if len(articleTransferInput.Name) == 0 {
-		return shim.Error("name field must be a non-empty string")
-	}
-	if len(articleTransferInput.Owner) == 0 {
-		return shim.Error("owner field must be a non-empty string")
-	}
-
-	articleAsBytes, err := stub.GetPrivateData("collectionArticles", articleTransferInput.Name)
-	if err != nil {
-		return shim.Error("Failed to get article:" + err.Error())
-	} else if articleAsBytes == nil {
-		return shim.Error("Article does not exist: " + articleTransferInput.Name)
-	}
-
-	articleToTransfer := article{}
-	err = json.Unmarshal(articleAsBytes, &articleToTransfer) //unmarshal it aka JSON.parse()
-	if err != nil {
-		return shim.Error(err.Error())
-	}
-	articleToTransfer.Owner = articleTransferInput.Owner //change the owner
-
-	articleJSONasBytes, _ := json.Marshal(articleToTransfer)
-	err = stub.PutPrivateData("collectionArticles", articleToTransfer.Name, articleJSONasBytes) //rewrite the article
+func main() {
+	cc, err := newArticlesPrivateChaincode()
 	if err != nil {
-		return shim.Error(err.Error())
-	}
-
-	fmt.Println("- end transferArticle (success)")
-	return shim.Success(nil)
-}
-
-// ===========================================================================================
-// getArticlesByRange performs a range query based on the start and end keys provided.
-
-// Read-only function results are not typically submitted to ordering. If the read-only
-// results are submitted to ordering, or if the query is used in an update transaction
-// and submitted to ordering, then the committing peers will re-execute to guarantee that
-// result sets are stable between endorsement time and commit time. The transaction is
-// invalidated by the committing peers if the result set has changed between endorsement
-// time and commit time.
-// Therefore, range queries are a safe option for performing update transactions based on query results.
-// ===========================================================================================
-func (t *ArticlesPrivateChaincode) getArticlesByRange(stub shim.ChaincodeStubInterface, args []string) pb.Response {
-
-	if len(args) < 2 {
-		return shim.Error("Incorrect number of arguments. Expecting 2")
+		fmt.Fprintf(os.Stderr, "Invalid chaincode configuration: %s", err)
+		os.Exit(2)
 	}
 
-	startKey := args[0]
-	endKey := args[1]
-
-	resultsIterator, err := stub.GetPrivateDataByRange("collectionArticles", startKey, endKey)
+	serverCfg, err := loadServerConfig()
 	if err != nil {
-		return shim.Error(err.Error())
+		fmt.Fprintf(os.Stderr, "Invalid chaincode server configuration: %s", err)
+		os.Exit(2)
 	}
-	defer resultsIterator.Close()
 
-	// buffer is a JSON array containing QueryResults
-	var buffer bytes.Buffer
-	buffer.WriteString("[")
-
-	bArrayMemberAlreadyWritten := false
-	for resultsIterator.HasNext() {
-		queryResponse, err := resultsIterator.Next()
-		if err != nil {
-			return shim.Error(err.Error())
-		}
-		// Add a comma before array members, suppress it for the first array member
-		if bArrayMemberAlreadyWritten {
-			buffer.WriteString(",")
+	if serverCfg == nil {
+		err = shim.Start(cc)
+	} else {
+		server := &shim.ChaincodeServer{
+			CCID:     serverCfg.ccID,
+			Address:  serverCfg.address,
+			CC:       cc,
+			TLSProps: serverCfg.tlsProps,
 		}
-
-		buffer.WriteString(
-			fmt.Sprintf(
-				`{"Key":"%s", "Record":%s}`,
-				queryResponse.Key, queryResponse.Value,
-			),
-		)
-		bArrayMemberAlreadyWritten = true
+		err = server.Start()
 	}
-	buffer.WriteString("]")
-
-	fmt.Printf("- getArticlesByRange queryResult:\n%s\n", buffer.String())
-
-	return shim.Success(buffer.Bytes())
-}
-
-func main() {
-	err := shim.Start(&ArticlesPrivateChaincode{})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Exiting Simple chaincode: %s", err)
 		os.Exit(2)
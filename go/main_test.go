@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+func TestInitKeepsCollectionNames(t *testing.T) {
+	s := newScenario(t)
+
+	// the former collection name arguments are rejected instead of changing the
+	// configuration of the endorsing peers only
+	tx := s.admin1.Submit(testutil.Invocation{Init: true, Args: []string{"otherArticles", "otherDetails"}})
+	if tx.Response.Status == shim.OK {
+		t.Fatalf("Init accepted collection names as arguments")
+	}
+	tx = s.admin1.Submit(testutil.Invocation{Init: true, Args: []string{"otherArticles", "otherDetails", "", "{}", "[]"}})
+	if tx.Response.Status == shim.OK {
+		t.Fatalf("Init accepted five arguments")
+	}
+
+	response := s.admin1.Query("metadata")
+	expectStatus(t, response, shim.OK)
+	expected := `"collections":["` + model.DefaultCollectionArticles + `","` + model.DefaultCollectionArticlePrivateDetails + `"]`
+	if !contains(response.Payload, expected) {
+		t.Errorf("metadata %s does not list the default collections", response.Payload)
+	}
+}
+
+func TestInitStoresACLAndColors(t *testing.T) {
+	s := newScenario(t)
+
+	tx := s.admin1.Submit(testutil.Invocation{Init: true, Args: []string{`{"initArticle":["` + org2 + `"]}`, `["red"]`}})
+	expectStatus(t, tx.Response, shim.OK)
+
+	response := s.user1.InvokeTransient("initArticle", testutil.Transient("article", scenarioArticle))
+	expectStatus(t, response, 403)
+
+	response = s.admin1.Query("getAllowedColors")
+	expectStatus(t, response, shim.OK)
+	if !contains(response.Payload, `"red"`) {
+		t.Errorf("getAllowedColors returned %s, expected red", response.Payload)
+	}
+}
+
+func contains(payload []byte, s string) bool {
+	return strings.Contains(string(payload), s)
+}
+
+func TestFunctionACL(t *testing.T) {
+	s := newScenario(t)
+	jerryArticle := `{"name":"article2","color":"red","size":10,"owner":"jerry","salt":"c2FsdHNhbHRzYWx0c2FsdHNhbHQ="}`
+
+	expectStatus(t, s.user1.Invoke("setFunctionACL", "initArticle", `["`+org1+`"]`), 403)
+	expectStatus(t, s.admin1.Invoke("setFunctionACL", "initArticle", `["`+org1+`"]`), shim.OK)
+	response := s.admin2.Query("getFunctionACL")
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != `{"initArticle":["`+org1+`"]}` {
+		t.Errorf("getFunctionACL returned %s", response.Payload)
+	}
+
+	// allowed
+	expectStatus(t, s.user1.InvokeTransient("initArticle", testutil.Transient("article", scenarioArticle)), shim.OK)
+	// denied before the handler runs
+	response = s.user2.InvokeTransient("initArticle", testutil.Transient("article", jerryArticle))
+	expectStatus(t, response, 403)
+	if !contains([]byte(response.Message), "clients of "+org2+" may not call initArticle") {
+		t.Errorf("denied initArticle returned %s", response.Message)
+	}
+	// unlisted
+	for _, client := range []*testutil.Client{s.user1, s.user2} {
+		expectStatus(t, client.Query("readArticle", "article1"), shim.OK)
+	}
+	response = s.admin1.Query("getFunctionACL", "readArticle")
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != "[]" {
+		t.Errorf("getFunctionACL of readArticle returned %s", response.Payload)
+	}
+
+	// an empty list lets anyone call the function again
+	expectStatus(t, s.admin1.Invoke("setFunctionACL", "initArticle", "[]"), shim.OK)
+	expectStatus(t, s.user2.InvokeTransient("initArticle", testutil.Transient("article", jerryArticle)), shim.OK)
+}
+
+func TestNewChaincodeRejectsInvalidEnvironment(t *testing.T) {
+	for _, test := range []struct {
+		variable string
+		value    string
+	}{
+		{"ARTICLE_PRICE_FORMAT", "decimal"},
+		{"ARTICLE_NEGOTIATION_COLLECTIONS", "shared"},
+		{"ARTICLE_REPLAY_PROTECTION", "on"},
+		{"ARTICLE_REPLAY_PROTECTION", ""},
+		{"ARTICLE_NONCE_WINDOW_SECONDS", "five minutes"},
+		{"ARTICLE_NONCE_WINDOW_SECONDS", "0"},
+		{"ARTICLE_NONCE_WINDOW_SECONDS", "-300"},
+	} {
+		t.Run(test.variable+"="+test.value, func(t *testing.T) {
+			t.Setenv(test.variable, test.value)
+			_, err := newArticlesPrivateChaincode()
+			if err == nil || !strings.Contains(err.Error(), test.variable) {
+				t.Errorf("expected an error about %s, got %v", test.variable, err)
+			}
+		})
+	}
+
+	t.Setenv("ARTICLE_REPLAY_PROTECTION", model.ReplayProtectionNonce)
+	t.Setenv("ARTICLE_NONCE_WINDOW_SECONDS", "60")
+	cc, err := newArticlesPrivateChaincode()
+	if err != nil {
+		t.Fatalf("failed to create chaincode: %v", err)
+	}
+	if cc.cfg.ReplayProtection != model.ReplayProtectionNonce || cc.cfg.NonceWindowSeconds != 60 {
+		t.Errorf("chaincode configured with replay protection %q and a nonce window of %d seconds", cc.cfg.ReplayProtection, cc.cfg.NonceWindowSeconds)
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+
+	"privatemarbles/internal/model"
+	"privatemarbles/internal/testutil"
+)
+
+const (
+	org1 = "Org1MSP"
+	org2 = "Org2MSP"
+)
+
+// scenario is the chaincode deployed on a channel of Org1 and Org2, where both orgs are
+// members of the articles collection and only Org1 of the private details collection
+type scenario struct {
+	network *testutil.Network
+	admin1  *testutil.Client
+	user1   *testutil.Client
+	admin2  *testutil.Client
+	user2   *testutil.Client
+}
+
+func newScenario(t *testing.T) *scenario {
+	return newScenarioWithCollections(t, model.DefaultCollectionArticles, model.DefaultCollectionArticlePrivateDetails)
+}
+
+// newScenarioWithCollections deploys the chaincode on a channel whose collections have
+// the names, which the chaincode must be configured with
+func newScenarioWithCollections(t *testing.T, articles string, details string) *scenario {
+	cc, err := newArticlesPrivateChaincode()
+	if err != nil {
+		t.Fatalf("failed to create chaincode: %v", err)
+	}
+	network := testutil.NewNetwork(t, cc,
+		testutil.CollectionConfig{Name: articles, Members: []string{org1, org2}, MemberOnlyRead: true},
+		testutil.CollectionConfig{Name: details, Members: []string{org1}, MemberOnlyRead: true},
+	)
+	s := &scenario{
+		network: network,
+		admin1:  network.Client(org1, "admin1", map[string]string{model.AdminAttribute: "true"}),
+		user1:   network.Client(org1, "user1", nil),
+		admin2:  network.Client(org2, "admin2", map[string]string{model.AdminAttribute: "true"}),
+		user2:   network.Client(org2, "user2", nil),
+	}
+	tx := s.admin1.Submit(testutil.Invocation{Init: true})
+	expectStatus(t, tx.Response, shim.OK)
+	for owner, mspID := range map[string]string{"tom": org1, "jerry": org2} {
+		response := s.admin1.InvokeTransient("registerOwner", testutil.Transient("owner_registration", `{"name":"`+owner+`","mspID":"`+mspID+`"}`))
+		expectStatus(t, response, shim.OK)
+	}
+	return s
+}
+
+func expectStatus(t *testing.T, response pb.Response, status int32) {
+	t.Helper()
+	if response.Status != status {
+		t.Fatalf("expected status %d, got %d: %s", status, response.Status, response.Message)
+	}
+}
+
+const scenarioArticle = `{"name":"article1","color":"blue","size":35,"owner":"tom","price":9900,"currency":"EUR","salt":"c2FsdHNhbHRzYWx0c2FsdHNhbHQ=","quantity":1}`
+
+func TestScenarioCreateByOrg1(t *testing.T) {
+	s := newScenario(t)
+
+	response := s.user1.InvokeTransient("initArticle", testutil.Transient("article", scenarioArticle))
+	expectStatus(t, response, shim.OK)
+
+	for _, client := range []*testutil.Client{s.user1, s.user2} {
+		response = client.Query("readArticle", "article1")
+		expectStatus(t, response, shim.OK)
+		var article model.Article
+		err := json.Unmarshal(response.Payload, &article)
+		if err != nil {
+			t.Fatalf("failed to decode article read by %s: %v", client.MSPID, err)
+		}
+		if article.Owner != "tom" || article.OwnerOrg != org1 {
+			t.Errorf("%s read owner %s of org %s, expected tom of %s", client.MSPID, article.Owner, article.OwnerOrg, org1)
+		}
+	}
+
+	// the peer of Org2 does not endorse writes of Org1 clients
+	response = s.user1.Submit(testutil.Invocation{
+		Function:  "initArticle",
+		Transient: testutil.Transient("article", strings.Replace(scenarioArticle, "article1", "article2", 1)),
+		Peer:      org2,
+	}).Response
+	expectStatus(t, response, 403)
+}
+
+func TestScenarioDetailsReadDeniedForOrg2(t *testing.T) {
+	s := newScenario(t)
+	expectStatus(t, s.user1.InvokeTransient("initArticle", testutil.Transient("article", scenarioArticle)), shim.OK)
+
+	response := s.user1.Query("readArticlePrivateDetails", "article1")
+	expectStatus(t, response, shim.OK)
+	if !bytes.Contains(response.Payload, []byte(`"price":9900`)) {
+		t.Errorf("Org1 read %s, expected the price", response.Payload)
+	}
+
+	// Org2 only learns that the details exist, from their hash
+	response = s.user2.Query("readArticlePrivateDetails", "article1")
+	if bytes.Contains(response.Payload, []byte("9900")) || strings.Contains(response.Message, "9900") {
+		t.Fatalf("Org2 read the price: %s %s", response.Payload, response.Message)
+	}
+	if response.Status == shim.OK {
+		var inaccessible struct {
+			Accessible bool   `json:"accessible"`
+			Hash       string `json:"hash"`
+		}
+		err := json.Unmarshal(response.Payload, &inaccessible)
+		if err != nil || inaccessible.Accessible || len(inaccessible.Hash) == 0 {
+			t.Errorf("Org2 read %s, expected the hash of inaccessible details", response.Payload)
+		}
+	}
+
+	// a client of Org1 going through the peer of Org2 gets no details either
+	response = s.user1.Submit(testutil.Invocation{Function: "readArticlePrivateDetails", Args: []string{"article1"}, Peer: org2, Evaluate: true}).Response
+	if bytes.Contains(response.Payload, []byte("9900")) {
+		t.Errorf("peer of Org2 returned the price: %s", response.Payload)
+	}
+}
+
+func TestScenarioTransferWithOwnerOrgCheck(t *testing.T) {
+	s := newScenario(t)
+	expectStatus(t, s.user1.InvokeTransient("initArticle", testutil.Transient("article", scenarioArticle)), shim.OK)
+
+	response := s.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1","price":9900,"currency":"EUR"}`))
+	expectStatus(t, response, shim.OK)
+
+	// only the org of the current owner transfers the article
+	transfer := testutil.Transient("article_owner", `{"name":"article1","owner":"jerry","ownerOrg":"`+org2+`"}`)
+	response = s.user2.InvokeTransient("transferArticle", transfer)
+	expectStatus(t, response, 403)
+
+	response = s.user1.InvokeTransient("transferArticle", transfer)
+	expectStatus(t, response, shim.OK)
+
+	response = s.user2.Query("readArticle", "article1")
+	expectStatus(t, response, shim.OK)
+	var article model.Article
+	err := json.Unmarshal(response.Payload, &article)
+	if err != nil {
+		t.Fatalf("failed to decode article: %v", err)
+	}
+	if article.Owner != "jerry" || article.OwnerOrg != org2 {
+		t.Errorf("article is owned by %s of %s, expected jerry of %s", article.Owner, article.OwnerOrg, org2)
+	}
+
+	// Org1 no longer owns it
+	response = s.user1.InvokeTransient("transferArticle", testutil.Transient("article_owner", `{"name":"article1","owner":"tom","ownerOrg":"`+org1+`"}`))
+	expectStatus(t, response, 403)
+}
+
+func TestScenarioHashVerificationByOrg2(t *testing.T) {
+	s := newScenario(t)
+	expectStatus(t, s.user1.InvokeTransient("initArticle", testutil.Transient("article", scenarioArticle)), shim.OK)
+
+	// Org2 reads the article and checks it against the hash on the channel
+	response := s.user2.Query("readArticle", "article1")
+	expectStatus(t, response, shim.OK)
+	articleHash := sha256.Sum256(s.network.PrivateData(model.DefaultCollectionArticles, "article1"))
+	response = s.user2.Query("getArticleHash", "article1")
+	expectStatus(t, response, shim.OK)
+	if !bytes.Equal(response.Payload, articleHash[:]) {
+		t.Errorf("getArticleHash returned %x, expected %x", response.Payload, articleHash)
+	}
+
+	// Org2 cannot read the details, but verifies the ones Org1 shows it off-channel
+	response = s.user1.Query("readArticlePrivateDetails", "article1")
+	expectStatus(t, response, shim.OK)
+	detailsHash := sha256.Sum256(response.Payload)
+	response = s.user2.Query("getArticlePrivateDetailsHash", "article1")
+	expectStatus(t, response, shim.OK)
+	if !bytes.Equal(response.Payload, detailsHash[:]) {
+		t.Errorf("getArticlePrivateDetailsHash returned %x, expected %x", response.Payload, detailsHash)
+	}
+
+	// and the properties claimed by Org1, with the salt
+	var article model.Article
+	err := json.Unmarshal(s.network.PrivateData(model.DefaultCollectionArticles, "article1"), &article)
+	if err != nil {
+		t.Fatalf("failed to decode article: %v", err)
+	}
+	claim, err := json.Marshal(&article)
+	if err != nil {
+		t.Fatalf("failed to encode claim: %v", err)
+	}
+	response = s.user2.Submit(testutil.Invocation{Function: "verifyArticleProperties", Transient: testutil.Transient("article_properties", string(claim)), Evaluate: true}).Response
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != `{"match":true}` {
+		t.Errorf("verifyArticleProperties returned %s for the stored article", response.Payload)
+	}
+
+	article.Color = "red"
+	claim, err = json.Marshal(&article)
+	if err != nil {
+		t.Fatalf("failed to encode claim: %v", err)
+	}
+	response = s.user2.Submit(testutil.Invocation{Function: "verifyArticleProperties", Transient: testutil.Transient("article_properties", string(claim)), Evaluate: true}).Response
+	expectStatus(t, response, shim.OK)
+	if string(response.Payload) != `{"match":false}` {
+		t.Errorf("verifyArticleProperties returned %s for another color", response.Payload)
+	}
+}
+
+func TestScenarioDeleteCleanup(t *testing.T) {
+	s := newScenario(t)
+	expectStatus(t, s.user1.InvokeTransient("initArticle", testutil.Transient("article", scenarioArticle)), shim.OK)
+	expectStatus(t, s.user1.InvokeTransient("updateArticlePrice", testutil.Transient("article_price", `{"name":"article1","price":12000,"currency":"EUR"}`)), shim.OK)
+	expectStatus(t, s.user1.InvokeTransient("addArticleTag", testutil.Transient("article_tag", `{"name":"article1","tag":"summer"}`)), shim.OK)
+
+	deletion := testutil.Transient("article_delete", `{"name":"article1"}`)
+	response := s.user1.InvokeTransient("delete", deletion)
+	expectStatus(t, response, 403)
+
+	response = s.admin1.InvokeTransient("delete", deletion)
+	expectStatus(t, response, shim.OK)
+
+	expectStatus(t, s.user2.Query("readArticle", "article1"), 404)
+	for _, collection := range []string{model.DefaultCollectionArticles, model.DefaultCollectionArticlePrivateDetails} {
+		for _, key := range s.network.PrivateKeys(collection) {
+			if strings.Contains(key, "article1") && !isKeptAfterDelete(key) {
+				t.Errorf("%s still holds %q after the delete", collection, key)
+			}
+		}
+		if policy := s.network.PrivateDataValidationParameter(collection, "article1"); policy != nil {
+			t.Errorf("%s still holds the endorsement policy of article1", collection)
+		}
+	}
+}
+
+func TestScenarioCustomCollectionNames(t *testing.T) {
+	t.Setenv("ARTICLES_COLLECTION", "channelArticles")
+	t.Setenv("ARTICLE_PRIVATE_DETAILS_COLLECTION", "channelArticlePrices")
+	s := newScenarioWithCollections(t, "channelArticles", "channelArticlePrices")
+
+	expectStatus(t, s.user1.InvokeTransient("initArticle", testutil.Transient("article", scenarioArticle)), shim.OK)
+	article2 := strings.Replace(scenarioArticle, "article1", "article2", 1)
+	expectStatus(t, s.user1.InvokeTransient("initArticle", testutil.Transient("article", article2)), shim.OK)
+	if s.network.PrivateData("channelArticles", "article1") == nil || s.network.PrivateData("channelArticlePrices", "article1") == nil {
+		t.Fatalf("article1 was not written to the configured collections")
+	}
+
+	response := s.user2.Query("readArticle", "article1")
+	expectStatus(t, response, shim.OK)
+	response = s.user1.Query("readArticlePrivateDetails", "article1")
+	expectStatus(t, response, shim.OK)
+	if !contains(response.Payload, `"price":9900`) {
+		t.Errorf("readArticlePrivateDetails returned %s, expected the price", response.Payload)
+	}
+
+	response = s.user2.InvokeTransient("agreeToTransfer", testutil.Transient("article_agreement", `{"name":"article1","price":9900,"currency":"EUR"}`))
+	expectStatus(t, response, shim.OK)
+	response = s.user1.InvokeTransient("transferArticle", testutil.Transient("article_owner", `{"name":"article1","owner":"jerry","ownerOrg":"`+org2+`"}`))
+	expectStatus(t, response, shim.OK)
+	response = s.user2.Query("readArticle", "article1")
+	expectStatus(t, response, shim.OK)
+	if !contains(response.Payload, `"owner":"jerry"`) {
+		t.Errorf("readArticle returned %s after the transfer to jerry", response.Payload)
+	}
+
+	expectStatus(t, s.admin1.InvokeTransient("delete", testutil.Transient("article_delete", `{"name":"article2"}`)), shim.OK)
+	expectStatus(t, s.user1.Query("readArticle", "article2"), 404)
+	if s.network.PrivateData("channelArticles", "article2") != nil || s.network.PrivateData("channelArticlePrices", "article2") != nil {
+		t.Errorf("article2 is still in the configured collections after the delete")
+	}
+
+	// nothing ever went to the default collections
+	for _, collection := range []string{model.DefaultCollectionArticles, model.DefaultCollectionArticlePrivateDetails} {
+		if keys := s.network.PrivateKeys(collection); len(keys) != 0 {
+			t.Errorf("%s holds %q", collection, keys)
+		}
+	}
+}
+
+// isKeptAfterDelete tells the records a delete keeps on purpose: the audit trail
+func isKeptAfterDelete(key string) bool {
+	prefix, err := shim.CreateCompositeKey(model.AuditIndex, nil)
+	return err == nil && strings.HasPrefix(key, prefix)
+}
+
+func TestScenarioPublicAuditTrail(t *testing.T) {
+	s := newScenario(t)
+	article2 := strings.Replace(scenarioArticle, "article1", "article2", 1)
+	expectStatus(t, s.user1.InvokeTransient("initArticles", testutil.Transient("articles", "["+scenarioArticle+","+article2+"]")), shim.OK)
+	rename := testutil.Transient("article_rename", `{"oldName":"article2","newName":"article3"}`)
+	expectStatus(t, s.user1.InvokeTransient("renameArticle", rename), shim.OK)
+
+	for _, test := range []struct {
+		name      string
+		functions []string
+	}{
+		{"article1", []string{"initArticles"}},
+		{"article2", []string{"initArticles", "renameArticle"}},
+		{"article3", []string{"renameArticle"}},
+	} {
+		// Org2 reads the trail without access to the private details
+		response := s.user2.Query("getPublicAuditTrail", test.name)
+		expectStatus(t, response, shim.OK)
+		var records []model.PublicAuditRecord
+		err := json.Unmarshal(response.Payload, &records)
+		if err != nil {
+			t.Fatalf("failed to decode %s: %v", response.Payload, err)
+		}
+		if len(records) != len(test.functions) {
+			t.Fatalf("%s has the public audit records %s, expected %d", test.name, response.Payload, len(test.functions))
+		}
+		for i, record := range records {
+			if record.Function != test.functions[i] || record.MSPID != org1 {
+				t.Errorf("public audit record %d of %s is %s by %s, expected %s by %s", i, test.name, record.Function, record.MSPID, test.functions[i], org1)
+			}
+		}
+		last := records[len(records)-1]
+		if last.ArticleHash != hex.EncodeToString(s.network.PrivateDataHash(model.DefaultCollectionArticles, test.name)) {
+			t.Errorf("public audit record of %s has the article hash %s, not the one of the stored article", test.name, last.ArticleHash)
+		}
+		if last.PrivateDetailsHash != hex.EncodeToString(s.network.PrivateDataHash(model.DefaultCollectionArticlePrivateDetails, test.name)) {
+			t.Errorf("public audit record of %s has the details hash %s, not the one of the stored details", test.name, last.PrivateDetailsHash)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// serverConfig is the configuration of the chaincode when it runs as an external
+// service (chaincode-as-a-service) instead of connecting to the peer itself
+type serverConfig struct {
+	ccID     string
+	address  string
+	tlsProps shim.TLSProperties
+}
+
+// loadServerConfig reads the chaincode-as-a-service configuration from the environment.
+// It returns nil when CHAINCODE_SERVER_ADDRESS is unset, the chaincode then connects to
+// the peer with shim.Start.
+//
+//	CHAINCODE_SERVER_ADDRESS       address the chaincode server listens on, e.g. 0.0.0.0:9999
+//	CHAINCODE_ID                   package ID of the chaincode as returned by the peer at install
+//	CHAINCODE_TLS_KEY_FILE         PEM private key of the server, enables TLS with the cert file
+//	CHAINCODE_TLS_CERT_FILE        PEM certificate of the server
+//	CHAINCODE_TLS_CLIENT_CA_FILE   PEM CA certificates of the peers, enables mutual TLS
+func loadServerConfig() (*serverConfig, error) {
+	address, ok := os.LookupEnv("CHAINCODE_SERVER_ADDRESS")
+	if !ok {
+		return nil, nil
+	}
+	if len(address) == 0 {
+		return nil, fmt.Errorf("CHAINCODE_SERVER_ADDRESS must be a non-empty address")
+	}
+	ccID := os.Getenv("CHAINCODE_ID")
+	if len(ccID) == 0 {
+		return nil, fmt.Errorf("CHAINCODE_ID must be set to the chaincode package ID when CHAINCODE_SERVER_ADDRESS is set")
+	}
+
+	keyFile := os.Getenv("CHAINCODE_TLS_KEY_FILE")
+	certFile := os.Getenv("CHAINCODE_TLS_CERT_FILE")
+	clientCAFile := os.Getenv("CHAINCODE_TLS_CLIENT_CA_FILE")
+
+	cfg := &serverConfig{ccID: ccID, address: address}
+	if len(keyFile) == 0 && len(certFile) == 0 {
+		if len(clientCAFile) != 0 {
+			return nil, fmt.Errorf("CHAINCODE_TLS_CLIENT_CA_FILE requires CHAINCODE_TLS_KEY_FILE and CHAINCODE_TLS_CERT_FILE")
+		}
+		cfg.tlsProps.Disabled = true
+		return cfg, nil
+	}
+	if len(keyFile) == 0 || len(certFile) == 0 {
+		return nil, fmt.Errorf("CHAINCODE_TLS_KEY_FILE and CHAINCODE_TLS_CERT_FILE must be set together")
+	}
+
+	var err error
+	cfg.tlsProps.Key, err = ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CHAINCODE_TLS_KEY_FILE: %v", err)
+	}
+	cfg.tlsProps.Cert, err = ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CHAINCODE_TLS_CERT_FILE: %v", err)
+	}
+	if len(clientCAFile) != 0 {
+		cfg.tlsProps.ClientCACerts, err = ioutil.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CHAINCODE_TLS_CLIENT_CA_FILE: %v", err)
+		}
+	}
+	return cfg, nil
+}
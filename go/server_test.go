@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// serverEnv are the environment variables loadServerConfig reads
+var serverEnv = []string{
+	"CHAINCODE_SERVER_ADDRESS",
+	"CHAINCODE_ID",
+	"CHAINCODE_TLS_KEY_FILE",
+	"CHAINCODE_TLS_CERT_FILE",
+	"CHAINCODE_TLS_CLIENT_CA_FILE",
+}
+
+func TestLoadServerConfig(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{}
+	for _, name := range []string{"key", "cert", "ca"} {
+		files[name] = filepath.Join(dir, name+".pem")
+		err := ioutil.WriteFile(files[name], []byte(name), 0600)
+		if err != nil {
+			t.Fatalf("failed to write %s: %v", files[name], err)
+		}
+	}
+	missing := filepath.Join(dir, "missing.pem")
+
+	for _, test := range []struct {
+		name     string
+		env      map[string]string
+		err      string // part of the expected error, or empty for none
+		disabled bool
+		key      string
+		cert     string
+		clientCA string
+	}{
+		{name: "unset", env: map[string]string{"CHAINCODE_ID": "articles:1"}},
+		{name: "empty address", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "", "CHAINCODE_ID": "articles:1"}, err: "CHAINCODE_SERVER_ADDRESS must be a non-empty address"},
+		{name: "no ID", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "0.0.0.0:9999"}, err: "CHAINCODE_ID must be set"},
+		{name: "no TLS", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "0.0.0.0:9999", "CHAINCODE_ID": "articles:1"}, disabled: true},
+		{name: "key only", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "0.0.0.0:9999", "CHAINCODE_ID": "articles:1", "CHAINCODE_TLS_KEY_FILE": files["key"]}, err: "must be set together"},
+		{name: "cert only", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "0.0.0.0:9999", "CHAINCODE_ID": "articles:1", "CHAINCODE_TLS_CERT_FILE": files["cert"]}, err: "must be set together"},
+		{name: "client CA only", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "0.0.0.0:9999", "CHAINCODE_ID": "articles:1", "CHAINCODE_TLS_CLIENT_CA_FILE": files["ca"]}, err: "CHAINCODE_TLS_CLIENT_CA_FILE requires"},
+		{name: "TLS", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "0.0.0.0:9999", "CHAINCODE_ID": "articles:1", "CHAINCODE_TLS_KEY_FILE": files["key"], "CHAINCODE_TLS_CERT_FILE": files["cert"]}, key: "key", cert: "cert"},
+		{name: "mutual TLS", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "0.0.0.0:9999", "CHAINCODE_ID": "articles:1", "CHAINCODE_TLS_KEY_FILE": files["key"], "CHAINCODE_TLS_CERT_FILE": files["cert"], "CHAINCODE_TLS_CLIENT_CA_FILE": files["ca"]}, key: "key", cert: "cert", clientCA: "ca"},
+		{name: "unreadable key", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "0.0.0.0:9999", "CHAINCODE_ID": "articles:1", "CHAINCODE_TLS_KEY_FILE": missing, "CHAINCODE_TLS_CERT_FILE": files["cert"]}, err: "failed to read CHAINCODE_TLS_KEY_FILE"},
+		{name: "unreadable client CA", env: map[string]string{"CHAINCODE_SERVER_ADDRESS": "0.0.0.0:9999", "CHAINCODE_ID": "articles:1", "CHAINCODE_TLS_KEY_FILE": files["key"], "CHAINCODE_TLS_CERT_FILE": files["cert"], "CHAINCODE_TLS_CLIENT_CA_FILE": missing}, err: "failed to read CHAINCODE_TLS_CLIENT_CA_FILE"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for _, name := range serverEnv {
+				value, ok := test.env[name]
+				t.Setenv(name, value)
+				if !ok {
+					os.Unsetenv(name)
+				}
+			}
+
+			cfg, err := loadServerConfig()
+			if len(test.err) != 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to load the config: %v", err)
+			}
+			if _, ok := test.env["CHAINCODE_SERVER_ADDRESS"]; !ok {
+				if cfg != nil {
+					t.Fatalf("expected no server config, got %+v", cfg)
+				}
+				return
+			}
+			if cfg.address != "0.0.0.0:9999" || cfg.ccID != "articles:1" {
+				t.Errorf("server config has address %s and ID %s", cfg.address, cfg.ccID)
+			}
+			if cfg.tlsProps.Disabled != test.disabled || string(cfg.tlsProps.Key) != test.key || string(cfg.tlsProps.Cert) != test.cert || string(cfg.tlsProps.ClientCACerts) != test.clientCA {
+				t.Errorf("TLS properties are disabled %v, key %q, cert %q, client CA %q", cfg.tlsProps.Disabled, cfg.tlsProps.Key, cfg.tlsProps.Cert, cfg.tlsProps.ClientCACerts)
+			}
+		})
+	}
+}